@@ -1,26 +1,174 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/git"
 	"github.com/techthos/clockwork/internal/server"
 	"github.com/techthos/clockwork/internal/tui"
 )
 
 func main() {
-	// Check for TUI mode
-	if len(os.Args) > 1 && os.Args[1] == "tui" {
-		runTUI()
-		return
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "tui":
+			runTUI()
+			return
+		case "doctor":
+			runDoctor(os.Args[2:])
+			return
+		case "validate":
+			runValidate(os.Args[2:])
+			return
+		case "sync-export":
+			runSyncExport(os.Args[2:])
+			return
+		case "sync-import":
+			runSyncImport(os.Args[2:])
+			return
+		}
 	}
 
 	// Default: Run MCP server
 	runMCPServer()
 }
 
+func runDoctor(args []string) {
+	store, _ := openReportStore("doctor", args)
+	defer store.Close()
+
+	report, err := store.Integrity()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Integrity check failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Database: %s\n", report.Path)
+	fmt.Printf("File size: %d bytes\n", report.FileSize)
+
+	buckets := make([]string, 0, len(report.BucketCounts))
+	for bucket := range report.BucketCounts {
+		buckets = append(buckets, bucket)
+	}
+	sort.Strings(buckets)
+	for _, bucket := range buckets {
+		fmt.Printf("  %-12s %d records\n", bucket, report.BucketCounts[bucket])
+	}
+
+	if report.OrphanCount > 0 {
+		fmt.Printf("\n⚠️  %d entries reference a deleted project (shown as \"Unknown Project\"); repair them with the repair_orphan_entries tool\n", report.OrphanCount)
+	}
+
+	if !report.OK {
+		fmt.Printf("\n❌ Consistency check found %d problem(s):\n", len(report.Errors))
+		for _, checkErr := range report.Errors {
+			fmt.Printf("  - %s\n", checkErr)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Println("\n✅ Database is healthy")
+}
+
+func runValidate(args []string) {
+	store, _ := openReportStore("validate", args)
+	defer store.Close()
+
+	report, err := store.ValidateCommits(git.ValidateCommitHash)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Commit validation failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Checked %d commit hash(es)\n", report.Checked)
+	if report.InvalidCount == 0 {
+		fmt.Println("\n✅ All commit hashes exist in their repositories")
+		return
+	}
+
+	for _, project := range report.Projects {
+		fmt.Printf("\n❌ %s (%s): %d of %d invalid\n", project.ProjectName, project.GitRepoPath, len(project.Invalid), project.Checked)
+		for _, invalid := range project.Invalid {
+			fmt.Printf("  - entry %s (%s): %s\n", invalid.EntryID, invalid.CreatedAt.Format("2006-01-02 15:04"), invalid.CommitHash)
+		}
+	}
+
+	fmt.Printf("\n%d invalid commit hash(es); repair them with fix-commits\n", report.InvalidCount)
+	os.Exit(1)
+}
+
+func runSyncExport(args []string) {
+	store, args := openReportStore("sync-export", args)
+	defer store.Close()
+
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: clockwork sync-export [--replica <path>] <file>")
+		os.Exit(2)
+	}
+
+	file, err := os.Create(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create snapshot: %v\n", err)
+		os.Exit(1)
+	}
+	if err := store.ExportSnapshot(file); err != nil {
+		file.Close()
+		fmt.Fprintf(os.Stderr, "Snapshot export failed: %v\n", err)
+		os.Exit(1)
+	}
+	if err := file.Close(); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to write snapshot: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✅ Wrote snapshot to %s\n", args[0])
+}
+
+func runSyncImport(args []string) {
+	if len(args) != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: clockwork sync-import <file>")
+		os.Exit(2)
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to open snapshot: %v\n", err)
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	store := openStore()
+	defer store.Close()
+
+	report, err := store.MergeSnapshot(file)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Snapshot import failed: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("Projects: %d added, %d updated\n", report.ProjectsAdded, report.ProjectsUpdated)
+	fmt.Printf("Entries:  %d added, %d updated\n", report.EntriesAdded, report.EntriesUpdated)
+	if len(report.SkippedEntries) > 0 {
+		fmt.Printf("\n⚠️  Skipped %d entries whose project exists in neither database\n", len(report.SkippedEntries))
+	}
+
+	if len(report.Conflicts) == 0 {
+		fmt.Println("\n✅ No conflicts")
+		return
+	}
+
+	fmt.Printf("\nResolved %d conflict(s), most recently updated wins:\n", len(report.Conflicts))
+	for _, conflict := range report.Conflicts {
+		fmt.Printf("  - %s %s: %s (local %s, incoming %s)\n", conflict.Target, conflict.ID, conflict.Resolution,
+			conflict.LocalUpdatedAt.Format("2006-01-02 15:04:05"), conflict.IncomingUpdatedAt.Format("2006-01-02 15:04:05"))
+	}
+}
+
 func runTUI() {
 	// Initialize database
 	dbPath, err := getDBPath()
@@ -44,20 +192,83 @@ func runTUI() {
 	}
 }
 
+// runMCPServer serves MCP over stdio, or over HTTP with --transport http (or CLOCKWORK_TRANSPORT=http)
 func runMCPServer() {
-	srv, err := server.New()
+	flags := flag.NewFlagSet("clockwork", flag.ExitOnError)
+	transport := flags.String("transport", envOr("CLOCKWORK_TRANSPORT", "stdio"), "MCP transport, 'stdio' or 'http' (env CLOCKWORK_TRANSPORT)")
+	addr := flags.String("addr", envOr("CLOCKWORK_HTTP_ADDR", server.DefaultHTTPAddr), "listen address of the http transport (env CLOCKWORK_HTTP_ADDR)")
+	replica := flags.String("replica", envOr("CLOCKWORK_REPLICA", ""), "serve the reporting and export tools from this copy of the database (read-only); writes still go to the live one (env CLOCKWORK_REPLICA)")
+	flags.Parse(os.Args[1:])
+
+	if *transport != "stdio" && *transport != "http" {
+		fmt.Fprintf(os.Stderr, "Unknown transport %q (use 'stdio' or 'http')\n", *transport)
+		os.Exit(2)
+	}
+
+	srv, err := server.NewWithReplica(*replica)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize server: %v\n", err)
 		os.Exit(1)
 	}
 	defer srv.Close()
 
-	if err := srv.Serve(); err != nil {
+	if *transport == "http" {
+		fmt.Fprintf(os.Stderr, "Serving MCP on http://%s%s\n", *addr, server.HTTPEndpoint)
+		err = srv.ServeHTTP(*addr)
+	} else {
+		err = srv.Serve()
+	}
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "Server error: %v\n", err)
 		os.Exit(1)
 	}
 }
 
+// envOr returns the environment variable key, or fallback when it is unset or empty
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// openReportStore opens the database for a reporting subcommand and returns it with the
+// arguments left after the flags
+// With --replica <path> it opens that copy read-only instead of the live database, so reports
+// don't contend with a running server or TUI; the results are only as fresh as the copy.
+func openReportStore(name string, args []string) (*db.Store, []string) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	replica := flags.String("replica", "", "read from this copy of the database (read-only) instead of the live one")
+	flags.Parse(args)
+
+	if *replica != "" {
+		store, err := db.NewReadOnly(*replica)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to open replica: %v\n", err)
+			os.Exit(1)
+		}
+		return store, flags.Args()
+	}
+
+	return openStore(), flags.Args()
+}
+
+// openStore opens the live database, exiting on failure
+func openStore() *db.Store {
+	dbPath, err := getDBPath()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to resolve database path: %v\n", err)
+		os.Exit(1)
+	}
+
+	store, err := db.New(dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize database: %v\n", err)
+		os.Exit(1)
+	}
+	return store
+}
+
 func getDBPath() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
@@ -5,8 +5,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/git"
 )
 
 func main() {
@@ -35,7 +38,7 @@ func main() {
 	defer store.Close()
 
 	// List all projects
-	projects, err := store.ListProjects()
+	projects, err := store.ListProjects(true)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to list projects: %v\n", err)
 		os.Exit(1)
@@ -50,40 +53,23 @@ func main() {
 		fmt.Printf("  Git Repo Path: %s\n", project.GitRepoPath)
 
 		// Check if path exists
-		absPath, err := filepath.Abs(project.GitRepoPath)
-		if err != nil {
-			fmt.Printf("  ❌ Path Error: %v\n", err)
-			continue
-		}
+		repo := git.ValidateRepo(project.GitRepoPath)
+		absPath := repo.Path
 		fmt.Printf("  Absolute Path: %s\n", absPath)
 
-		if _, err := os.Stat(absPath); os.IsNotExist(err) {
-			fmt.Printf("  ❌ Status: Path does not exist\n")
-		} else if err != nil {
-			fmt.Printf("  ❌ Status: Cannot access path (%v)\n", err)
-		} else {
+		switch {
+		case !repo.Exists:
+			fmt.Printf("  ❌ Status: %v\n", repo.Err)
+		case !repo.IsRepo:
 			fmt.Printf("  ✓ Status: Path exists\n")
-
-			// Check if it's a git repository
-			cmd := exec.Command("git", "rev-parse", "--git-dir")
-			cmd.Dir = absPath
-			output, err := cmd.Output()
-			if err != nil {
-				fmt.Printf("  ❌ Git Status: Not a git repository (exit %v)\n", err)
+			fmt.Printf("  ❌ Git Status: %v\n", repo.Err)
+		default:
+			fmt.Printf("  ✓ Status: Path exists\n")
+			fmt.Printf("  ✓ Git Status: Valid repository (%s)\n", repo.GitDir)
+			if repo.HeadHash == "" {
+				fmt.Printf("  ❌ HEAD: Cannot resolve HEAD (no commits yet)\n")
 			} else {
-				gitDir := string(output)
-				fmt.Printf("  ✓ Git Status: Valid repository (%s)\n", gitDir[:len(gitDir)-1])
-
-				// Get HEAD commit
-				cmd = exec.Command("git", "rev-parse", "HEAD")
-				cmd.Dir = absPath
-				output, err = cmd.Output()
-				if err != nil {
-					fmt.Printf("  ❌ HEAD: Cannot resolve HEAD (%v)\n", err)
-				} else {
-					headHash := string(output)[:40]
-					fmt.Printf("  ✓ HEAD: %s\n", headHash)
-				}
+				fmt.Printf("  ✓ HEAD: %s\n", repo.HeadHash)
 			}
 		}
 
@@ -114,5 +100,23 @@ func main() {
 		fmt.Println()
 	}
 
+	// Entries with a future or inconsistent date sort above everything logged since
+	skewed, err := store.FindClockSkewEntries(time.Now(), db.DefaultSkewTolerance)
+	if err != nil {
+		fmt.Printf("❌ Clock Skew Check Error: %v\n", err)
+	} else if len(skewed) == 0 {
+		fmt.Println("✓ Clock Skew: No entries with future or inconsistent dates")
+	} else {
+		fmt.Printf("⚠️  Clock Skew: %d entr(ies) to review:\n", len(skewed))
+		for _, flagged := range skewed {
+			fmt.Printf("  - entry %s (created %s, updated %s): %s\n",
+				flagged.Entry.ID,
+				flagged.Entry.CreatedAt.Format("2006-01-02 15:04"),
+				flagged.Entry.UpdatedAt.Format("2006-01-02 15:04"),
+				strings.Join(flagged.Reasons, ", "))
+		}
+		fmt.Println("     Fix the date with update_entry's created_at.")
+	}
+
 	fmt.Println("\nDiagnostic complete.")
 }
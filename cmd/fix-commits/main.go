@@ -28,7 +28,7 @@ func main() {
 	defer store.Close()
 
 	// Get all projects
-	projects, err := store.ListProjects()
+	projects, err := store.ListProjects(true)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to list projects: %v\n", err)
 		os.Exit(1)
@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"os/exec"
+	"strings"
 
 	"github.com/techthos/clockwork/internal/git"
 )
@@ -35,17 +36,7 @@ func main() {
 		return
 	}
 
-	lines := []string{}
-	start := 0
-	for i := 0; i < len(output); i++ {
-		if output[i] == '\n' {
-			lines = append(lines, string(output[start:i]))
-			start = i + 1
-		}
-	}
-	if start < len(output) {
-		lines = append(lines, string(output[start:]))
-	}
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 
 	if len(lines) >= 4 {
 		sinceHash := lines[3] // 4th commit
@@ -70,7 +61,7 @@ func main() {
 						fmt.Printf("   ❌ WARNING: Hash has repeated pattern!\n")
 					}
 					// Check for e8e8e8 pattern
-					if contains(commit.Hash[20:], "e8e8") {
+					if strings.Contains(commit.Hash[20:], "e8e8") {
 						fmt.Printf("   ❌ WARNING: Hash contains e8e8 pattern in second half!\n")
 					}
 				} else if len(commit.Hash) != 40 {
@@ -85,7 +76,7 @@ func main() {
 
 	// Test 3: Raw git log output
 	fmt.Println("=== Test 3: Raw git log output (last commit) ===")
-	cmd = exec.Command("git", "log", "--pretty=format:%H|%an|%s|%at", "-n", "1")
+	cmd = exec.Command("git", "log", "--encoding=UTF-8", "--pretty=format:%H|%an|%s|%at", "-n", "1")
 	cmd.Dir = repoPath
 	output, err = cmd.Output()
 	if err != nil {
@@ -94,17 +85,7 @@ func main() {
 		fmt.Printf("Raw output: %s\n", string(output))
 		fmt.Printf("Raw output length: %d\n", len(output))
 		// Parse it
-		parts := []string{}
-		start := 0
-		for i := 0; i < len(output); i++ {
-			if output[i] == '|' {
-				parts = append(parts, string(output[start:i]))
-				start = i + 1
-			}
-		}
-		if start < len(output) {
-			parts = append(parts, string(output[start:]))
-		}
+		parts := strings.Split(string(output), "|")
 
 		if len(parts) >= 1 {
 			fmt.Printf("Parsed hash: %s\n", parts[0])
@@ -113,12 +94,3 @@ func main() {
 		}
 	}
 }
-
-func contains(s, substr string) bool {
-	for i := 0; i <= len(s)-len(substr); i++ {
-		if s[i:i+len(substr)] == substr {
-			return true
-		}
-	}
-	return false
-}
@@ -0,0 +1,98 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SetEntryAdjustment flags or clears an entry as needing an invoice adjustment
+// The note describes the correction; clearing the flag also clears the note.
+// Locked and invoiced entries can be flagged, since corrections are usually found after invoicing.
+func (s *Store) SetEntryAdjustment(id string, needsAdjustment bool, note string) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.NeedsAdjustment = needsAdjustment
+		entry.AdjustmentNote = ""
+		if needsAdjustment {
+			entry.AdjustmentNote = strings.TrimSpace(note)
+		}
+		return nil
+	})
+}
+
+// ReopenEntry reopens an invoiced or locked entry for correction: it clears the invoiced flag and
+// invoice number and unlocks the entry. The reason is required and recorded in the audit log, so
+// reopening is always deliberate and traceable, unlike a plain update.
+func (s *Store) ReopenEntry(id, reason string) (*models.Entry, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("a reason is required to reopen an entry")
+	}
+
+	var entry models.Entry
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(entriesBucket))
+		data := b.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("entry not found")
+		}
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+		if !entry.Invoiced && !entry.Locked {
+			return fmt.Errorf("entry is neither invoiced nor locked")
+		}
+
+		before := entry
+		entry.Invoiced = false
+		entry.InvoiceNumber = ""
+		entry.Locked = false
+		entry.UpdatedAt = time.Now()
+
+		if err := putEntry(b, &entry); err != nil {
+			return err
+		}
+		return recordAudit(tx, AuditReopen, AuditTargetEntry, id, diffEntry(&before, &entry), reason)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to reopen entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// FindAdjustmentEntries returns entries flagged as needing an invoice adjustment, oldest first
+// An empty projectID returns flagged entries of all projects
+func (s *Store) FindAdjustmentEntries(projectID string) ([]*models.Entry, error) {
+	var flagged []*models.Entry
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(entriesBucket)).ForEach(func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if entry.NeedsAdjustment && (projectID == "" || entry.ProjectID == projectID) {
+				flagged = append(flagged, &entry)
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find entries needing adjustment: %w", err)
+	}
+
+	sort.Slice(flagged, func(i, j int) bool {
+		return flagged[i].CreatedAt.Before(flagged[j].CreatedAt)
+	})
+
+	return flagged, nil
+}
@@ -0,0 +1,122 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetEntryAdjustment(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	entry, _ := store.CreateEntry(project.ID, 60, "Invoiced work", "", true, time.Now())
+	store.SetEntryLocked(entry.ID, true)
+
+	flagged, err := store.SetEntryAdjustment(entry.ID, true, "  billed 1h, should be 45m ")
+	if err != nil {
+		t.Fatalf("SetEntryAdjustment() error = %v", err)
+	}
+	if !flagged.NeedsAdjustment || flagged.AdjustmentNote != "billed 1h, should be 45m" {
+		t.Errorf("Expected flag with trimmed note, got %v %q", flagged.NeedsAdjustment, flagged.AdjustmentNote)
+	}
+	if !flagged.Invoiced {
+		t.Error("Expected flagging to leave the entry invoiced")
+	}
+
+	retrieved, _ := store.GetEntry(entry.ID)
+	if !retrieved.NeedsAdjustment || retrieved.AdjustmentNote != "billed 1h, should be 45m" {
+		t.Errorf("Expected persisted flag and note, got %v %q", retrieved.NeedsAdjustment, retrieved.AdjustmentNote)
+	}
+
+	cleared, err := store.SetEntryAdjustment(entry.ID, false, "ignored")
+	if err != nil {
+		t.Fatalf("SetEntryAdjustment() error = %v", err)
+	}
+	if cleared.NeedsAdjustment || cleared.AdjustmentNote != "" {
+		t.Errorf("Expected flag and note to be cleared, got %v %q", cleared.NeedsAdjustment, cleared.AdjustmentNote)
+	}
+
+	if _, err := store.SetEntryAdjustment("missing", true, ""); err == nil {
+		t.Error("Expected error for nonexistent entry")
+	}
+}
+
+func TestFindAdjustmentEntries(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	other, _ := store.CreateProject("Other", "/other")
+	now := time.Now()
+
+	newer, _ := store.CreateEntry(project.ID, 30, "Newer", "", true, now)
+	older, _ := store.CreateEntry(project.ID, 30, "Older", "", true, now.Add(-48*time.Hour))
+	store.CreateEntry(project.ID, 30, "Fine", "", true, now.Add(-time.Hour))
+	elsewhere, _ := store.CreateEntry(other.ID, 30, "Other project", "", false, now.Add(-time.Hour))
+	resolved, _ := store.CreateEntry(project.ID, 30, "Resolved", "", true, now)
+
+	store.SetEntryAdjustment(newer.ID, true, "")
+	store.SetEntryAdjustment(older.ID, true, "wrong rate")
+	store.SetEntryAdjustment(elsewhere.ID, true, "")
+	store.SetEntryAdjustment(resolved.ID, true, "")
+	store.SetEntryAdjustment(resolved.ID, false, "")
+
+	all, err := store.FindAdjustmentEntries("")
+	if err != nil {
+		t.Fatalf("FindAdjustmentEntries() error = %v", err)
+	}
+	if len(all) != 3 || all[0].ID != older.ID || all[1].ID != elsewhere.ID || all[2].ID != newer.ID {
+		t.Errorf("Expected the 3 flagged entries oldest first, got %v", all)
+	}
+
+	scoped, _ := store.FindAdjustmentEntries(project.ID)
+	if len(scoped) != 2 || scoped[0].ID != older.ID || scoped[1].ID != newer.ID {
+		t.Errorf("Expected the project's 2 flagged entries, got %v", scoped)
+	}
+}
+
+func TestReopenEntry(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Acme", "/path")
+	entry, _ := store.CreateEntry(project.ID, 60, "Billed work", "", false, time.Now())
+	invoice, _ := store.IssueInvoice(project.ID, nil, nil, time.Now())
+	store.SetEntryLocked(entry.ID, true)
+
+	if _, err := store.ReopenEntry(entry.ID, "  "); err == nil {
+		t.Fatal("Expected error without a reason")
+	}
+	if unchanged, _ := store.GetEntry(entry.ID); !unchanged.Invoiced || !unchanged.Locked {
+		t.Fatal("Expected a rejected reopen to leave the entry untouched")
+	}
+
+	reopened, err := store.ReopenEntry(entry.ID, "Client disputed hours")
+	if err != nil {
+		t.Fatalf("ReopenEntry() error = %v", err)
+	}
+	if reopened.Invoiced || reopened.Locked || reopened.InvoiceNumber != "" {
+		t.Errorf("Expected the entry uninvoiced, unlocked and without invoice number %s, got %+v", invoice.Number, reopened)
+	}
+
+	events, _ := store.AuditLog(1)
+	if len(events) != 1 || events[0].Operation != AuditReopen || events[0].TargetID != entry.ID || events[0].Detail != "Client disputed hours" {
+		t.Fatalf("Expected a reopen audit event with the reason, got %+v", events)
+	}
+	fields := map[string]bool{}
+	for _, change := range events[0].Changes {
+		fields[change.Field] = true
+	}
+	if !fields["invoiced"] || !fields["locked"] || !fields["invoice_number"] {
+		t.Errorf("Expected invoiced, locked and invoice_number in the audit diff, got %+v", events[0].Changes)
+	}
+
+	// Nothing to reopen any more
+	if _, err := store.ReopenEntry(entry.ID, "Again"); err == nil {
+		t.Error("Expected error reopening an open entry")
+	}
+	if _, err := store.ReopenEntry("missing", "Reason"); err == nil {
+		t.Error("Expected error for a missing entry")
+	}
+}
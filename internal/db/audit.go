@@ -0,0 +1,191 @@
+package db
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// auditBucket holds the audit log, keyed by big-endian sequence so keys sort chronologically
+const auditBucket = "audit"
+
+// MaxAuditEvents bounds the audit log; the oldest events are purged as new ones are recorded
+const MaxAuditEvents = 1000
+
+// maxAuditValue is the longest field value kept in an audit diff
+const maxAuditValue = 80
+
+// Audit operations
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+	AuditTrash  = "trash"
+	AuditReopen = "reopen" // An invoiced or locked entry reopened for correction; the detail holds the reason
+)
+
+// Audit targets
+const (
+	AuditTargetProject = "project"
+	AuditTargetEntry   = "entry"
+)
+
+// diffEntry returns the fields that differ between two entry versions
+// Diffing against an empty entry lists every set field of a created or deleted entry
+func diffEntry(before, after *models.Entry) []models.FieldChange {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	}
+
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"project_id", before.ProjectID, after.ProjectID},
+		{"duration", strconv.FormatInt(before.Duration, 10), strconv.FormatInt(after.Duration, 10)},
+		{"estimate_minutes", strconv.FormatInt(before.EstimateMinutes, 10), strconv.FormatInt(after.EstimateMinutes, 10)},
+		{"message", before.Message, after.Message},
+		{"author", before.Author, after.Author},
+		{"commit_hash", before.CommitHash, after.CommitHash},
+		{"mode", before.Mode, after.Mode},
+		{"reference", before.Reference, after.Reference},
+		{"category", before.Category, after.Category},
+		{"location", before.Location, after.Location},
+		{"source", before.Source, after.Source},
+		{"invoiced", strconv.FormatBool(before.Invoiced), strconv.FormatBool(after.Invoiced)},
+		{"invoice_number", before.InvoiceNumber, after.InvoiceNumber},
+		{"locked", strconv.FormatBool(before.Locked), strconv.FormatBool(after.Locked)},
+		{"pinned", strconv.FormatBool(before.Pinned), strconv.FormatBool(after.Pinned)},
+		{"needs_adjustment", strconv.FormatBool(before.NeedsAdjustment), strconv.FormatBool(after.NeedsAdjustment)},
+		{"adjustment_note", before.AdjustmentNote, after.AdjustmentNote},
+		{"tags", strings.Join(before.Tags, ","), strings.Join(after.Tags, ",")},
+		{"created_at", formatTime(before.CreatedAt), formatTime(after.CreatedAt)},
+	}
+
+	var changes []models.FieldChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, models.FieldChange{
+				Field:    f.name,
+				OldValue: f.old,
+				NewValue: f.new,
+			})
+		}
+	}
+	return changes
+}
+
+// recordEntryUpdate audits an entry edit, skipping edits that changed no tracked field
+func recordEntryUpdate(tx *bolt.Tx, before, after *models.Entry) error {
+	changes := diffEntry(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+	return recordAudit(tx, AuditUpdate, AuditTargetEntry, after.ID, changes, "")
+}
+
+// briefAuditValue truncates a value to maxAuditValue characters
+func briefAuditValue(value string) string {
+	runes := []rune(value)
+	if len(runes) <= maxAuditValue {
+		return value
+	}
+	return string(runes[:maxAuditValue-1]) + "…"
+}
+
+// recordAudit appends an audit event within a write transaction and purges events
+// that fell out of the MaxAuditEvents window
+func recordAudit(tx *bolt.Tx, operation, target, targetID string, changes []models.FieldChange, detail string) error {
+	b := tx.Bucket([]byte(auditBucket))
+	seq, err := b.NextSequence()
+	if err != nil {
+		return err
+	}
+
+	for i := range changes {
+		changes[i].OldValue = briefAuditValue(changes[i].OldValue)
+		changes[i].NewValue = briefAuditValue(changes[i].NewValue)
+	}
+
+	event := models.AuditEvent{
+		Seq:       seq,
+		Timestamp: time.Now(),
+		Operation: operation,
+		Target:    target,
+		TargetID:  targetID,
+		Changes:   changes,
+		Detail:    detail,
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if err := b.Put(auditKey(seq), data); err != nil {
+		return err
+	}
+
+	if seq <= MaxAuditEvents {
+		return nil
+	}
+
+	// Collect keys first; deleting while iterating a cursor skips items
+	cutoff := seq - MaxAuditEvents
+	var expired [][]byte
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil && binary.BigEndian.Uint64(k) <= cutoff; k, _ = c.Next() {
+		expired = append(expired, append([]byte(nil), k...))
+	}
+	for _, k := range expired {
+		if err := b.Delete(k); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// auditKey encodes an audit sequence number as a sortable key
+func auditKey(seq uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, seq)
+	return key
+}
+
+// AuditLog returns the most recent limit audit events, oldest first (limit <= 0 returns all)
+func (s *Store) AuditLog(limit int) ([]*models.AuditEvent, error) {
+	var events []*models.AuditEvent
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket([]byte(auditBucket)).Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if limit > 0 && len(events) >= limit {
+				break
+			}
+			var event models.AuditEvent
+			if err := json.Unmarshal(v, &event); err != nil {
+				return err
+			}
+			events = append(events, &event)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+
+	// Collected newest first; return them in the order they happened
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	return events, nil
+}
@@ -0,0 +1,129 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func TestAuditLog(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	entry, _ := store.CreateEntry(project.ID, 30, "Initial", "", false, time.Now())
+	duration := int64(45)
+	store.UpdateEntry(entry.ID, &duration, nil, nil, nil, nil)
+	store.SetEntryLocked(entry.ID, true)
+	store.SetEntryLocked(entry.ID, true) // No change, not audited
+	store.DeleteEntry("missing")         // Nothing deleted, not audited
+
+	events, err := store.AuditLog(0)
+	if err != nil {
+		t.Fatalf("AuditLog() error = %v", err)
+	}
+
+	expected := []struct {
+		operation string
+		target    string
+		targetID  string
+	}{
+		{AuditCreate, AuditTargetProject, project.ID},
+		{AuditCreate, AuditTargetEntry, entry.ID},
+		{AuditUpdate, AuditTargetEntry, entry.ID},
+		{AuditUpdate, AuditTargetEntry, entry.ID},
+	}
+	if len(events) != len(expected) {
+		t.Fatalf("Expected %d events, got %d: %+v", len(expected), len(events), events)
+	}
+	for i, want := range expected {
+		got := events[i]
+		if got.Operation != want.operation || got.Target != want.target || got.TargetID != want.targetID {
+			t.Errorf("Event %d = %s %s %s, want %s %s %s", i, got.Operation, got.Target, got.TargetID, want.operation, want.target, want.targetID)
+		}
+		if i > 0 && got.Seq <= events[i-1].Seq {
+			t.Errorf("Expected increasing sequence, got %d after %d", got.Seq, events[i-1].Seq)
+		}
+	}
+
+	// The update records a brief diff of the changed field only
+	update := events[2]
+	if len(update.Changes) != 1 || update.Changes[0].Field != "duration" || update.Changes[0].OldValue != "30" || update.Changes[0].NewValue != "45" {
+		t.Errorf("Expected duration 30 -> 45 diff, got %+v", update.Changes)
+	}
+
+	// Limit keeps the most recent events, still oldest first
+	recent, _ := store.AuditLog(2)
+	if len(recent) != 2 || recent[0].Seq != events[2].Seq || recent[1].Seq != events[3].Seq {
+		t.Errorf("Expected the last 2 events in order, got %+v", recent)
+	}
+
+	// Deleting the project records the cascade
+	store.SetEntryLocked(entry.ID, false)
+	store.DeleteProject(project.ID)
+	recent, _ = store.AuditLog(1)
+	if len(recent) != 1 || recent[0].Operation != AuditDelete || recent[0].TargetID != project.ID || recent[0].Detail != "deleted with 1 entries" {
+		t.Errorf("Expected project delete event, got %+v", recent)
+	}
+}
+
+func TestAuditLogTruncatesAndBounds(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	entry, _ := store.CreateEntry(project.ID, 30, strings.Repeat("x", 500), "", false, time.Now())
+
+	events, _ := store.AuditLog(1)
+	for _, change := range events[0].Changes {
+		if change.Field == "message" && len([]rune(change.NewValue)) != maxAuditValue {
+			t.Errorf("Expected message truncated to %d characters, got %d", maxAuditValue, len([]rune(change.NewValue)))
+		}
+	}
+
+	for i := 0; i < MaxAuditEvents; i++ {
+		store.SetEntryCategory(entry.ID, strings.Repeat("c", i%2+1))
+	}
+
+	events, err := store.AuditLog(0)
+	if err != nil {
+		t.Fatalf("AuditLog() error = %v", err)
+	}
+	if len(events) != MaxAuditEvents {
+		t.Fatalf("Expected the log to be bounded at %d events, got %d", MaxAuditEvents, len(events))
+	}
+	if events[0].Seq != 3 || events[len(events)-1].Seq != MaxAuditEvents+2 {
+		t.Errorf("Expected the oldest events purged, got seq %d to %d", events[0].Seq, events[len(events)-1].Seq)
+	}
+}
+
+func TestCreateEntryWithOptions(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	entry, err := store.CreateEntryWithOptions(project.ID, 30, "Work", "", false, time.Now(), EntryOptions{
+		Mode:     models.EntryModeGit,
+		Author:   " Jane Doe ",
+		Tags:     []string{"Backend", "backend"},
+		Estimate: 45,
+	})
+	if err != nil {
+		t.Fatalf("CreateEntryWithOptions() error = %v", err)
+	}
+	if entry.Mode != models.EntryModeGit || entry.Author != "Jane Doe" || strings.Join(entry.Tags, ",") != "backend" || entry.EstimateMinutes != 45 {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+
+	// The options are part of the create, not follow-up updates
+	events, _ := store.AuditLog(0)
+	if len(events) != 2 || events[1].Operation != AuditCreate || events[1].TargetID != entry.ID {
+		t.Errorf("Expected a single create event for the entry, got %+v", events)
+	}
+
+	if _, err := store.CreateEntryWithOptions(project.ID, 30, "Work", "", false, time.Now(), EntryOptions{Estimate: -1}); err == nil {
+		t.Error("Expected error for a negative estimate")
+	}
+}
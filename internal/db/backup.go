@@ -0,0 +1,154 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/google/uuid"
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// ImportReport summarizes an ImportJSON
+type ImportReport struct {
+	ProjectsImported int              `json:"projects_imported"`
+	EntriesImported  int              `json:"entries_imported"`
+	ProjectsSkipped  []string         `json:"projects_skipped"` // IDs already in the database (PreserveIDs only)
+	EntriesSkipped   []string         `json:"entries_skipped"`  // IDs already in the database (PreserveIDs only)
+	Rejected         []RejectedRecord `json:"rejected"`
+}
+
+// RejectedRecord is a backup record ImportJSON refused, with the reason
+type RejectedRecord struct {
+	Target string `json:"target"` // AuditTargetProject or AuditTargetEntry
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
+
+// ImportOptions controls how ImportJSON restores records
+type ImportOptions struct {
+	// PreserveIDs keeps the backup's IDs; records whose ID already exists are skipped.
+	// Otherwise every project and entry gets a new ID, so a backup can be imported
+	// alongside the data it came from.
+	PreserveIDs bool
+}
+
+// ExportJSON writes a backup of every project and entry with IDs and timestamps intact
+// The backup uses the SyncSnapshot envelope, so sync snapshots can be restored with ImportJSON too
+func (s *Store) ExportJSON(w io.Writer) error {
+	return s.ExportSnapshot(w)
+}
+
+// ImportJSON restores a backup written by ExportJSON in one transaction. Unlike MergeSnapshot it
+// never overwrites existing records. Entries whose project is neither in the backup nor in the
+// database, and entries with a corrupted commit hash, are rejected and reported; the rest are
+// imported. Stored records are left alone, so their hashes are not checked again.
+func (s *Store) ImportJSON(r io.Reader, opts ImportOptions) (*ImportReport, error) {
+	var backup SyncSnapshot
+	if err := json.NewDecoder(r).Decode(&backup); err != nil {
+		return nil, fmt.Errorf("failed to decode backup: %w", err)
+	}
+	if backup.Version != SyncSnapshotVersion {
+		return nil, fmt.Errorf("unsupported backup version %d (expected %d)", backup.Version, SyncSnapshotVersion)
+	}
+
+	report := &ImportReport{ProjectsSkipped: []string{}, EntriesSkipped: []string{}, Rejected: []RejectedRecord{}}
+	detail := "json import"
+	if backup.Source != "" {
+		detail = "json import from " + backup.Source
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		pb := tx.Bucket([]byte(projectsBucket))
+
+		// Backup project IDs mapped to the IDs they are stored under
+		projectIDs := make(map[string]string)
+		for _, project := range backup.Projects {
+			if project == nil || project.ID == "" {
+				return fmt.Errorf("backup contains a project without an ID")
+			}
+
+			oldID := project.ID
+			if opts.PreserveIDs {
+				if pb.Get([]byte(project.ID)) != nil {
+					report.ProjectsSkipped = append(report.ProjectsSkipped, project.ID)
+					projectIDs[oldID] = project.ID
+					continue
+				}
+			} else {
+				project.ID = uuid.New().String()
+			}
+
+			if err := putProject(pb, project); err != nil {
+				return err
+			}
+			if err := recordAudit(tx, AuditCreate, AuditTargetProject, project.ID, diffProject(&models.Project{}, project), detail); err != nil {
+				return err
+			}
+			projectIDs[oldID] = project.ID
+			report.ProjectsImported++
+		}
+
+		// Entries get local sequence numbers in the backup's creation order
+		sortByCreation(backup.Entries)
+
+		eb := tx.Bucket([]byte(entriesBucket))
+		for _, entry := range backup.Entries {
+			if entry == nil || entry.ID == "" {
+				return fmt.Errorf("backup contains an entry without an ID")
+			}
+
+			if opts.PreserveIDs && eb.Get([]byte(entry.ID)) != nil {
+				report.EntriesSkipped = append(report.EntriesSkipped, entry.ID)
+				continue
+			}
+
+			projectID, ok := projectIDs[entry.ProjectID]
+			if !ok && pb.Get([]byte(entry.ProjectID)) != nil {
+				projectID, ok = entry.ProjectID, true
+			}
+			if !ok {
+				report.Rejected = append(report.Rejected, RejectedRecord{
+					Target: AuditTargetEntry,
+					ID:     entry.ID,
+					Reason: fmt.Sprintf("project %s not found", entry.ProjectID),
+				})
+				continue
+			}
+			if err := checkCommitHash(entry.CommitHash); err != nil {
+				report.Rejected = append(report.Rejected, RejectedRecord{Target: AuditTargetEntry, ID: entry.ID, Reason: err.Error()})
+				continue
+			}
+
+			entry.ProjectID = projectID
+			if !opts.PreserveIDs {
+				entry.ID = uuid.New().String()
+			}
+
+			// Sequence numbers are per database; give the entry a local one
+			seq, err := eb.NextSequence()
+			if err != nil {
+				return err
+			}
+			entry.Seq = seq
+			if err := putEntry(eb, entry); err != nil {
+				return err
+			}
+			if err := indexEntry(tx, entry.ProjectID, entry.ID); err != nil {
+				return err
+			}
+			if err := recordAudit(tx, AuditCreate, AuditTargetEntry, entry.ID, diffEntry(&models.Entry{}, entry), detail); err != nil {
+				return err
+			}
+			report.EntriesImported++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to import backup: %w", err)
+	}
+
+	return report, nil
+}
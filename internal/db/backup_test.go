@@ -0,0 +1,184 @@
+package db
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// backupOf exports the store as a JSON backup
+func backupOf(t *testing.T, store *Store) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := store.ExportJSON(&buf); err != nil {
+		t.Fatalf("ExportJSON() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestImportJSONRoundTrip(t *testing.T) {
+	source, _ := setupTestDB(t)
+	defer source.Close()
+
+	base := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	alpha, _ := source.CreateProject("Alpha", "/alpha")
+	beta, _ := source.CreateProject("Beta", "/beta")
+	source.SetProjectRate(alpha.ID, 90, "EUR")
+	first, _ := source.CreateEntry(alpha.ID, 60, "Alpha work", "abc123", true, base)
+	source.SetEntryTags(first.ID, []string{"dev"})
+	source.CreateEntry(beta.ID, 30, "Beta work", "", false, base.Add(time.Hour))
+	// Logged last but backdated: it holds alpha's git baseline
+	source.CreateEntry(alpha.ID, 15, "Backdated", "def456", false, base.Add(-24*time.Hour))
+	baseline, _ := source.GetLastCommitHash(alpha.ID)
+
+	target, _ := setupTestDB(t)
+	defer target.Close()
+
+	report, err := target.ImportJSON(bytes.NewReader(backupOf(t, source)), ImportOptions{PreserveIDs: true})
+	if err != nil {
+		t.Fatalf("ImportJSON() error = %v", err)
+	}
+	if report.ProjectsImported != 2 || report.EntriesImported != 3 || len(report.Rejected) != 0 {
+		t.Fatalf("Expected 2 projects and 3 entries imported, got %+v", report)
+	}
+
+	// Both stores now export the same projects and entries
+	wantProjects, _ := source.ListProjects(false)
+	for _, want := range wantProjects {
+		got, err := target.GetProject(want.ID)
+		if err != nil {
+			t.Fatalf("Expected project %s to be imported: %v", want.Name, err)
+		}
+		if same, _ := sameRecord(want, got); !same {
+			t.Errorf("Project differs after round trip:\ngot  %+v\nwant %+v", got, want)
+		}
+	}
+	wantEntries, _ := source.ListEntries("")
+	gotEntries, _ := target.ListEntries("")
+	if len(gotEntries) != len(wantEntries) {
+		t.Fatalf("Expected %d entries, got %d", len(wantEntries), len(gotEntries))
+	}
+	for _, want := range wantEntries {
+		got, err := target.GetEntry(want.ID)
+		if err != nil {
+			t.Fatalf("Expected entry %s to be imported: %v", want.ID, err)
+		}
+		if same, _ := sameRecord(want, got); !same {
+			t.Errorf("Entry differs after round trip:\ngot  %+v\nwant %+v", got, want)
+		}
+	}
+
+	// Project-scoped reads find the imported entries through the index
+	if entries, _ := target.ListEntries(alpha.ID); len(entries) != 2 {
+		t.Errorf("Expected the imported entries to be indexed under their project, got %v", entries)
+	}
+
+	// The git baseline stays on the same entry
+	if got, _ := target.GetLastCommitHash(alpha.ID); got != baseline {
+		t.Errorf("Expected the imported baseline to stay %s, got %s", baseline, got)
+	}
+
+	// Importing again skips everything instead of duplicating
+	report, _ = target.ImportJSON(bytes.NewReader(backupOf(t, source)), ImportOptions{PreserveIDs: true})
+	if report.ProjectsImported+report.EntriesImported != 0 || len(report.ProjectsSkipped) != 2 || len(report.EntriesSkipped) != 3 {
+		t.Errorf("Expected a repeated import to skip every record, got %+v", report)
+	}
+}
+
+func TestImportJSONNewIDs(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Alpha", "/alpha")
+	entry, _ := store.CreateEntry(project.ID, 60, "Work", "", false, time.Now())
+
+	report, err := store.ImportJSON(bytes.NewReader(backupOf(t, store)), ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportJSON() error = %v", err)
+	}
+	if report.ProjectsImported != 1 || report.EntriesImported != 1 {
+		t.Fatalf("Expected a copy of the project and entry, got %+v", report)
+	}
+
+	projects, _ := store.ListProjects(false)
+	if len(projects) != 2 {
+		t.Fatalf("Expected 2 projects, got %d", len(projects))
+	}
+	for _, copied := range projects {
+		if copied.ID == project.ID {
+			continue
+		}
+		entries, _ := store.ListEntries(copied.ID)
+		if len(entries) != 1 || entries[0].ID == entry.ID || entries[0].Message != "Work" {
+			t.Errorf("Expected the copied entry under the copied project with a new ID, got %v", entries)
+		}
+	}
+}
+
+func TestImportJSONKeepsCommitBaseline(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	// Backups used to list entries by CreatedAt, which puts a backdated baseline first
+	backup := `{
+		"version": 1,
+		"projects": [{"id": "p1", "name": "Imported"}],
+		"entries": [
+			{"id": "e2", "project_id": "p1", "duration": 15, "commit_hash": "def456", "seq": 2, "created_at": "2026-09-30T09:00:00Z"},
+			{"id": "e1", "project_id": "p1", "duration": 60, "commit_hash": "abc123", "seq": 1, "created_at": "2026-10-01T09:00:00Z"}
+		]
+	}`
+
+	for _, opts := range []ImportOptions{{PreserveIDs: true}, {}} {
+		if _, err := store.ImportJSON(strings.NewReader(backup), opts); err != nil {
+			t.Fatalf("ImportJSON(%+v) error = %v", opts, err)
+		}
+	}
+
+	projects, _ := store.ListProjects(false)
+	if len(projects) != 2 {
+		t.Fatalf("Expected the project and its copy, got %d projects", len(projects))
+	}
+	for _, project := range projects {
+		if got, _ := store.GetLastCommitHash(project.ID); got != "def456" {
+			t.Errorf("Expected baseline def456 for project %s, got %s", project.ID, got)
+		}
+	}
+}
+
+func TestImportJSONRejectsInvalidEntries(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	existing, _ := store.CreateProject("Existing", "/existing")
+	corrupted := strings.Repeat("abcdef0123", 2) + strings.Repeat("abcdef0123", 2)
+	backup := `{
+		"version": 1,
+		"projects": [{"id": "p1", "name": "Imported"}],
+		"entries": [
+			{"id": "e1", "project_id": "p1", "duration": 30, "message": "Fine"},
+			{"id": "e2", "project_id": "` + existing.ID + `", "duration": 15, "message": "Existing project"},
+			{"id": "e3", "project_id": "gone", "duration": 45, "message": "Orphan"},
+			{"id": "e4", "project_id": "p1", "duration": 10, "message": "Corrupted", "commit_hash": "` + corrupted + `"}
+		]
+	}`
+
+	report, err := store.ImportJSON(strings.NewReader(backup), ImportOptions{PreserveIDs: true})
+	if err != nil {
+		t.Fatalf("ImportJSON() error = %v", err)
+	}
+	if report.EntriesImported != 2 || len(report.Rejected) != 2 {
+		t.Fatalf("Expected 2 entries imported and 2 rejected, got %+v", report)
+	}
+	if report.Rejected[0].ID != "e3" || report.Rejected[1].ID != "e4" {
+		t.Errorf("Expected the orphan and the corrupted entry rejected, got %+v", report.Rejected)
+	}
+	if _, err := store.GetEntry("e3"); err == nil {
+		t.Error("Expected the orphan entry not to be stored")
+	}
+
+	if _, err := store.ImportJSON(strings.NewReader(`{"version": 99}`), ImportOptions{}); err == nil {
+		t.Error("Expected error for an unsupported version")
+	}
+}
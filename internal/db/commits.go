@@ -0,0 +1,186 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/techthos/clockwork/internal/git"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// CommitChecker reports whether hash exists in the repository at repoPath (e.g. git.ValidateCommitHash)
+type CommitChecker func(repoPath, hash string) bool
+
+// InvalidCommit is an entry whose stored commit hash no longer exists in its project's repo
+type InvalidCommit struct {
+	EntryID    string    `json:"entry_id"`
+	CommitHash string    `json:"commit_hash"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// ProjectCommitReport lists the entries of one project with stale commit hashes, oldest first
+type ProjectCommitReport struct {
+	ProjectID   string          `json:"project_id"`
+	ProjectName string          `json:"project_name"`
+	GitRepoPath string          `json:"git_repo_path"`
+	Checked     int             `json:"checked"` // Entries with a commit hash
+	Invalid     []InvalidCommit `json:"invalid"`
+}
+
+// CommitReport is the result of ValidateCommits
+type CommitReport struct {
+	Checked      int                   `json:"checked"`
+	InvalidCount int                   `json:"invalid_count"`
+	Projects     []ProjectCommitReport `json:"projects"` // Only projects with invalid hashes, by name
+}
+
+// ValidateCommits checks every stored commit hash against its project's repo without modifying anything
+// Entries without a hash and orphan entries are skipped; repair stale hashes with fix-commits
+func (s *Store) ValidateCommits(exists CommitChecker) (*CommitReport, error) {
+	projects, err := s.ListProjects(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		return projects[i].Name < projects[j].Name
+	})
+
+	report := &CommitReport{Projects: []ProjectCommitReport{}}
+	for _, project := range projects {
+		entries, err := s.ListEntriesFiltered(project.ID, nil, nil, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list entries for %s: %w", project.Name, err)
+		}
+
+		projectReport := ProjectCommitReport{
+			ProjectID:   project.ID,
+			ProjectName: project.Name,
+			GitRepoPath: project.GitRepoPath,
+		}
+
+		// Several entries often share a hash; check each one once
+		known := make(map[string]bool)
+		for _, entry := range entries {
+			if entry.CommitHash == "" {
+				continue
+			}
+			projectReport.Checked++
+
+			valid, ok := known[entry.CommitHash]
+			if !ok {
+				valid = exists(project.GitRepoPath, entry.CommitHash)
+				known[entry.CommitHash] = valid
+			}
+			if !valid {
+				projectReport.Invalid = append(projectReport.Invalid, InvalidCommit{
+					EntryID:    entry.ID,
+					CommitHash: entry.CommitHash,
+					CreatedAt:  entry.CreatedAt,
+				})
+			}
+		}
+
+		report.Checked += projectReport.Checked
+		if len(projectReport.Invalid) == 0 {
+			continue
+		}
+
+		sort.Slice(projectReport.Invalid, func(i, j int) bool {
+			return projectReport.Invalid[i].CreatedAt.Before(projectReport.Invalid[j].CreatedAt)
+		})
+		report.InvalidCount += len(projectReport.Invalid)
+		report.Projects = append(report.Projects, projectReport)
+	}
+
+	return report, nil
+}
+
+// HashExpander resolves an abbreviated commit hash to the full hash in the repository at
+// repoPath (e.g. git.ExpandCommitHash), failing when it is unknown or ambiguous
+type HashExpander func(repoPath, hash string) (string, error)
+
+// errHashChanged aborts an expansion when the entry's hash was edited in the meantime
+var errHashChanged = errors.New("commit hash changed")
+
+// UnresolvedHash is an abbreviated commit hash ExpandShortHashes could not expand
+type UnresolvedHash struct {
+	EntryID     string `json:"entry_id"`
+	ProjectID   string `json:"project_id"`
+	ProjectName string `json:"project_name"`
+	CommitHash  string `json:"commit_hash"`
+	Reason      string `json:"reason"`
+}
+
+// HashExpansionReport is the result of ExpandShortHashes
+type HashExpansionReport struct {
+	Checked    int              `json:"checked"`  // Entries with a commit hash
+	Expanded   int              `json:"expanded"` // Abbreviated hashes replaced by the full hash
+	Unresolved []UnresolvedHash `json:"unresolved"`
+}
+
+// ExpandShortHashes replaces abbreviated commit hashes with full ones resolved in each project's repo
+// Hashes that cannot be resolved (repo moved, commit gone, ambiguous) are left as they are
+// and listed in the report, as are those of orphan entries. An entry whose hash changed
+// since it was read is skipped rather than overwritten.
+func (s *Store) ExpandShortHashes(expand HashExpander) (*HashExpansionReport, error) {
+	projects, err := s.ListProjects(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	byID := make(map[string]*models.Project, len(projects))
+	for _, project := range projects {
+		byID[project.ID] = project
+	}
+
+	entries, err := s.ListEntriesFiltered("", nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entries: %w", err)
+	}
+
+	report := &HashExpansionReport{Unresolved: []UnresolvedHash{}}
+	for _, entry := range entries {
+		if entry.CommitHash == "" {
+			continue
+		}
+		report.Checked++
+		if len(entry.CommitHash) == git.FullHashLength {
+			continue
+		}
+
+		unresolved := UnresolvedHash{EntryID: entry.ID, ProjectID: entry.ProjectID, CommitHash: entry.CommitHash}
+		project, ok := byID[entry.ProjectID]
+		if !ok {
+			unresolved.Reason = "project not found"
+			report.Unresolved = append(report.Unresolved, unresolved)
+			continue
+		}
+		unresolved.ProjectName = project.Name
+
+		full, err := expand(project.GitRepoPath, entry.CommitHash)
+		if err != nil {
+			unresolved.Reason = err.Error()
+			report.Unresolved = append(report.Unresolved, unresolved)
+			continue
+		}
+
+		short := entry.CommitHash
+		_, err = s.modifyEntry(entry.ID, func(stored *models.Entry) error {
+			if stored.CommitHash != short {
+				return errHashChanged
+			}
+			stored.CommitHash = full
+			return nil
+		})
+		if errors.Is(err, errHashChanged) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		report.Expanded++
+	}
+
+	return report, nil
+}
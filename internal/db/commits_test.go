@@ -0,0 +1,131 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/git"
+	"github.com/techthos/clockwork/internal/testutil"
+)
+
+// initCommitRepo creates a git repo with two commits and returns its path and commit hashes, oldest first
+func initCommitRepo(t *testing.T) (string, []string) {
+	t.Helper()
+	repo := testutil.NewGitRepo(t)
+	repo.Run("commit", "-q", "--allow-empty", "-m", "First")
+	first := repo.Run("rev-parse", "HEAD")
+	repo.Run("commit", "-q", "--allow-empty", "-m", "Second")
+	return repo.Dir, []string{first, repo.Run("rev-parse", "HEAD")}
+}
+
+func TestValidateCommits(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	repo, hashes := initCommitRepo(t)
+	stale := "0123456789abcdef0123456789abcdef01234567"
+	base := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+
+	valid, _ := store.CreateProject("Valid", repo)
+	store.CreateEntry(valid.ID, 30, "First", hashes[0], false, base)
+	store.CreateEntry(valid.ID, 30, "Second", hashes[1], false, base.Add(time.Hour))
+	store.CreateEntry(valid.ID, 30, "Manual", "", false, base.Add(2*time.Hour))
+
+	reset, _ := store.CreateProject("Reset", repo)
+	newer, _ := store.CreateEntry(reset.ID, 30, "Rewritten", stale, false, base.Add(time.Hour))
+	older, _ := store.CreateEntry(reset.ID, 30, "Rewritten earlier", stale, false, base)
+	store.CreateEntry(reset.ID, 30, "Still there", hashes[1], false, base.Add(2*time.Hour))
+
+	gone, _ := store.CreateProject("Gone", t.TempDir())
+	missing, _ := store.CreateEntry(gone.ID, 30, "Repo removed", hashes[0], false, base)
+
+	report, err := store.ValidateCommits(git.ValidateCommitHash)
+	if err != nil {
+		t.Fatalf("ValidateCommits() error = %v", err)
+	}
+
+	if report.Checked != 6 || report.InvalidCount != 3 {
+		t.Errorf("Expected 6 checked and 3 invalid, got %d and %d", report.Checked, report.InvalidCount)
+	}
+	if len(report.Projects) != 2 || report.Projects[0].ProjectID != gone.ID || report.Projects[1].ProjectID != reset.ID {
+		t.Fatalf("Expected invalid hashes in Gone and Reset, got %+v", report.Projects)
+	}
+
+	if invalid := report.Projects[0].Invalid; len(invalid) != 1 || invalid[0].EntryID != missing.ID {
+		t.Errorf("Expected Gone's entry to be invalid, got %+v", invalid)
+	}
+
+	resetReport := report.Projects[1]
+	if resetReport.Checked != 3 || len(resetReport.Invalid) != 2 {
+		t.Fatalf("Expected 2 of 3 invalid in Reset, got %+v", resetReport)
+	}
+	if resetReport.Invalid[0].EntryID != older.ID || resetReport.Invalid[1].EntryID != newer.ID || resetReport.Invalid[0].CommitHash != stale {
+		t.Errorf("Expected Reset's invalid entries oldest first, got %+v", resetReport.Invalid)
+	}
+
+	// Read-only: stored hashes are left alone
+	if entry, _ := store.GetEntry(older.ID); entry.CommitHash != stale {
+		t.Errorf("Expected hash to be unchanged, got %s", entry.CommitHash)
+	}
+}
+
+func TestValidateCommitsChecksEachHashOnce(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	for i := 0; i < 3; i++ {
+		store.CreateEntry(project.ID, 30, "Same commit", "abc123", false, time.Now())
+	}
+
+	calls := 0
+	report, err := store.ValidateCommits(func(repoPath, hash string) bool {
+		calls++
+		return true
+	})
+	if err != nil {
+		t.Fatalf("ValidateCommits() error = %v", err)
+	}
+	if calls != 1 || report.Checked != 3 || len(report.Projects) != 0 {
+		t.Errorf("Expected one check for 3 entries and no invalid projects, got %d calls: %+v", calls, report)
+	}
+}
+
+func TestExpandShortHashes(t *testing.T) {
+	repo, hashes := initCommitRepo(t)
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Repo", repo)
+	short, _ := store.CreateEntry(project.ID, 60, "Short", hashes[0][:7], false, time.Now())
+	full, _ := store.CreateEntry(project.ID, 60, "Full", hashes[1], false, time.Now())
+	unknown, _ := store.CreateEntry(project.ID, 60, "Unknown", "0000000", false, time.Now())
+	store.CreateEntry(project.ID, 60, "Manual", "", false, time.Now())
+
+	report, err := store.ExpandShortHashes(git.ExpandCommitHash)
+	if err != nil {
+		t.Fatalf("ExpandShortHashes failed: %v", err)
+	}
+	if report.Checked != 3 || report.Expanded != 1 {
+		t.Errorf("Expected 3 checked and 1 expanded, got %+v", report)
+	}
+	if len(report.Unresolved) != 1 || report.Unresolved[0].EntryID != unknown.ID || report.Unresolved[0].Reason == "" {
+		t.Errorf("Expected the unknown hash to be reported unresolved, got %+v", report.Unresolved)
+	}
+
+	if got, _ := store.GetEntry(short.ID); got.CommitHash != hashes[0] {
+		t.Errorf("Expected short hash expanded to %s, got %s", hashes[0], got.CommitHash)
+	}
+	if got, _ := store.GetEntry(full.ID); got.CommitHash != hashes[1] {
+		t.Errorf("Expected full hash untouched, got %s", got.CommitHash)
+	}
+	if got, _ := store.GetEntry(unknown.ID); got.CommitHash != "0000000" {
+		t.Errorf("Expected unresolved hash left as is, got %s", got.CommitHash)
+	}
+
+	// Running it again finds nothing left to expand
+	report, _ = store.ExpandShortHashes(git.ExpandCommitHash)
+	if report.Expanded != 0 || len(report.Unresolved) != 1 {
+		t.Errorf("Expected a second run to expand nothing, got %+v", report)
+	}
+}
@@ -0,0 +1,478 @@
+package db
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Export formats supported by StreamExport
+const (
+	ExportCSV      = "csv"
+	ExportJSON     = "json"
+	ExportHarvest  = "harvest"  // CSV for Harvest's time import
+	ExportClockify = "clockify" // CSV for Clockify's time entry import
+)
+
+// ExportFormats lists every format StreamExport accepts, in display order
+var ExportFormats = []string{ExportCSV, ExportJSON, ExportHarvest, ExportClockify}
+
+// ValidExportFormat reports whether StreamExport accepts format
+func ValidExportFormat(format string) bool {
+	if format == ExportJSON {
+		return true
+	}
+	_, ok := csvLayouts[format]
+	return ok
+}
+
+// exportFormatError describes an unsupported format
+func exportFormatError(format string) error {
+	return fmt.Errorf("unsupported export format %q (use one of: %s)", format, strings.Join(ExportFormats, ", "))
+}
+
+// Entry sort orders for StreamExport and ListEntriesPage
+const (
+	SortByDate     = "date"     // Newest first
+	SortByDuration = "duration" // Longest first, newest first on ties
+	SortByProject  = "project"  // Project name A-Z, newest first within a project
+	SortByInvoiced = "invoiced" // Uninvoiced first, newest first within each group
+)
+
+// SortKeys lists every sort order, in display order
+var SortKeys = []string{SortByDate, SortByDuration, SortByProject, SortByInvoiced}
+
+// ValidSortKey reports whether sortBy is a known sort order; "" means SortByDate
+func ValidSortKey(sortBy string) bool {
+	if sortBy == "" {
+		return true
+	}
+	for _, key := range SortKeys {
+		if key == sortBy {
+			return true
+		}
+	}
+	return false
+}
+
+// EntryFilter selects and orders the entries written by StreamExport
+type EntryFilter struct {
+	ProjectID      string     // Empty = all projects
+	StartDate      *time.Time // Optional range start
+	EndDate        *time.Time // Optional range end
+	InvoicedFilter *bool      // nil = all, true = invoiced only, false = uninvoiced only
+	ModifiedSince  *time.Time // Optional: only entries created or modified after this time
+	Location       string     // Empty = all locations, otherwise case-insensitive match
+	Source         string     // Empty = all machines, otherwise case-insensitive hostname match
+	Tag            string     // Empty = all entries, otherwise only entries carrying this tag
+	SortBy         string     // One of SortKeys, SortByDate when empty
+	PinnedFirst    bool       // Put pinned entries ahead of the rest, each part in SortBy order
+}
+
+// exportKey holds just enough of an entry to sort it before it is loaded, for writing or
+// for one page of ListEntriesPage
+type exportKey struct {
+	key         []byte
+	createdAt   time.Time
+	updatedAt   time.Time
+	duration    int64
+	projectName string // Lowercased, only filled in for SortByProject
+	invoiced    bool
+	pinned      bool
+}
+
+// StreamExport writes the entries matching filter to w in the given format, one at a time.
+// Only keys and sort fields are held in memory; each entry is decoded and written in turn,
+// so the output matches encoding the sorted ListEntriesFiltered result without materializing it.
+func (s *Store) StreamExport(w io.Writer, format string, filter EntryFilter) error {
+	_, _, err := s.streamExport(w, format, filter)
+	return err
+}
+
+// streamExport implements StreamExport and returns the number of entries written and the
+// newest UpdatedAt among them (zero when none matched)
+func (s *Store) streamExport(w io.Writer, format string, filter EntryFilter) (int, time.Time, error) {
+	var count int
+	var latest time.Time
+	if !ValidExportFormat(format) {
+		return 0, latest, exportFormatError(format)
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		eb := tx.Bucket([]byte(entriesBucket))
+
+		// First pass: collect the keys of matching entries with their sort fields
+		keys, err := collectEntryKeys(tx, filter)
+		if err != nil {
+			return err
+		}
+
+		count = len(keys)
+		for _, k := range keys {
+			if k.updatedAt.After(latest) {
+				latest = k.updatedAt
+			}
+		}
+
+		var encoder entryEncoder
+		if format == ExportJSON {
+			encoder = newJSONEncoder(w)
+		} else {
+			projectNames, err := projectNamesTx(tx)
+			if err != nil {
+				return err
+			}
+			encoder, err = newLayoutEncoder(w, csvLayouts[format], projectNames)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Second pass: load and write each entry in sorted order
+		for _, k := range keys {
+			var entry models.Entry
+			if err := json.Unmarshal(eb.Get(k.key), &entry); err != nil {
+				return err
+			}
+			if err := encoder.Encode(&entry); err != nil {
+				return err
+			}
+		}
+
+		return encoder.Close()
+	})
+
+	if err != nil {
+		return 0, time.Time{}, fmt.Errorf("failed to export entries: %w", err)
+	}
+
+	return count, latest, nil
+}
+
+// lastExportPrefix prefixes the settings key holding a project's ExportNew marker
+const lastExportPrefix = "last_export:"
+
+// GetLastExport returns the marker of the project's last ExportNew, or nil if it was never exported
+// The marker is the newest UpdatedAt among the entries written by that export
+func (s *Store) GetLastExport(projectID string) (*time.Time, error) {
+	value, err := s.GetSetting(lastExportPrefix + projectID)
+	if err != nil || value == "" {
+		return nil, err
+	}
+	marker, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid export marker for project %s: %w", projectID, err)
+	}
+	return &marker, nil
+}
+
+// ExportNew streams the project's entries created or modified since its last ExportNew and
+// moves the marker forward once the export succeeds, so an entry is only exported again
+// after it changes. Returns the number of entries written. Other filters are deliberately
+// not supported: entries they skipped would fall behind the marker and never be exported.
+func (s *Store) ExportNew(w io.Writer, format, projectID, sortBy string) (int, error) {
+	if projectID == "" {
+		return 0, fmt.Errorf("exporting new entries requires a project")
+	}
+	if _, err := s.GetProject(projectID); err != nil {
+		return 0, err
+	}
+
+	since, err := s.GetLastExport(projectID)
+	if err != nil {
+		return 0, err
+	}
+
+	count, latest, err := s.streamExport(w, format, EntryFilter{ProjectID: projectID, ModifiedSince: since, SortBy: sortBy})
+	if err != nil {
+		return 0, err
+	}
+
+	// Nothing new: keep the current marker
+	if count == 0 {
+		return 0, nil
+	}
+	return count, s.SetSetting(lastExportPrefix+projectID, latest.Format(time.RFC3339Nano))
+}
+
+// collectEntryKeys returns the sorted keys of the entries matching filter
+func collectEntryKeys(tx *bolt.Tx, filter EntryFilter) ([]exportKey, error) {
+	var projectNames map[string]string
+	if filter.SortBy == SortByProject {
+		var err error
+		if projectNames, err = projectNamesTx(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	var keys []exportKey
+	err := forEachEntry(tx, filter.ProjectID, func(k, v []byte) error {
+		var entry models.Entry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		if !matchesFilter(&entry, filter.ProjectID, filter.StartDate, filter.EndDate, filter.InvoicedFilter) {
+			return nil
+		}
+		if filter.ModifiedSince != nil && !entry.UpdatedAt.After(*filter.ModifiedSince) {
+			return nil
+		}
+		if !MatchesLocation(&entry, filter.Location) || !MatchesSource(&entry, filter.Source) || !MatchesTag(&entry, filter.Tag) {
+			return nil
+		}
+		keys = append(keys, exportKey{
+			key:         append([]byte(nil), k...),
+			createdAt:   entry.CreatedAt,
+			updatedAt:   entry.UpdatedAt,
+			duration:    entry.Duration,
+			projectName: strings.ToLower(projectNames[entry.ProjectID]),
+			invoiced:    entry.Invoiced,
+			pinned:      entry.Pinned,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	sortExportKeys(keys, filter.SortBy)
+	if filter.PinnedFirst {
+		// Stable, so pinned and unpinned entries each keep the SortBy order
+		sort.SliceStable(keys, func(i, j int) bool {
+			return keys[i].pinned && !keys[j].pinned
+		})
+	}
+	return keys, nil
+}
+
+// sortExportKeys orders keys by the sort key, keeping bucket (entry ID) order on full ties
+func sortExportKeys(keys []exportKey, sortBy string) {
+	switch sortBy {
+	case SortByProject:
+		sort.SliceStable(keys, func(i, j int) bool {
+			if keys[i].projectName != keys[j].projectName {
+				return keys[i].projectName < keys[j].projectName
+			}
+			return keys[i].createdAt.After(keys[j].createdAt)
+		})
+	case SortByInvoiced:
+		sort.SliceStable(keys, func(i, j int) bool {
+			if keys[i].invoiced != keys[j].invoiced {
+				return !keys[i].invoiced
+			}
+			return keys[i].createdAt.After(keys[j].createdAt)
+		})
+	case SortByDuration:
+		sort.SliceStable(keys, func(i, j int) bool {
+			if keys[i].duration != keys[j].duration {
+				return keys[i].duration > keys[j].duration
+			}
+			return keys[i].createdAt.After(keys[j].createdAt)
+		})
+	default:
+		sort.SliceStable(keys, func(i, j int) bool {
+			return keys[i].createdAt.After(keys[j].createdAt)
+		})
+	}
+}
+
+// projectNamesTx maps project IDs to names within a transaction
+func projectNamesTx(tx *bolt.Tx) (map[string]string, error) {
+	names := make(map[string]string)
+	err := tx.Bucket([]byte(projectsBucket)).ForEach(func(k, v []byte) error {
+		var project models.Project
+		if err := json.Unmarshal(v, &project); err != nil {
+			return err
+		}
+		names[project.ID] = project.Name
+		return nil
+	})
+	return names, err
+}
+
+// entryEncoder writes entries one at a time; Close finishes the output
+type entryEncoder interface {
+	Encode(entry *models.Entry) error
+	Close() error
+}
+
+// csvLayout is the column layout of a CSV export format
+type csvLayout struct {
+	header []string
+	row    func(entry *models.Entry, project string) []string
+}
+
+// csvLayouts maps each CSV export format to its columns; adding a target means adding a layout here
+//
+//   - csv: id, project, date (RFC 3339), duration_minutes, hours (2 decimals), message, commit_hash,
+//     invoiced, tags (;-separated), location, source
+//   - harvest: Date (YYYY-MM-DD), Client and Project (both the project name; clockwork has no
+//     clients), Task (first tag, or "General"), Notes (message), Hours (2 decimals),
+//     First name and Last name (the entry author split at the first space)
+//   - clockify: Project, Client (project name), Description (message), Task (first tag), User
+//     (author), Tags (comma-separated), Billable (Yes), Start Date and End Date (YYYY-MM-DD),
+//     Start Time and End Time (HH:MM:SS; the entry starts at its creation time), Duration (h)
+//     (HH:MM:SS), Duration (decimal)
+var csvLayouts = map[string]csvLayout{
+	ExportCSV: {
+		header: []string{"id", "project", "date", "duration_minutes", "hours", "message", "commit_hash", "invoiced", "tags", "location", "source"},
+		row: func(entry *models.Entry, project string) []string {
+			return []string{
+				entry.ID,
+				project,
+				entry.CreatedAt.Format(time.RFC3339),
+				strconv.FormatInt(entry.Duration, 10),
+				decimalHours(entry.Duration),
+				entry.Message,
+				entry.CommitHash,
+				strconv.FormatBool(entry.Invoiced),
+				strings.Join(entry.Tags, ";"),
+				entry.Location,
+				entry.Source,
+			}
+		},
+	},
+	ExportHarvest: {
+		header: []string{"Date", "Client", "Project", "Task", "Notes", "Hours", "First name", "Last name"},
+		row: func(entry *models.Entry, project string) []string {
+			task := "General"
+			if len(entry.Tags) > 0 {
+				task = entry.Tags[0]
+			}
+			first, last, _ := strings.Cut(strings.TrimSpace(entry.Author), " ")
+			return []string{
+				entry.CreatedAt.Format("2006-01-02"),
+				project,
+				project,
+				task,
+				entry.Message,
+				decimalHours(entry.Duration),
+				first,
+				strings.TrimSpace(last),
+			}
+		},
+	},
+	ExportClockify: {
+		header: []string{"Project", "Client", "Description", "Task", "User", "Tags", "Billable", "Start Date", "Start Time", "End Date", "End Time", "Duration (h)", "Duration (decimal)"},
+		row: func(entry *models.Entry, project string) []string {
+			task := ""
+			if len(entry.Tags) > 0 {
+				task = entry.Tags[0]
+			}
+			start := entry.CreatedAt
+			end := start.Add(time.Duration(entry.Duration) * time.Minute)
+			return []string{
+				project,
+				project,
+				entry.Message,
+				task,
+				entry.Author,
+				strings.Join(entry.Tags, ", "),
+				"Yes",
+				start.Format("2006-01-02"),
+				start.Format("15:04:05"),
+				end.Format("2006-01-02"),
+				end.Format("15:04:05"),
+				fmt.Sprintf("%02d:%02d:00", entry.Duration/60, entry.Duration%60),
+				decimalHours(entry.Duration),
+			}
+		},
+	},
+}
+
+// decimalHours formats minutes as hours with two decimals
+func decimalHours(minutes int64) string {
+	return fmt.Sprintf("%.2f", float64(minutes)/60.0)
+}
+
+// CSVEncoder writes entries as CSV rows beneath a header row
+type CSVEncoder struct {
+	writer       *csv.Writer
+	layout       csvLayout
+	projectNames map[string]string
+}
+
+// NewCSVEncoder writes the header row and returns an encoder for the entry rows
+// projectNames maps project IDs to display names; unknown IDs fall back to the raw ID
+func NewCSVEncoder(w io.Writer, projectNames map[string]string) (*CSVEncoder, error) {
+	return newLayoutEncoder(w, csvLayouts[ExportCSV], projectNames)
+}
+
+// newLayoutEncoder is NewCSVEncoder for any CSV layout
+func newLayoutEncoder(w io.Writer, layout csvLayout, projectNames map[string]string) (*CSVEncoder, error) {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(layout.header); err != nil {
+		return nil, fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	return &CSVEncoder{writer: writer, layout: layout, projectNames: projectNames}, nil
+}
+
+// Encode writes one entry as a CSV row
+func (e *CSVEncoder) Encode(entry *models.Entry) error {
+	project, ok := e.projectNames[entry.ProjectID]
+	if !ok {
+		project = entry.ProjectID
+	}
+
+	if err := e.writer.Write(e.layout.row(entry, project)); err != nil {
+		return fmt.Errorf("failed to write CSV record: %w", err)
+	}
+	return nil
+}
+
+// Close flushes buffered rows
+func (e *CSVEncoder) Close() error {
+	e.writer.Flush()
+	return e.writer.Error()
+}
+
+// jsonEncoder writes entries as an indented JSON array, byte-for-byte
+// identical to json.MarshalIndent(entries, "", "  ")
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONEncoder(w io.Writer) *jsonEncoder {
+	return &jsonEncoder{w: w}
+}
+
+// Encode writes one entry as the next array element
+func (e *jsonEncoder) Encode(entry *models.Entry) error {
+	data, err := json.MarshalIndent(entry, "  ", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode entry: %w", err)
+	}
+
+	separator := ",\n  "
+	if e.count == 0 {
+		separator = "[\n  "
+	}
+	if _, err := io.WriteString(e.w, separator); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+
+	e.count++
+	return nil
+}
+
+// Close terminates the array
+func (e *jsonEncoder) Close() error {
+	closing := "\n]"
+	if e.count == 0 {
+		closing = "[]"
+	}
+	_, err := io.WriteString(e.w, closing)
+	return err
+}
@@ -0,0 +1,374 @@
+package db
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// exportInMemory builds the expected export by materializing and sorting every entry
+func exportInMemory(t *testing.T, store *Store, format string, filter EntryFilter) []byte {
+	t.Helper()
+
+	entries, err := store.ListEntriesFiltered(filter.ProjectID, filter.StartDate, filter.EndDate, filter.InvoicedFilter)
+	if err != nil {
+		t.Fatalf("Failed to list entries: %v", err)
+	}
+	entries = FilterByLocation(entries, filter.Location)
+	entries = FilterBySource(entries, filter.Source)
+	sort.SliceStable(entries, func(i, j int) bool {
+		if filter.SortBy == SortByDuration && entries[i].Duration != entries[j].Duration {
+			return entries[i].Duration > entries[j].Duration
+		}
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+
+	var buf bytes.Buffer
+	if format == ExportJSON {
+		if entries == nil {
+			entries = []*models.Entry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			t.Fatalf("Failed to encode entries: %v", err)
+		}
+		buf.Write(data)
+		return buf.Bytes()
+	}
+
+	projects, _ := store.ListProjects(false)
+	names := make(map[string]string)
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+	encoder, err := NewCSVEncoder(&buf, names)
+	if err != nil {
+		t.Fatalf("Failed to create CSV encoder: %v", err)
+	}
+	for _, entry := range entries {
+		encoder.Encode(entry)
+	}
+	encoder.Close()
+	return buf.Bytes()
+}
+
+func TestStreamExportMatchesInMemory(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project1, _ := store.CreateProject("Project 1", "/path/1")
+	project2, _ := store.CreateProject("Project 2", "/path/2")
+	base := time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC)
+	for i := 0; i < 50; i++ {
+		project := project1
+		if i%3 == 0 {
+			project = project2
+		}
+		entry, _ := store.CreateEntry(project.ID, int64(15*(i%7+1)), fmt.Sprintf("Entry %d, with \"quotes\"", i), "", i%2 == 0, base.Add(time.Duration(i%20)*24*time.Hour))
+		if i%5 == 0 {
+			store.SetEntryTags(entry.ID, []string{"dev", "review"})
+		}
+		if i%4 == 0 {
+			store.SetEntryLocation(entry.ID, "remote")
+		}
+		if i%6 == 0 {
+			store.SetEntrySource(entry.ID, "laptop")
+		}
+	}
+
+	uninvoiced := false
+	start := base.Add(5 * 24 * time.Hour)
+	filters := map[string]EntryFilter{
+		"all by date":            {},
+		"all by duration":        {SortBy: SortByDuration},
+		"project uninvoiced":     {ProjectID: project1.ID, InvoicedFilter: &uninvoiced},
+		"date range by duration": {StartDate: &start, SortBy: SortByDuration},
+		"no matches":             {ProjectID: "missing"},
+		"location":               {Location: "Remote"},
+		"source":                 {Source: "LAPTOP"},
+	}
+
+	for name, filter := range filters {
+		for _, format := range []string{ExportCSV, ExportJSON} {
+			t.Run(name+"/"+format, func(t *testing.T) {
+				var buf bytes.Buffer
+				if err := store.StreamExport(&buf, format, filter); err != nil {
+					t.Fatalf("StreamExport() error = %v", err)
+				}
+
+				want := exportInMemory(t, store, format, filter)
+				if !bytes.Equal(buf.Bytes(), want) {
+					t.Errorf("StreamExport() output differs from in-memory export\ngot:\n%s\nwant:\n%s", buf.String(), want)
+				}
+			})
+		}
+	}
+}
+
+func TestStreamExportUnknownFormat(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	var buf bytes.Buffer
+	if err := store.StreamExport(&buf, "xml", EntryFilter{}); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output for unknown format, got %q", buf.String())
+	}
+}
+
+// exportedIDs decodes a JSON export into its entry IDs
+func exportedIDs(t *testing.T, data []byte) []string {
+	t.Helper()
+	var entries []*models.Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		t.Fatalf("Failed to decode export: %v", err)
+	}
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.ID
+	}
+	return ids
+}
+
+func TestExportNew(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	other, _ := store.CreateProject("Other", "/other")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	first, _ := store.CreateEntry(project.ID, 60, "First", "", false, base)
+	second, _ := store.CreateEntry(project.ID, 30, "Second", "", false, base.Add(time.Hour))
+	store.CreateEntry(other.ID, 45, "Other project", "", false, base)
+
+	if marker, _ := store.GetLastExport(project.ID); marker != nil {
+		t.Fatalf("Expected no export marker before the first export, got %v", marker)
+	}
+
+	var buf bytes.Buffer
+	count, err := store.ExportNew(&buf, ExportJSON, project.ID, SortByDate)
+	if err != nil {
+		t.Fatalf("ExportNew() error = %v", err)
+	}
+	if ids := exportedIDs(t, buf.Bytes()); count != 2 || len(ids) != 2 || ids[0] != second.ID || ids[1] != first.ID {
+		t.Fatalf("Expected first export of both entries, got %d: %v", count, ids)
+	}
+
+	// Only the entry added (even backdated) between exports is exported again
+	time.Sleep(time.Millisecond)
+	added, _ := store.CreateEntry(project.ID, 15, "Added later", "", false, base.Add(-24*time.Hour))
+
+	buf.Reset()
+	count, err = store.ExportNew(&buf, ExportJSON, project.ID, SortByDate)
+	if err != nil {
+		t.Fatalf("ExportNew() error = %v", err)
+	}
+	if ids := exportedIDs(t, buf.Bytes()); count != 1 || len(ids) != 1 || ids[0] != added.ID {
+		t.Errorf("Expected second export of only the added entry, got %d: %v", count, ids)
+	}
+
+	// A modified entry is exported once more
+	time.Sleep(time.Millisecond)
+	message := "First (reworked)"
+	store.UpdateEntry(first.ID, nil, &message, nil, nil, nil)
+
+	buf.Reset()
+	store.ExportNew(&buf, ExportJSON, project.ID, SortByDate)
+	if ids := exportedIDs(t, buf.Bytes()); len(ids) != 1 || ids[0] != first.ID {
+		t.Errorf("Expected modified entry to be re-exported, got %v", ids)
+	}
+
+	// Nothing new: empty export, marker unchanged
+	marker, _ := store.GetLastExport(project.ID)
+	buf.Reset()
+	count, _ = store.ExportNew(&buf, ExportJSON, project.ID, SortByDate)
+	if count != 0 || buf.String() != "[]" {
+		t.Errorf("Expected empty export, got %d: %q", count, buf.String())
+	}
+	if after, _ := store.GetLastExport(project.ID); !after.Equal(*marker) {
+		t.Errorf("Expected marker to stay at %v, got %v", marker, after)
+	}
+
+	// Markers are per project
+	buf.Reset()
+	count, _ = store.ExportNew(&buf, ExportCSV, other.ID, SortByDate)
+	if count != 1 {
+		t.Errorf("Expected other project's entry on its first export, got %d", count)
+	}
+
+	if _, err := store.ExportNew(&buf, ExportJSON, "", SortByDate); err == nil {
+		t.Error("Expected error without a project")
+	}
+}
+
+func TestExportNewFailureKeepsMarker(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	store.CreateEntry(project.ID, 60, "Entry", "", false, time.Now())
+
+	if _, err := store.ExportNew(&bytes.Buffer{}, "xml", project.ID, SortByDate); err == nil {
+		t.Fatal("Expected unknown format to fail")
+	}
+	if marker, _ := store.GetLastExport(project.ID); marker != nil {
+		t.Errorf("Expected no marker after a failed export, got %v", marker)
+	}
+
+	store.ExportNew(&bytes.Buffer{}, ExportJSON, project.ID, SortByDate)
+	store.DeleteProject(project.ID)
+	if value, _ := store.GetSetting(lastExportPrefix + project.ID); value != "" {
+		t.Errorf("Expected marker removed with the project, got %q", value)
+	}
+}
+
+func TestExportLocationColumn(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	onSite, _ := store.CreateEntry(project.ID, 60, "Workshop", "", false, base)
+	store.SetEntryLocation(onSite.ID, "on-site")
+	store.CreateEntry(project.ID, 30, "No location", "", false, base.Add(time.Hour))
+
+	var buf bytes.Buffer
+	if err := store.StreamExport(&buf, ExportCSV, EntryFilter{Location: "ON-SITE"}); err != nil {
+		t.Fatalf("StreamExport() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header and one row, got %v", records)
+	}
+	column := len(records[0]) - 2
+	if records[0][column] != "location" || records[1][column] != "on-site" || records[1][0] != onSite.ID {
+		t.Errorf("Expected location column with on-site entry, got %v", records)
+	}
+}
+
+func TestExportSourceColumn(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	laptop, _ := store.CreateEntry(project.ID, 60, "On the laptop", "", false, base)
+	store.SetEntrySource(laptop.ID, "laptop")
+	desktop, _ := store.CreateEntry(project.ID, 30, "On the desktop", "", false, base.Add(time.Hour))
+	store.SetEntrySource(desktop.ID, "desktop")
+
+	var buf bytes.Buffer
+	if err := store.StreamExport(&buf, ExportCSV, EntryFilter{Source: "Laptop"}); err != nil {
+		t.Fatalf("StreamExport() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header and one row, got %v", records)
+	}
+	last := len(records[0]) - 1
+	if records[0][last] != "source" || records[1][last] != "laptop" || records[1][0] != laptop.ID {
+		t.Errorf("Expected source column with laptop entry, got %v", records)
+	}
+}
+
+func TestExportCSVEscapingAndFilters(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Acme, Inc.", "/acme")
+	other, _ := store.CreateProject("Other", "/other")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	tricky, _ := store.CreateEntry(project.ID, 90, "Fix login, signup\nand \"reset\" flows", "abc123", true, base)
+	store.CreateEntry(project.ID, 30, "Uninvoiced", "", false, base.Add(time.Hour))
+	store.CreateEntry(other.ID, 45, "Other project", "", true, base)
+
+	invoiced := true
+	var buf bytes.Buffer
+	if err := store.StreamExport(&buf, ExportCSV, EntryFilter{ProjectID: project.ID, InvoicedFilter: &invoiced}); err != nil {
+		t.Fatalf("StreamExport() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header and only the invoiced entry of the project, got %v", records)
+	}
+
+	header := records[0]
+	if len(header) != len(csvLayouts[ExportCSV].header) || header[0] != "id" || header[2] != "date" || header[3] != "duration_minutes" || header[4] != "hours" {
+		t.Errorf("Unexpected header row %v", header)
+	}
+
+	row := records[1]
+	if row[0] != tricky.ID || row[1] != "Acme, Inc." || row[3] != "90" || row[4] != "1.50" {
+		t.Errorf("Unexpected row %v", row)
+	}
+	if row[5] != tricky.Message || row[6] != "abc123" || row[7] != "true" {
+		t.Errorf("Expected the message to round-trip through quoting, got %q", row[5])
+	}
+}
+
+// exportRecords streams a CSV-based export and parses it back
+func exportRecords(t *testing.T, store *Store, format string, filter EntryFilter) [][]string {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := store.StreamExport(&buf, format, filter); err != nil {
+		t.Fatalf("StreamExport(%s) error = %v", format, err)
+	}
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read %s CSV: %v", format, err)
+	}
+	return records
+}
+
+func TestExportHarvestAndClockify(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Acme Website", "/acme")
+	start := time.Date(2026, time.October, 1, 23, 15, 0, 0, time.UTC)
+	entry, _ := store.CreateEntry(project.ID, 95, "Checkout, \"guest\" flow", "", false, start)
+	store.SetEntryTags(entry.ID, []string{"dev", "frontend"})
+	author := "Ada Lovelace King"
+	store.SetEntryAuthor(entry.ID, author)
+
+	harvest := exportRecords(t, store, ExportHarvest, EntryFilter{})
+	wantHeader := []string{"Date", "Client", "Project", "Task", "Notes", "Hours", "First name", "Last name"}
+	wantRow := []string{"2026-10-01", "Acme Website", "Acme Website", "dev", "Checkout, \"guest\" flow", "1.58", "Ada", "Lovelace King"}
+	if len(harvest) != 2 || fmt.Sprint(harvest[0]) != fmt.Sprint(wantHeader) || fmt.Sprint(harvest[1]) != fmt.Sprint(wantRow) {
+		t.Errorf("Unexpected Harvest export:\ngot  %q\nwant %q / %q", harvest, wantHeader, wantRow)
+	}
+
+	clockify := exportRecords(t, store, ExportClockify, EntryFilter{})
+	wantHeader = []string{"Project", "Client", "Description", "Task", "User", "Tags", "Billable", "Start Date", "Start Time", "End Date", "End Time", "Duration (h)", "Duration (decimal)"}
+	wantRow = []string{"Acme Website", "Acme Website", "Checkout, \"guest\" flow", "dev", author, "dev, frontend", "Yes", "2026-10-01", "23:15:00", "2026-10-02", "00:50:00", "01:35:00", "1.58"}
+	if len(clockify) != 2 || fmt.Sprint(clockify[0]) != fmt.Sprint(wantHeader) || fmt.Sprint(clockify[1]) != fmt.Sprint(wantRow) {
+		t.Errorf("Unexpected Clockify export:\ngot  %q\nwant %q / %q", clockify, wantHeader, wantRow)
+	}
+
+	for _, format := range ExportFormats {
+		if !ValidExportFormat(format) {
+			t.Errorf("Expected %q to be a valid export format", format)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+const projectHistoryBucket = "project_history"
+
+// diffProject returns the tracked fields that differ between two project versions
+func diffProject(before, after *models.Project) []models.FieldChange {
+	fields := []struct {
+		name     string
+		old, new string
+	}{
+		{"name", before.Name, after.Name},
+		{"git_repo_path", before.GitRepoPath, after.GitRepoPath},
+		{"duration_method", before.DurationMethod, after.DurationMethod},
+		{"record_head_for_manual", strconv.FormatBool(before.RecordsHeadForManual()), strconv.FormatBool(after.RecordsHeadForManual())},
+		{"own_commits_only", strconv.FormatBool(before.OwnCommitsOnly), strconv.FormatBool(after.OwnCommitsOnly)},
+		{"hourly_rate", strconv.FormatFloat(before.HourlyRate, 'f', -1, 64), strconv.FormatFloat(after.HourlyRate, 'f', -1, 64)},
+		{"currency", before.Currency, after.Currency},
+		{"budget_minutes", strconv.FormatInt(before.BudgetMinutes, 10), strconv.FormatInt(after.BudgetMinutes, 10)},
+		{"archived", strconv.FormatBool(before.Archived), strconv.FormatBool(after.Archived)},
+		{"auto_schedule", before.AutoSchedule, after.AutoSchedule},
+	}
+
+	var changes []models.FieldChange
+	for _, f := range fields {
+		if f.old != f.new {
+			changes = append(changes, models.FieldChange{
+				Field:    f.name,
+				OldValue: f.old,
+				NewValue: f.new,
+			})
+		}
+	}
+	return changes
+}
+
+// recordProjectChange appends a history record within an update transaction
+// No record is written when nothing changed or history tracking is disabled
+func recordProjectChange(tx *bolt.Tx, before, after *models.Project, changedAt time.Time) error {
+	changes := diffProject(before, after)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if string(tx.Bucket([]byte(settingsBucket)).Get([]byte(SettingTrackProjectHistory))) == "false" {
+		return nil
+	}
+
+	change := models.ProjectChange{
+		ID:        uuid.New().String(),
+		ProjectID: after.ID,
+		Changes:   changes,
+		ChangedAt: changedAt,
+	}
+
+	data, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket([]byte(projectHistoryBucket)).Put([]byte(change.ID), data)
+}
+
+// ProjectHistory returns the recorded edits for a project, oldest first
+func (s *Store) ProjectHistory(id string) ([]*models.ProjectChange, error) {
+	var history []*models.ProjectChange
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(projectHistoryBucket))
+		return b.ForEach(func(k, v []byte) error {
+			var change models.ProjectChange
+			if err := json.Unmarshal(v, &change); err != nil {
+				return err
+			}
+			if change.ProjectID == id {
+				history = append(history, &change)
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get project history: %w", err)
+	}
+
+	sort.Slice(history, func(i, j int) bool {
+		return history[i].ChangedAt.Before(history[j].ChangedAt)
+	})
+
+	return history, nil
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"testing"
+)
+
+func TestProjectHistory(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Original", "/path/one")
+
+	if _, err := store.UpdateProject(project.ID, "Renamed", ""); err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+	if _, err := store.UpdateProject(project.ID, "", "/path/two"); err != nil {
+		t.Fatalf("Failed to update project: %v", err)
+	}
+
+	history, err := store.ProjectHistory(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get project history: %v", err)
+	}
+
+	if len(history) != 2 {
+		t.Fatalf("Expected 2 history records, got %d", len(history))
+	}
+
+	first := history[0].Changes
+	if len(first) != 1 || first[0].Field != "name" || first[0].OldValue != "Original" || first[0].NewValue != "Renamed" {
+		t.Errorf("Unexpected first change: %+v", first)
+	}
+
+	second := history[1].Changes
+	if len(second) != 1 || second[0].Field != "git_repo_path" || second[0].OldValue != "/path/one" || second[0].NewValue != "/path/two" {
+		t.Errorf("Unexpected second change: %+v", second)
+	}
+
+	if history[1].ChangedAt.Before(history[0].ChangedAt) {
+		t.Error("Expected history ordered oldest first")
+	}
+}
+
+func TestProjectHistorySkipsNoOpUpdates(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+
+	// Same values and empty arguments change nothing
+	store.UpdateProject(project.ID, "Test", "")
+	store.UpdateProject(project.ID, "", "")
+
+	history, _ := store.ProjectHistory(project.ID)
+	if len(history) != 0 {
+		t.Errorf("Expected no history for no-op updates, got %d", len(history))
+	}
+}
+
+func TestProjectHistoryDisabled(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	store.SetSetting(SettingTrackProjectHistory, "false")
+
+	store.UpdateProject(project.ID, "Renamed", "")
+
+	history, _ := store.ProjectHistory(project.ID)
+	if len(history) != 0 {
+		t.Errorf("Expected no history when tracking is disabled, got %d", len(history))
+	}
+}
+
+func TestDeleteProjectRemovesHistory(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	store.UpdateProject(project.ID, "Renamed", "")
+	store.DeleteProject(project.ID)
+
+	history, _ := store.ProjectHistory(project.ID)
+	if len(history) != 0 {
+		t.Errorf("Expected history to be removed with project, got %d", len(history))
+	}
+}
@@ -0,0 +1,87 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// entryIndexBucket indexes entries by project: keys are projectID + 0x00 + entryID with empty
+// values, so a cursor seek on the project prefix finds its entries without scanning the others
+const entryIndexBucket = "entry_index"
+
+// entryIndexKey returns the index key of an entry in a project
+func entryIndexKey(projectID, entryID string) []byte {
+	key := make([]byte, 0, len(projectID)+1+len(entryID))
+	key = append(key, projectID...)
+	key = append(key, 0)
+	return append(key, entryID...)
+}
+
+// indexEntry adds an entry to its project's index within a write transaction
+func indexEntry(tx *bolt.Tx, projectID, entryID string) error {
+	return tx.Bucket([]byte(entryIndexBucket)).Put(entryIndexKey(projectID, entryID), []byte{})
+}
+
+// unindexEntry removes an entry from a project's index within a write transaction
+func unindexEntry(tx *bolt.Tx, projectID, entryID string) error {
+	return tx.Bucket([]byte(entryIndexBucket)).Delete(entryIndexKey(projectID, entryID))
+}
+
+// reindexEntry moves an entry in the index when its project changed
+func reindexEntry(tx *bolt.Tx, oldProjectID, newProjectID, entryID string) error {
+	if oldProjectID == newProjectID {
+		return nil
+	}
+	if err := unindexEntry(tx, oldProjectID, entryID); err != nil {
+		return err
+	}
+	return indexEntry(tx, newProjectID, entryID)
+}
+
+// rebuildEntryIndex recreates the index from the entries bucket
+// New runs it once for databases created before the index existed
+func rebuildEntryIndex(tx *bolt.Tx) error {
+	if tx.Bucket([]byte(entryIndexBucket)) != nil {
+		if err := tx.DeleteBucket([]byte(entryIndexBucket)); err != nil {
+			return err
+		}
+	}
+	ib, err := tx.CreateBucket([]byte(entryIndexBucket))
+	if err != nil {
+		return err
+	}
+
+	return tx.Bucket([]byte(entriesBucket)).ForEach(func(k, v []byte) error {
+		var entry models.Entry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		return ib.Put(entryIndexKey(entry.ProjectID, entry.ID), []byte{})
+	})
+}
+
+// forEachEntry calls fn with the key and raw value of every entry, or only of the project's
+// entries (looked up through the index) when projectID is set
+func forEachEntry(tx *bolt.Tx, projectID string, fn func(k, v []byte) error) error {
+	eb := tx.Bucket([]byte(entriesBucket))
+	if projectID == "" {
+		return eb.ForEach(fn)
+	}
+
+	prefix := entryIndexKey(projectID, "")
+	c := tx.Bucket([]byte(entryIndexBucket)).Cursor()
+	for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+		id := k[len(prefix):]
+		v := eb.Get(id)
+		if v == nil {
+			continue
+		}
+		if err := fn(id, v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// scanEntries is the full-scan behaviour the index replaces: every entry, filtered in memory
+func scanEntries(t *testing.T, store *Store, projectID string, startDate, endDate *time.Time, invoicedFilter *bool) []string {
+	t.Helper()
+	var ids []string
+	err := store.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(entriesBucket)).ForEach(func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if matchesFilter(&entry, projectID, startDate, endDate, invoicedFilter) {
+				ids = append(ids, entry.ID)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		t.Fatalf("Failed to scan entries: %v", err)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func entryIDs(entries []*models.Entry) []string {
+	ids := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		ids = append(ids, entry.ID)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+func TestEntryIndexMatchesFullScan(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	alpha, _ := store.CreateProject("Alpha", "/alpha")
+	beta, _ := store.CreateProject("Beta", "/beta")
+	doomed, _ := store.CreateProject("Doomed", "/doomed")
+	base := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 6; i++ {
+		store.CreateEntry(alpha.ID, 30, "Alpha", "", i%2 == 0, base.Add(time.Duration(i)*time.Hour))
+		store.CreateEntry(beta.ID, 45, "Beta", "", false, base.Add(time.Duration(i)*time.Hour))
+	}
+	deleted, _ := store.CreateEntry(alpha.ID, 15, "Deleted", "", false, base)
+	store.DeleteEntry(deleted.ID)
+
+	// Timer entries are indexed too
+	store.StartTimer(beta.ID, base)
+	if _, err := store.StopTimer(beta.ID, "Timed", false, base.Add(time.Hour)); err != nil {
+		t.Fatalf("StopTimer() error = %v", err)
+	}
+
+	// Orphans moved to another project follow it in the index
+	store.CreateEntry(doomed.ID, 60, "Orphaned", "", false, base)
+	store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(projectsBucket)).Delete([]byte(doomed.ID))
+	})
+	if _, err := store.ReassignOrphanEntries(beta.ID); err != nil {
+		t.Fatalf("ReassignOrphanEntries() error = %v", err)
+	}
+
+	// Bulk-deleted entries leave the index
+	start, end := base.Add(4*time.Hour), base.Add(6*time.Hour)
+	store.DeleteEntriesFiltered(alpha.ID, &start, &end, nil)
+
+	invoiced := true
+	for _, project := range []*models.Project{alpha, beta, doomed} {
+		cases := []struct {
+			name       string
+			start, end *time.Time
+			invoiced   *bool
+		}{
+			{"all", nil, nil, nil},
+			{"range", &base, &start, nil},
+			{"invoiced", nil, nil, &invoiced},
+		}
+		for _, tc := range cases {
+			entries, err := store.ListEntriesFiltered(project.ID, tc.start, tc.end, tc.invoiced)
+			if err != nil {
+				t.Fatalf("ListEntriesFiltered() error = %v", err)
+			}
+			got, want := entryIDs(entries), scanEntries(t, store, project.ID, tc.start, tc.end, tc.invoiced)
+			if strings.Join(got, ",") != strings.Join(want, ",") {
+				t.Errorf("%s/%s: indexed = %v, full scan = %v", project.Name, tc.name, got, want)
+			}
+		}
+
+		listed, _ := store.ListEntries(project.ID)
+		if got, want := entryIDs(listed), scanEntries(t, store, project.ID, nil, nil, nil); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("%s: ListEntries = %v, full scan = %v", project.Name, got, want)
+		}
+	}
+
+	if entries, _ := store.ListEntries(beta.ID); len(entries) != 8 {
+		t.Errorf("Expected 6 + timer + reassigned = 8 Beta entries, got %d", len(entries))
+	}
+
+	stats, _ := store.GetStatistics(alpha.ID, nil, nil, nil)
+	if stats.EntryCount != 4 {
+		t.Errorf("Expected 4 Alpha entries in statistics, got %d", stats.EntryCount)
+	}
+
+	// Deleting a project drops its index keys
+	store.DeleteProject(beta.ID)
+	store.db.View(func(tx *bolt.Tx) error {
+		prefix := entryIndexKey(beta.ID, "")
+		c := tx.Bucket([]byte(entryIndexBucket)).Cursor()
+		if k, _ := c.Seek(prefix); k != nil && strings.HasPrefix(string(k), string(prefix)) {
+			t.Errorf("Expected no index keys for a deleted project, found %q", k)
+		}
+		return nil
+	})
+}
+
+func TestEntryIndexBuiltOnOpen(t *testing.T) {
+	store, dbPath := setupTestDB(t)
+
+	project, _ := store.CreateProject("Test", "/path")
+	entry, _ := store.CreateEntry(project.ID, 30, "Before the index", "", false, time.Now())
+
+	// Simulate a database written before the index existed
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		return tx.DeleteBucket([]byte(entryIndexBucket))
+	})
+	if err != nil {
+		t.Fatalf("Failed to drop index: %v", err)
+	}
+	store.Close()
+
+	store, err = New(dbPath)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer store.Close()
+
+	entries, err := store.ListEntries(project.ID)
+	if err != nil || len(entries) != 1 || entries[0].ID != entry.ID {
+		t.Errorf("Expected the existing entry to be indexed on open, got %v (%v)", entries, err)
+	}
+}
+
+func BenchmarkListEntriesFilteredByProject(b *testing.B) {
+	store, _ := setupTestDB(b)
+	defer store.Close()
+
+	// One small project among many busy ones
+	target, _ := store.CreateProject("Target", "/target")
+	now := time.Now()
+	for p := 0; p < 20; p++ {
+		project, _ := store.CreateProject(fmt.Sprintf("Busy %d", p), "/busy")
+		for i := 0; i < 250; i++ {
+			store.CreateEntry(project.ID, 30, "Busy work", "", false, now)
+		}
+	}
+	for i := 0; i < 50; i++ {
+		store.CreateEntry(target.ID, 30, "Target work", "", false, now)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := store.ListEntriesFiltered(target.ID, nil, nil, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"fmt"
+	"os"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// IntegrityReport describes the health of the database file
+type IntegrityReport struct {
+	Path         string         `json:"path"`
+	FileSize     int64          `json:"file_size"` // Bytes on disk
+	BucketCounts map[string]int `json:"bucket_counts"`
+	OrphanCount  int            `json:"orphan_entries"` // Entries whose project no longer exists
+	Errors       []string       `json:"errors,omitempty"`
+	OK           bool           `json:"ok"`
+}
+
+// Integrity runs bbolt's consistency check, counts records per bucket, and counts orphan entries
+func (s *Store) Integrity() (*IntegrityReport, error) {
+	report := &IntegrityReport{
+		Path:         s.db.Path(),
+		BucketCounts: make(map[string]int),
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		for checkErr := range tx.Check() {
+			report.Errors = append(report.Errors, checkErr.Error())
+		}
+
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			report.BucketCounts[string(name)] = b.Stats().KeyN
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to check database: %w", err)
+	}
+
+	orphans, err := s.FindOrphanEntries()
+	if err != nil {
+		report.Errors = append(report.Errors, err.Error())
+	}
+	report.OrphanCount = len(orphans)
+
+	info, err := os.Stat(report.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat database file: %w", err)
+	}
+	report.FileSize = info.Size()
+	report.OK = len(report.Errors) == 0
+
+	return report, nil
+}
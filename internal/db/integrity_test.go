@@ -0,0 +1,41 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestIntegrity(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project1, _ := store.CreateProject("Project 1", "/path/1")
+	project2, _ := store.CreateProject("Project 2", "/path/2")
+	store.CreateEntry(project1.ID, 60, "Entry 1", "abc", false, time.Now())
+	store.CreateEntry(project1.ID, 90, "Entry 2", "def", false, time.Now())
+	store.CreateEntry(project2.ID, 30, "Entry 3", "ghi", true, time.Now())
+	store.SetSetting(SettingManualMessageTemplate, "{project}")
+
+	report, err := store.Integrity()
+	if err != nil {
+		t.Fatalf("Failed to run integrity check: %v", err)
+	}
+
+	if !report.OK {
+		t.Errorf("Expected healthy database, got errors: %v", report.Errors)
+	}
+	if report.FileSize <= 0 {
+		t.Errorf("Expected positive file size, got %d", report.FileSize)
+	}
+
+	expected := map[string]int{
+		projectsBucket: 2,
+		entriesBucket:  3,
+		settingsBucket: 1,
+	}
+	for bucket, count := range expected {
+		if report.BucketCounts[bucket] != count {
+			t.Errorf("Expected %d records in %s, got %d", count, bucket, report.BucketCounts[bucket])
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package db
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// invoiceCountersBucket holds the last invoice number issued per project, keyed by project ID
+const invoiceCountersBucket = "invoice_counters"
+
+// Invoice describes an invoice issued with IssueInvoice
+type Invoice struct {
+	Number      string    `json:"number"`   // Formatted, e.g. "ACME-0003"
+	Sequence    int       `json:"sequence"` // Per-project counter behind Number
+	IssuedAt    time.Time `json:"issued_at"`
+	ProjectID   string    `json:"project_id"`
+	ProjectName string    `json:"project_name"`
+	GitRepoPath string    `json:"git_repo_path"`
+	EntryIDs    []string  `json:"entry_ids"`
+	Minutes     int64     `json:"minutes"`
+}
+
+// NextInvoiceNumber assigns the project's next invoice number, starting at 1
+// Each project counts separately; numbers are never reused
+func (s *Store) NextInvoiceNumber(projectID string) (int, error) {
+	var number int
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(projectsBucket)).Get([]byte(projectID)) == nil {
+			return fmt.Errorf("project not found")
+		}
+		var err error
+		number, err = nextInvoiceNumber(tx, projectID)
+		return err
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to assign invoice number: %w", err)
+	}
+
+	return number, nil
+}
+
+// nextInvoiceNumber increments the project's counter within a write transaction
+func nextInvoiceNumber(tx *bolt.Tx, projectID string) (int, error) {
+	b := tx.Bucket([]byte(invoiceCountersBucket))
+
+	var last uint64
+	if data := b.Get([]byte(projectID)); len(data) == 8 {
+		last = binary.BigEndian.Uint64(data)
+	}
+
+	next := make([]byte, 8)
+	binary.BigEndian.PutUint64(next, last+1)
+	if err := b.Put([]byte(projectID), next); err != nil {
+		return 0, err
+	}
+	return int(last + 1), nil
+}
+
+// FormatInvoiceNumber builds the printed invoice number from the project name and sequence,
+// e.g. "Acme Website" and 3 give "ACMEWEBS-0003"
+func FormatInvoiceNumber(projectName string, sequence int) string {
+	var prefix strings.Builder
+	for _, r := range strings.ToUpper(projectName) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			prefix.WriteRune(r)
+		}
+		if prefix.Len() == 8 {
+			break
+		}
+	}
+	if prefix.Len() == 0 {
+		prefix.WriteString("INV")
+	}
+	return fmt.Sprintf("%s-%04d", prefix.String(), sequence)
+}
+
+// IssueInvoice assigns the project's next invoice number and marks its uninvoiced entries in
+// the range as invoiced under that number, in one transaction. Nothing is numbered when no
+// uninvoiced entries match.
+func (s *Store) IssueInvoice(projectID string, startDate, endDate *time.Time, issuedAt time.Time) (*Invoice, error) {
+	invoice := &Invoice{IssuedAt: issuedAt, ProjectID: projectID, EntryIDs: []string{}}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(projectsBucket)).Get([]byte(projectID))
+		if data == nil {
+			return fmt.Errorf("project not found")
+		}
+		var project models.Project
+		if err := json.Unmarshal(data, &project); err != nil {
+			return err
+		}
+		invoice.ProjectName = project.Name
+		invoice.GitRepoPath = project.GitRepoPath
+
+		// Collect first; the bucket must not be modified while iterating
+		uninvoiced := false
+		var entries []models.Entry
+		err := forEachEntry(tx, projectID, func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if matchesFilter(&entry, projectID, startDate, endDate, &uninvoiced) {
+				entries = append(entries, entry)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			return fmt.Errorf("no uninvoiced entries to invoice")
+		}
+
+		invoice.Sequence, err = nextInvoiceNumber(tx, projectID)
+		if err != nil {
+			return err
+		}
+		invoice.Number = FormatInvoiceNumber(project.Name, invoice.Sequence)
+
+		eb := tx.Bucket([]byte(entriesBucket))
+		for _, entry := range entries {
+			before := entry
+			entry.Invoiced = true
+			entry.InvoiceNumber = invoice.Number
+			entry.UpdatedAt = issuedAt
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			if err := eb.Put([]byte(entry.ID), data); err != nil {
+				return err
+			}
+			if err := recordEntryUpdate(tx, &before, &entry); err != nil {
+				return err
+			}
+			invoice.EntryIDs = append(invoice.EntryIDs, entry.ID)
+			invoice.Minutes += entry.Duration
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to issue invoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// MarkInvoiced sets the invoiced flag of every entry matching the filters in one transaction,
+// so a failure leaves no entry changed. Locked entries and entries already in the requested
+// state are skipped. Returns the number of entries changed.
+func (s *Store) MarkInvoiced(projectID string, startDate, endDate *time.Time, invoiced bool) (int, error) {
+	changed := 0
+	now := time.Now()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		// Collect first; the bucket must not be modified while iterating
+		var entries []models.Entry
+		err := forEachEntry(tx, projectID, func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if !entry.Locked && entry.Invoiced != invoiced && matchesFilter(&entry, projectID, startDate, endDate, nil) {
+				entries = append(entries, entry)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		eb := tx.Bucket([]byte(entriesBucket))
+		for _, entry := range entries {
+			before := entry
+			setInvoiced(&entry, invoiced)
+			entry.UpdatedAt = now
+			if err := putEntry(eb, &entry); err != nil {
+				return err
+			}
+			if err := recordEntryUpdate(tx, &before, &entry); err != nil {
+				return err
+			}
+		}
+
+		changed = len(entries)
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark entries: %w", err)
+	}
+
+	return changed, nil
+}
+
+// setInvoiced sets an entry's invoiced flag; an uninvoiced entry keeps no invoice number
+func setInvoiced(entry *models.Entry, invoiced bool) {
+	entry.Invoiced = invoiced
+	if !invoiced {
+		entry.InvoiceNumber = ""
+	}
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNextInvoiceNumber(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	alpha, _ := store.CreateProject("Alpha", "/alpha")
+	beta, _ := store.CreateProject("Beta", "/beta")
+
+	// Each project counts on its own
+	for want := 1; want <= 3; want++ {
+		got, err := store.NextInvoiceNumber(alpha.ID)
+		if err != nil {
+			t.Fatalf("NextInvoiceNumber() error = %v", err)
+		}
+		if got != want {
+			t.Errorf("Expected Alpha invoice %d, got %d", want, got)
+		}
+	}
+	if got, _ := store.NextInvoiceNumber(beta.ID); got != 1 {
+		t.Errorf("Expected Beta to start at 1, got %d", got)
+	}
+	if got, _ := store.NextInvoiceNumber(alpha.ID); got != 4 {
+		t.Errorf("Expected Alpha to continue at 4, got %d", got)
+	}
+
+	if _, err := store.NextInvoiceNumber("missing"); err == nil {
+		t.Error("Expected error for a missing project")
+	}
+}
+
+func TestFormatInvoiceNumber(t *testing.T) {
+	tests := []struct {
+		name     string
+		sequence int
+		want     string
+	}{
+		{"Acme", 3, "ACME-0003"},
+		{"Acme Website Redesign", 12, "ACMEWEBS-0012"},
+		{"Café 2", 1, "CAF2-0001"},
+		{"時間", 7, "INV-0007"},
+	}
+	for _, tt := range tests {
+		if got := FormatInvoiceNumber(tt.name, tt.sequence); got != tt.want {
+			t.Errorf("FormatInvoiceNumber(%q, %d) = %q, want %q", tt.name, tt.sequence, got, tt.want)
+		}
+	}
+}
+
+func TestIssueInvoice(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Client", "/client")
+	other, _ := store.CreateProject("Other", "/other")
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+	first, _ := store.CreateEntry(project.ID, 90, "Work", "", false, now)
+	second, _ := store.CreateEntry(project.ID, 30, "More work", "", false, now)
+	store.CreateEntry(project.ID, 60, "Already invoiced", "", true, now)
+	store.CreateEntry(other.ID, 45, "Other project", "", false, now)
+
+	invoice, err := store.IssueInvoice(project.ID, nil, nil, now)
+	if err != nil {
+		t.Fatalf("IssueInvoice() error = %v", err)
+	}
+	if invoice.Number != "CLIENT-0001" || invoice.Sequence != 1 || invoice.ProjectName != "Client" {
+		t.Errorf("Expected CLIENT-0001 for Client, got %+v", invoice)
+	}
+	if len(invoice.EntryIDs) != 2 || invoice.Minutes != 120 {
+		t.Errorf("Expected 2 entries for 120 minutes, got %d for %d", len(invoice.EntryIDs), invoice.Minutes)
+	}
+
+	for _, id := range []string{first.ID, second.ID} {
+		entry, _ := store.GetEntry(id)
+		if !entry.Invoiced || entry.InvoiceNumber != invoice.Number {
+			t.Errorf("Expected entry %s invoiced under %s, got %+v", id, invoice.Number, entry)
+		}
+	}
+
+	// Nothing left to invoice: fails without consuming a number
+	if _, err := store.IssueInvoice(project.ID, nil, nil, now); err == nil {
+		t.Error("Expected error with no uninvoiced entries")
+	}
+	store.CreateEntry(project.ID, 15, "Follow-up", "", false, now)
+	invoice, _ = store.IssueInvoice(project.ID, nil, nil, now)
+	if invoice.Number != "CLIENT-0002" {
+		t.Errorf("Expected CLIENT-0002 next, got %s", invoice.Number)
+	}
+
+	// The other project's numbering is unaffected
+	invoice, _ = store.IssueInvoice(other.ID, nil, nil, now)
+	if invoice.Number != "OTHER-0001" {
+		t.Errorf("Expected OTHER-0001, got %s", invoice.Number)
+	}
+
+	// Un-invoicing drops the invoice number, whichever way it is done
+	notInvoiced := false
+	store.UpdateEntry(first.ID, nil, nil, nil, &notInvoiced, nil)
+	store.MarkInvoiced(project.ID, nil, nil, false)
+	for _, id := range []string{first.ID, second.ID} {
+		if entry, _ := store.GetEntry(id); entry.Invoiced || entry.InvoiceNumber != "" {
+			t.Errorf("Expected entry %s uninvoiced without a number, got %+v", id, entry)
+		}
+	}
+}
+
+func TestMarkInvoiced(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Acme", "/path")
+	other, _ := store.CreateProject("Other", "/other")
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 10, 0, 0, 0, time.UTC) }
+
+	before, _ := store.CreateEntry(project.ID, 30, "Before", "", false, day(1))
+	inside, _ := store.CreateEntry(project.ID, 60, "Inside", "", false, day(5))
+	alreadyInvoiced, _ := store.CreateEntry(project.ID, 45, "Already invoiced", "", true, day(6))
+	locked, _ := store.CreateEntry(project.ID, 15, "Locked", "", false, day(7))
+	store.SetEntryLocked(locked.ID, true)
+	after, _ := store.CreateEntry(project.ID, 20, "After", "", false, day(20))
+	otherProject, _ := store.CreateEntry(other.ID, 90, "Other project", "", false, day(5))
+
+	start, end := day(3), day(10)
+	changed, err := store.MarkInvoiced(project.ID, &start, &end, true)
+	if err != nil {
+		t.Fatalf("MarkInvoiced() error = %v", err)
+	}
+	if changed != 1 {
+		t.Errorf("Expected 1 entry changed, got %d", changed)
+	}
+
+	want := map[string]bool{
+		before.ID:          false,
+		inside.ID:          true,
+		alreadyInvoiced.ID: true,
+		locked.ID:          false,
+		after.ID:           false,
+		otherProject.ID:    false,
+	}
+	for id, invoiced := range want {
+		entry, _ := store.GetEntry(id)
+		if entry.Invoiced != invoiced {
+			t.Errorf("Entry %q: expected invoiced=%v, got %v", entry.Message, invoiced, entry.Invoiced)
+		}
+	}
+
+	// Unmarking the whole project leaves the locked entry and other projects alone
+	changed, err = store.MarkInvoiced(project.ID, nil, nil, false)
+	if err != nil {
+		t.Fatalf("MarkInvoiced() error = %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("Expected 2 entries unmarked, got %d", changed)
+	}
+}
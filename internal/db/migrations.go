@@ -0,0 +1,225 @@
+package db
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+
+	"github.com/techthos/clockwork/internal/git"
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+const (
+	metaBucket       = "meta"
+	schemaVersionKey = "schema_version"
+)
+
+// migration upgrades a database by one schema version inside New's write transaction
+type migration struct {
+	description string
+	apply       func(tx *bolt.Tx) error
+}
+
+// migrations are applied in order; a database at version n has had migrations[:n] applied
+// Append new migrations, never reorder or remove them: the stored version counts them.
+var migrations = []migration{
+	{"truncate commit hashes damaged by the e8e8 corruption bug", truncateCorruptCommitHashes},
+	{"expand abbreviated commit hashes", expandShortCommitHashes},
+	{"rename the sessions duration method to gap_aware", renameSessionsDurationMethod},
+}
+
+// migrationHashExpander resolves abbreviated hashes for expandShortCommitHashes
+var migrationHashExpander HashExpander = git.ExpandCommitHash
+
+// SchemaVersion is the schema version this build writes, the number of known migrations
+var SchemaVersion = uint64(len(migrations))
+
+// readSchemaVersion returns the stored schema version, 0 for databases from before versioning
+func readSchemaVersion(tx *bolt.Tx) uint64 {
+	b := tx.Bucket([]byte(metaBucket))
+	if b == nil {
+		return 0
+	}
+	data := b.Get([]byte(schemaVersionKey))
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+func writeSchemaVersion(tx *bolt.Tx, version uint64) error {
+	data := make([]byte, 8)
+	binary.BigEndian.PutUint64(data, version)
+	return tx.Bucket([]byte(metaBucket)).Put([]byte(schemaVersionKey), data)
+}
+
+// checkSchemaVersion refuses databases written by a newer build, whose data this one may misread
+func checkSchemaVersion(version uint64) error {
+	if version > SchemaVersion {
+		return fmt.Errorf("database schema version %d is newer than this build supports (%d); upgrade clockwork", version, SchemaVersion)
+	}
+	return nil
+}
+
+// migrate applies the migrations a database is missing and records the new version
+// It runs in the same transaction as bucket creation, so a failed migration leaves
+// the file as it was.
+func migrate(tx *bolt.Tx) error {
+	version := readSchemaVersion(tx)
+	if err := checkSchemaVersion(version); err != nil {
+		return err
+	}
+
+	for ; version < SchemaVersion; version++ {
+		m := migrations[version]
+		if err := m.apply(tx); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", version+1, m.description, err)
+		}
+	}
+	return writeSchemaVersion(tx, version)
+}
+
+// SchemaVersion returns the schema version stored in the database
+func (s *Store) SchemaVersion() (uint64, error) {
+	var version uint64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		version = readSchemaVersion(tx)
+		return nil
+	})
+	return version, err
+}
+
+// truncateCorruptCommitHashes cuts hashes matching the e8e8 corruption patterns (see
+// checkCommitHash) down to their first half, which was stored intact
+// The result is a valid abbreviated hash that expand_commit_hashes can resolve again.
+func truncateCorruptCommitHashes(tx *bolt.Tx) error {
+	eb := tx.Bucket([]byte(entriesBucket))
+
+	var damaged []*models.Entry
+	err := eb.ForEach(func(k, v []byte) error {
+		var entry models.Entry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		if checkCommitHash(entry.CommitHash) != nil {
+			damaged = append(damaged, &entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Written after the scan, as bbolt forbids modifying a bucket while iterating it
+	for _, entry := range damaged {
+		before := *entry
+		entry.CommitHash = entry.CommitHash[:git.FullHashLength/2]
+		if err := putEntry(eb, entry); err != nil {
+			return err
+		}
+		if err := recordAudit(tx, AuditUpdate, AuditTargetEntry, entry.ID, diffEntry(&before, entry), "schema migration"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expandShortCommitHashes replaces abbreviated commit hashes, including those left by
+// truncateCorruptCommitHashes, with the full hash resolved in the project's repo, as
+// ExpandShortHashes does on demand
+// Hashes that cannot be resolved are kept and their entries flagged as needing an
+// adjustment, with the reason in the adjustment note, so list_adjustments surfaces them.
+func expandShortCommitHashes(tx *bolt.Tx) error {
+	projects := make(map[string]*models.Project)
+	err := tx.Bucket([]byte(projectsBucket)).ForEach(func(k, v []byte) error {
+		var project models.Project
+		if err := json.Unmarshal(v, &project); err != nil {
+			return err
+		}
+		projects[project.ID] = &project
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	eb := tx.Bucket([]byte(entriesBucket))
+	var short []*models.Entry
+	err = eb.ForEach(func(k, v []byte) error {
+		var entry models.Entry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		if entry.CommitHash != "" && len(entry.CommitHash) < git.FullHashLength {
+			short = append(short, &entry)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range short {
+		before := *entry
+		reason := "project not found"
+		if project, ok := projects[entry.ProjectID]; ok {
+			full, err := migrationHashExpander(project.GitRepoPath, entry.CommitHash)
+			if err == nil {
+				entry.CommitHash = full
+				reason = ""
+			} else {
+				reason = err.Error()
+			}
+		}
+		if reason != "" {
+			note := fmt.Sprintf("commit hash %s could not be expanded: %s", entry.CommitHash, reason)
+			if entry.NeedsAdjustment && entry.AdjustmentNote != "" {
+				note = entry.AdjustmentNote + "; " + note
+			}
+			entry.NeedsAdjustment = true
+			entry.AdjustmentNote = note
+		}
+
+		if err := putEntry(eb, entry); err != nil {
+			return err
+		}
+		if err := recordAudit(tx, AuditUpdate, AuditTargetEntry, entry.ID, diffEntry(&before, entry), "schema migration"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// renameSessionsDurationMethod moves projects using the former "sessions" duration method
+// to its new name, gap_aware, so their estimates keep resolving a strategy
+func renameSessionsDurationMethod(tx *bolt.Tx) error {
+	pb := tx.Bucket([]byte(projectsBucket))
+
+	var renamed []*models.Project
+	err := pb.ForEach(func(k, v []byte) error {
+		var project models.Project
+		if err := json.Unmarshal(v, &project); err != nil {
+			return err
+		}
+		if project.DurationMethod == "sessions" {
+			renamed = append(renamed, &project)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, project := range renamed {
+		before := *project
+		project.DurationMethod = "gap_aware"
+		if err := putProject(pb, project); err != nil {
+			return err
+		}
+		if err := recordAudit(tx, AuditUpdate, AuditTargetProject, project.ID, diffProject(&before, project), "schema migration"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package db
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/testutil"
+	bolt "go.etcd.io/bbolt"
+)
+
+// writeV0Fixture writes a database as the first releases did: projects and entries only,
+// no meta bucket, no entry index, and two entries with hashes damaged by the e8e8 bug
+func writeV0Fixture(t *testing.T, dbPath string) {
+	t.Helper()
+
+	db, err := bolt.Open(dbPath, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create fixture: %v", err)
+	}
+	defer db.Close()
+
+	now := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	project := models.Project{ID: "p1", Name: "Legacy", GitRepoPath: "/legacy", CreatedAt: now, UpdatedAt: now}
+	entries := []models.Entry{
+		{ID: "e1", ProjectID: "p1", Duration: 30, Message: "Corrupted", CommitHash: "0123456789abcdef0123e8e8e8e8e8e8e8e8e8e8", CreatedAt: now, UpdatedAt: now},
+		{ID: "e2", ProjectID: "p1", Duration: 45, Message: "Repeated", CommitHash: strings.Repeat("abcdef0123456789abcd", 2), CreatedAt: now, UpdatedAt: now},
+		{ID: "e3", ProjectID: "p1", Duration: 60, Message: "Healthy", CommitHash: "89abcdef0123456789abcdef0123456789abcdef", CreatedAt: now, UpdatedAt: now},
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		pb, err := tx.CreateBucket([]byte(projectsBucket))
+		if err != nil {
+			return err
+		}
+		data, _ := json.Marshal(project)
+		if err := pb.Put([]byte(project.ID), data); err != nil {
+			return err
+		}
+
+		eb, err := tx.CreateBucket([]byte(entriesBucket))
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			data, _ := json.Marshal(entry)
+			if err := eb.Put([]byte(entry.ID), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+}
+
+func TestMigrateV0Database(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "v0.db")
+	writeV0Fixture(t, dbPath)
+
+	store, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer store.Close()
+
+	version, err := store.SchemaVersion()
+	if err != nil || version != SchemaVersion {
+		t.Fatalf("Expected schema version %d, got %d (%v)", SchemaVersion, version, err)
+	}
+
+	entries, err := store.ListEntries("p1")
+	if err != nil || len(entries) != 3 {
+		t.Fatalf("Expected the 3 legacy entries, got %d (%v)", len(entries), err)
+	}
+
+	want := map[string]string{
+		"e1": "0123456789abcdef0123",
+		"e2": "abcdef0123456789abcd",
+		"e3": "89abcdef0123456789abcdef0123456789abcdef",
+	}
+	for _, entry := range entries {
+		if entry.CommitHash != want[entry.ID] {
+			t.Errorf("Entry %s: expected hash %q, got %q", entry.ID, want[entry.ID], entry.CommitHash)
+		}
+	}
+
+	events, err := store.AuditLog(0)
+	if err != nil {
+		t.Fatalf("AuditLog() error = %v", err)
+	}
+	migrated := 0
+	for _, event := range events {
+		if event.Detail == "schema migration" {
+			migrated++
+		}
+	}
+	// Truncated, then flagged since /legacy can't expand them
+	if migrated != 4 {
+		t.Errorf("Expected 4 audited migration updates, got %d", migrated)
+	}
+
+	for _, entry := range entries {
+		flagged := entry.ID != "e3"
+		if entry.NeedsAdjustment != flagged || (flagged && !strings.Contains(entry.AdjustmentNote, "could not be expanded")) {
+			t.Errorf("Entry %s: expected flagged %v, got %v (%q)", entry.ID, flagged, entry.NeedsAdjustment, entry.AdjustmentNote)
+		}
+	}
+}
+
+func TestMigrateExpandsShortHashes(t *testing.T) {
+	repo := testutil.NewGitRepo(t)
+	repo.Run("commit", "-q", "--allow-empty", "-m", "Work")
+	head := repo.Run("rev-parse", "HEAD")
+
+	// A database from before the expansion migration, holding abbreviated hashes
+	store, dbPath := setupTestDB(t)
+	project, _ := store.CreateProject("Test", repo.Dir)
+	known, _ := store.CreateEntry(project.ID, 30, "Known", head[:7], false, time.Now())
+	unknown, _ := store.CreateEntry(project.ID, 30, "Unknown", "abc1234", false, time.Now())
+	full, _ := store.CreateEntry(project.ID, 30, "Full", head, false, time.Now())
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		return writeSchemaVersion(tx, 1)
+	})
+	if err != nil {
+		t.Fatalf("Failed to set version: %v", err)
+	}
+	store.Close()
+
+	store, err = New(dbPath)
+	if err != nil {
+		t.Fatalf("Reopen error = %v", err)
+	}
+	defer store.Close()
+
+	got, _ := store.GetEntry(known.ID)
+	if got.CommitHash != head || got.NeedsAdjustment {
+		t.Errorf("Expected %s expanded to %s, got %s (flagged %v)", head[:7], head, got.CommitHash, got.NeedsAdjustment)
+	}
+
+	got, _ = store.GetEntry(unknown.ID)
+	if got.CommitHash != "abc1234" || !got.NeedsAdjustment || !strings.Contains(got.AdjustmentNote, "abc1234 could not be expanded") {
+		t.Errorf("Expected abc1234 kept and flagged, got %s (flagged %v, %q)", got.CommitHash, got.NeedsAdjustment, got.AdjustmentNote)
+	}
+
+	got, _ = store.GetEntry(full.ID)
+	if got.CommitHash != head || got.NeedsAdjustment {
+		t.Errorf("Expected the full hash untouched, got %s (flagged %v)", got.CommitHash, got.NeedsAdjustment)
+	}
+
+	// The migration ran once: a later reopen leaves new short hashes alone
+	later, _ := store.CreateEntry(project.ID, 30, "Later", "abc1234", false, time.Now())
+	store.Close()
+	reopened, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("Reopen error = %v", err)
+	}
+	defer reopened.Close()
+	if got, _ := reopened.GetEntry(later.ID); got.NeedsAdjustment {
+		t.Error("Expected the migration not to run again")
+	}
+}
+
+func TestMigrateRenamesSessionsMethod(t *testing.T) {
+	// A database from before the rename, with a project on the old method name
+	store, dbPath := setupTestDB(t)
+	renamed, _ := store.CreateProject("Renamed", "/path")
+	other, _ := store.CreateProject("Other", "/path")
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(projectsBucket))
+		renamed.DurationMethod = "sessions"
+		other.DurationMethod = "per_commit"
+		if err := putProject(b, renamed); err != nil {
+			return err
+		}
+		if err := putProject(b, other); err != nil {
+			return err
+		}
+		return writeSchemaVersion(tx, 2)
+	})
+	if err != nil {
+		t.Fatalf("Failed to write fixture: %v", err)
+	}
+	store.Close()
+
+	store, err = New(dbPath)
+	if err != nil {
+		t.Fatalf("Reopen error = %v", err)
+	}
+	defer store.Close()
+
+	if got, _ := store.GetProject(renamed.ID); got.DurationMethod != "gap_aware" {
+		t.Errorf("Expected sessions renamed to gap_aware, got %q", got.DurationMethod)
+	}
+	if got, _ := store.GetProject(other.ID); got.DurationMethod != "per_commit" {
+		t.Errorf("Expected per_commit untouched, got %q", got.DurationMethod)
+	}
+}
+
+func TestMigrateIsIdempotent(t *testing.T) {
+	store, dbPath := setupTestDB(t)
+	project, _ := store.CreateProject("Test", "/path")
+	entry, _ := store.CreateEntry(project.ID, 30, "Work", "abc1234", false, time.Now())
+	store.Close()
+
+	store, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("Reopen error = %v", err)
+	}
+	defer store.Close()
+
+	version, _ := store.SchemaVersion()
+	if version != SchemaVersion {
+		t.Errorf("Expected schema version %d, got %d", SchemaVersion, version)
+	}
+	got, err := store.GetEntry(entry.ID)
+	if err != nil || got.CommitHash != "abc1234" {
+		t.Errorf("Expected the entry unchanged, got %v (%v)", got, err)
+	}
+}
+
+func TestNewerSchemaRejected(t *testing.T) {
+	store, dbPath := setupTestDB(t)
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		return writeSchemaVersion(tx, SchemaVersion+1)
+	})
+	if err != nil {
+		t.Fatalf("Failed to bump version: %v", err)
+	}
+	store.Close()
+
+	if _, err := New(dbPath); err == nil || !strings.Contains(err.Error(), "newer than this build") {
+		t.Errorf("Expected New to refuse a newer schema, got %v", err)
+	}
+	if _, err := NewReadOnly(dbPath); err == nil || !strings.Contains(err.Error(), "newer than this build") {
+		t.Errorf("Expected NewReadOnly to refuse a newer schema, got %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// FindOrphanEntries returns entries whose project no longer exists, oldest first
+// These appear as "Unknown Project" in stats; repair them with ReassignOrphanEntries or TrashOrphanEntries
+func (s *Store) FindOrphanEntries() ([]*models.Entry, error) {
+	var orphans []*models.Entry
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return forEachOrphan(tx, func(k []byte, entry *models.Entry) error {
+			orphans = append(orphans, entry)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find orphan entries: %w", err)
+	}
+
+	sort.Slice(orphans, func(i, j int) bool {
+		return orphans[i].CreatedAt.Before(orphans[j].CreatedAt)
+	})
+
+	return orphans, nil
+}
+
+// ReassignOrphanEntries moves all orphan entries to an existing project in one transaction
+// Returns the number of entries reassigned.
+func (s *Store) ReassignOrphanEntries(projectID string) (int, error) {
+	reassigned := 0
+	now := time.Now()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(projectsBucket)).Get([]byte(projectID)) == nil {
+			return fmt.Errorf("project not found")
+		}
+
+		eb := tx.Bucket([]byte(entriesBucket))
+
+		// Collect first; writing while iterating a cursor is unsafe
+		var keys [][]byte
+		var entries []*models.Entry
+		err := forEachOrphan(tx, func(k []byte, entry *models.Entry) error {
+			keys = append(keys, append([]byte(nil), k...))
+			entries = append(entries, entry)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, entry := range entries {
+			before := *entry
+			entry.ProjectID = projectID
+			entry.UpdatedAt = now
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			if err := eb.Put(keys[i], data); err != nil {
+				return err
+			}
+			if err := reindexEntry(tx, before.ProjectID, projectID, entry.ID); err != nil {
+				return err
+			}
+			if err := recordEntryUpdate(tx, &before, entry); err != nil {
+				return err
+			}
+		}
+
+		reassigned = len(entries)
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to reassign orphan entries: %w", err)
+	}
+
+	return reassigned, nil
+}
+
+// TrashOrphanEntries moves all orphan entries to the trash in one transaction
+// Locked entries are skipped. Returns the number of entries moved.
+func (s *Store) TrashOrphanEntries() (int, error) {
+	trashedCount := 0
+	now := time.Now()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		eb := tx.Bucket([]byte(entriesBucket))
+		tb := tx.Bucket([]byte(trashBucket))
+
+		// Collect keys first; deleting while iterating a cursor skips items
+		var keys [][]byte
+		var trashed []models.TrashedEntry
+		err := forEachOrphan(tx, func(k []byte, entry *models.Entry) error {
+			if entry.Locked {
+				return nil
+			}
+			keys = append(keys, append([]byte(nil), k...))
+			trashed = append(trashed, models.TrashedEntry{Entry: *entry, DeletedAt: now})
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, k := range keys {
+			data, err := json.Marshal(trashed[i])
+			if err != nil {
+				return err
+			}
+			if err := tb.Put(k, data); err != nil {
+				return err
+			}
+			if err := eb.Delete(k); err != nil {
+				return err
+			}
+			if err := unindexEntry(tx, trashed[i].ProjectID, trashed[i].ID); err != nil {
+				return err
+			}
+			if err := recordAudit(tx, AuditTrash, AuditTargetEntry, string(k), nil, "orphan entry"); err != nil {
+				return err
+			}
+		}
+
+		trashedCount = len(keys)
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to trash orphan entries: %w", err)
+	}
+
+	return trashedCount, nil
+}
+
+// forEachOrphan calls fn for every entry whose project does not exist
+func forEachOrphan(tx *bolt.Tx, fn func(k []byte, entry *models.Entry) error) error {
+	pb := tx.Bucket([]byte(projectsBucket))
+
+	return tx.Bucket([]byte(entriesBucket)).ForEach(func(k, v []byte) error {
+		var entry models.Entry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		if pb.Get([]byte(entry.ProjectID)) != nil {
+			return nil
+		}
+		return fn(k, &entry)
+	})
+}
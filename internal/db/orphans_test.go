@@ -0,0 +1,126 @@
+package db
+
+import (
+	"testing"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// seedOrphan creates an entry and then removes its project without the cascade
+func seedOrphan(t *testing.T, store *Store) string {
+	t.Helper()
+
+	gone, _ := store.CreateProject("Gone", "/path/gone")
+	entry, err := store.CreateEntry(gone.ID, 45, "Orphan", "abc", false, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to create entry: %v", err)
+	}
+
+	err = store.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(projectsBucket)).Delete([]byte(gone.ID))
+	})
+	if err != nil {
+		t.Fatalf("Failed to remove project: %v", err)
+	}
+
+	return entry.ID
+}
+
+func TestFindOrphanEntries(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Kept", "/path/kept")
+	store.CreateEntry(project.ID, 60, "Healthy", "def", false, time.Now())
+
+	orphans, err := store.FindOrphanEntries()
+	if err != nil {
+		t.Fatalf("Failed to find orphans: %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Fatalf("Expected no orphans, got %d", len(orphans))
+	}
+
+	orphanID := seedOrphan(t, store)
+
+	orphans, err = store.FindOrphanEntries()
+	if err != nil {
+		t.Fatalf("Failed to find orphans: %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].ID != orphanID {
+		t.Fatalf("Expected orphan %s, got %v", orphanID, orphans)
+	}
+
+	report, err := store.Integrity()
+	if err != nil {
+		t.Fatalf("Failed to run integrity check: %v", err)
+	}
+	if report.OrphanCount != 1 {
+		t.Errorf("Expected integrity report to count 1 orphan, got %d", report.OrphanCount)
+	}
+}
+
+func TestReassignOrphanEntries(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Kept", "/path/kept")
+	orphanID := seedOrphan(t, store)
+
+	if _, err := store.ReassignOrphanEntries("missing"); err == nil {
+		t.Error("Expected error reassigning to a nonexistent project")
+	}
+
+	reassigned, err := store.ReassignOrphanEntries(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to reassign orphans: %v", err)
+	}
+	if reassigned != 1 {
+		t.Errorf("Expected 1 reassigned entry, got %d", reassigned)
+	}
+
+	entry, err := store.GetEntry(orphanID)
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if entry.ProjectID != project.ID {
+		t.Errorf("Expected project %s, got %s", project.ID, entry.ProjectID)
+	}
+
+	orphans, _ := store.FindOrphanEntries()
+	if len(orphans) != 0 {
+		t.Errorf("Expected no orphans after repair, got %d", len(orphans))
+	}
+}
+
+func TestTrashOrphanEntries(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	orphanID := seedOrphan(t, store)
+	lockedID := seedOrphan(t, store)
+	store.SetEntryLocked(lockedID, true)
+
+	trashed, err := store.TrashOrphanEntries()
+	if err != nil {
+		t.Fatalf("Failed to trash orphans: %v", err)
+	}
+	if trashed != 1 {
+		t.Errorf("Expected 1 trashed entry, got %d", trashed)
+	}
+
+	if _, err := store.GetEntry(orphanID); err == nil {
+		t.Error("Expected orphan entry to be removed")
+	}
+
+	trash, _ := store.ListTrash()
+	if len(trash) != 1 || trash[0].ID != orphanID {
+		t.Errorf("Expected orphan %s in trash, got %v", orphanID, trash)
+	}
+
+	orphans, _ := store.FindOrphanEntries()
+	if len(orphans) != 1 || orphans[0].ID != lockedID {
+		t.Errorf("Expected locked orphan %s to remain, got %v", lockedID, orphans)
+	}
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// EntryOverlap is a pair of entries by the same author whose time intervals intersect
+type EntryOverlap struct {
+	First          *models.Entry `json:"first"` // Starts no later than Second
+	Second         *models.Entry `json:"second"`
+	OverlapMinutes int64         `json:"overlap_minutes"` // Shared time, rounded up to whole minutes
+}
+
+// entryEnd returns when an entry is taken to end: Duration minutes after its CreatedAt start
+func entryEnd(entry *models.Entry) time.Time {
+	return entry.CreatedAt.Add(time.Duration(entry.Duration) * time.Minute)
+}
+
+// DetectOverlaps returns pairs of the project's entries (all projects when projectID is empty)
+// whose intervals intersect, ordered by the first entry's start
+// Each entry starts at CreatedAt and lasts Duration minutes. Intervals are half-open, so an
+// entry starting exactly when another ends is adjacent, not overlapping; entries sharing a
+// start time overlap. Zero-duration entries bill nothing and never overlap. Only entries by
+// the same author (case-insensitive) are compared, as teammates in a shared database work
+// in parallel.
+func (s *Store) DetectOverlaps(projectID string) ([]EntryOverlap, error) {
+	var entries []*models.Entry
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return forEachEntry(tx, projectID, func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if entry.Duration > 0 {
+				entries = append(entries, &entry)
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect overlaps: %w", err)
+	}
+
+	return findOverlaps(entries), nil
+}
+
+// findOverlaps sweeps entries in start order, pairing each with the later-starting entries
+// that begin before it ends
+func findOverlaps(entries []*models.Entry) []EntryOverlap {
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].CreatedAt.Before(entries[j].CreatedAt)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+
+	overlaps := []EntryOverlap{}
+	for i, first := range entries {
+		end := entryEnd(first)
+		author := strings.ToLower(strings.TrimSpace(first.Author))
+
+		for _, second := range entries[i+1:] {
+			if !second.CreatedAt.Before(end) {
+				break
+			}
+			if strings.ToLower(strings.TrimSpace(second.Author)) != author {
+				continue
+			}
+
+			shared := entryEnd(second)
+			if end.Before(shared) {
+				shared = end
+			}
+			overlaps = append(overlaps, EntryOverlap{
+				First:          first,
+				Second:         second,
+				OverlapMinutes: int64((shared.Sub(second.CreatedAt) + time.Minute - 1) / time.Minute),
+			})
+		}
+	}
+
+	return overlaps
+}
+
+// OverlappingEntryIDs returns the IDs of every entry in overlaps
+func OverlappingEntryIDs(overlaps []EntryOverlap) map[string]bool {
+	ids := make(map[string]bool, 2*len(overlaps))
+	for _, overlap := range overlaps {
+		ids[overlap.First.ID] = true
+		ids[overlap.Second.ID] = true
+	}
+	return ids
+}
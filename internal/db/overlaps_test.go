@@ -0,0 +1,90 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDetectOverlaps(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	other, _ := store.CreateProject("Other", "/other")
+	day := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.UTC)
+
+	// 09:00-10:00 and 09:30-10:30 overlap by 30 minutes
+	morning, _ := store.CreateEntry(project.ID, 60, "Morning", "", false, day)
+	doubled, _ := store.CreateEntry(project.ID, 60, "Double-logged", "", false, day.Add(30*time.Minute))
+
+	// 11:00-12:00 and 12:00-13:00 are adjacent, not overlapping
+	store.CreateEntry(project.ID, 60, "Before lunch", "", false, day.Add(2*time.Hour))
+	store.CreateEntry(project.ID, 60, "After lunch", "", false, day.Add(3*time.Hour))
+
+	// Same start: 14:00-14:45 and 14:00-15:00 overlap by the shorter one
+	sameA, _ := store.CreateEntry(project.ID, 45, "Same start A", "", false, day.Add(5*time.Hour))
+	sameB, _ := store.CreateEntry(project.ID, 60, "Same start B", "", false, day.Add(5*time.Hour))
+
+	// A zero-duration entry inside another block bills nothing and is ignored
+	store.CreateEntry(project.ID, 0, "Note", "", false, day.Add(15*time.Minute))
+
+	// Another project's entry overlapping the morning only counts across all projects
+	elsewhere, _ := store.CreateEntry(other.ID, 30, "Elsewhere", "", false, day.Add(10*time.Minute))
+
+	overlaps, err := store.DetectOverlaps(project.ID)
+	if err != nil {
+		t.Fatalf("DetectOverlaps() error = %v", err)
+	}
+	if len(overlaps) != 2 {
+		t.Fatalf("Expected 2 overlaps, got %d: %+v", len(overlaps), overlaps)
+	}
+
+	if overlaps[0].First.ID != morning.ID || overlaps[0].Second.ID != doubled.ID || overlaps[0].OverlapMinutes != 30 {
+		t.Errorf("Expected Morning/Double-logged overlapping 30m, got %s/%s %dm",
+			overlaps[0].First.Message, overlaps[0].Second.Message, overlaps[0].OverlapMinutes)
+	}
+
+	same := map[string]bool{overlaps[1].First.ID: true, overlaps[1].Second.ID: true}
+	if !same[sameA.ID] || !same[sameB.ID] || overlaps[1].OverlapMinutes != 45 {
+		t.Errorf("Expected the same-start pair overlapping 45m, got %s/%s %dm",
+			overlaps[1].First.Message, overlaps[1].Second.Message, overlaps[1].OverlapMinutes)
+	}
+
+	all, err := store.DetectOverlaps("")
+	if err != nil {
+		t.Fatalf("DetectOverlaps(all) error = %v", err)
+	}
+	ids := OverlappingEntryIDs(all)
+	if len(all) != 4 || !ids[elsewhere.ID] {
+		t.Errorf("Expected the other project's entry to overlap across projects, got %d overlaps", len(all))
+	}
+}
+
+func TestDetectOverlapsPerAuthor(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Shared", "/shared")
+	day := time.Date(2026, time.October, 12, 9, 0, 0, 0, time.UTC)
+
+	alice, _ := store.CreateEntry(project.ID, 60, "Alice", "", false, day)
+	store.SetEntryAuthor(alice.ID, "Alice")
+	bob, _ := store.CreateEntry(project.ID, 60, "Bob", "", false, day)
+	store.SetEntryAuthor(bob.ID, "Bob")
+
+	overlaps, err := store.DetectOverlaps(project.ID)
+	if err != nil {
+		t.Fatalf("DetectOverlaps() error = %v", err)
+	}
+	if len(overlaps) != 0 {
+		t.Errorf("Expected teammates working in parallel not to overlap, got %+v", overlaps)
+	}
+
+	again, _ := store.CreateEntry(project.ID, 30, "Alice again", "", false, day.Add(45*time.Minute))
+	store.SetEntryAuthor(again.ID, " alice ")
+
+	overlaps, _ = store.DetectOverlaps(project.ID)
+	if len(overlaps) != 1 || overlaps[0].First.ID != alice.ID || overlaps[0].OverlapMinutes != 15 {
+		t.Errorf("Expected Alice's entries to overlap by 15m, got %+v", overlaps)
+	}
+}
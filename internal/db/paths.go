@@ -0,0 +1,104 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Relations between a candidate repo path and an existing project's repo path
+const (
+	PathSame     = "same"     // Both paths name the same directory
+	PathInside   = "inside"   // The candidate is a subdirectory of the project's repo
+	PathContains = "contains" // The candidate is a parent of the project's repo
+)
+
+// pathRelation reports how path relates to other: PathSame, PathInside, PathContains,
+// or "" when neither contains the other. Relative paths are resolved against the
+// working directory so they compare like the absolute paths git is run in.
+func pathRelation(path, other string) string {
+	path, other = absPath(path), absPath(other)
+	switch {
+	case path == other:
+		return PathSame
+	case pathContains(other, path):
+		return PathInside
+	case pathContains(path, other):
+		return PathContains
+	}
+	return ""
+}
+
+// pathContains reports whether child lies strictly below parent
+func pathContains(parent, child string) bool {
+	rel, err := filepath.Rel(parent, child)
+	if err != nil || rel == "." {
+		return false
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// absPath cleans path and makes it absolute when possible
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// FindOverlappingProject returns the first project, other than excludeID, whose repo path is
+// the same as, a parent of, or inside path, together with the relation of path to it.
+// Returns nil when no project overlaps or path is empty.
+func (s *Store) FindOverlappingProject(path, excludeID string) (*models.Project, string, error) {
+	if path == "" {
+		return nil, "", nil
+	}
+
+	var overlapping *models.Project
+	var relation string
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(projectsBucket)).ForEach(func(k, v []byte) error {
+			if overlapping != nil {
+				return nil
+			}
+
+			var project models.Project
+			if err := json.Unmarshal(v, &project); err != nil {
+				return err
+			}
+			if project.ID == excludeID || project.GitRepoPath == "" {
+				return nil
+			}
+			if rel := pathRelation(path, project.GitRepoPath); rel != "" {
+				overlapping = &project
+				relation = rel
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to check project paths: %w", err)
+	}
+
+	return overlapping, relation, nil
+}
+
+// OverlapMessage describes how path overlaps an existing project's repo
+func OverlapMessage(path string, project *models.Project, relation string) string {
+	var where string
+	switch relation {
+	case PathSame:
+		where = "is already the repo of"
+	case PathInside:
+		where = "is inside the repo of"
+	default:
+		where = "contains the repo of"
+	}
+	return fmt.Sprintf("git_repo_path %s %s project %q (%s, %s)", path, where, project.Name, project.ID, project.GitRepoPath)
+}
@@ -0,0 +1,65 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindOverlappingProject(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("App", "/work/app")
+	store.CreateProject("No repo", "")
+
+	tests := []struct {
+		name     string
+		path     string
+		relation string
+	}{
+		{"nested path", "/work/app/services/api", PathInside},
+		{"parent path", "/work", PathContains},
+		{"same path", "/work/app/", PathSame},
+		{"unrelated path", "/home/user/notes", ""},
+		{"sibling with shared prefix", "/work/app-legacy", ""},
+		{"empty path", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overlapping, relation, err := store.FindOverlappingProject(tt.path, "")
+			if err != nil {
+				t.Fatalf("FindOverlappingProject() error = %v", err)
+			}
+			if relation != tt.relation {
+				t.Errorf("FindOverlappingProject(%q) relation = %q, want %q", tt.path, relation, tt.relation)
+			}
+			if tt.relation == "" && overlapping != nil {
+				t.Errorf("Expected no overlapping project, got %q", overlapping.Name)
+			}
+			if tt.relation != "" && (overlapping == nil || overlapping.ID != project.ID) {
+				t.Errorf("Expected project %q to overlap, got %v", project.Name, overlapping)
+			}
+		})
+	}
+
+	// A project never overlaps itself
+	if overlapping, _, _ := store.FindOverlappingProject("/work/app", project.ID); overlapping != nil {
+		t.Errorf("Expected project to be excluded from its own check, got %q", overlapping.Name)
+	}
+}
+
+func TestOverlapMessage(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("App", "/work/app")
+	overlapping, relation, _ := store.FindOverlappingProject("/work/app/api", "")
+
+	message := OverlapMessage("/work/app/api", overlapping, relation)
+	for _, want := range []string{"/work/app/api", "is inside the repo of", `"App"`, project.ID} {
+		if !strings.Contains(message, want) {
+			t.Errorf("Expected message to contain %q, got %q", want, message)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Statistics grouping periods for GetPeriodTotals
+const (
+	GroupByDay   = "day"
+	GroupByWeek  = "week" // ISO 8601 weeks, Monday to Sunday
+	GroupByMonth = "month"
+)
+
+// PeriodTotal is the time logged in one day, week or month
+type PeriodTotal struct {
+	Label      string    `json:"label"` // "2026-03-02", "2026-W10" or "2026-03"
+	Start      time.Time `json:"start"` // Midnight at the start of the period
+	Minutes    int64     `json:"minutes"`
+	EntryCount int       `json:"entry_count"`
+}
+
+// ValidGroupBy reports whether groupBy is a supported grouping period
+func ValidGroupBy(groupBy string) bool {
+	return groupBy == GroupByDay || groupBy == GroupByWeek || groupBy == GroupByMonth
+}
+
+// periodStart returns the start of the period containing t, in t's location
+func periodStart(t time.Time, groupBy string) time.Time {
+	year, month, day := t.Date()
+	switch groupBy {
+	case GroupByMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case GroupByWeek:
+		// Weekday counts from Sunday; ISO weeks start on Monday
+		offset := (int(t.Weekday()) + 6) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at start
+func nextPeriod(start time.Time, groupBy string) time.Time {
+	switch groupBy {
+	case GroupByMonth:
+		return start.AddDate(0, 1, 0)
+	case GroupByWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// periodLabel names the period starting at start
+// Weeks are labelled with their ISO year, which differs from the calendar year around New Year
+func periodLabel(start time.Time, groupBy string) string {
+	switch groupBy {
+	case GroupByMonth:
+		return start.Format("2006-01")
+	case GroupByWeek:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", year, week)
+	default:
+		return start.Format("2006-01-02")
+	}
+}
+
+// GetPeriodTotals sums the time of the entries matching the filters per day, ISO week or month,
+// with periods taken from each entry's CreatedAt in loc. The result is ordered oldest first and
+// covers every period from startDate (or the earliest entry) through endDate (or the latest
+// entry), so periods without entries appear with zero minutes.
+func (s *Store) GetPeriodTotals(projectID string, startDate, endDate *time.Time, invoicedFilter *bool, groupBy string, loc *time.Location) ([]PeriodTotal, error) {
+	if !ValidGroupBy(groupBy) {
+		return nil, fmt.Errorf("unsupported grouping %q (use '%s', '%s' or '%s')", groupBy, GroupByDay, GroupByWeek, GroupByMonth)
+	}
+	if loc == nil {
+		loc = time.Local
+	}
+
+	totals := make(map[time.Time]*PeriodTotal)
+	var first, last time.Time
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return forEachEntry(tx, projectID, func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if !matchesFilter(&entry, projectID, startDate, endDate, invoicedFilter) {
+				return nil
+			}
+
+			start := periodStart(entry.CreatedAt.In(loc), groupBy)
+			total, ok := totals[start]
+			if !ok {
+				total = &PeriodTotal{Label: periodLabel(start, groupBy), Start: start}
+				totals[start] = total
+			}
+			total.Minutes += entry.Duration
+			total.EntryCount++
+
+			if first.IsZero() || start.Before(first) {
+				first = start
+			}
+			if start.After(last) {
+				last = start
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to group statistics: %w", err)
+	}
+
+	// An explicit range is covered in full, even where it has no entries
+	if startDate != nil {
+		first = periodStart(startDate.In(loc), groupBy)
+	}
+	if endDate != nil {
+		last = periodStart(endDate.In(loc), groupBy)
+	}
+
+	periods := []PeriodTotal{}
+	if first.IsZero() || last.IsZero() {
+		return periods, nil
+	}
+	for start := first; !start.After(last); start = nextPeriod(start, groupBy) {
+		if total, ok := totals[start]; ok {
+			periods = append(periods, *total)
+			continue
+		}
+		periods = append(periods, PeriodTotal{Label: periodLabel(start, groupBy), Start: start})
+	}
+
+	return periods, nil
+}
+
+// WeekdayTotal is the time logged on one day of the week
+type WeekdayTotal struct {
+	Weekday string `json:"weekday"` // "Monday" ... "Sunday"
+	Minutes int64  `json:"minutes"`
+}
+
+// WeekdayBreakdown sums the time of the entries matching the filters per day of the week, taken
+// from each entry's CreatedAt in loc. Every weekday is present, with zero minutes when idle.
+func (s *Store) WeekdayBreakdown(projectID string, startDate, endDate *time.Time, invoicedFilter *bool, loc *time.Location) (map[time.Weekday]int64, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+
+	breakdown := make(map[time.Weekday]int64, 7)
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		breakdown[day] = 0
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return forEachEntry(tx, projectID, func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if matchesFilter(&entry, projectID, startDate, endDate, invoicedFilter) {
+				breakdown[entry.CreatedAt.In(loc).Weekday()] += entry.Duration
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to group statistics by weekday: %w", err)
+	}
+
+	return breakdown, nil
+}
+
+// WeekdayTotals orders a weekday breakdown Monday first, as in ISO weeks
+func WeekdayTotals(breakdown map[time.Weekday]int64) []WeekdayTotal {
+	totals := make([]WeekdayTotal, 0, 7)
+	for i := 1; i <= 7; i++ {
+		day := time.Weekday(i % 7)
+		totals = append(totals, WeekdayTotal{Weekday: day.String(), Minutes: breakdown[day]})
+	}
+	return totals
+}
@@ -0,0 +1,132 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetPeriodTotals(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	at := func(month time.Month, day, hour int) time.Time {
+		return time.Date(2026, month, day, hour, 0, 0, 0, time.UTC)
+	}
+
+	store.CreateEntry(project.ID, 60, "Wed", "", false, at(time.January, 28, 10))
+	store.CreateEntry(project.ID, 30, "Sun", "", false, at(time.February, 1, 22))
+	store.CreateEntry(project.ID, 45, "Mon", "", false, at(time.February, 2, 9))
+	store.CreateEntry(project.ID, 90, "March", "", false, at(time.March, 16, 9))
+
+	months, err := store.GetPeriodTotals("", nil, nil, nil, GroupByMonth, time.UTC)
+	if err != nil {
+		t.Fatalf("GetPeriodTotals() error = %v", err)
+	}
+	wantMonths := []PeriodTotal{{Label: "2026-01", Minutes: 60, EntryCount: 1}, {Label: "2026-02", Minutes: 75, EntryCount: 2}, {Label: "2026-03", Minutes: 90, EntryCount: 1}}
+	if len(months) != len(wantMonths) {
+		t.Fatalf("Expected %d months, got %+v", len(wantMonths), months)
+	}
+	for i, want := range wantMonths {
+		if months[i].Label != want.Label || months[i].Minutes != want.Minutes || months[i].EntryCount != want.EntryCount {
+			t.Errorf("Month %d: expected %+v, got %+v", i, want, months[i])
+		}
+	}
+
+	// Sunday February 1 closes ISO week 5; Monday February 2 opens week 6.
+	// The weeks up to mid-March without entries are reported as zero.
+	weeks, _ := store.GetPeriodTotals("", nil, nil, nil, GroupByWeek, time.UTC)
+	if len(weeks) != 8 {
+		t.Fatalf("Expected weeks 5 through 12, got %d: %+v", len(weeks), weeks)
+	}
+	if weeks[0].Label != "2026-W05" || weeks[0].Minutes != 90 || !weeks[0].Start.Equal(at(time.January, 26, 0)) {
+		t.Errorf("Expected week 5 from Monday January 26 with 90 minutes, got %+v", weeks[0])
+	}
+	if weeks[1].Label != "2026-W06" || weeks[1].Minutes != 45 {
+		t.Errorf("Expected week 6 with 45 minutes, got %+v", weeks[1])
+	}
+	if weeks[2].Minutes != 0 || weeks[2].EntryCount != 0 || weeks[7].Label != "2026-W12" || weeks[7].Minutes != 90 {
+		t.Errorf("Expected empty weeks as zero through week 12, got %+v", weeks)
+	}
+
+	// The time zone decides the day: 22:00 UTC on Sunday is Monday in Tokyo
+	tokyo := time.FixedZone("JST", 9*60*60)
+	weeks, _ = store.GetPeriodTotals("", nil, nil, nil, GroupByWeek, tokyo)
+	if weeks[0].Minutes != 60 || weeks[1].Minutes != 75 {
+		t.Errorf("Expected the Sunday entry in week 6 in Tokyo, got %+v", weeks[:2])
+	}
+
+	// An explicit range is covered in full
+	start, end := at(time.February, 2, 0), at(time.February, 5, 23)
+	days, _ := store.GetPeriodTotals(project.ID, &start, &end, nil, GroupByDay, time.UTC)
+	if len(days) != 4 || days[0].Label != "2026-02-02" || days[0].Minutes != 45 || days[3].Label != "2026-02-05" || days[3].Minutes != 0 {
+		t.Errorf("Expected 4 days from February 2, got %+v", days)
+	}
+
+	if _, err := store.GetPeriodTotals("", nil, nil, nil, "year", time.UTC); err == nil {
+		t.Error("Expected error for an unsupported grouping")
+	}
+}
+
+func TestGetPeriodTotalsISOYearBoundary(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+
+	// Thursday January 1 2026 belongs to 2026-W01, which starts Monday December 29 2025
+	store.CreateEntry(project.ID, 30, "Old year", "", false, time.Date(2025, time.December, 29, 9, 0, 0, 0, time.UTC))
+	store.CreateEntry(project.ID, 40, "New year", "", false, time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC))
+
+	weeks, _ := store.GetPeriodTotals("", nil, nil, nil, GroupByWeek, time.UTC)
+	if len(weeks) != 1 || weeks[0].Label != "2026-W01" || weeks[0].Minutes != 70 {
+		t.Errorf("Expected both entries in 2026-W01, got %+v", weeks)
+	}
+
+	months, _ := store.GetPeriodTotals("", nil, nil, nil, GroupByMonth, time.UTC)
+	if len(months) != 2 || months[0].Label != "2025-12" || months[1].Label != "2026-01" {
+		t.Errorf("Expected December and January, got %+v", months)
+	}
+}
+
+func TestWeekdayBreakdown(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	other, _ := store.CreateProject("Other", "/other")
+
+	// Monday March 2, Wednesday March 4 (twice), Sunday March 8 late in the evening UTC
+	store.CreateEntry(project.ID, 60, "Mon", "", false, time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC))
+	store.CreateEntry(project.ID, 30, "Wed", "", false, time.Date(2026, time.March, 4, 10, 0, 0, 0, time.UTC))
+	store.CreateEntry(project.ID, 45, "Wed", "", false, time.Date(2026, time.March, 4, 15, 0, 0, 0, time.UTC))
+	store.CreateEntry(project.ID, 20, "Sun", "", false, time.Date(2026, time.March, 8, 23, 30, 0, 0, time.UTC))
+	store.CreateEntry(other.ID, 90, "Other", "", false, time.Date(2026, time.March, 3, 9, 0, 0, 0, time.UTC))
+
+	breakdown, err := store.WeekdayBreakdown(project.ID, nil, nil, nil, time.UTC)
+	if err != nil {
+		t.Fatalf("WeekdayBreakdown() error = %v", err)
+	}
+	want := map[time.Weekday]int64{time.Monday: 60, time.Wednesday: 75, time.Sunday: 20}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		minutes, ok := breakdown[day]
+		if !ok {
+			t.Errorf("Expected %s to be present", day)
+		}
+		if minutes != want[day] {
+			t.Errorf("%s: expected %d minutes, got %d", day, want[day], minutes)
+		}
+	}
+
+	// An hour east of UTC the Sunday evening entry falls on Monday
+	berlin := time.FixedZone("CET", 60*60)
+	breakdown, _ = store.WeekdayBreakdown(project.ID, nil, nil, nil, berlin)
+	if breakdown[time.Sunday] != 0 || breakdown[time.Monday] != 80 {
+		t.Errorf("Expected the Sunday entry on Monday in CET, got %v", breakdown)
+	}
+
+	totals := WeekdayTotals(breakdown)
+	if len(totals) != 7 || totals[0].Weekday != "Monday" || totals[6].Weekday != "Sunday" {
+		t.Errorf("Expected Monday through Sunday, got %+v", totals)
+	}
+}
@@ -0,0 +1,164 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SetProjectRate sets the project's hourly billing rate and its currency code
+// A rate of 0 stops the project's time from being priced
+func (s *Store) SetProjectRate(id string, rate float64, currency string) (*models.Project, error) {
+	if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return nil, fmt.Errorf("hourly rate must be a non-negative number")
+	}
+	return s.modifyProject(id, func(project *models.Project) error {
+		project.HourlyRate = rate
+		project.Currency = strings.ToUpper(strings.TrimSpace(currency))
+		return nil
+	})
+}
+
+// SetProjectBudget sets the project's fixed-bid time budget in minutes
+// A budget of 0 removes it
+func (s *Store) SetProjectBudget(id string, minutes int64) (*models.Project, error) {
+	if minutes < 0 {
+		return nil, fmt.Errorf("budget must not be negative")
+	}
+	return s.modifyProject(id, func(project *models.Project) error {
+		project.BudgetMinutes = minutes
+		return nil
+	})
+}
+
+// authorRatesBucket holds hourly rates per author, keyed by authorRateKey
+const authorRatesBucket = "author_rates"
+
+// authorRateKey normalizes an author name so rates match regardless of case and spacing
+func authorRateKey(author string) string {
+	return strings.ToLower(strings.TrimSpace(author))
+}
+
+// SetAuthorRate sets the hourly rate billed for an author's entries, in the project's currency
+// It overrides the project rate for entries by that author; a rate of 0 removes it
+func (s *Store) SetAuthorRate(author string, rate float64) error {
+	key := authorRateKey(author)
+	if key == "" {
+		return fmt.Errorf("author cannot be empty")
+	}
+	if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+		return fmt.Errorf("hourly rate must be a non-negative number")
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(authorRatesBucket))
+		if rate == 0 {
+			return b.Delete([]byte(key))
+		}
+		return b.Put([]byte(key), []byte(strconv.FormatFloat(rate, 'f', -1, 64)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to set author rate: %w", err)
+	}
+	return nil
+}
+
+// GetAuthorRates returns the configured author rates keyed by normalized author name
+func (s *Store) GetAuthorRates() (map[string]float64, error) {
+	var rates map[string]float64
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		rates, err = authorRatesTx(tx)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load author rates: %w", err)
+	}
+	return rates, nil
+}
+
+// authorRatesTx reads the author rates within a transaction
+func authorRatesTx(tx *bolt.Tx) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	err := tx.Bucket([]byte(authorRatesBucket)).ForEach(func(k, v []byte) error {
+		rate, err := strconv.ParseFloat(string(v), 64)
+		if err != nil {
+			return fmt.Errorf("invalid rate for author %q: %w", k, err)
+		}
+		rates[string(k)] = rate
+		return nil
+	})
+	return rates, err
+}
+
+// priceStatistics prices the minutes in authorMinutes (project ID -> author key -> minutes):
+// an author's time at their rate when one is set, otherwise at the project's hourly rate, in the
+// project's currency. Time with neither rate (or in projects no longer existing) counts towards
+// UnpricedMinutes.
+func priceStatistics(tx *bolt.Tx, stats *Statistics, authorMinutes map[string]map[string]int64) error {
+	amounts, unpriced, err := priceMinutes(tx, authorMinutes)
+	if err != nil {
+		return err
+	}
+	stats.UnpricedMinutes += unpriced
+	if len(amounts) == 0 {
+		return nil
+	}
+	stats.Amounts = amounts
+
+	// Mixed currencies are not summed; see Amounts
+	if len(stats.Amounts) == 1 {
+		for currency, amount := range stats.Amounts {
+			stats.TotalAmount = amount
+			stats.Currency = currency
+		}
+	}
+
+	return nil
+}
+
+// priceMinutes prices authorMinutes like priceStatistics, returning the amounts per currency
+// rounded to cents (nil when nothing is priced) and the minutes without a rate
+func priceMinutes(tx *bolt.Tx, authorMinutes map[string]map[string]int64) (map[string]float64, int64, error) {
+	pb := tx.Bucket([]byte(projectsBucket))
+	amounts := make(map[string]float64)
+	var unpriced int64
+
+	authorRates, err := authorRatesTx(tx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for projectID, byAuthor := range authorMinutes {
+		var project models.Project
+		if data := pb.Get([]byte(projectID)); data != nil {
+			if err := json.Unmarshal(data, &project); err != nil {
+				return nil, 0, err
+			}
+		}
+		for author, minutes := range byAuthor {
+			rate, ok := authorRates[author]
+			if !ok || author == "" {
+				rate = project.HourlyRate
+			}
+			if rate <= 0 {
+				unpriced += minutes
+				continue
+			}
+			amounts[project.Currency] += float64(minutes) / 60 * rate
+		}
+	}
+
+	if len(amounts) == 0 {
+		return nil, unpriced, nil
+	}
+	for currency, amount := range amounts {
+		amounts[currency] = math.Round(amount*100) / 100
+	}
+	return amounts, unpriced, nil
+}
@@ -0,0 +1,151 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSetProjectRate(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+
+	updated, err := store.SetProjectRate(project.ID, 85.5, " eur ")
+	if err != nil {
+		t.Fatalf("SetProjectRate() error = %v", err)
+	}
+	if updated.HourlyRate != 85.5 || updated.Currency != "EUR" {
+		t.Errorf("Expected 85.5 EUR, got %v %q", updated.HourlyRate, updated.Currency)
+	}
+
+	if _, err := store.SetProjectRate(project.ID, -1, "EUR"); err == nil {
+		t.Error("Expected error for a negative rate")
+	}
+	if _, err := store.SetProjectRate("missing", 10, "EUR"); err == nil {
+		t.Error("Expected error for a missing project")
+	}
+}
+
+func TestStatisticsAmounts(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	now := time.Now()
+	alpha, _ := store.CreateProject("Alpha", "/alpha")
+	beta, _ := store.CreateProject("Beta", "/beta")
+	unpriced, _ := store.CreateProject("Internal", "/internal")
+
+	store.SetProjectRate(alpha.ID, 100, "EUR")
+	store.SetProjectRate(beta.ID, 60, "EUR")
+
+	store.CreateEntry(alpha.ID, 90, "Alpha work", "", false, now)
+	store.CreateEntry(beta.ID, 20, "Beta work", "", false, now)
+	store.CreateEntry(unpriced.ID, 45, "Internal work", "", false, now)
+
+	stats, err := store.GetStatistics("", nil, nil, nil)
+	if err != nil {
+		t.Fatalf("GetStatistics() error = %v", err)
+	}
+
+	// 1.5h at 100 + 20m at 60
+	if stats.TotalAmount != 170 || stats.Currency != "EUR" || stats.Amounts["EUR"] != 170 {
+		t.Errorf("Expected 170 EUR, got %v %q (%v)", stats.TotalAmount, stats.Currency, stats.Amounts)
+	}
+	if stats.UnpricedMinutes != 45 {
+		t.Errorf("Expected 45 unpriced minutes, got %d", stats.UnpricedMinutes)
+	}
+
+	// Mixed currencies are reported per currency without a total
+	store.SetProjectRate(beta.ID, 60, "USD")
+	stats, _ = store.GetStatistics("", nil, nil, nil)
+	if stats.TotalAmount != 0 || stats.Currency != "" {
+		t.Errorf("Expected no total for mixed currencies, got %v %q", stats.TotalAmount, stats.Currency)
+	}
+	if stats.Amounts["EUR"] != 150 || stats.Amounts["USD"] != 20 {
+		t.Errorf("Expected 150 EUR and 20 USD, got %v", stats.Amounts)
+	}
+
+	// Without any rates there are no amounts
+	stats, _ = store.GetStatistics(unpriced.ID, nil, nil, nil)
+	if stats.Amounts != nil || stats.UnpricedMinutes != 45 {
+		t.Errorf("Expected no amounts for an unpriced project, got %v", stats.Amounts)
+	}
+}
+
+func TestSetProjectBudget(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+
+	updated, err := store.SetProjectBudget(project.ID, 6000)
+	if err != nil {
+		t.Fatalf("SetProjectBudget() error = %v", err)
+	}
+	if updated.BudgetMinutes != 6000 {
+		t.Errorf("Expected a 6000 minute budget, got %d", updated.BudgetMinutes)
+	}
+
+	history, _ := store.ProjectHistory(project.ID)
+	if len(history) != 1 || history[0].Changes[0].Field != "budget_minutes" {
+		t.Errorf("Expected the budget change in the project history, got %+v", history)
+	}
+
+	if _, err := store.SetProjectBudget(project.ID, -1); err == nil {
+		t.Error("Expected error for a negative budget")
+	}
+}
+
+func TestAuthorRates(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	now := time.Now()
+	project, _ := store.CreateProject("Shared", "/shared")
+	store.SetProjectRate(project.ID, 80, "EUR")
+
+	senior, _ := store.CreateEntry(project.ID, 60, "Architecture", "", false, now)
+	store.SetEntryAuthor(senior.ID, "Alice Senior")
+	junior, _ := store.CreateEntry(project.ID, 60, "Tests", "", false, now)
+	store.SetEntryAuthor(junior.ID, "Bob Junior")
+	store.CreateEntry(project.ID, 30, "No author", "", false, now)
+
+	if err := store.SetAuthorRate(" alice senior ", 150); err != nil {
+		t.Fatalf("SetAuthorRate() error = %v", err)
+	}
+	store.SetAuthorRate("Bob Junior", 50)
+
+	// 1h at 150 + 1h at 50 + 0.5h at the project's 80
+	stats, _ := store.GetStatistics(project.ID, nil, nil, nil)
+	if stats.TotalAmount != 240 || stats.Currency != "EUR" {
+		t.Errorf("Expected 240 EUR from author rates, got %v %q", stats.TotalAmount, stats.Currency)
+	}
+
+	// Removing a rate falls back to the project rate
+	store.SetAuthorRate("BOB JUNIOR", 0)
+	stats, _ = store.GetStatistics(project.ID, nil, nil, nil)
+	if stats.TotalAmount != 270 {
+		t.Errorf("Expected 270 EUR after removing Bob's rate, got %v", stats.TotalAmount)
+	}
+	if rates, _ := store.GetAuthorRates(); len(rates) != 1 || rates["alice senior"] != 150 {
+		t.Errorf("Expected only Alice's rate, got %v", rates)
+	}
+
+	// An author rate prices time even in a project without a rate
+	internal, _ := store.CreateProject("Internal", "/internal")
+	entry, _ := store.CreateEntry(internal.ID, 30, "Support", "", false, now)
+	store.SetEntryAuthor(entry.ID, "Alice Senior")
+	store.CreateEntry(internal.ID, 15, "Unpriced", "", false, now)
+	stats, _ = store.GetStatistics(internal.ID, nil, nil, nil)
+	if stats.Amounts[""] != 75 || stats.UnpricedMinutes != 15 {
+		t.Errorf("Expected 75 from Alice's rate and 15 unpriced minutes, got %v / %d", stats.Amounts, stats.UnpricedMinutes)
+	}
+
+	if err := store.SetAuthorRate(" ", 10); err == nil {
+		t.Error("Expected error for an empty author")
+	}
+	if err := store.SetAuthorRate("Alice Senior", -5); err == nil {
+		t.Error("Expected error for a negative rate")
+	}
+}
@@ -0,0 +1,67 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// GetEntryPolicy returns which entry fields the configured policy requires
+func (s *Store) GetEntryPolicy() (requireReference, requireCategory bool, err error) {
+	reference, err := s.GetSetting(SettingRequireReference)
+	if err != nil {
+		return false, false, err
+	}
+	category, err := s.GetSetting(SettingRequireCategory)
+	if err != nil {
+		return false, false, err
+	}
+	return reference == "true", category == "true", nil
+}
+
+// MissingEntryFields lists the required fields an entry lacks ("reference", "category")
+func MissingEntryFields(entry *models.Entry, requireReference, requireCategory bool) []string {
+	var missing []string
+	if requireReference && entry.Reference == "" {
+		missing = append(missing, "reference")
+	}
+	if requireCategory && entry.Category == "" {
+		missing = append(missing, "category")
+	}
+	return missing
+}
+
+// FindIncompleteEntries returns entries missing a required reference or category, newest first
+// Returns no entries when neither field is required
+func (s *Store) FindIncompleteEntries(requireReference, requireCategory bool) ([]*models.Entry, error) {
+	var incomplete []*models.Entry
+	if !requireReference && !requireCategory {
+		return incomplete, nil
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(entriesBucket)).ForEach(func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if len(MissingEntryFields(&entry, requireReference, requireCategory)) > 0 {
+				incomplete = append(incomplete, &entry)
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to find incomplete entries: %w", err)
+	}
+
+	sort.Slice(incomplete, func(i, j int) bool {
+		return incomplete[i].CreatedAt.After(incomplete[j].CreatedAt)
+	})
+
+	return incomplete, nil
+}
@@ -0,0 +1,102 @@
+package db
+
+import (
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestFindIncompleteEntries(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	now := time.Now()
+
+	complete, _ := store.CreateEntry(project.ID, 60, "Complete", "", false, now)
+	store.SetEntryReference(complete.ID, "PROJ-1")
+	store.SetEntryCategory(complete.ID, "development")
+
+	noReference, _ := store.CreateEntry(project.ID, 30, "No reference", "", false, now.Add(-time.Hour))
+	store.SetEntryCategory(noReference.ID, "meeting")
+
+	noCategory, _ := store.CreateEntry(project.ID, 45, "No category", "", false, now.Add(-2*time.Hour))
+	store.SetEntryReference(noCategory.ID, "PROJ-2")
+
+	bare, _ := store.CreateEntry(project.ID, 15, "Bare", "", false, now.Add(-3*time.Hour))
+
+	tests := []struct {
+		name             string
+		requireReference bool
+		requireCategory  bool
+		want             []string
+	}{
+		{"no policy", false, false, nil},
+		{"reference required", true, false, []string{noReference.ID, bare.ID}},
+		{"category required", false, true, []string{noCategory.ID, bare.ID}},
+		{"both required", true, true, []string{noReference.ID, noCategory.ID, bare.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := store.FindIncompleteEntries(tt.requireReference, tt.requireCategory)
+			if err != nil {
+				t.Fatalf("Failed to find incomplete entries: %v", err)
+			}
+
+			var got []string
+			for _, entry := range entries {
+				got = append(got, entry.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Expected %d incomplete entries, got %d", len(tt.want), len(got))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Expected entry %d to be %s, got %s", i, tt.want[i], got[i])
+				}
+			}
+		})
+	}
+}
+
+func TestGetEntryPolicy(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	requireReference, requireCategory, err := store.GetEntryPolicy()
+	if err != nil {
+		t.Fatalf("Failed to get entry policy: %v", err)
+	}
+	if requireReference || requireCategory {
+		t.Error("Expected no required fields by default")
+	}
+
+	store.SetSetting(SettingRequireCategory, "true")
+	requireReference, requireCategory, _ = store.GetEntryPolicy()
+	if requireReference || !requireCategory {
+		t.Errorf("Expected only category required, got reference=%v category=%v", requireReference, requireCategory)
+	}
+}
+
+func TestMissingEntryFields(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	entry, _ := store.CreateEntry(project.ID, 60, "Entry", "", false, time.Now())
+
+	missing := MissingEntryFields(entry, true, true)
+	sort.Strings(missing)
+	if len(missing) != 2 || missing[0] != "category" || missing[1] != "reference" {
+		t.Errorf("Expected category and reference missing, got %v", missing)
+	}
+
+	entry, _ = store.SetEntryReference(entry.ID, "  PROJ-3 ")
+	if entry.Reference != "PROJ-3" {
+		t.Errorf("Expected trimmed reference 'PROJ-3', got %q", entry.Reference)
+	}
+	if missing := MissingEntryFields(entry, true, false); len(missing) != 0 {
+		t.Errorf("Expected nothing missing, got %v", missing)
+	}
+}
@@ -0,0 +1,70 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/utils"
+)
+
+// lastAutoRunPrefix prefixes the settings key holding when a project's schedule last ran
+const lastAutoRunPrefix = "last_auto_run:"
+
+// SetProjectAutoSchedule sets when the server creates git entries for a project by itself
+// An empty schedule turns it off. Setting one starts it from now, so times that passed
+// before it was set are not owed.
+func (s *Store) SetProjectAutoSchedule(id, schedule string) (*models.Project, error) {
+	return s.UpdateProjectWithOptions(id, "", "", nil, ProjectOptions{AutoSchedule: &schedule})
+}
+
+// GetLastAutoRun returns when the project's schedule last ran, zero if it never did
+func (s *Store) GetLastAutoRun(projectID string) (time.Time, error) {
+	value, err := s.GetSetting(lastAutoRunPrefix + projectID)
+	if err != nil || value == "" {
+		return time.Time{}, err
+	}
+	lastRun, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid last auto run for project %s: %w", projectID, err)
+	}
+	return lastRun, nil
+}
+
+// SetLastAutoRun records when the project's schedule ran, whether or not it created an entry
+func (s *Store) SetLastAutoRun(projectID string, at time.Time) error {
+	return s.SetSetting(lastAutoRunPrefix+projectID, at.Format(time.RFC3339Nano))
+}
+
+// DueAutoSchedules returns the unarchived projects whose schedule is due at now, by name
+// Projects with a schedule that no longer parses are skipped.
+func (s *Store) DueAutoSchedules(now time.Time) ([]*models.Project, error) {
+	projects, err := s.ListProjects(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+
+	var due []*models.Project
+	for _, project := range projects {
+		if project.AutoSchedule == "" {
+			continue
+		}
+		schedule, err := utils.ParseSchedule(project.AutoSchedule)
+		if err != nil {
+			continue
+		}
+		lastRun, err := s.GetLastAutoRun(project.ID)
+		if err != nil {
+			return nil, err
+		}
+		if schedule.Due(now, lastRun) {
+			due = append(due, project)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].Name < due[j].Name
+	})
+	return due, nil
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDueAutoSchedules(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Scheduled", "/path")
+	store.CreateProject("Unscheduled", "/other")
+
+	if _, err := store.SetProjectAutoSchedule(project.ID, "6pm"); err == nil {
+		t.Error("Expected an invalid schedule to be rejected")
+	}
+	if _, err := store.SetProjectAutoSchedule(project.ID, "00:00"); err != nil {
+		t.Fatalf("SetProjectAutoSchedule() error = %v", err)
+	}
+
+	// Setting a schedule starts it from now, so nothing is owed yet
+	now := time.Now()
+	due, err := store.DueAutoSchedules(now)
+	if err != nil {
+		t.Fatalf("DueAutoSchedules() error = %v", err)
+	}
+	if len(due) != 0 {
+		t.Errorf("Expected nothing due right after setting the schedule, got %d", len(due))
+	}
+
+	tomorrow := now.Add(25 * time.Hour)
+	due, _ = store.DueAutoSchedules(tomorrow)
+	if len(due) != 1 || due[0].ID != project.ID {
+		t.Fatalf("Expected the project to be due after midnight, got %+v", due)
+	}
+
+	store.SetLastAutoRun(project.ID, tomorrow)
+	if due, _ = store.DueAutoSchedules(tomorrow); len(due) != 0 {
+		t.Errorf("Expected a recorded run to clear the schedule until its next time, got %d", len(due))
+	}
+
+	store.ArchiveProject(project.ID)
+	if due, _ = store.DueAutoSchedules(tomorrow.Add(25 * time.Hour)); len(due) != 0 {
+		t.Errorf("Expected archived projects to be skipped, got %d", len(due))
+	}
+}
@@ -0,0 +1,404 @@
+package db
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/techthos/clockwork/internal/git"
+	"github.com/techthos/clockwork/internal/utils"
+	bolt "go.etcd.io/bbolt"
+)
+
+const settingsBucket = "settings"
+
+// Setting keys
+const (
+	// SettingManualMessageTemplate is the template used for manual entries without a message
+	SettingManualMessageTemplate = "manual_message_template"
+	// SettingTimerRounding is the policy used to round timer durations to whole minutes
+	SettingTimerRounding = "timer_rounding"
+	// SettingDefaultProject is the project ID used when an operation omits one
+	SettingDefaultProject = "default_project"
+	// SettingCommitExcludePatterns is a comma-separated list of commit subject prefixes left out of messages
+	SettingCommitExcludePatterns = "commit_exclude_patterns"
+	// SettingExcludeFromDuration controls whether excluded commits also stop counting towards duration
+	SettingExcludeFromDuration = "exclude_from_duration"
+	// SettingIncludeCommitBodies controls whether commit bodies are appended to aggregated messages
+	SettingIncludeCommitBodies = "include_commit_bodies"
+	// SettingGroupConventionalCommits controls whether aggregated messages group Conventional Commit subjects by type
+	SettingGroupConventionalCommits = "group_conventional_commits"
+	// SettingDailySubtotals controls whether aggregated messages list commits per day with each day's estimated time
+	SettingDailySubtotals = "daily_subtotals"
+	// SettingDefaultAuthorFromRepo controls whether manual entries default to the repo's git user.name (enabled unless "false")
+	SettingDefaultAuthorFromRepo = "default_author_from_repo"
+	// SettingMinCommitLines drops commits changing fewer lines from aggregated messages (0 or unset = off)
+	SettingMinCommitLines = "min_commit_lines"
+	// SettingStripSubjectPrefix is a regular expression stripped from the start of aggregated commit subjects (unset = off)
+	SettingStripSubjectPrefix = "strip_subject_prefix"
+	// SettingCollectSubjectPrefixes lists the stripped subject prefixes on a Refs line ("true" to enable)
+	SettingCollectSubjectPrefixes = "collect_subject_prefixes"
+	// SettingShortHashLength is the number of hash characters shown in aggregated commit messages (default 7)
+	SettingShortHashLength = "short_hash_length"
+	// SettingMinEntryInterval is the minimum number of minutes between git entries of a project (0 or unset = off)
+	SettingMinEntryInterval = "min_entry_interval"
+	// SettingUseCommitTrailers controls whether Time-Spent commit trailers replace duration estimates ("true" to enable)
+	SettingUseCommitTrailers = "use_commit_trailers"
+	// SettingCurrencyRates is the conversion table used to total amounts across currencies ("EUR=1,USD=1.08")
+	SettingCurrencyRates = "currency_rates"
+	// SettingTicketPattern is the regular expression that finds ticket IDs in entry messages and references
+	SettingTicketPattern = "ticket_pattern"
+	// SettingFocusMapping maps tags to focus/overhead categories for the focus split ("dev=focus,meeting=overhead")
+	SettingFocusMapping = "focus_mapping"
+	// SettingDurationDisplay selects how the TUI shows durations, "clock" (default) or "decimal" hours
+	SettingDurationDisplay = "duration_display"
+	// SettingRequireReference marks entries without a ticket reference as incomplete ("true" to enable)
+	SettingRequireReference = "require_reference"
+	// SettingRequireCategory marks entries without a category as incomplete ("true" to enable)
+	SettingRequireCategory = "require_category"
+	// SettingTrackProjectHistory controls whether project edits are recorded (enabled unless "false")
+	SettingTrackProjectHistory = "track_project_history"
+	// SettingMaxMessageLength is the largest entry message in bytes the store accepts (default 8192, "0" = unlimited)
+	SettingMaxMessageLength = "max_message_length"
+	// SettingMaxSessionMinutes caps each estimated stretch of git work in minutes (0 or unset = no cap)
+	SettingMaxSessionMinutes = "max_session_minutes"
+	// SettingSessionGapMinutes is the idle gap in minutes after which the gap_aware method starts a new session (unset = 90)
+	SettingSessionGapMinutes = "session_gap_minutes"
+	// SettingMaxTimerMinutes caps the time a stopped timer logs in minutes; longer timers are
+	// logged at the cap and flagged as needing adjustment (0 or unset = no cap)
+	SettingMaxTimerMinutes = "max_timer_minutes"
+	// SettingProjectOrder orders TUI project dropdowns, "name" (default) or "recent" to float recently used projects to the top
+	SettingProjectOrder = "project_order"
+	// SettingDefaultManualDuration pre-fills the TUI manual entry duration, e.g. "30m" (unset = no prefill)
+	SettingDefaultManualDuration = "default_manual_duration"
+)
+
+// Project dropdown orderings for SettingProjectOrder
+const (
+	ProjectOrderName   = "name"
+	ProjectOrderRecent = "recent"
+)
+
+// DefaultMaxMessageLength is the message size limit used when max_message_length is unset
+const DefaultMaxMessageLength = 8192
+
+// GetSetting retrieves a setting value by key
+// Returns "" if the setting has not been set
+func (s *Store) GetSetting(key string) (string, error) {
+	var value string
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(settingsBucket))
+		if b == nil {
+			return nil
+		}
+		value = string(b.Get([]byte(key)))
+		return nil
+	})
+
+	if err != nil {
+		return "", fmt.Errorf("failed to get setting: %w", err)
+	}
+
+	return value, nil
+}
+
+// GetSettingOrDefault retrieves a setting value, falling back to def when unset
+func (s *Store) GetSettingOrDefault(key, def string) (string, error) {
+	value, err := s.GetSetting(key)
+	if err != nil {
+		return "", err
+	}
+	if value == "" {
+		return def, nil
+	}
+	return value, nil
+}
+
+// SetSetting stores a setting value
+// An empty value removes the setting so that defaults apply again
+func (s *Store) SetSetting(key, value string) error {
+	if key == "" {
+		return fmt.Errorf("setting key cannot be empty")
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(settingsBucket))
+		if value == "" {
+			return b.Delete([]byte(key))
+		}
+		return b.Put([]byte(key), []byte(value))
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to set setting: %w", err)
+	}
+
+	return nil
+}
+
+// ListSettings returns all explicitly configured settings
+func (s *Store) ListSettings() (map[string]string, error) {
+	settings := make(map[string]string)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(settingsBucket))
+		if b == nil {
+			return nil
+		}
+		return b.ForEach(func(k, v []byte) error {
+			settings[string(k)] = string(v)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settings: %w", err)
+	}
+
+	return settings, nil
+}
+
+// SetDefaultProject sets the project used when no project is specified
+// An empty id clears the default
+func (s *Store) SetDefaultProject(id string) error {
+	// Check and write in one transaction so a concurrent DeleteProject cannot leave a dangling default
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(settingsBucket))
+		if id == "" {
+			return b.Delete([]byte(SettingDefaultProject))
+		}
+		if tx.Bucket([]byte(projectsBucket)).Get([]byte(id)) == nil {
+			return fmt.Errorf("project not found")
+		}
+		return b.Put([]byte(SettingDefaultProject), []byte(id))
+	})
+}
+
+// GetDefaultProject returns the default project ID, or "" if none is configured
+func (s *Store) GetDefaultProject() (string, error) {
+	return s.GetSetting(SettingDefaultProject)
+}
+
+// ResolveProjectID returns projectID if set, otherwise the configured default project
+func (s *Store) ResolveProjectID(projectID string) (string, error) {
+	if projectID != "" {
+		return projectID, nil
+	}
+
+	defaultID, err := s.GetDefaultProject()
+	if err != nil {
+		return "", err
+	}
+	if defaultID == "" {
+		return "", fmt.Errorf("project_id is required when no default project is configured")
+	}
+
+	return defaultID, nil
+}
+
+// GetCommitFilter returns the configured commit exclusion patterns and whether
+// excluded commits should also be left out of duration estimation.
+// Returns defaultPatterns when unset, and no patterns when set to "none".
+func (s *Store) GetCommitFilter(defaultPatterns []string) ([]string, bool, error) {
+	value, err := s.GetSetting(SettingCommitExcludePatterns)
+	if err != nil {
+		return nil, false, err
+	}
+
+	patterns := append([]string(nil), defaultPatterns...)
+	if value == "none" {
+		patterns = nil
+	} else if value != "" {
+		patterns = nil
+		for _, pattern := range strings.Split(value, ",") {
+			if pattern = strings.TrimSpace(pattern); pattern != "" {
+				patterns = append(patterns, pattern)
+			}
+		}
+	}
+
+	excludeFromDuration, err := s.GetSetting(SettingExcludeFromDuration)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return patterns, excludeFromDuration == "true", nil
+}
+
+// GetSubjectPrefix returns the subject prefix pattern stripped from aggregated commits ("" = off)
+// and whether the stripped prefixes are collected into a Refs line
+func (s *Store) GetSubjectPrefix() (string, bool, error) {
+	pattern, err := s.GetSetting(SettingStripSubjectPrefix)
+	if err != nil {
+		return "", false, err
+	}
+	collect, err := s.GetSetting(SettingCollectSubjectPrefixes)
+	if err != nil {
+		return "", false, err
+	}
+	return pattern, collect == "true", nil
+}
+
+// GetCurrencyRates returns the configured currency conversion table
+// Returns an empty table when no rates are configured
+func (s *Store) GetCurrencyRates() (map[string]float64, error) {
+	value, err := s.GetSetting(SettingCurrencyRates)
+	if err != nil {
+		return nil, err
+	}
+	return utils.ParseRates(value)
+}
+
+// GetMinEntryInterval returns the configured minimum time between git entries, or 0 when unset
+func (s *Store) GetMinEntryInterval() (time.Duration, error) {
+	value, err := s.GetSetting(SettingMinEntryInterval)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", SettingMinEntryInterval, value, err)
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// GetMaxSessionMinutes returns the configured cap on estimated git work stretches, or 0 when unset
+func (s *Store) GetMaxSessionMinutes() (int64, error) {
+	value, err := s.GetSetting(SettingMaxSessionMinutes)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	minutes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", SettingMaxSessionMinutes, value, err)
+	}
+	return minutes, nil
+}
+
+// GetSessionGap returns the configured idle threshold of the gap_aware method, or 0 when unset
+func (s *Store) GetSessionGap() (time.Duration, error) {
+	value, err := s.GetSetting(SettingSessionGapMinutes)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	minutes, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", SettingSessionGapMinutes, value, err)
+	}
+	return time.Duration(minutes) * time.Minute, nil
+}
+
+// GetMaxMessageLength returns the entry message size limit in bytes, DefaultMaxMessageLength when unset
+// A limit of 0 disables the check
+func (s *Store) GetMaxMessageLength() (int, error) {
+	var length int
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var err error
+		length, err = maxMessageLength(tx)
+		return err
+	})
+	return length, err
+}
+
+// maxMessageLength reads the max_message_length setting within a transaction
+func maxMessageLength(tx *bolt.Tx) (int, error) {
+	value := string(tx.Bucket([]byte(settingsBucket)).Get([]byte(SettingMaxMessageLength)))
+	if value == "" {
+		return DefaultMaxMessageLength, nil
+	}
+	length, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", SettingMaxMessageLength, value, err)
+	}
+	return length, nil
+}
+
+// checkMessageLength rejects a message longer than limit bytes (0 = unlimited)
+func checkMessageLength(message string, limit int) error {
+	if limit > 0 && len(message) > limit {
+		return fmt.Errorf("message is %d bytes, over the %d byte limit (%s setting)", len(message), limit, SettingMaxMessageLength)
+	}
+	return nil
+}
+
+// GetMinCommitLines returns the changed-lines threshold for trivial commits, or 0 when unset
+func (s *Store) GetMinCommitLines() (int, error) {
+	value, err := s.GetSetting(SettingMinCommitLines)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	lines, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", SettingMinCommitLines, value, err)
+	}
+	return lines, nil
+}
+
+// GetShortHashLength returns the configured short hash length, or 0 when unset
+func (s *Store) GetShortHashLength() (int, error) {
+	value, err := s.GetSetting(SettingShortHashLength)
+	if err != nil || value == "" {
+		return 0, err
+	}
+	length, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", SettingShortHashLength, value, err)
+	}
+	return length, nil
+}
+
+// GetSummarizeOptions returns the git.SummarizeOptions the settings call for
+// Callers add the duration Strategy and the repo's .clockworkignore subjects.
+func (s *Store) GetSummarizeOptions() (git.SummarizeOptions, error) {
+	var opts git.SummarizeOptions
+	var err error
+
+	// Commits matching these patterns (e.g. fixup!) are kept out of the message
+	opts.ExcludePatterns, opts.ExcludeFromDuration, err = s.GetCommitFilter(git.DefaultExcludePatterns)
+	if err != nil {
+		return opts, err
+	}
+
+	// Trivial commits need line counts, which cost a second git log
+	if opts.MinChangedLines, err = s.GetMinCommitLines(); err != nil {
+		return opts, err
+	}
+
+	flags := []struct {
+		key   string
+		value *bool
+	}{
+		{SettingIncludeCommitBodies, &opts.IncludeBodies},
+		{SettingGroupConventionalCommits, &opts.GroupConventional},
+		{SettingDailySubtotals, &opts.DailySubtotals},
+		// Time-Spent trailers take precedence over the estimate when enabled
+		{SettingUseCommitTrailers, &opts.UseTrailers},
+	}
+	for _, flag := range flags {
+		value, err := s.GetSetting(flag.key)
+		if err != nil {
+			return opts, err
+		}
+		*flag.value = value == "true"
+	}
+
+	// Ticket prefixes such as "PROJ-123: " are stripped from subjects when configured
+	if opts.StripPrefixPattern, opts.CollectPrefixes, err = s.GetSubjectPrefix(); err != nil {
+		return opts, err
+	}
+	if opts.ShortHashLength, err = s.GetShortHashLength(); err != nil {
+		return opts, err
+	}
+
+	// Long commit gaps are clamped rather than billed as continuous work
+	if opts.MaxSessionMinutes, err = s.GetMaxSessionMinutes(); err != nil {
+		return opts, err
+	}
+	if opts.SessionGap, err = s.GetSessionGap(); err != nil {
+		return opts, err
+	}
+
+	return opts, nil
+}
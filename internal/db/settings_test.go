@@ -0,0 +1,238 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSettings(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	// Unset setting returns empty string
+	value, err := store.GetSetting(SettingManualMessageTemplate)
+	if err != nil {
+		t.Fatalf("Failed to get setting: %v", err)
+	}
+	if value != "" {
+		t.Errorf("Expected empty value for unset setting, got '%s'", value)
+	}
+
+	// Default applies when unset
+	value, err = store.GetSettingOrDefault(SettingManualMessageTemplate, "Manual entry")
+	if err != nil {
+		t.Fatalf("Failed to get setting: %v", err)
+	}
+	if value != "Manual entry" {
+		t.Errorf("Expected default 'Manual entry', got '%s'", value)
+	}
+
+	if err := store.SetSetting(SettingManualMessageTemplate, "{project} — {date}"); err != nil {
+		t.Fatalf("Failed to set setting: %v", err)
+	}
+
+	value, _ = store.GetSetting(SettingManualMessageTemplate)
+	if value != "{project} — {date}" {
+		t.Errorf("Expected '{project} — {date}', got '%s'", value)
+	}
+
+	settings, err := store.ListSettings()
+	if err != nil {
+		t.Fatalf("Failed to list settings: %v", err)
+	}
+	if len(settings) != 1 {
+		t.Errorf("Expected 1 setting, got %d", len(settings))
+	}
+
+	// Empty value resets to default
+	if err := store.SetSetting(SettingManualMessageTemplate, ""); err != nil {
+		t.Fatalf("Failed to reset setting: %v", err)
+	}
+	value, _ = store.GetSetting(SettingManualMessageTemplate)
+	if value != "" {
+		t.Errorf("Expected setting to be cleared, got '%s'", value)
+	}
+
+	if err := store.SetSetting("", "value"); err == nil {
+		t.Error("Expected error for empty setting key")
+	}
+}
+
+func TestDefaultProjectResolution(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Main", "/path")
+
+	// Explicit ID always wins
+	resolved, err := store.ResolveProjectID("explicit-id")
+	if err != nil {
+		t.Fatalf("Failed to resolve explicit project: %v", err)
+	}
+	if resolved != "explicit-id" {
+		t.Errorf("Expected 'explicit-id', got '%s'", resolved)
+	}
+
+	if err := store.SetDefaultProject(project.ID); err != nil {
+		t.Fatalf("Failed to set default project: %v", err)
+	}
+
+	resolved, err = store.ResolveProjectID("")
+	if err != nil {
+		t.Fatalf("Failed to resolve default project: %v", err)
+	}
+	if resolved != project.ID {
+		t.Errorf("Expected default project '%s', got '%s'", project.ID, resolved)
+	}
+
+	// Unknown project cannot become the default
+	if err := store.SetDefaultProject("missing"); err == nil {
+		t.Error("Expected error when setting a nonexistent default project")
+	}
+
+	// Deleting the default project clears the setting
+	if err := store.DeleteProject(project.ID); err != nil {
+		t.Fatalf("Failed to delete project: %v", err)
+	}
+	defaultID, _ := store.GetDefaultProject()
+	if defaultID != "" {
+		t.Errorf("Expected default project to be cleared, got '%s'", defaultID)
+	}
+}
+
+func TestResolveProjectIDWithoutDefault(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	if _, err := store.ResolveProjectID(""); err == nil {
+		t.Error("Expected error when project_id is omitted and no default exists")
+	}
+}
+
+func TestGetShortHashLength(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	length, err := store.GetShortHashLength()
+	if err != nil || length != 0 {
+		t.Errorf("Expected 0 when unset, got %d (err %v)", length, err)
+	}
+
+	store.SetSetting(SettingShortHashLength, "12")
+	if length, _ := store.GetShortHashLength(); length != 12 {
+		t.Errorf("Expected 12, got %d", length)
+	}
+
+	store.SetSetting(SettingShortHashLength, "long")
+	if _, err := store.GetShortHashLength(); err == nil {
+		t.Error("Expected error for non-numeric length")
+	}
+}
+
+func TestGetMinEntryInterval(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	interval, err := store.GetMinEntryInterval()
+	if err != nil || interval != 0 {
+		t.Errorf("Expected 0 when unset, got %v (err %v)", interval, err)
+	}
+
+	store.SetSetting(SettingMinEntryInterval, "15")
+	if interval, _ := store.GetMinEntryInterval(); interval != 15*time.Minute {
+		t.Errorf("Expected 15m, got %v", interval)
+	}
+
+	store.SetSetting(SettingMinEntryInterval, "soon")
+	if _, err := store.GetMinEntryInterval(); err == nil {
+		t.Error("Expected error for non-numeric interval")
+	}
+}
+
+func TestGetMaxMessageLength(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	length, err := store.GetMaxMessageLength()
+	if err != nil || length != DefaultMaxMessageLength {
+		t.Errorf("Expected default %d when unset, got %d (err %v)", DefaultMaxMessageLength, length, err)
+	}
+
+	store.SetSetting(SettingMaxMessageLength, "0")
+	if length, _ := store.GetMaxMessageLength(); length != 0 {
+		t.Errorf("Expected 0 (unlimited), got %d", length)
+	}
+
+	store.SetSetting(SettingMaxMessageLength, "huge")
+	if _, err := store.GetMaxMessageLength(); err == nil {
+		t.Error("Expected error for non-numeric length")
+	}
+}
+
+func TestGetMaxSessionMinutes(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	if minutes, err := store.GetMaxSessionMinutes(); err != nil || minutes != 0 {
+		t.Errorf("Expected 0 (no cap) when unset, got %d (err %v)", minutes, err)
+	}
+
+	store.SetSetting(SettingMaxSessionMinutes, "240")
+	if minutes, _ := store.GetMaxSessionMinutes(); minutes != 240 {
+		t.Errorf("Expected 240, got %d", minutes)
+	}
+
+	store.SetSetting(SettingMaxSessionMinutes, "4h")
+	if _, err := store.GetMaxSessionMinutes(); err == nil {
+		t.Error("Expected error for non-numeric minutes")
+	}
+}
+
+func TestGetSessionGap(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	if gap, err := store.GetSessionGap(); err != nil || gap != 0 {
+		t.Errorf("Expected 0 (strategy default) when unset, got %v (err %v)", gap, err)
+	}
+
+	store.SetSetting(SettingSessionGapMinutes, "90")
+	if gap, _ := store.GetSessionGap(); gap != 90*time.Minute {
+		t.Errorf("Expected 90m, got %v", gap)
+	}
+
+	store.SetSetting(SettingSessionGapMinutes, "1h")
+	if _, err := store.GetSessionGap(); err == nil {
+		t.Error("Expected error for non-numeric minutes")
+	}
+}
+
+func TestGetSummarizeOptions(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	opts, err := store.GetSummarizeOptions()
+	if err != nil {
+		t.Fatalf("GetSummarizeOptions() error = %v", err)
+	}
+	if len(opts.ExcludePatterns) != 2 || opts.IncludeBodies || opts.UseTrailers || opts.MaxSessionMinutes != 0 {
+		t.Errorf("Expected the default exclude patterns and nothing else, got %+v", opts)
+	}
+
+	store.SetSetting(SettingCommitExcludePatterns, "wip")
+	store.SetSetting(SettingIncludeCommitBodies, "true")
+	store.SetSetting(SettingDailySubtotals, "true")
+	store.SetSetting(SettingMaxSessionMinutes, "240")
+	store.SetSetting(SettingShortHashLength, "10")
+
+	opts, _ = store.GetSummarizeOptions()
+	if len(opts.ExcludePatterns) != 1 || !opts.IncludeBodies || !opts.DailySubtotals || opts.GroupConventional ||
+		opts.MaxSessionMinutes != 240 || opts.ShortHashLength != 10 {
+		t.Errorf("Expected the configured settings, got %+v", opts)
+	}
+
+	store.SetSetting(SettingSessionGapMinutes, "1h")
+	if _, err := store.GetSummarizeOptions(); err == nil {
+		t.Error("Expected an invalid setting to fail")
+	}
+}
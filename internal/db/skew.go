@@ -0,0 +1,64 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// DefaultSkewTolerance is how far timestamps may disagree before FindClockSkewEntries flags an entry
+const DefaultSkewTolerance = 5 * time.Minute
+
+// Clock skew reasons reported by FindClockSkewEntries
+const (
+	SkewFutureCreatedAt      = "created_at is in the future"
+	SkewUpdatedBeforeCreated = "updated_at precedes created_at"
+)
+
+// ClockSkewEntry is an entry with suspicious timestamps and the reasons it was flagged
+type ClockSkewEntry struct {
+	Entry   *models.Entry `json:"entry"`
+	Reasons []string      `json:"reasons"`
+}
+
+// FindClockSkewEntries returns entries whose CreatedAt is after now, or whose UpdatedAt precedes
+// CreatedAt, by more than tolerance, oldest first. These usually come from a mistyped created_at
+// and sort above everything logged since; nothing is modified.
+func (s *Store) FindClockSkewEntries(now time.Time, tolerance time.Duration) ([]ClockSkewEntry, error) {
+	var skewed []ClockSkewEntry
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(entriesBucket)).ForEach(func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+
+			var reasons []string
+			if entry.CreatedAt.Sub(now) > tolerance {
+				reasons = append(reasons, SkewFutureCreatedAt)
+			}
+			if entry.CreatedAt.Sub(entry.UpdatedAt) > tolerance {
+				reasons = append(reasons, SkewUpdatedBeforeCreated)
+			}
+			if len(reasons) > 0 {
+				skewed = append(skewed, ClockSkewEntry{Entry: &entry, Reasons: reasons})
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to check entry timestamps: %w", err)
+	}
+
+	sort.Slice(skewed, func(i, j int) bool {
+		return skewed[i].Entry.CreatedAt.Before(skewed[j].Entry.CreatedAt)
+	})
+
+	return skewed, nil
+}
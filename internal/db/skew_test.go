@@ -0,0 +1,68 @@
+package db
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+func TestFindClockSkewEntries(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	now := time.Now()
+
+	// Normal entries: current, backdated, and slightly ahead within tolerance
+	store.CreateEntry(project.ID, 30, "Now", "", false, now)
+	store.CreateEntry(project.ID, 30, "Backdated", "", false, now.Add(-72*time.Hour))
+	store.CreateEntry(project.ID, 30, "Within tolerance", "", false, now.Add(2*time.Minute))
+
+	// Future entry: both in the future and ahead of its own UpdatedAt
+	future, _ := store.CreateEntry(project.ID, 30, "Next year", "", false, now.AddDate(1, 0, 0))
+
+	// Past entry whose UpdatedAt was recorded before its CreatedAt (e.g. written on a skewed clock)
+	skewed := models.Entry{
+		ID:        "skewed",
+		ProjectID: project.ID,
+		Duration:  30,
+		CreatedAt: now.Add(-time.Hour),
+		UpdatedAt: now.Add(-3 * time.Hour),
+	}
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(skewed)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket([]byte(entriesBucket)).Put([]byte(skewed.ID), data)
+	})
+	if err != nil {
+		t.Fatalf("Failed to write skewed entry: %v", err)
+	}
+
+	flagged, err := store.FindClockSkewEntries(now, DefaultSkewTolerance)
+	if err != nil {
+		t.Fatalf("FindClockSkewEntries() error = %v", err)
+	}
+
+	if len(flagged) != 2 {
+		t.Fatalf("Expected 2 flagged entries, got %d: %+v", len(flagged), flagged)
+	}
+
+	if flagged[0].Entry.ID != skewed.ID || len(flagged[0].Reasons) != 1 || flagged[0].Reasons[0] != SkewUpdatedBeforeCreated {
+		t.Errorf("Expected skewed entry first with updated_at reason, got %+v", flagged[0])
+	}
+
+	if flagged[1].Entry.ID != future.ID || len(flagged[1].Reasons) != 2 || flagged[1].Reasons[0] != SkewFutureCreatedAt {
+		t.Errorf("Expected future entry with both reasons, got %+v", flagged[1])
+	}
+
+	// A wider tolerance lets the skewed entry through
+	flagged, _ = store.FindClockSkewEntries(now, 4*time.Hour)
+	if len(flagged) != 1 || flagged[0].Entry.ID != future.ID {
+		t.Errorf("Expected only the future entry with a 4h tolerance, got %+v", flagged)
+	}
+}
@@ -3,12 +3,17 @@ package db
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/stats"
+	"github.com/techthos/clockwork/internal/utils"
 	bolt "go.etcd.io/bbolt"
 )
 
@@ -17,7 +22,18 @@ const (
 	entriesBucket  = "entries"
 )
 
+// storeBuckets lists the buckets New creates
+var storeBuckets = []string{projectsBucket, entriesBucket, settingsBucket, projectHistoryBucket, timersBucket, trashBucket, auditBucket, entryIndexBucket, invoiceCountersBucket, authorRatesBucket, metaBucket}
+
 // Store manages database operations for clockwork
+//
+// A Store is safe for concurrent use and relies on bbolt's transactions rather than
+// its own locking: every mutating method does its reads, checks, and writes inside a
+// single db.Update, which bbolt runs one at a time, so read-modify-write updates
+// cannot lose each other's changes. Reads such as ListEntriesFiltered and
+// GetStatistics run in one db.View and see a consistent snapshot, never a
+// half-applied write. Values read outside a write transaction (settings such as
+// max_message_length) only tune validation and are not relied on for consistency.
 type Store struct {
 	db *bolt.DB
 }
@@ -38,17 +54,64 @@ func New(dbPath string) (*Store, error) {
 
 	// Initialize buckets
 	err = db.Update(func(tx *bolt.Tx) error {
-		if _, err := tx.CreateBucketIfNotExists([]byte(projectsBucket)); err != nil {
-			return err
+		// Databases from before the entry index get it built on first open
+		missingIndex := tx.Bucket([]byte(entryIndexBucket)) == nil
+
+		for _, name := range storeBuckets {
+			if _, err := tx.CreateBucketIfNotExists([]byte(name)); err != nil {
+				return err
+			}
 		}
-		if _, err := tx.CreateBucketIfNotExists([]byte(entriesBucket)); err != nil {
-			return err
+
+		if missingIndex {
+			if err := rebuildEntryIndex(tx); err != nil {
+				return err
+			}
+		}
+		return migrate(tx)
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// NewReadOnly opens an existing database, typically a replica copied from the live one, for reads only
+// Writes fail with bolt.ErrDatabaseReadOnly. The live database holds an exclusive lock while the
+// server or TUI runs, so point this at a copy; it reflects the live data only as of the copy.
+func NewReadOnly(dbPath string) (*Store, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	db, err := bolt.Open(dbPath, 0600, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	// Buckets cannot be created read-only, so the file must already be a clockwork database
+	err = db.View(func(tx *bolt.Tx) error {
+		for _, name := range storeBuckets {
+			if tx.Bucket([]byte(name)) == nil {
+				return fmt.Errorf("missing bucket %q (open it once with clockwork to initialize it)", name)
+			}
 		}
 		return nil
 	})
 	if err != nil {
 		db.Close()
-		return nil, fmt.Errorf("failed to create buckets: %w", err)
+		return nil, fmt.Errorf("not a clockwork database: %w", err)
+	}
+
+	// Older schemas are read as they are; migrations only run on a writable open
+	err = db.View(func(tx *bolt.Tx) error {
+		return checkSchemaVersion(readSchemaVersion(tx))
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 
 	return &Store{db: db}, nil
@@ -59,8 +122,42 @@ func (s *Store) Close() error {
 	return s.db.Close()
 }
 
+// Path returns the path of the database file
+func (s *Store) Path() string {
+	return s.db.Path()
+}
+
+// ReadOnly reports whether the database was opened read-only (see NewReadOnly)
+func (s *Store) ReadOnly() bool {
+	return s.db.IsReadOnly()
+}
+
+// RepoChecker rejects a project repository path that cannot be used, e.g. one that is not a git repository
+type RepoChecker func(path string) error
+
 // CreateProject creates a new project
 func (s *Store) CreateProject(name, gitRepoPath string) (*models.Project, error) {
+	return s.CreateProjectWithCheck(name, gitRepoPath, nil)
+}
+
+// CreateProjectWithCheck creates a new project after check accepts its repository path
+// A nil check accepts any path
+func (s *Store) CreateProjectWithCheck(name, gitRepoPath string, check RepoChecker) (*models.Project, error) {
+	return s.CreateProjectWithOptions(name, gitRepoPath, check, ProjectOptions{})
+}
+
+// CreateProjectWithOptions is CreateProjectWithCheck with the project's optional settings
+// opts is validated before anything is written, and the project is created with it in one transaction
+func (s *Store) CreateProjectWithOptions(name, gitRepoPath string, check RepoChecker, opts ProjectOptions) (*models.Project, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if check != nil {
+		if err := check(gitRepoPath); err != nil {
+			return nil, fmt.Errorf("invalid git repository: %w", err)
+		}
+	}
+
 	project := &models.Project{
 		ID:          uuid.New().String(),
 		Name:        name,
@@ -70,12 +167,19 @@ func (s *Store) CreateProject(name, gitRepoPath string) (*models.Project, error)
 	}
 
 	err := s.db.Update(func(tx *bolt.Tx) error {
+		if err := opts.apply(tx, project); err != nil {
+			return err
+		}
+
 		b := tx.Bucket([]byte(projectsBucket))
 		data, err := json.Marshal(project)
 		if err != nil {
 			return err
 		}
-		return b.Put([]byte(project.ID), data)
+		if err := b.Put([]byte(project.ID), data); err != nil {
+			return err
+		}
+		return recordAudit(tx, AuditCreate, AuditTargetProject, project.ID, diffProject(&models.Project{}, project), "")
 	})
 
 	if err != nil {
@@ -107,6 +211,155 @@ func (s *Store) GetProject(id string) (*models.Project, error) {
 
 // UpdateProject updates an existing project
 func (s *Store) UpdateProject(id, name, gitRepoPath string) (*models.Project, error) {
+	return s.UpdateProjectWithCheck(id, name, gitRepoPath, nil)
+}
+
+// UpdateProjectWithCheck is UpdateProject with a new repository path checked by check first
+// A nil check accepts any path
+func (s *Store) UpdateProjectWithCheck(id, name, gitRepoPath string, check RepoChecker) (*models.Project, error) {
+	return s.UpdateProjectWithOptions(id, name, gitRepoPath, check, ProjectOptions{})
+}
+
+// UpdateProjectWithOptions is UpdateProjectWithCheck that also changes the settings set in opts
+// opts is validated before anything is written, and all changes share one transaction,
+// history record, and audit record
+func (s *Store) UpdateProjectWithOptions(id, name, gitRepoPath string, check RepoChecker, opts ProjectOptions) (*models.Project, error) {
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if gitRepoPath != "" && check != nil {
+		if err := check(gitRepoPath); err != nil {
+			return nil, fmt.Errorf("invalid git repository: %w", err)
+		}
+	}
+	return s.modifyProjectTx(id, func(tx *bolt.Tx, project *models.Project) error {
+		if name != "" {
+			project.Name = name
+		}
+		if gitRepoPath != "" {
+			project.GitRepoPath = gitRepoPath
+		}
+		return opts.apply(tx, project)
+	})
+}
+
+// ProjectOptions holds optional project settings to store along with a create or update
+// Nil fields are left unchanged
+type ProjectOptions struct {
+	DurationMethod      *string  // Default duration strategy; "" resets to the global default
+	RecordHeadForManual *bool    // Store HEAD on manual entries
+	OwnCommitsOnly      *bool    // Aggregate only the local user's commits
+	HourlyRate          *float64 // 0 stops pricing the project's time
+	Currency            *string  // Currency code of the hourly rate
+	BudgetMinutes       *int64   // Fixed-bid budget; 0 removes it
+	AutoSchedule        *string  // When the server logs git entries by itself; "" turns it off
+}
+
+// validate checks opts without touching the database, so a bad value fails before any write
+func (opts ProjectOptions) validate() error {
+	if rate := opts.HourlyRate; rate != nil && (*rate < 0 || math.IsNaN(*rate) || math.IsInf(*rate, 0)) {
+		return fmt.Errorf("hourly rate must be a non-negative number")
+	}
+	if opts.BudgetMinutes != nil && *opts.BudgetMinutes < 0 {
+		return fmt.Errorf("budget must not be negative")
+	}
+	if opts.AutoSchedule != nil {
+		if schedule := strings.TrimSpace(*opts.AutoSchedule); schedule != "" {
+			if _, err := utils.ParseSchedule(schedule); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// apply sets the given options on project inside tx
+// A new auto schedule starts from now, as with SetProjectAutoSchedule
+func (opts ProjectOptions) apply(tx *bolt.Tx, project *models.Project) error {
+	if opts.DurationMethod != nil {
+		project.DurationMethod = *opts.DurationMethod
+	}
+	if opts.RecordHeadForManual != nil {
+		record := *opts.RecordHeadForManual
+		project.RecordHeadForManual = &record
+	}
+	if opts.OwnCommitsOnly != nil {
+		project.OwnCommitsOnly = *opts.OwnCommitsOnly
+	}
+	if opts.HourlyRate != nil {
+		project.HourlyRate = *opts.HourlyRate
+	}
+	if opts.Currency != nil {
+		project.Currency = strings.ToUpper(strings.TrimSpace(*opts.Currency))
+	}
+	if opts.BudgetMinutes != nil {
+		project.BudgetMinutes = *opts.BudgetMinutes
+	}
+	if opts.AutoSchedule != nil {
+		project.AutoSchedule = strings.TrimSpace(*opts.AutoSchedule)
+		if project.AutoSchedule != "" {
+			settings := tx.Bucket([]byte(settingsBucket))
+			if err := settings.Put([]byte(lastAutoRunPrefix+project.ID), []byte(time.Now().Format(time.RFC3339Nano))); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// SetProjectDurationMethod sets the project's default duration estimation strategy
+// An empty method resets the project to the global default
+func (s *Store) SetProjectDurationMethod(id, method string) (*models.Project, error) {
+	return s.modifyProject(id, func(project *models.Project) error {
+		project.DurationMethod = method
+		return nil
+	})
+}
+
+// SetProjectRecordHeadForManual sets whether manual entries for the project store the repo's HEAD
+func (s *Store) SetProjectRecordHeadForManual(id string, record bool) (*models.Project, error) {
+	return s.modifyProject(id, func(project *models.Project) error {
+		project.RecordHeadForManual = &record
+		return nil
+	})
+}
+
+// SetProjectOwnCommitsOnly sets whether git entries for the project aggregate only the local user's commits
+func (s *Store) SetProjectOwnCommitsOnly(id string, ownOnly bool) (*models.Project, error) {
+	return s.modifyProject(id, func(project *models.Project) error {
+		project.OwnCommitsOnly = ownOnly
+		return nil
+	})
+}
+
+// ArchiveProject hides a project from default project lists, keeping it and its entries
+// Use DeleteProject to remove a project for good
+func (s *Store) ArchiveProject(id string) (*models.Project, error) {
+	return s.setProjectArchived(id, true)
+}
+
+// UnarchiveProject lists an archived project again
+func (s *Store) UnarchiveProject(id string) (*models.Project, error) {
+	return s.setProjectArchived(id, false)
+}
+
+func (s *Store) setProjectArchived(id string, archived bool) (*models.Project, error) {
+	return s.modifyProject(id, func(project *models.Project) error {
+		project.Archived = archived
+		return nil
+	})
+}
+
+// modifyProject loads a project, applies mutate, and saves it in one transaction
+// Changed fields are appended to the project history in the same transaction
+func (s *Store) modifyProject(id string, mutate func(project *models.Project) error) (*models.Project, error) {
+	return s.modifyProjectTx(id, func(_ *bolt.Tx, project *models.Project) error {
+		return mutate(project)
+	})
+}
+
+// modifyProjectTx is modifyProject for mutations that also write other buckets in the transaction
+func (s *Store) modifyProjectTx(id string, mutate func(tx *bolt.Tx, project *models.Project) error) (*models.Project, error) {
 	var project models.Project
 
 	err := s.db.Update(func(tx *bolt.Tx) error {
@@ -120,14 +373,21 @@ func (s *Store) UpdateProject(id, name, gitRepoPath string) (*models.Project, er
 			return err
 		}
 
-		if name != "" {
-			project.Name = name
-		}
-		if gitRepoPath != "" {
-			project.GitRepoPath = gitRepoPath
+		before := project
+		if err := mutate(tx, &project); err != nil {
+			return err
 		}
 		project.UpdatedAt = time.Now()
 
+		if err := recordProjectChange(tx, &before, &project, project.UpdatedAt); err != nil {
+			return err
+		}
+		if changes := diffProject(&before, &project); len(changes) > 0 {
+			if err := recordAudit(tx, AuditUpdate, AuditTargetProject, id, changes, ""); err != nil {
+				return err
+			}
+		}
+
 		updatedData, err := json.Marshal(project)
 		if err != nil {
 			return err
@@ -148,13 +408,54 @@ func (s *Store) DeleteProject(id string) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
 		// Delete project
 		pb := tx.Bucket([]byte(projectsBucket))
+		var project models.Project
+		data := pb.Get([]byte(id))
+		if data != nil {
+			if err := json.Unmarshal(data, &project); err != nil {
+				return err
+			}
+		}
 		if err := pb.Delete([]byte(id)); err != nil {
 			return err
 		}
 
+		// Clear the default project if it pointed here
+		sb := tx.Bucket([]byte(settingsBucket))
+		if string(sb.Get([]byte(SettingDefaultProject))) == id {
+			if err := sb.Delete([]byte(SettingDefaultProject)); err != nil {
+				return err
+			}
+		}
+
+		// Forget the project's export marker
+		if err := sb.Delete([]byte(lastExportPrefix + id)); err != nil {
+			return err
+		}
+
+		// Delete the project's running timer
+		if err := tx.Bucket([]byte(timersBucket)).Delete([]byte(id)); err != nil {
+			return err
+		}
+
+		// Delete the project's change history
+		hb := tx.Bucket([]byte(projectHistoryBucket))
+		hc := hb.Cursor()
+		for k, v := hc.First(); k != nil; k, v = hc.Next() {
+			var change models.ProjectChange
+			if err := json.Unmarshal(v, &change); err != nil {
+				continue
+			}
+			if change.ProjectID == id {
+				if err := hb.Delete(k); err != nil {
+					return err
+				}
+			}
+		}
+
 		// Delete associated entries
 		eb := tx.Bucket([]byte(entriesBucket))
 		c := eb.Cursor()
+		deletedEntries := 0
 		for k, v := c.First(); k != nil; k, v = c.Next() {
 			var entry models.Entry
 			if err := json.Unmarshal(v, &entry); err != nil {
@@ -164,15 +465,23 @@ func (s *Store) DeleteProject(id string) error {
 				if err := eb.Delete(k); err != nil {
 					return err
 				}
+				if err := unindexEntry(tx, id, entry.ID); err != nil {
+					return err
+				}
+				deletedEntries++
 			}
 		}
 
-		return nil
+		if data == nil {
+			return nil
+		}
+		detail := fmt.Sprintf("deleted with %d entries", deletedEntries)
+		return recordAudit(tx, AuditDelete, AuditTargetProject, id, diffProject(&project, &models.Project{}), detail)
 	})
 }
 
-// ListProjects returns all projects
-func (s *Store) ListProjects() ([]*models.Project, error) {
+// ListProjects returns all projects, leaving out archived ones unless includeArchived is set
+func (s *Store) ListProjects(includeArchived bool) ([]*models.Project, error) {
 	var projects []*models.Project
 
 	err := s.db.View(func(tx *bolt.Tx) error {
@@ -182,6 +491,9 @@ func (s *Store) ListProjects() ([]*models.Project, error) {
 			if err := json.Unmarshal(v, &project); err != nil {
 				return err
 			}
+			if project.Archived && !includeArchived {
+				return nil
+			}
 			projects = append(projects, &project)
 			return nil
 		})
@@ -194,26 +506,81 @@ func (s *Store) ListProjects() ([]*models.Project, error) {
 	return projects, nil
 }
 
+// ProjectLastActivity returns the newest entry CreatedAt per project in a single scan
+// Projects without entries are absent from the map
+func (s *Store) ProjectLastActivity() (map[string]time.Time, error) {
+	lastActivity := make(map[string]time.Time)
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(entriesBucket))
+		return b.ForEach(func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if last, ok := lastActivity[entry.ProjectID]; !ok || entry.CreatedAt.After(last) {
+				lastActivity[entry.ProjectID] = entry.CreatedAt
+			}
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute project activity: %w", err)
+	}
+
+	return lastActivity, nil
+}
+
+// checkCommitHash rejects full-length hashes showing known corruption patterns
+func checkCommitHash(commitHash string) error {
+	if commitHash == "" || len(commitHash) < 40 {
+		return nil
+	}
+
+	// Check for suspicious patterns in full-length hashes
+	// This specifically catches the e8e8e8e8 corruption bug
+	if commitHash[:20] == commitHash[20:40] {
+		return fmt.Errorf("invalid commit hash: repeated pattern detected - possible corruption (hash: %s)", commitHash)
+	}
+	// Check for the specific e8 repetition pattern
+	if len(commitHash) == 40 && commitHash[20:] == "e8e8e8e8e8e8e8e8e8e8" {
+		return fmt.Errorf("invalid commit hash: e8e8 corruption pattern detected (hash: %s)", commitHash)
+	}
+	return nil
+}
+
+// EntryOptions holds the optional fields of a new entry, stored in the same transaction
+type EntryOptions struct {
+	Mode     string   // How the entry was created (e.g. models.EntryModeGit)
+	Author   string   // Who the entry is attributed to
+	Tags     []string // Normalized before storing
+	Estimate int64    // Estimated duration in minutes; 0 leaves it unset
+}
+
 // CreateEntry creates a new worklog entry
 func (s *Store) CreateEntry(projectID string, duration int64, message, commitHash string, invoiced bool, createdAt time.Time) (*models.Entry, error) {
-	// Verify project exists
-	if _, err := s.GetProject(projectID); err != nil {
-		return nil, fmt.Errorf("project not found: %w", err)
-	}
+	return s.CreateEntryWithOptions(projectID, duration, message, commitHash, invoiced, createdAt, EntryOptions{})
+}
 
+// CreateEntryWithOptions creates a new worklog entry with the given optional fields
+// The entry is written in one transaction with a single audit record
+func (s *Store) CreateEntryWithOptions(projectID string, duration int64, message, commitHash string, invoiced bool, createdAt time.Time, opts EntryOptions) (*models.Entry, error) {
 	// Validate commit hash for corruption patterns
-	if commitHash != "" && len(commitHash) >= 40 {
-		// Check for suspicious patterns in full-length hashes
-		// This specifically catches the e8e8e8e8 corruption bug
-		firstHalf := commitHash[:20]
-		secondHalf := commitHash[20:40]
-		if firstHalf == secondHalf {
-			return nil, fmt.Errorf("invalid commit hash: repeated pattern detected - possible corruption (hash: %s)", commitHash)
-		}
-		// Check for the specific e8 repetition pattern
-		if len(commitHash) == 40 && commitHash[20:] == "e8e8e8e8e8e8e8e8e8e8" {
-			return nil, fmt.Errorf("invalid commit hash: e8e8 corruption pattern detected (hash: %s)", commitHash)
-		}
+	if err := checkCommitHash(commitHash); err != nil {
+		return nil, err
+	}
+	if opts.Estimate < 0 {
+		return nil, fmt.Errorf("estimate cannot be negative")
+	}
+
+	// Keep pathological messages out of the database
+	limit, err := s.GetMaxMessageLength()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMessageLength(message, limit); err != nil {
+		return nil, err
 	}
 
 	entry := &models.Entry{
@@ -222,18 +589,44 @@ func (s *Store) CreateEntry(projectID string, duration int64, message, commitHas
 		Duration:   duration,
 		Message:    message,
 		CommitHash: commitHash,
+		Source:     hostname(),
 		Invoiced:   invoiced,
 		CreatedAt:  createdAt,
 		UpdatedAt:  time.Now(),
+
+		Mode:            opts.Mode,
+		Author:          strings.TrimSpace(opts.Author),
+		EstimateMinutes: opts.Estimate,
+	}
+	if len(opts.Tags) > 0 {
+		entry.Tags = models.NormalizeTags(opts.Tags)
 	}
 
-	err := s.db.Update(func(tx *bolt.Tx) error {
+	err = s.db.Update(func(tx *bolt.Tx) error {
+		// Verify the project exists in the same transaction, so a concurrent
+		// DeleteProject cannot leave the entry orphaned
+		if tx.Bucket([]byte(projectsBucket)).Get([]byte(projectID)) == nil {
+			return fmt.Errorf("project not found")
+		}
+
 		b := tx.Bucket([]byte(entriesBucket))
+		seq, err := b.NextSequence()
+		if err != nil {
+			return err
+		}
+		entry.Seq = seq
+
 		data, err := json.Marshal(entry)
 		if err != nil {
 			return err
 		}
-		return b.Put([]byte(entry.ID), data)
+		if err := b.Put([]byte(entry.ID), data); err != nil {
+			return err
+		}
+		if err := indexEntry(tx, projectID, entry.ID); err != nil {
+			return err
+		}
+		return recordAudit(tx, AuditCreate, AuditTargetEntry, entry.ID, diffEntry(&models.Entry{}, entry), "")
 	})
 
 	if err != nil {
@@ -267,6 +660,16 @@ func (s *Store) GetEntry(id string) (*models.Entry, error) {
 func (s *Store) UpdateEntry(id string, duration *int64, message, commitHash *string, invoiced *bool, createdAt *time.Time) (*models.Entry, error) {
 	var entry models.Entry
 
+	if message != nil {
+		limit, err := s.GetMaxMessageLength()
+		if err != nil {
+			return nil, err
+		}
+		if err := checkMessageLength(*message, limit); err != nil {
+			return nil, err
+		}
+	}
+
 	err := s.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(entriesBucket))
 		data := b.Get([]byte(id))
@@ -277,6 +680,7 @@ func (s *Store) UpdateEntry(id string, duration *int64, message, commitHash *str
 		if err := json.Unmarshal(data, &entry); err != nil {
 			return err
 		}
+		before := entry
 
 		if duration != nil {
 			entry.Duration = *duration
@@ -286,23 +690,13 @@ func (s *Store) UpdateEntry(id string, duration *int64, message, commitHash *str
 		}
 		if commitHash != nil {
 			// Validate commit hash for corruption patterns
-			newHash := *commitHash
-			if newHash != "" && len(newHash) >= 40 {
-				// Check for suspicious patterns in full-length hashes
-				firstHalf := newHash[:20]
-				secondHalf := newHash[20:40]
-				if firstHalf == secondHalf {
-					return fmt.Errorf("invalid commit hash: repeated pattern detected - possible corruption (hash: %s)", newHash)
-				}
-				// Check for the specific e8 repetition pattern
-				if len(newHash) == 40 && newHash[20:] == "e8e8e8e8e8e8e8e8e8e8" {
-					return fmt.Errorf("invalid commit hash: e8e8 corruption pattern detected (hash: %s)", newHash)
-				}
+			if err := checkCommitHash(*commitHash); err != nil {
+				return err
 			}
-			entry.CommitHash = newHash
+			entry.CommitHash = *commitHash
 		}
 		if invoiced != nil {
-			entry.Invoiced = *invoiced
+			setInvoiced(&entry, *invoiced)
 		}
 		if createdAt != nil {
 			entry.CreatedAt = *createdAt
@@ -314,7 +708,182 @@ func (s *Store) UpdateEntry(id string, duration *int64, message, commitHash *str
 			return err
 		}
 
-		return b.Put([]byte(id), updatedData)
+		if err := b.Put([]byte(id), updatedData); err != nil {
+			return err
+		}
+		return recordEntryUpdate(tx, &before, &entry)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update entry: %w", err)
+	}
+
+	return &entry, nil
+}
+
+// SetEntryTags replaces the tags of an existing entry
+func (s *Store) SetEntryTags(id string, tags []string) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.Tags = models.NormalizeTags(tags)
+		return nil
+	})
+}
+
+// SetEntryLocked locks or unlocks an entry
+// Locked entries are skipped by bulk deletes
+func (s *Store) SetEntryLocked(id string, locked bool) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.Locked = locked
+		return nil
+	})
+}
+
+// SetEntryPinned pins or unpins an entry
+// Pinned entries head sorted lists with EntryFilter.PinnedFirst; billing is unaffected
+func (s *Store) SetEntryPinned(id string, pinned bool) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.Pinned = pinned
+		return nil
+	})
+}
+
+// SetEntryAuthor sets the author an entry is attributed to
+func (s *Store) SetEntryAuthor(id, author string) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.Author = strings.TrimSpace(author)
+		return nil
+	})
+}
+
+// SetEntryMode records how an entry was created (e.g. models.EntryModeGit)
+func (s *Store) SetEntryMode(id, mode string) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.Mode = mode
+		return nil
+	})
+}
+
+// ExtendEntry adds duration to an entry, appends message on a new line, and moves it to commitHash
+// Used to fold a later git aggregation into an earlier entry from the same day; opts adds its
+// tags and replaces the estimate when set, while the entry keeps its mode and author
+func (s *Store) ExtendEntry(id string, duration int64, message, commitHash string, opts EntryOptions) (*models.Entry, error) {
+	if opts.Estimate < 0 {
+		return nil, fmt.Errorf("estimate cannot be negative")
+	}
+	limit, err := s.GetMaxMessageLength()
+	if err != nil {
+		return nil, err
+	}
+
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		if entry.Locked {
+			return fmt.Errorf("entry is locked")
+		}
+		entry.Duration += duration
+		if message != "" {
+			if entry.Message != "" {
+				entry.Message = strings.TrimRight(entry.Message, "\n") + "\n"
+			}
+			entry.Message += message
+			if err := checkMessageLength(entry.Message, limit); err != nil {
+				return err
+			}
+		}
+		if commitHash != "" {
+			entry.CommitHash = commitHash
+		}
+		if len(opts.Tags) > 0 {
+			entry.Tags = models.NormalizeTags(append(append([]string(nil), entry.Tags...), opts.Tags...))
+		}
+		if opts.Estimate > 0 {
+			entry.EstimateMinutes = opts.Estimate
+		}
+		return nil
+	})
+}
+
+// SetEntryEstimate sets the estimated duration of an entry in minutes; 0 clears the estimate
+func (s *Store) SetEntryEstimate(id string, minutes int64) (*models.Entry, error) {
+	if minutes < 0 {
+		return nil, fmt.Errorf("estimate cannot be negative")
+	}
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.EstimateMinutes = minutes
+		return nil
+	})
+}
+
+// SetEntryReference sets the ticket or issue reference of an entry
+func (s *Store) SetEntryReference(id, reference string) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.Reference = strings.TrimSpace(reference)
+		return nil
+	})
+}
+
+// SetEntryCategory sets the work category of an entry
+func (s *Store) SetEntryCategory(id, category string) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.Category = strings.TrimSpace(category)
+		return nil
+	})
+}
+
+// SetEntryLocation sets where the work of an entry happened
+func (s *Store) SetEntryLocation(id, location string) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.Location = strings.TrimSpace(location)
+		return nil
+	})
+}
+
+// SetEntrySource sets the machine an entry is attributed to, overriding the detected hostname
+func (s *Store) SetEntrySource(id, source string) (*models.Entry, error) {
+	return s.modifyEntry(id, func(entry *models.Entry) error {
+		entry.Source = strings.TrimSpace(source)
+		return nil
+	})
+}
+
+// hostname returns this machine's name, recorded as the Source of new entries ("" when unknown)
+func hostname() string {
+	name, err := os.Hostname()
+	if err != nil {
+		return ""
+	}
+	return name
+}
+
+// modifyEntry loads an entry, applies mutate, and saves it in one transaction
+func (s *Store) modifyEntry(id string, mutate func(entry *models.Entry) error) (*models.Entry, error) {
+	var entry models.Entry
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(entriesBucket))
+		data := b.Get([]byte(id))
+		if data == nil {
+			return fmt.Errorf("entry not found")
+		}
+
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+
+		before := entry
+		if err := mutate(&entry); err != nil {
+			return err
+		}
+		entry.UpdatedAt = time.Now()
+
+		updatedData, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		if err := b.Put([]byte(id), updatedData); err != nil {
+			return err
+		}
+		return recordEntryUpdate(tx, &before, &entry)
 	})
 
 	if err != nil {
@@ -328,17 +897,90 @@ func (s *Store) UpdateEntry(id string, duration *int64, message, commitHash *str
 func (s *Store) DeleteEntry(id string) error {
 	return s.db.Update(func(tx *bolt.Tx) error {
 		b := tx.Bucket([]byte(entriesBucket))
-		return b.Delete([]byte(id))
+		data := b.Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+
+		var entry models.Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return err
+		}
+		if err := b.Delete([]byte(id)); err != nil {
+			return err
+		}
+		if err := unindexEntry(tx, entry.ProjectID, id); err != nil {
+			return err
+		}
+		return recordAudit(tx, AuditDelete, AuditTargetEntry, id, diffEntry(&entry, &models.Entry{}), "")
 	})
 }
 
+// RestoreEntry re-inserts a deleted entry under its original ID and sequence number, e.g. to undo DeleteEntry
+// Fails when the ID is taken or the entry's project no longer exists
+func (s *Store) RestoreEntry(entry *models.Entry) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(projectsBucket)).Get([]byte(entry.ProjectID)) == nil {
+			return fmt.Errorf("project not found")
+		}
+		return insertEntry(tx, entry)
+	})
+}
+
+// RestoreProject re-inserts a deleted project and its entries under their original IDs, e.g. to undo DeleteProject
+// The project's change history and settings pointing at it (default project, export marker) are not restored
+func (s *Store) RestoreProject(project *models.Project, entries []*models.Entry) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(projectsBucket))
+		if project.ID == "" {
+			return fmt.Errorf("project ID cannot be empty")
+		}
+		if b.Get([]byte(project.ID)) != nil {
+			return fmt.Errorf("project %s already exists", project.ID)
+		}
+
+		if err := putProject(b, project); err != nil {
+			return err
+		}
+
+		for _, entry := range entries {
+			if entry.ProjectID != project.ID {
+				return fmt.Errorf("entry %s belongs to another project", entry.ID)
+			}
+			if err := insertEntry(tx, entry); err != nil {
+				return err
+			}
+		}
+		detail := fmt.Sprintf("restored with %d entries", len(entries))
+		return recordAudit(tx, AuditCreate, AuditTargetProject, project.ID, diffProject(&models.Project{}, project), detail)
+	})
+}
+
+// insertEntry stores an entry under its existing ID, refusing to overwrite another one
+func insertEntry(tx *bolt.Tx, entry *models.Entry) error {
+	b := tx.Bucket([]byte(entriesBucket))
+	if entry.ID == "" {
+		return fmt.Errorf("entry ID cannot be empty")
+	}
+	if b.Get([]byte(entry.ID)) != nil {
+		return fmt.Errorf("entry %s already exists", entry.ID)
+	}
+
+	if err := putEntry(b, entry); err != nil {
+		return err
+	}
+	if err := indexEntry(tx, entry.ProjectID, entry.ID); err != nil {
+		return err
+	}
+	return recordAudit(tx, AuditCreate, AuditTargetEntry, entry.ID, diffEntry(&models.Entry{}, entry), "restored")
+}
+
 // ListEntries returns all entries for a project
 func (s *Store) ListEntries(projectID string) ([]*models.Entry, error) {
 	var entries []*models.Entry
 
 	err := s.db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(entriesBucket))
-		return b.ForEach(func(k, v []byte) error {
+		return forEachEntry(tx, projectID, func(k, v []byte) error {
 			var entry models.Entry
 			if err := json.Unmarshal(v, &entry); err != nil {
 				return err
@@ -382,26 +1024,162 @@ func (s *Store) GetLastEntry(projectID string) (*models.Entry, error) {
 // GetLastCommitHash returns the most recent non-empty commit hash across all entries for a project.
 // Returns "" if no entry has a commit hash.
 func (s *Store) GetLastCommitHash(projectID string) (string, error) {
-	entries, err := s.ListEntries(projectID)
+	latest, err := s.GetLastCommitEntry(projectID)
 	if err != nil {
 		return "", err
 	}
 
+	if latest == nil {
+		return "", nil
+	}
+
+	return latest.CommitHash, nil
+}
+
+// GetLastCommitEntry returns the most recently created entry with a commit hash for a project.
+// This entry holds the baseline for the next git aggregation. Returns nil if none exists.
+// Entries are ordered by creation sequence, so a backdated entry cannot become the baseline.
+func (s *Store) GetLastCommitEntry(projectID string) (*models.Entry, error) {
+	entries, err := s.ListEntries(projectID)
+	if err != nil {
+		return nil, err
+	}
+
 	var latest *models.Entry
 	for _, entry := range entries {
 		if entry.CommitHash == "" {
 			continue
 		}
-		if latest == nil || entry.CreatedAt.After(latest.CreatedAt) {
+		if latest == nil || createdAfter(entry, latest) {
 			latest = entry
 		}
 	}
 
-	if latest == nil {
-		return "", nil
+	return latest, nil
+}
+
+// createdAfter reports whether entry a was created after entry b.
+// Uses the creation sequence when both have one; entries from before sequencing
+// are older than any sequenced entry and fall back to CreatedAt among themselves.
+func createdAfter(a, b *models.Entry) bool {
+	if a.Seq != 0 && b.Seq != 0 {
+		return a.Seq > b.Seq
+	}
+	if a.Seq != b.Seq {
+		return a.Seq != 0
 	}
+	return a.CreatedAt.After(b.CreatedAt)
+}
 
-	return latest.CommitHash, nil
+// sortByCreation orders entries oldest first by the createdAfter rule.
+// Copying entries between databases assigns local sequence numbers in this order,
+// which keeps each project's git baseline on the same entry. Nil entries sort first.
+func sortByCreation(entries []*models.Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i] == nil || entries[j] == nil {
+			return entries[i] == nil && entries[j] != nil
+		}
+		return createdAfter(entries[j], entries[i])
+	})
+}
+
+// matchesFilter reports whether an entry passes the project, date range, and invoiced filters
+func matchesFilter(entry *models.Entry, projectID string, startDate, endDate *time.Time, invoicedFilter *bool) bool {
+	// Filter by project (empty = all projects)
+	if projectID != "" && entry.ProjectID != projectID {
+		return false
+	}
+
+	// Filter by date range
+	if startDate != nil && entry.CreatedAt.Before(*startDate) {
+		return false
+	}
+	if endDate != nil && entry.CreatedAt.After(*endDate) {
+		return false
+	}
+
+	// Filter by invoiced status (nil = all entries)
+	if invoicedFilter != nil && entry.Invoiced != *invoicedFilter {
+		return false
+	}
+
+	return true
+}
+
+// MatchesLocation reports whether an entry's location equals location, ignoring case
+// An empty location matches every entry
+func MatchesLocation(entry *models.Entry, location string) bool {
+	location = strings.TrimSpace(location)
+	return location == "" || strings.EqualFold(entry.Location, location)
+}
+
+// FilterByLocation returns the entries whose location matches (see MatchesLocation)
+func FilterByLocation(entries []*models.Entry, location string) []*models.Entry {
+	if strings.TrimSpace(location) == "" {
+		return entries
+	}
+
+	var filtered []*models.Entry
+	for _, entry := range entries {
+		if MatchesLocation(entry, location) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// MatchesSource reports whether an entry's source equals source, ignoring case
+// An empty source matches every entry
+func MatchesSource(entry *models.Entry, source string) bool {
+	source = strings.TrimSpace(source)
+	return source == "" || strings.EqualFold(entry.Source, source)
+}
+
+// FilterBySource returns the entries whose source matches (see MatchesSource)
+func FilterBySource(entries []*models.Entry, source string) []*models.Entry {
+	if strings.TrimSpace(source) == "" {
+		return entries
+	}
+
+	var filtered []*models.Entry
+	for _, entry := range entries {
+		if MatchesSource(entry, source) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// MatchesTag reports whether an entry carries tag, ignoring case
+// An empty tag matches every entry
+func MatchesTag(entry *models.Entry, tag string) bool {
+	return strings.TrimSpace(tag) == "" || entry.HasTag(tag)
+}
+
+// FilterByTag returns the entries carrying tag (see MatchesTag)
+func FilterByTag(entries []*models.Entry, tag string) []*models.Entry {
+	if strings.TrimSpace(tag) == "" {
+		return entries
+	}
+
+	var filtered []*models.Entry
+	for _, entry := range entries {
+		if MatchesTag(entry, tag) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// FilterPinned returns the pinned entries
+func FilterPinned(entries []*models.Entry) []*models.Entry {
+	var pinned []*models.Entry
+	for _, entry := range entries {
+		if entry.Pinned {
+			pinned = append(pinned, entry)
+		}
+	}
+	return pinned
 }
 
 // ListEntriesFiltered returns entries with optional filtering
@@ -409,32 +1187,13 @@ func (s *Store) ListEntriesFiltered(projectID string, startDate, endDate *time.T
 	var entries []*models.Entry
 
 	err := s.db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(entriesBucket))
-		if b == nil {
-			return nil
-		}
-
-		return b.ForEach(func(k, v []byte) error {
+		return forEachEntry(tx, projectID, func(k, v []byte) error {
 			var entry models.Entry
 			if err := json.Unmarshal(v, &entry); err != nil {
 				return err
 			}
 
-			// Filter by project (empty = all projects)
-			if projectID != "" && entry.ProjectID != projectID {
-				return nil
-			}
-
-			// Filter by date range
-			if startDate != nil && entry.CreatedAt.Before(*startDate) {
-				return nil
-			}
-			if endDate != nil && entry.CreatedAt.After(*endDate) {
-				return nil
-			}
-
-			// Filter by invoiced status (nil = all entries)
-			if invoicedFilter != nil && entry.Invoiced != *invoicedFilter {
+			if !matchesFilter(&entry, projectID, startDate, endDate, invoicedFilter) {
 				return nil
 			}
 
@@ -450,6 +1209,69 @@ func (s *Store) ListEntriesFiltered(projectID string, startDate, endDate *time.T
 	return entries, nil
 }
 
+// EntryPage is one page of the entries matching a filter, returned by ListEntriesPage
+type EntryPage struct {
+	Entries []*models.Entry `json:"entries"`
+	Offset  int             `json:"offset"`
+	// Totals over every matching entry, not just this page
+	Total           int   `json:"total"`
+	TotalMinutes    int64 `json:"total_minutes"`
+	InvoicedMinutes int64 `json:"invoiced_minutes"`
+}
+
+// ListEntriesPage returns up to limit entries matching filter, in filter.SortBy order, from offset on
+// Like StreamExport it sorts only keys and decodes just the entries on the page, so paging
+// through a long history does not load all of it. A limit <= 0 returns everything from
+// offset on; an offset past the end returns an empty page. Full ties keep entry ID order,
+// so pages do not overlap or skip entries between calls.
+func (s *Store) ListEntriesPage(filter EntryFilter, offset, limit int) (*EntryPage, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+	if !ValidSortKey(filter.SortBy) {
+		return nil, fmt.Errorf("unknown sort order %q (use one of: %s)", filter.SortBy, strings.Join(SortKeys, ", "))
+	}
+
+	page := &EntryPage{Entries: []*models.Entry{}, Offset: offset}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		keys, err := collectEntryKeys(tx, filter)
+		if err != nil {
+			return err
+		}
+
+		page.Total = len(keys)
+		for _, k := range keys {
+			page.TotalMinutes += k.duration
+			if k.invoiced {
+				page.InvoicedMinutes += k.duration
+			}
+		}
+		if offset >= len(keys) {
+			return nil
+		}
+		keys = keys[offset:]
+		if limit > 0 && limit < len(keys) {
+			keys = keys[:limit]
+		}
+
+		eb := tx.Bucket([]byte(entriesBucket))
+		for _, k := range keys {
+			var entry models.Entry
+			if err := json.Unmarshal(eb.Get(k.key), &entry); err != nil {
+				return err
+			}
+			page.Entries = append(page.Entries, &entry)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list entries: %w", err)
+	}
+
+	return page, nil
+}
+
 // Statistics represents aggregated entry statistics
 type Statistics struct {
 	TotalMinutes      int64            `json:"total_minutes"`
@@ -458,23 +1280,37 @@ type Statistics struct {
 	InvoicedMinutes   int64            `json:"invoiced_minutes"`
 	UninvoicedMinutes int64            `json:"uninvoiced_minutes"`
 	ProjectBreakdown  map[string]int64 `json:"project_breakdown"` // projectID -> minutes
+	TagBreakdown      map[string]int64 `json:"tag_breakdown"`     // tag -> minutes; entries with several tags count towards each
 	EarliestEntry     *time.Time       `json:"earliest_entry,omitempty"`
 	LatestEntry       *time.Time       `json:"latest_entry,omitempty"`
+
+	// Billing, priced per project from its hourly rate
+	Amounts         map[string]float64 `json:"amounts,omitempty"`          // Currency -> amount, rounded to cents ("" = no currency)
+	TotalAmount     float64            `json:"total_amount"`               // Sum of Amounts when they share one currency, else 0
+	Currency        string             `json:"currency,omitempty"`         // Currency of TotalAmount
+	UnpricedMinutes int64              `json:"unpriced_minutes,omitempty"` // Time in projects without an hourly rate
+
+	// Time series, filled in from GetPeriodTotals when statistics are grouped
+	Periods []PeriodTotal `json:"periods,omitempty"`
+	// Minutes per day of the week, Monday first, filled in from WeekdayBreakdown when requested
+	Weekdays []WeekdayTotal `json:"weekdays,omitempty"`
+	// Estimate vs actual time, filled in from stats.SummarizeEstimates when entries carry estimates
+	Estimates *stats.EstimateSummary `json:"estimates,omitempty"`
 }
 
 // GetStatistics calculates aggregated statistics with optional filtering
 func (s *Store) GetStatistics(projectID string, startDate, endDate *time.Time, invoicedFilter *bool) (*Statistics, error) {
 	stats := &Statistics{
 		ProjectBreakdown: make(map[string]int64),
+		TagBreakdown:     make(map[string]int64),
 	}
 
-	err := s.db.View(func(tx *bolt.Tx) error {
-		b := tx.Bucket([]byte(entriesBucket))
-		if b == nil {
-			return nil
-		}
+	// Minutes per project and author, for pricing at author rates
+	authorMinutes := make(map[string]map[string]int64)
+	var estimated []*models.Entry
 
-		return b.ForEach(func(k, v []byte) error {
+	err := s.db.View(func(tx *bolt.Tx) error {
+		err := forEachEntry(tx, projectID, func(k, v []byte) error {
 			var entry models.Entry
 			if err := json.Unmarshal(v, &entry); err != nil {
 				return err
@@ -510,6 +1346,16 @@ func (s *Store) GetStatistics(projectID string, startDate, endDate *time.Time, i
 
 			// Project breakdown
 			stats.ProjectBreakdown[entry.ProjectID] += entry.Duration
+			if authorMinutes[entry.ProjectID] == nil {
+				authorMinutes[entry.ProjectID] = make(map[string]int64)
+			}
+			authorMinutes[entry.ProjectID][authorRateKey(entry.Author)] += entry.Duration
+			for _, tag := range entry.Tags {
+				stats.TagBreakdown[tag] += entry.Duration
+			}
+			if entry.EstimateMinutes > 0 {
+				estimated = append(estimated, &entry)
+			}
 
 			// Track earliest and latest entries
 			if stats.EarliestEntry == nil || entry.CreatedAt.Before(*stats.EarliestEntry) {
@@ -523,6 +1369,11 @@ func (s *Store) GetStatistics(projectID string, startDate, endDate *time.Time, i
 
 			return nil
 		})
+		if err != nil {
+			return err
+		}
+
+		return priceStatistics(tx, stats, authorMinutes)
 	})
 
 	if err != nil {
@@ -530,5 +1381,15 @@ func (s *Store) GetStatistics(projectID string, startDate, endDate *time.Time, i
 	}
 
 	stats.TotalHours = float64(stats.TotalMinutes) / 60.0
+	stats.Estimates = summarizeEstimates(estimated)
 	return stats, nil
 }
+
+// summarizeEstimates compares estimates with actual time, nil when no entry has an estimate
+func summarizeEstimates(entries []*models.Entry) *stats.EstimateSummary {
+	if len(entries) == 0 {
+		return nil
+	}
+	summary := stats.SummarizeEstimates(entries)
+	return &summary
+}
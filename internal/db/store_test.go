@@ -1,13 +1,22 @@
 package db
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
 )
 
-func setupTestDB(t *testing.T) (*Store, string) {
+func setupTestDB(t testing.TB) (*Store, string) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "test.db")
 
@@ -62,6 +71,44 @@ func TestUpdateProject(t *testing.T) {
 	}
 }
 
+func TestProjectRepoCheck(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	check := func(path string) error {
+		if path != "/repos/good" {
+			return errors.New("not a git repository")
+		}
+		return nil
+	}
+
+	if _, err := store.CreateProjectWithCheck("Bad", "/repos/bad", check); err == nil {
+		t.Fatal("expected the bad path to be rejected")
+	}
+	projects, _ := store.ListProjects(false)
+	if len(projects) != 0 {
+		t.Fatalf("rejected project should not be stored, got %d projects", len(projects))
+	}
+
+	project, err := store.CreateProjectWithCheck("Good", "/repos/good", check)
+	if err != nil {
+		t.Fatalf("Failed to create project: %v", err)
+	}
+
+	if _, err := store.UpdateProjectWithCheck(project.ID, "", "/repos/bad", check); err == nil {
+		t.Fatal("expected the bad path to be rejected on update")
+	}
+	// Renaming leaves the path alone, so it is not checked again
+	if _, err := store.UpdateProjectWithCheck(project.ID, "Renamed", "", check); err != nil {
+		t.Fatalf("Failed to rename project: %v", err)
+	}
+
+	stored, _ := store.GetProject(project.ID)
+	if stored.Name != "Renamed" || stored.GitRepoPath != "/repos/good" {
+		t.Errorf("unexpected project after updates: %+v", stored)
+	}
+}
+
 func TestDeleteProject(t *testing.T) {
 	store, _ := setupTestDB(t)
 	defer store.Close()
@@ -86,7 +133,7 @@ func TestListProjects(t *testing.T) {
 	store.CreateProject("Project 1", "/path/1")
 	store.CreateProject("Project 2", "/path/2")
 
-	projects, err := store.ListProjects()
+	projects, err := store.ListProjects(false)
 	if err != nil {
 		t.Fatalf("Failed to list projects: %v", err)
 	}
@@ -96,6 +143,54 @@ func TestListProjects(t *testing.T) {
 	}
 }
 
+func TestArchiveProject(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	active, _ := store.CreateProject("Active", "/path/1")
+	archived, _ := store.CreateProject("Old", "/path/2")
+	entry, _ := store.CreateEntry(archived.ID, 60, "Old work", "", false, time.Now())
+
+	updated, err := store.ArchiveProject(archived.ID)
+	if err != nil {
+		t.Fatalf("Failed to archive project: %v", err)
+	}
+	if !updated.Archived {
+		t.Error("Expected project to be archived")
+	}
+
+	projects, _ := store.ListProjects(false)
+	if len(projects) != 1 || projects[0].ID != active.ID {
+		t.Errorf("Expected only the active project by default, got %d projects", len(projects))
+	}
+	all, _ := store.ListProjects(true)
+	if len(all) != 2 {
+		t.Errorf("Expected 2 projects including archived, got %d", len(all))
+	}
+
+	// The project and its entries are kept
+	if _, err := store.GetProject(archived.ID); err != nil {
+		t.Errorf("Expected archived project to remain: %v", err)
+	}
+	if _, err := store.GetEntry(entry.ID); err != nil {
+		t.Errorf("Expected archived project's entry to remain: %v", err)
+	}
+	if entries, _ := store.ListEntries(archived.ID); len(entries) != 1 {
+		t.Errorf("Expected 1 entry for the archived project, got %d", len(entries))
+	}
+
+	if _, err := store.UnarchiveProject(archived.ID); err != nil {
+		t.Fatalf("Failed to unarchive project: %v", err)
+	}
+	if projects, _ := store.ListProjects(false); len(projects) != 2 {
+		t.Errorf("Expected unarchived project to be listed again, got %d projects", len(projects))
+	}
+
+	if _, err := store.ArchiveProject("missing"); err == nil {
+		t.Error("Expected error archiving a nonexistent project")
+	}
+}
+
 func TestCreateAndGetEntry(t *testing.T) {
 	store, _ := setupTestDB(t)
 	defer store.Close()
@@ -197,6 +292,84 @@ func TestGetLastEntry(t *testing.T) {
 	}
 }
 
+func TestGetLastCommitHashIgnoresBackdating(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	now := time.Now()
+
+	// Git entry at the old HEAD, then a manual entry at the new HEAD backdated a week
+	store.CreateEntry(project.ID, 60, "Git entry", "aaa1111", false, now)
+	store.CreateEntry(project.ID, 30, "Manual entry", "bbb2222", false, now.Add(-7*24*time.Hour))
+
+	hash, err := store.GetLastCommitHash(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to get last commit hash: %v", err)
+	}
+	if hash != "bbb2222" {
+		t.Errorf("Expected baseline 'bbb2222' from the latest created entry, got '%s'", hash)
+	}
+
+	// Backdating an existing entry does not change the baseline either
+	store.CreateEntry(project.ID, 45, "Later entry", "ccc3333", false, now)
+	entries, _ := store.ListEntries(project.ID)
+	for _, entry := range entries {
+		if entry.CommitHash == "ccc3333" {
+			past := now.Add(-30 * 24 * time.Hour)
+			store.UpdateEntry(entry.ID, nil, nil, nil, nil, &past)
+		}
+	}
+
+	hash, _ = store.GetLastCommitHash(project.ID)
+	if hash != "ccc3333" {
+		t.Errorf("Expected baseline 'ccc3333' after backdating, got '%s'", hash)
+	}
+}
+
+func TestGetLastCommitHashLegacyEntries(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	now := time.Now()
+
+	// Entries written before sequencing have no Seq and are ordered by CreatedAt
+	legacy := []models.Entry{
+		{ID: "legacy-1", ProjectID: project.ID, CommitHash: "old1111", CreatedAt: now.Add(-2 * time.Hour)},
+		{ID: "legacy-2", ProjectID: project.ID, CommitHash: "old2222", CreatedAt: now.Add(-time.Hour)},
+	}
+	err := store.db.Update(func(tx *bolt.Tx) error {
+		for _, entry := range legacy {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			if err := tx.Bucket([]byte(entriesBucket)).Put([]byte(entry.ID), data); err != nil {
+				return err
+			}
+		}
+		// As when a legacy database is first opened
+		return rebuildEntryIndex(tx)
+	})
+	if err != nil {
+		t.Fatalf("Failed to write legacy entries: %v", err)
+	}
+
+	hash, _ := store.GetLastCommitHash(project.ID)
+	if hash != "old2222" {
+		t.Errorf("Expected baseline 'old2222' among legacy entries, got '%s'", hash)
+	}
+
+	// A new (sequenced) entry wins over legacy ones even when backdated
+	store.CreateEntry(project.ID, 30, "Manual entry", "new3333", false, now.Add(-24*time.Hour))
+
+	hash, _ = store.GetLastCommitHash(project.ID)
+	if hash != "new3333" {
+		t.Errorf("Expected baseline 'new3333', got '%s'", hash)
+	}
+}
+
 func TestDatabasePersistence(t *testing.T) {
 	tmpDir := t.TempDir()
 	dbPath := filepath.Join(tmpDir, "persist.db")
@@ -227,6 +400,73 @@ func TestDatabasePersistence(t *testing.T) {
 	}
 }
 
+func TestRestoreEntry(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	entry, _ := store.CreateEntry(project.ID, 60, "Work", "abc", false, time.Now())
+	entry, _ = store.SetEntryTags(entry.ID, []string{"dev"})
+	if err := store.DeleteEntry(entry.ID); err != nil {
+		t.Fatalf("Failed to delete entry: %v", err)
+	}
+
+	if err := store.RestoreEntry(entry); err != nil {
+		t.Fatalf("Failed to restore entry: %v", err)
+	}
+	restored, err := store.GetEntry(entry.ID)
+	if err != nil {
+		t.Fatalf("Expected entry under its original ID: %v", err)
+	}
+	if restored.Seq != entry.Seq || restored.Message != "Work" || len(restored.Tags) != 1 {
+		t.Errorf("Expected restored entry to match the deleted one, got %+v", restored)
+	}
+	if entries, _ := store.ListEntries(project.ID); len(entries) != 1 {
+		t.Errorf("Expected restored entry in the project index, got %d entries", len(entries))
+	}
+
+	if err := store.RestoreEntry(entry); err == nil {
+		t.Error("Expected error restoring over an existing entry")
+	}
+
+	orphan := *entry
+	orphan.ID = "orphan"
+	orphan.ProjectID = "missing"
+	if err := store.RestoreEntry(&orphan); err == nil {
+		t.Error("Expected error restoring an entry of a missing project")
+	}
+}
+
+func TestRestoreProject(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	store.CreateEntry(project.ID, 60, "One", "", false, time.Now())
+	store.CreateEntry(project.ID, 30, "Two", "", true, time.Now())
+	entries, _ := store.ListEntries(project.ID)
+
+	if err := store.DeleteProject(project.ID); err != nil {
+		t.Fatalf("Failed to delete project: %v", err)
+	}
+
+	if err := store.RestoreProject(project, entries); err != nil {
+		t.Fatalf("Failed to restore project: %v", err)
+	}
+	restored, err := store.GetProject(project.ID)
+	if err != nil || restored.Name != "Test" {
+		t.Fatalf("Expected project under its original ID, got %v (%v)", restored, err)
+	}
+	restoredEntries, _ := store.ListEntries(project.ID)
+	if len(restoredEntries) != 2 {
+		t.Errorf("Expected 2 restored entries, got %d", len(restoredEntries))
+	}
+
+	if err := store.RestoreProject(project, nil); err == nil {
+		t.Error("Expected error restoring over an existing project")
+	}
+}
+
 func TestDeleteProjectCascade(t *testing.T) {
 	store, _ := setupTestDB(t)
 	defer store.Close()
@@ -320,6 +560,191 @@ func TestListEntriesFiltered(t *testing.T) {
 	}
 }
 
+func TestListEntriesPageSortKeys(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	beta, _ := store.CreateProject("beta", "/beta")
+	alpha, _ := store.CreateProject("Alpha", "/alpha")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	old, _ := store.CreateEntry(beta.ID, 30, "old", "", true, base)
+	recent, _ := store.CreateEntry(beta.ID, 90, "recent", "", false, base.Add(48*time.Hour))
+	mid, _ := store.CreateEntry(alpha.ID, 60, "mid", "", false, base.Add(24*time.Hour))
+	long, _ := store.CreateEntry(alpha.ID, 90, "long", "", true, base)
+
+	// old and long share a timestamp; full ties keep entry ID order
+	tied := []*models.Entry{old, long}
+	if long.ID < old.ID {
+		tied = []*models.Entry{long, old}
+	}
+	byDate := append([]*models.Entry{recent, mid}, tied...)
+
+	tests := []struct {
+		sortBy string
+		want   []*models.Entry
+	}{
+		{"", byDate},
+		{SortByDate, byDate},
+		{SortByDuration, []*models.Entry{recent, long, mid, old}},
+		{SortByProject, []*models.Entry{mid, long, recent, old}},
+		{SortByInvoiced, []*models.Entry{recent, mid, tied[0], tied[1]}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			page, err := store.ListEntriesPage(EntryFilter{SortBy: tt.sortBy}, 0, 0)
+			if err != nil {
+				t.Fatalf("ListEntriesPage() error = %v", err)
+			}
+			if len(page.Entries) != len(tt.want) {
+				t.Fatalf("Expected %d entries, got %d", len(tt.want), len(page.Entries))
+			}
+			for i, entry := range page.Entries {
+				if entry.ID != tt.want[i].ID {
+					t.Errorf("entries[%d] = %s, want %s", i, entry.Message, tt.want[i].Message)
+				}
+			}
+		})
+	}
+
+	if _, err := store.ListEntriesPage(EntryFilter{SortBy: "message"}, 0, 0); err == nil {
+		t.Error("Expected an unknown sort order to be rejected")
+	}
+}
+
+func TestListEntriesPageEqualTimestamps(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	at := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	var ids []string
+	for i := 0; i < 10; i++ {
+		entry, _ := store.CreateEntry(project.ID, 30, fmt.Sprintf("Entry %d", i), "", false, at)
+		ids = append(ids, entry.ID)
+	}
+	sort.Strings(ids)
+
+	// Paged in threes, the pages join up to entry ID order without gaps or repeats
+	var got []string
+	for offset := 0; offset < len(ids); offset += 3 {
+		page, err := store.ListEntriesPage(EntryFilter{ProjectID: project.ID, SortBy: SortByDuration}, offset, 3)
+		if err != nil {
+			t.Fatalf("ListEntriesPage() error = %v", err)
+		}
+		for _, entry := range page.Entries {
+			got = append(got, entry.ID)
+		}
+	}
+	if strings.Join(got, ",") != strings.Join(ids, ",") {
+		t.Errorf("Expected entry ID order across pages\n got %v\nwant %v", got, ids)
+	}
+}
+
+func TestListEntriesPageSlicing(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		store.CreateEntry(project.ID, int64(10*(i+1)), fmt.Sprintf("Day %d", i), "", i%2 == 0, base.Add(time.Duration(i)*24*time.Hour))
+	}
+
+	tests := []struct {
+		name          string
+		offset, limit int
+		want          []string
+	}{
+		{"first page", 0, 2, []string{"Day 4", "Day 3"}},
+		{"middle page", 2, 2, []string{"Day 2", "Day 1"}},
+		{"short last page", 4, 2, []string{"Day 0"}},
+		{"past the end", 5, 2, nil},
+		{"no limit", 1, 0, []string{"Day 3", "Day 2", "Day 1", "Day 0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := store.ListEntriesPage(EntryFilter{ProjectID: project.ID}, tt.offset, tt.limit)
+			if err != nil {
+				t.Fatalf("ListEntriesPage() error = %v", err)
+			}
+			var got []string
+			for _, entry := range page.Entries {
+				got = append(got, entry.Message)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			// Totals cover every page: 10+20+30+40+50, invoiced days 0, 2, 4
+			if page.Total != 5 || page.TotalMinutes != 150 || page.InvoicedMinutes != 90 || page.Offset != tt.offset {
+				t.Errorf("Unexpected totals: total=%d minutes=%d invoiced=%d offset=%d", page.Total, page.TotalMinutes, page.InvoicedMinutes, page.Offset)
+			}
+		})
+	}
+
+	if _, err := store.ListEntriesPage(EntryFilter{}, -1, 2); err == nil {
+		t.Error("Expected a negative offset to be rejected")
+	}
+}
+
+func TestListEntriesPagePinnedFirst(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	var entries []*models.Entry
+	for i, minutes := range []int64{30, 120, 60, 90, 15} {
+		entry, _ := store.CreateEntry(project.ID, minutes, fmt.Sprintf("Day %d", i), "", false, base.Add(time.Duration(i)*24*time.Hour))
+		entries = append(entries, entry)
+	}
+	// Pinned: Day 0 (30m) and Day 3 (90m)
+	for _, i := range []int{0, 3} {
+		if _, err := store.SetEntryPinned(entries[i].ID, true); err != nil {
+			t.Fatalf("SetEntryPinned() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{SortByDate, []string{"Day 3", "Day 0", "Day 4", "Day 2", "Day 1"}},
+		{SortByDuration, []string{"Day 3", "Day 0", "Day 1", "Day 2", "Day 4"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.sortBy, func(t *testing.T) {
+			page, err := store.ListEntriesPage(EntryFilter{ProjectID: project.ID, SortBy: tt.sortBy, PinnedFirst: true}, 0, 0)
+			if err != nil {
+				t.Fatalf("ListEntriesPage() error = %v", err)
+			}
+			var got []string
+			for _, entry := range page.Entries {
+				got = append(got, entry.Message)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	// Without PinnedFirst, pins do not affect the order
+	page, _ := store.ListEntriesPage(EntryFilter{ProjectID: project.ID}, 0, 1)
+	if page.Entries[0].Message != "Day 4" {
+		t.Errorf("Expected the newest entry first without PinnedFirst, got %s", page.Entries[0].Message)
+	}
+
+	// Pinning is independent of billing
+	unpinned, err := store.SetEntryPinned(entries[0].ID, false)
+	if err != nil || unpinned.Pinned || unpinned.Invoiced {
+		t.Errorf("Expected an unpinned, uninvoiced entry, got %+v (%v)", unpinned, err)
+	}
+}
+
 func TestListEntriesFilteredByDateRange(t *testing.T) {
 	store, _ := setupTestDB(t)
 	defer store.Close()
@@ -601,3 +1026,418 @@ func TestMain(m *testing.M) {
 	code := m.Run()
 	os.Exit(code)
 }
+
+func TestProjectLastActivity(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project1, _ := store.CreateProject("Project 1", "/path/1")
+	project2, _ := store.CreateProject("Project 2", "/path/2")
+	idle, _ := store.CreateProject("Idle", "/path/3")
+
+	older := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	newer := time.Date(2026, 2, 20, 17, 30, 0, 0, time.UTC)
+
+	store.CreateEntry(project1.ID, 60, "Old", "abc", false, older)
+	store.CreateEntry(project1.ID, 60, "New", "def", false, newer)
+	store.CreateEntry(project2.ID, 30, "Only", "ghi", false, older)
+
+	activity, err := store.ProjectLastActivity()
+	if err != nil {
+		t.Fatalf("Failed to get project activity: %v", err)
+	}
+
+	if !activity[project1.ID].Equal(newer) {
+		t.Errorf("Expected project 1 last activity %v, got %v", newer, activity[project1.ID])
+	}
+	if !activity[project2.ID].Equal(older) {
+		t.Errorf("Expected project 2 last activity %v, got %v", older, activity[project2.ID])
+	}
+	if _, ok := activity[idle.ID]; ok {
+		t.Error("Expected no activity for project without entries")
+	}
+}
+
+func TestSetProjectDurationMethod(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+
+	updated, err := store.SetProjectDurationMethod(project.ID, "gap_aware")
+	if err != nil {
+		t.Fatalf("Failed to set duration method: %v", err)
+	}
+	if updated.DurationMethod != "gap_aware" {
+		t.Errorf("Expected duration method 'gap_aware', got '%s'", updated.DurationMethod)
+	}
+
+	retrieved, _ := store.GetProject(project.ID)
+	if retrieved.DurationMethod != "gap_aware" {
+		t.Errorf("Expected persisted duration method 'gap_aware', got '%s'", retrieved.DurationMethod)
+	}
+
+	if _, err := store.SetProjectDurationMethod("missing", "span"); err == nil {
+		t.Error("Expected error for nonexistent project")
+	}
+}
+
+func TestSetProjectRecordHeadForManual(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	if !project.RecordsHeadForManual() {
+		t.Error("Expected new projects to record HEAD for manual entries")
+	}
+
+	updated, err := store.SetProjectRecordHeadForManual(project.ID, false)
+	if err != nil {
+		t.Fatalf("Failed to set record_head_for_manual: %v", err)
+	}
+	if updated.RecordsHeadForManual() {
+		t.Error("Expected record_head_for_manual to be disabled")
+	}
+
+	retrieved, _ := store.GetProject(project.ID)
+	if retrieved.RecordsHeadForManual() {
+		t.Error("Expected persisted record_head_for_manual to be disabled")
+	}
+
+	history, _ := store.ProjectHistory(project.ID)
+	if len(history) != 1 || history[0].Changes[0].Field != "record_head_for_manual" || history[0].Changes[0].NewValue != "false" {
+		t.Errorf("Expected history record for record_head_for_manual, got %+v", history)
+	}
+
+	if _, err := store.SetProjectRecordHeadForManual("missing", true); err == nil {
+		t.Error("Expected error for nonexistent project")
+	}
+}
+
+func TestSetEntryLocation(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	entry, _ := store.CreateEntry(project.ID, 60, "Work", "", false, time.Now())
+	if entry.Location != "" {
+		t.Errorf("Expected empty location by default, got %q", entry.Location)
+	}
+
+	updated, err := store.SetEntryLocation(entry.ID, "  remote ")
+	if err != nil {
+		t.Fatalf("Failed to set location: %v", err)
+	}
+	if updated.Location != "remote" {
+		t.Errorf("Expected trimmed location 'remote', got %q", updated.Location)
+	}
+
+	retrieved, _ := store.GetEntry(entry.ID)
+	if retrieved.Location != "remote" {
+		t.Errorf("Expected persisted location 'remote', got %q", retrieved.Location)
+	}
+
+	cleared, _ := store.SetEntryLocation(entry.ID, "")
+	if cleared.Location != "" {
+		t.Errorf("Expected location to be cleared, got %q", cleared.Location)
+	}
+
+	if _, err := store.SetEntryLocation("missing", "remote"); err == nil {
+		t.Error("Expected error for nonexistent entry")
+	}
+}
+
+func TestSetEntryEstimate(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	estimated, _ := store.CreateEntry(project.ID, 90, "Feature", "", false, time.Now())
+	store.CreateEntry(project.ID, 30, "Unplanned", "", false, time.Now())
+
+	updated, err := store.SetEntryEstimate(estimated.ID, 60)
+	if err != nil {
+		t.Fatalf("Failed to set estimate: %v", err)
+	}
+	if updated.EstimateMinutes != 60 {
+		t.Errorf("Expected estimate 60, got %d", updated.EstimateMinutes)
+	}
+	if _, err := store.SetEntryEstimate(estimated.ID, -5); err == nil {
+		t.Error("Expected error for a negative estimate")
+	}
+
+	stats, err := store.GetStatistics(project.ID, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to get statistics: %v", err)
+	}
+	if stats.Estimates == nil || stats.Estimates.EntryCount != 1 || stats.Estimates.VarianceMinutes != 30 {
+		t.Errorf("Expected one estimated entry 30m over, got %+v", stats.Estimates)
+	}
+
+	store.SetEntryEstimate(estimated.ID, 0)
+	stats, _ = store.GetStatistics(project.ID, nil, nil, nil)
+	if stats.Estimates != nil {
+		t.Errorf("Expected no estimate summary after clearing, got %+v", stats.Estimates)
+	}
+}
+
+func TestFilterByLocation(t *testing.T) {
+	entries := []*models.Entry{
+		{ID: "1", Location: "remote"},
+		{ID: "2", Location: "On-Site"},
+		{ID: "3"},
+	}
+
+	tests := []struct {
+		location string
+		want     []string
+	}{
+		{"", []string{"1", "2", "3"}},
+		{"remote", []string{"1"}},
+		{"on-site", []string{"2"}},
+		{" REMOTE ", []string{"1"}},
+		{"office", nil},
+	}
+
+	for _, tt := range tests {
+		got := FilterByLocation(entries, tt.location)
+		var ids []string
+		for _, entry := range got {
+			ids = append(ids, entry.ID)
+		}
+		if strings.Join(ids, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("FilterByLocation(%q) = %v, want %v", tt.location, ids, tt.want)
+		}
+	}
+}
+
+func TestEntrySource(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	host, err := os.Hostname()
+	if err != nil {
+		t.Skipf("hostname unavailable: %v", err)
+	}
+
+	project, _ := store.CreateProject("Test", "/path")
+	entry, _ := store.CreateEntry(project.ID, 30, "Logged here", "", false, time.Now())
+	if entry.Source != host {
+		t.Errorf("Expected source %q, got %q", host, entry.Source)
+	}
+
+	updated, err := store.SetEntrySource(entry.ID, "  build-server ")
+	if err != nil {
+		t.Fatalf("SetEntrySource() error = %v", err)
+	}
+	if updated.Source != "build-server" {
+		t.Errorf("Expected overridden source 'build-server', got %q", updated.Source)
+	}
+
+	entries, _ := store.ListEntries(project.ID)
+	if got := FilterBySource(entries, "BUILD-SERVER"); len(got) != 1 || got[0].ID != entry.ID {
+		t.Errorf("Expected entry to match its source case-insensitively, got %v", got)
+	}
+	if got := FilterBySource(entries, host); len(got) != 0 {
+		t.Errorf("Expected no entries from %q after override, got %v", host, got)
+	}
+	if got := FilterBySource(entries, ""); len(got) != 1 {
+		t.Errorf("Expected empty source to match every entry, got %v", got)
+	}
+}
+
+func TestNewReadOnly(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "replica.db")
+
+	// Seed a database, then close it as a copied replica would be
+	seed, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create database: %v", err)
+	}
+	project, _ := seed.CreateProject("Test", "/path")
+	entry, _ := seed.CreateEntry(project.ID, 60, "Seeded", "", false, time.Now())
+	seed.Close()
+
+	store, err := NewReadOnly(dbPath)
+	if err != nil {
+		t.Fatalf("NewReadOnly() error = %v", err)
+	}
+	defer store.Close()
+
+	if !store.ReadOnly() || store.Path() != dbPath {
+		t.Errorf("Expected read-only store at %s, got %s (read-only %v)", dbPath, store.Path(), store.ReadOnly())
+	}
+
+	projects, err := store.ListProjects(false)
+	if err != nil || len(projects) != 1 || projects[0].ID != project.ID {
+		t.Errorf("Expected seeded project, got %v (err %v)", projects, err)
+	}
+	entries, err := store.ListEntries(project.ID)
+	if err != nil || len(entries) != 1 || entries[0].ID != entry.ID {
+		t.Errorf("Expected seeded entry, got %v (err %v)", entries, err)
+	}
+
+	if _, err := store.CreateProject("Other", "/other"); !errors.Is(err, bolt.ErrDatabaseReadOnly) {
+		t.Errorf("Expected read-only error creating a project, got %v", err)
+	}
+	if err := store.SetSetting(SettingTimerRounding, "up"); err == nil {
+		t.Error("Expected error writing a setting")
+	}
+	if _, err := store.SetEntryLocation(entry.ID, "remote"); err == nil {
+		t.Error("Expected error updating an entry")
+	}
+}
+
+func TestNewReadOnlyRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := NewReadOnly(filepath.Join(dir, "missing.db")); err == nil {
+		t.Error("Expected error for a missing file")
+	}
+
+	// A bolt file without clockwork's buckets cannot be initialized read-only
+	emptyPath := filepath.Join(dir, "empty.db")
+	empty, err := bolt.Open(emptyPath, 0600, nil)
+	if err != nil {
+		t.Fatalf("Failed to create bolt file: %v", err)
+	}
+	empty.Close()
+
+	if _, err := NewReadOnly(emptyPath); err == nil {
+		t.Error("Expected error for a database without clockwork buckets")
+	}
+}
+
+func TestMaxMessageLength(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+
+	// Default limit
+	atDefault := strings.Repeat("x", DefaultMaxMessageLength)
+	if _, err := store.CreateEntry(project.ID, 30, atDefault, "", false, time.Now()); err != nil {
+		t.Errorf("Expected message at the default limit to be accepted, got %v", err)
+	}
+	if _, err := store.CreateEntry(project.ID, 30, atDefault+"x", "", false, time.Now()); err == nil {
+		t.Error("Expected message over the default limit to be rejected")
+	}
+
+	// Configured limit applies to create, update, and merge
+	store.SetSetting(SettingMaxMessageLength, "10")
+
+	entry, err := store.CreateEntry(project.ID, 30, "0123456789", "", false, time.Now())
+	if err != nil {
+		t.Fatalf("Expected message at the limit to be accepted, got %v", err)
+	}
+	if _, err := store.CreateEntry(project.ID, 30, "0123456789a", "", false, time.Now()); err == nil || !strings.Contains(err.Error(), SettingMaxMessageLength) {
+		t.Errorf("Expected a max_message_length error, got %v", err)
+	}
+
+	long := "0123456789a"
+	if _, err := store.UpdateEntry(entry.ID, nil, &long, nil, nil, nil); err == nil {
+		t.Error("Expected update over the limit to be rejected")
+	}
+	if retrieved, _ := store.GetEntry(entry.ID); retrieved.Message != "0123456789" {
+		t.Errorf("Expected rejected update to leave the message alone, got %q", retrieved.Message)
+	}
+
+	// Updates that don't touch the message still work on entries stored before a lower limit
+	invoiced := true
+	if _, err := store.UpdateEntry(entry.ID, nil, nil, nil, &invoiced, nil); err != nil {
+		t.Errorf("Expected update without a message to be accepted, got %v", err)
+	}
+
+	if _, err := store.ExtendEntry(entry.ID, 15, "more", "", EntryOptions{}); err == nil {
+		t.Error("Expected merge that grows the message over the limit to be rejected")
+	}
+
+	// 0 disables the limit
+	store.SetSetting(SettingMaxMessageLength, "0")
+	if _, err := store.CreateEntry(project.ID, 30, atDefault+"x", "", false, time.Now()); err != nil {
+		t.Errorf("Expected no limit with 0, got %v", err)
+	}
+}
+
+func TestConcurrentEntryWrites(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	shared, _ := store.CreateEntry(project.ID, 0, "Shared", "", false, time.Now())
+
+	const workers = 8
+	const perWorker = 25
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers*perWorker*3)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perWorker; i++ {
+				if _, err := store.CreateEntry(project.ID, 10, "Work", "", false, time.Now()); err != nil {
+					errs <- err
+				}
+				// Read-modify-write on one entry: a lost update would drop minutes
+				if _, err := store.ExtendEntry(shared.ID, 1, "", "", EntryOptions{}); err != nil {
+					errs <- err
+				}
+				// Every snapshot must be internally consistent
+				stats, err := store.GetStatistics(project.ID, nil, nil, nil)
+				if err != nil {
+					errs <- err
+				} else if stats.TotalMinutes != stats.InvoicedMinutes+stats.UninvoicedMinutes {
+					errs <- fmt.Errorf("inconsistent statistics: %+v", stats)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	entry, err := store.GetEntry(shared.ID)
+	if err != nil {
+		t.Fatalf("Failed to get shared entry: %v", err)
+	}
+	if entry.Duration != workers*perWorker {
+		t.Errorf("Expected %d minutes on the shared entry, got %d (lost updates)", workers*perWorker, entry.Duration)
+	}
+
+	entries, err := store.ListEntries(project.ID)
+	if err != nil {
+		t.Fatalf("Failed to list entries: %v", err)
+	}
+	if len(entries) != workers*perWorker+1 {
+		t.Errorf("Expected %d entries, got %d", workers*perWorker+1, len(entries))
+	}
+
+	// Sequence numbers stay unique under concurrent creation
+	seen := make(map[uint64]bool)
+	for _, e := range entries {
+		if seen[e.Seq] {
+			t.Errorf("Duplicate sequence number %d", e.Seq)
+		}
+		seen[e.Seq] = true
+	}
+}
+
+func TestCreateEntryForDeletedProject(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	if err := store.DeleteProject(project.ID); err != nil {
+		t.Fatalf("Failed to delete project: %v", err)
+	}
+
+	if _, err := store.CreateEntry(project.ID, 60, "Work", "", false, time.Now()); err == nil {
+		t.Error("Expected error creating an entry for a deleted project")
+	}
+	if err := store.SetDefaultProject(project.ID); err == nil {
+		t.Error("Expected error making a deleted project the default")
+	}
+}
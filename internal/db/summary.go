@@ -0,0 +1,159 @@
+package db
+
+import (
+	"fmt"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// MonthSummary represents aggregated time for a single calendar month
+type MonthSummary struct {
+	Month             time.Month `json:"month"`
+	TotalMinutes      int64      `json:"total_minutes"`
+	EntryCount        int        `json:"entry_count"`
+	InvoicedMinutes   int64      `json:"invoiced_minutes"`
+	UninvoicedMinutes int64      `json:"uninvoiced_minutes"`
+
+	Amounts map[string]float64 `json:"amounts,omitempty"` // Currency -> billed amount, priced like Statistics
+}
+
+// AnnualSummary represents a year-end report with monthly and per-project breakdowns
+type AnnualSummary struct {
+	Year              int              `json:"year"`
+	ProjectID         string           `json:"project_id,omitempty"`
+	TotalMinutes      int64            `json:"total_minutes"`
+	TotalHours        float64          `json:"total_hours"`
+	EntryCount        int              `json:"entry_count"`
+	InvoicedMinutes   int64            `json:"invoiced_minutes"`
+	UninvoicedMinutes int64            `json:"uninvoiced_minutes"`
+	Months            []MonthSummary   `json:"months"`            // Always 12 buckets, January first
+	ProjectBreakdown  map[string]int64 `json:"project_breakdown"` // projectID -> minutes
+
+	// Billing, priced per project and author rate like GetStatistics; amounts are per currency
+	Amounts            map[string]float64            `json:"amounts,omitempty"`
+	TotalAmount        float64                       `json:"total_amount"`               // Sum of Amounts when they share one currency, else 0
+	Currency           string                        `json:"currency,omitempty"`         // Currency of TotalAmount
+	UnpricedMinutes    int64                         `json:"unpriced_minutes,omitempty"` // Time without an hourly rate
+	InvoicedAmounts    map[string]float64            `json:"invoiced_amounts,omitempty"`
+	OutstandingAmounts map[string]float64            `json:"outstanding_amounts,omitempty"`
+	ProjectAmounts     map[string]map[string]float64 `json:"project_amounts,omitempty"` // projectID -> currency -> amount
+}
+
+// GetAnnualSummary builds the annual summary for a year, optionally filtered by project
+// Year boundaries are evaluated in local time
+func (s *Store) GetAnnualSummary(year int, projectID string) (*AnnualSummary, error) {
+	if year < 1 {
+		return nil, fmt.Errorf("invalid year: %d", year)
+	}
+
+	startDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	endDate := startDate.AddDate(1, 0, 0).Add(-time.Nanosecond)
+
+	stats, err := s.GetStatistics(projectID, &startDate, &endDate, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get annual statistics: %w", err)
+	}
+
+	entries, err := s.ListEntriesFiltered(projectID, &startDate, &endDate, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list annual entries: %w", err)
+	}
+
+	summary := &AnnualSummary{
+		Year:              year,
+		ProjectID:         projectID,
+		TotalMinutes:      stats.TotalMinutes,
+		TotalHours:        stats.TotalHours,
+		EntryCount:        stats.EntryCount,
+		InvoicedMinutes:   stats.InvoicedMinutes,
+		UninvoicedMinutes: stats.UninvoicedMinutes,
+		Months:            make([]MonthSummary, 12),
+		ProjectBreakdown:  stats.ProjectBreakdown,
+		Amounts:           stats.Amounts,
+		TotalAmount:       stats.TotalAmount,
+		Currency:          stats.Currency,
+		UnpricedMinutes:   stats.UnpricedMinutes,
+	}
+
+	for i := range summary.Months {
+		summary.Months[i].Month = time.Month(i + 1)
+	}
+
+	// Minutes per project and author for each breakdown, priced below
+	var monthMinutes [12]minutesByAuthor
+	invoicedMinutes, outstandingMinutes := minutesByAuthor{}, minutesByAuthor{}
+	projectMinutes := make(map[string]minutesByAuthor)
+
+	for _, entry := range entries {
+		index := entry.CreatedAt.In(time.Local).Month() - 1
+		month := &summary.Months[index]
+		month.TotalMinutes += entry.Duration
+		month.EntryCount++
+		if entry.Invoiced {
+			month.InvoicedMinutes += entry.Duration
+			invoicedMinutes.add(entry.ProjectID, entry.Author, entry.Duration)
+		} else {
+			month.UninvoicedMinutes += entry.Duration
+			outstandingMinutes.add(entry.ProjectID, entry.Author, entry.Duration)
+		}
+
+		if monthMinutes[index] == nil {
+			monthMinutes[index] = minutesByAuthor{}
+		}
+		monthMinutes[index].add(entry.ProjectID, entry.Author, entry.Duration)
+		if projectMinutes[entry.ProjectID] == nil {
+			projectMinutes[entry.ProjectID] = minutesByAuthor{}
+		}
+		projectMinutes[entry.ProjectID].add(entry.ProjectID, entry.Author, entry.Duration)
+	}
+
+	err = s.db.View(func(tx *bolt.Tx) error {
+		price := func(minutes minutesByAuthor) (map[string]float64, error) {
+			amounts, _, err := priceMinutes(tx, minutes)
+			return amounts, err
+		}
+
+		var err error
+		for i := range summary.Months {
+			if summary.Months[i].Amounts, err = price(monthMinutes[i]); err != nil {
+				return err
+			}
+		}
+		if summary.InvoicedAmounts, err = price(invoicedMinutes); err != nil {
+			return err
+		}
+		if summary.OutstandingAmounts, err = price(outstandingMinutes); err != nil {
+			return err
+		}
+		for projectID, minutes := range projectMinutes {
+			amounts, err := price(minutes)
+			if err != nil {
+				return err
+			}
+			if amounts != nil {
+				if summary.ProjectAmounts == nil {
+					summary.ProjectAmounts = make(map[string]map[string]float64)
+				}
+				summary.ProjectAmounts[projectID] = amounts
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to price annual summary: %w", err)
+	}
+
+	return summary, nil
+}
+
+// minutesByAuthor holds minutes per project and author key, the input of priceMinutes
+type minutesByAuthor map[string]map[string]int64
+
+// add counts minutes an author logged on a project
+func (m minutesByAuthor) add(projectID, author string, minutes int64) {
+	if m[projectID] == nil {
+		m[projectID] = make(map[string]int64)
+	}
+	m[projectID][authorRateKey(author)] += minutes
+}
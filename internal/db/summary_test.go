@@ -0,0 +1,139 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGetAnnualSummary(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project1, _ := store.CreateProject("Project 1", "/path/1")
+	project2, _ := store.CreateProject("Project 2", "/path/2")
+
+	march := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.Local)
+	july := time.Date(2025, time.July, 20, 14, 0, 0, 0, time.Local)
+
+	store.CreateEntry(project1.ID, 60, "March work", "abc", true, march)
+	store.CreateEntry(project2.ID, 90, "March work", "def", false, march)
+	store.CreateEntry(project1.ID, 120, "July work", "ghi", false, july)
+
+	// Outside the year
+	store.CreateEntry(project1.ID, 30, "Old work", "jkl", false, time.Date(2024, time.December, 31, 23, 0, 0, 0, time.Local))
+
+	summary, err := store.GetAnnualSummary(2025, "")
+	if err != nil {
+		t.Fatalf("Failed to get annual summary: %v", err)
+	}
+
+	if len(summary.Months) != 12 {
+		t.Fatalf("Expected 12 monthly buckets, got %d", len(summary.Months))
+	}
+
+	if summary.TotalMinutes != 270 {
+		t.Errorf("Expected 270 total minutes, got %d", summary.TotalMinutes)
+	}
+	if summary.EntryCount != 3 {
+		t.Errorf("Expected 3 entries, got %d", summary.EntryCount)
+	}
+	if summary.InvoicedMinutes != 60 {
+		t.Errorf("Expected 60 invoiced minutes, got %d", summary.InvoicedMinutes)
+	}
+	if summary.UninvoicedMinutes != 210 {
+		t.Errorf("Expected 210 outstanding minutes, got %d", summary.UninvoicedMinutes)
+	}
+
+	if summary.Months[time.March-1].TotalMinutes != 150 {
+		t.Errorf("Expected 150 minutes in March, got %d", summary.Months[time.March-1].TotalMinutes)
+	}
+	if summary.Months[time.July-1].TotalMinutes != 120 {
+		t.Errorf("Expected 120 minutes in July, got %d", summary.Months[time.July-1].TotalMinutes)
+	}
+
+	// Monthly buckets must add up to the total
+	var monthlyTotal int64
+	for _, month := range summary.Months {
+		monthlyTotal += month.TotalMinutes
+	}
+	if monthlyTotal != summary.TotalMinutes {
+		t.Errorf("Monthly total %d does not match total %d", monthlyTotal, summary.TotalMinutes)
+	}
+
+	if summary.ProjectBreakdown[project1.ID] != 180 {
+		t.Errorf("Expected 180 minutes for project 1, got %d", summary.ProjectBreakdown[project1.ID])
+	}
+
+	// Project filter
+	filtered, err := store.GetAnnualSummary(2025, project2.ID)
+	if err != nil {
+		t.Fatalf("Failed to get filtered annual summary: %v", err)
+	}
+	if filtered.TotalMinutes != 90 {
+		t.Errorf("Expected 90 minutes for project 2, got %d", filtered.TotalMinutes)
+	}
+}
+
+func TestGetAnnualSummaryEmptyYear(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	summary, err := store.GetAnnualSummary(2030, "")
+	if err != nil {
+		t.Fatalf("Failed to get annual summary: %v", err)
+	}
+
+	if len(summary.Months) != 12 {
+		t.Errorf("Expected 12 monthly buckets, got %d", len(summary.Months))
+	}
+	if summary.TotalMinutes != 0 || summary.EntryCount != 0 {
+		t.Errorf("Expected empty summary, got %d minutes in %d entries", summary.TotalMinutes, summary.EntryCount)
+	}
+}
+
+func TestGetAnnualSummaryAmounts(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	billed, _ := store.CreateProject("Billed", "/path/1")
+	store.SetProjectRate(billed.ID, 100, "EUR")
+	unbilled, _ := store.CreateProject("Unbilled", "/path/2")
+
+	march := time.Date(2025, time.March, 10, 9, 0, 0, 0, time.Local)
+	july := time.Date(2025, time.July, 20, 14, 0, 0, 0, time.Local)
+
+	store.CreateEntry(billed.ID, 60, "March work", "", true, march)
+	store.CreateEntry(billed.ID, 90, "July work", "", false, july)
+	store.CreateEntry(unbilled.ID, 30, "Side work", "", false, july)
+
+	// Author rates take precedence over the project rate, as in GetStatistics
+	store.SetAuthorRate("Jane Doe", 120)
+	store.CreateEntryWithOptions(billed.ID, 30, "Review", "", false, july, EntryOptions{Author: "Jane Doe"})
+
+	summary, err := store.GetAnnualSummary(2025, "")
+	if err != nil {
+		t.Fatalf("Failed to get annual summary: %v", err)
+	}
+
+	if summary.TotalAmount != 310 || summary.Currency != "EUR" {
+		t.Errorf("Expected 310 EUR in total, got %.2f %q", summary.TotalAmount, summary.Currency)
+	}
+	if summary.UnpricedMinutes != 30 {
+		t.Errorf("Expected 30 unpriced minutes, got %d", summary.UnpricedMinutes)
+	}
+	if summary.InvoicedAmounts["EUR"] != 100 || summary.OutstandingAmounts["EUR"] != 210 {
+		t.Errorf("Expected 100 EUR invoiced and 210 EUR outstanding, got %v / %v", summary.InvoicedAmounts, summary.OutstandingAmounts)
+	}
+	if summary.Months[time.March-1].Amounts["EUR"] != 100 || summary.Months[time.July-1].Amounts["EUR"] != 210 {
+		t.Errorf("Unexpected monthly amounts: March %v, July %v", summary.Months[time.March-1].Amounts, summary.Months[time.July-1].Amounts)
+	}
+	if summary.Months[time.January-1].Amounts != nil {
+		t.Errorf("Expected no amount in an empty month, got %v", summary.Months[time.January-1].Amounts)
+	}
+	if summary.ProjectAmounts[billed.ID]["EUR"] != 310 {
+		t.Errorf("Expected 310 EUR for the billed project, got %v", summary.ProjectAmounts[billed.ID])
+	}
+	if _, ok := summary.ProjectAmounts[unbilled.ID]; ok {
+		t.Errorf("Expected no amount for the project without a rate, got %v", summary.ProjectAmounts[unbilled.ID])
+	}
+}
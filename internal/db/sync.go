@@ -0,0 +1,282 @@
+package db
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// SyncSnapshotVersion is the snapshot format written by ExportSnapshot
+const SyncSnapshotVersion = 1
+
+// Conflict resolutions reported by MergeSnapshot
+const (
+	SyncKeptLocal    = "kept_local"    // The local record was updated at the same time or later
+	SyncTookIncoming = "took_incoming" // The incoming record was updated later
+)
+
+// SyncSnapshot is a full copy of the projects and entries with their IDs and timestamps
+// intact, for moving a database between machines that share it through a cloud drive
+type SyncSnapshot struct {
+	Version    int               `json:"version"`
+	ExportedAt time.Time         `json:"exported_at"`
+	Source     string            `json:"source"` // Hostname of the exporting machine
+	Projects   []*models.Project `json:"projects"`
+	Entries    []*models.Entry   `json:"entries"`
+}
+
+// SyncConflict is a record that was changed on both sides, and which version was kept
+type SyncConflict struct {
+	Target            string    `json:"target"` // AuditTargetProject or AuditTargetEntry
+	ID                string    `json:"id"`
+	Resolution        string    `json:"resolution"`
+	LocalUpdatedAt    time.Time `json:"local_updated_at"`
+	IncomingUpdatedAt time.Time `json:"incoming_updated_at"`
+}
+
+// MergeReport summarizes a MergeSnapshot
+type MergeReport struct {
+	ProjectsAdded   int            `json:"projects_added"`
+	ProjectsUpdated int            `json:"projects_updated"`
+	EntriesAdded    int            `json:"entries_added"`
+	EntriesUpdated  int            `json:"entries_updated"`
+	SkippedEntries  []string       `json:"skipped_entries"` // Incoming entries whose project exists on neither side
+	Conflicts       []SyncConflict `json:"conflicts"`
+}
+
+// ExportSnapshot writes every project and entry as an indented SyncSnapshot
+func (s *Store) ExportSnapshot(w io.Writer) error {
+	snapshot := SyncSnapshot{
+		Version:    SyncSnapshotVersion,
+		ExportedAt: time.Now(),
+		Source:     hostname(),
+		Projects:   []*models.Project{},
+		Entries:    []*models.Entry{},
+	}
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		err := tx.Bucket([]byte(projectsBucket)).ForEach(func(k, v []byte) error {
+			var project models.Project
+			if err := json.Unmarshal(v, &project); err != nil {
+				return err
+			}
+			snapshot.Projects = append(snapshot.Projects, &project)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket([]byte(entriesBucket)).ForEach(func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			snapshot.Entries = append(snapshot.Entries, &entry)
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read database: %w", err)
+	}
+
+	sortByCreation(snapshot.Entries)
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// MergeSnapshot merges a snapshot written by ExportSnapshot into the database in one transaction.
+// Records are matched by ID: new ones are added, and when the two versions of a record differ the
+// one with the later UpdatedAt wins (last writer wins; ties keep the local record) and the decision
+// is reported as a conflict. Identical records are left alone, and records missing from the
+// snapshot are kept, so deletions do not propagate.
+func (s *Store) MergeSnapshot(r io.Reader) (*MergeReport, error) {
+	var snapshot SyncSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	if snapshot.Version != SyncSnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d (expected %d)", snapshot.Version, SyncSnapshotVersion)
+	}
+
+	report := &MergeReport{SkippedEntries: []string{}, Conflicts: []SyncConflict{}}
+	detail := "sync import"
+	if snapshot.Source != "" {
+		detail = "sync import from " + snapshot.Source
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		pb := tx.Bucket([]byte(projectsBucket))
+		for _, incoming := range snapshot.Projects {
+			if incoming == nil || incoming.ID == "" {
+				return fmt.Errorf("snapshot contains a project without an ID")
+			}
+
+			data := pb.Get([]byte(incoming.ID))
+			if data == nil {
+				if err := putProject(pb, incoming); err != nil {
+					return err
+				}
+				report.ProjectsAdded++
+				if err := recordAudit(tx, AuditCreate, AuditTargetProject, incoming.ID, diffProject(&models.Project{}, incoming), detail); err != nil {
+					return err
+				}
+				continue
+			}
+
+			var local models.Project
+			if err := json.Unmarshal(data, &local); err != nil {
+				return err
+			}
+			if same, err := sameRecord(&local, incoming); err != nil || same {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			conflict := newSyncConflict(AuditTargetProject, incoming.ID, local.UpdatedAt, incoming.UpdatedAt)
+			report.Conflicts = append(report.Conflicts, conflict)
+			if conflict.Resolution == SyncKeptLocal {
+				continue
+			}
+
+			if err := recordProjectChange(tx, &local, incoming, incoming.UpdatedAt); err != nil {
+				return err
+			}
+			if err := recordAudit(tx, AuditUpdate, AuditTargetProject, incoming.ID, diffProject(&local, incoming), detail); err != nil {
+				return err
+			}
+			if err := putProject(pb, incoming); err != nil {
+				return err
+			}
+			report.ProjectsUpdated++
+		}
+
+		// Added entries get local sequence numbers in the source's creation order
+		sortByCreation(snapshot.Entries)
+
+		eb := tx.Bucket([]byte(entriesBucket))
+		for _, incoming := range snapshot.Entries {
+			if incoming == nil || incoming.ID == "" {
+				return fmt.Errorf("snapshot contains an entry without an ID")
+			}
+
+			data := eb.Get([]byte(incoming.ID))
+			if data == nil {
+				if pb.Get([]byte(incoming.ProjectID)) == nil {
+					report.SkippedEntries = append(report.SkippedEntries, incoming.ID)
+					continue
+				}
+
+				// Sequence numbers are per database; give the entry a local one
+				seq, err := eb.NextSequence()
+				if err != nil {
+					return err
+				}
+				incoming.Seq = seq
+				if err := putEntry(eb, incoming); err != nil {
+					return err
+				}
+				if err := indexEntry(tx, incoming.ProjectID, incoming.ID); err != nil {
+					return err
+				}
+				report.EntriesAdded++
+				if err := recordAudit(tx, AuditCreate, AuditTargetEntry, incoming.ID, diffEntry(&models.Entry{}, incoming), detail); err != nil {
+					return err
+				}
+				continue
+			}
+
+			var local models.Entry
+			if err := json.Unmarshal(data, &local); err != nil {
+				return err
+			}
+			incoming.Seq = local.Seq
+			if same, err := sameRecord(&local, incoming); err != nil || same {
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			conflict := newSyncConflict(AuditTargetEntry, incoming.ID, local.UpdatedAt, incoming.UpdatedAt)
+			report.Conflicts = append(report.Conflicts, conflict)
+			if conflict.Resolution == SyncKeptLocal {
+				continue
+			}
+
+			if err := recordAudit(tx, AuditUpdate, AuditTargetEntry, incoming.ID, diffEntry(&local, incoming), detail); err != nil {
+				return err
+			}
+			if err := putEntry(eb, incoming); err != nil {
+				return err
+			}
+			if err := reindexEntry(tx, local.ProjectID, incoming.ProjectID, incoming.ID); err != nil {
+				return err
+			}
+			report.EntriesUpdated++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge snapshot: %w", err)
+	}
+
+	return report, nil
+}
+
+// newSyncConflict resolves a record changed on both sides by its UpdatedAt
+func newSyncConflict(target, id string, local, incoming time.Time) SyncConflict {
+	resolution := SyncKeptLocal
+	if incoming.After(local) {
+		resolution = SyncTookIncoming
+	}
+	return SyncConflict{
+		Target:            target,
+		ID:                id,
+		Resolution:        resolution,
+		LocalUpdatedAt:    local,
+		IncomingUpdatedAt: incoming,
+	}
+}
+
+// sameRecord reports whether two records encode identically
+func sameRecord(a, b interface{}) (bool, error) {
+	left, err := json.Marshal(a)
+	if err != nil {
+		return false, err
+	}
+	right, err := json.Marshal(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(left, right), nil
+}
+
+func putProject(b *bolt.Bucket, project *models.Project) error {
+	data, err := json.Marshal(project)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(project.ID), data)
+}
+
+func putEntry(b *bolt.Bucket, entry *models.Entry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(entry.ID), data)
+}
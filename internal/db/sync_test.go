@@ -0,0 +1,139 @@
+package db
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// snapshotOf exports the store into a buffer
+func snapshotOf(t *testing.T, store *Store) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := store.ExportSnapshot(&buf); err != nil {
+		t.Fatalf("ExportSnapshot() error = %v", err)
+	}
+	return &buf
+}
+
+func TestMergeSnapshotIntoEmpty(t *testing.T) {
+	remote, _ := setupTestDB(t)
+	defer remote.Close()
+	local, _ := setupTestDB(t)
+	defer local.Close()
+
+	project, _ := remote.CreateProject("Test", "/path")
+	entry, _ := remote.CreateEntry(project.ID, 45, "Remote work", "abc123", false, time.Now())
+
+	report, err := local.MergeSnapshot(snapshotOf(t, remote))
+	if err != nil {
+		t.Fatalf("MergeSnapshot() error = %v", err)
+	}
+	if report.ProjectsAdded != 1 || report.EntriesAdded != 1 || len(report.Conflicts) != 0 {
+		t.Errorf("Expected 1 project and 1 entry added without conflicts, got %+v", report)
+	}
+
+	// IDs and timestamps survive the round trip
+	imported, err := local.GetEntry(entry.ID)
+	if err != nil {
+		t.Fatalf("Expected entry %s to be imported: %v", entry.ID, err)
+	}
+	if imported.ProjectID != project.ID || !imported.UpdatedAt.Equal(entry.UpdatedAt) || imported.Message != entry.Message {
+		t.Errorf("Expected imported entry to match, got %+v", imported)
+	}
+
+	// Merging the same snapshot again changes nothing
+	report, _ = local.MergeSnapshot(snapshotOf(t, remote))
+	if report.ProjectsAdded+report.ProjectsUpdated+report.EntriesAdded+report.EntriesUpdated != 0 || len(report.Conflicts) != 0 {
+		t.Errorf("Expected a repeated merge to be a no-op, got %+v", report)
+	}
+}
+
+func TestMergeSnapshotKeepsCommitBaseline(t *testing.T) {
+	remote, _ := setupTestDB(t)
+	defer remote.Close()
+	local, _ := setupTestDB(t)
+	defer local.Close()
+
+	project, _ := remote.CreateProject("Test", "/path")
+	now := time.Now()
+	remote.CreateEntry(project.ID, 30, "Earlier commits", "0123456789abcdef0123456789abcdef01234567", false, now)
+	// Logged later but backdated: it still holds the newest HEAD
+	remote.CreateEntry(project.ID, 30, "Backdated", "fedcba9876543210fedcba9876543210fedcba98", false, now.Add(-24*time.Hour))
+
+	want, _ := remote.GetLastCommitHash(project.ID)
+
+	if _, err := local.MergeSnapshot(snapshotOf(t, remote)); err != nil {
+		t.Fatalf("MergeSnapshot() error = %v", err)
+	}
+	if got, _ := local.GetLastCommitHash(project.ID); got != want {
+		t.Errorf("Expected the merged baseline to stay %s, got %s", want, got)
+	}
+}
+
+func TestMergeSnapshotConflicts(t *testing.T) {
+	local, _ := setupTestDB(t)
+	defer local.Close()
+	remote, _ := setupTestDB(t)
+	defer remote.Close()
+
+	project, _ := local.CreateProject("Test", "/path")
+	newer, _ := local.CreateEntry(project.ID, 30, "Original", "", false, time.Now())
+	older, _ := local.CreateEntry(project.ID, 30, "Original", "", false, time.Now())
+
+	// Both machines start from the same data
+	if _, err := remote.MergeSnapshot(snapshotOf(t, local)); err != nil {
+		t.Fatalf("MergeSnapshot() error = %v", err)
+	}
+
+	// The remote machine edits the first entry last; the local one edits the second entry last
+	edit := func(store *Store, id string, duration int64, message string) {
+		if _, err := store.UpdateEntry(id, &duration, &message, nil, nil, nil); err != nil {
+			t.Fatalf("UpdateEntry() error = %v", err)
+		}
+	}
+	edit(local, newer.ID, 45, "Local edit")
+	edit(remote, older.ID, 15, "Stale remote edit")
+	time.Sleep(10 * time.Millisecond)
+	edit(remote, newer.ID, 60, "Remote edit")
+	edit(local, older.ID, 55, "Later local edit")
+
+	report, err := local.MergeSnapshot(snapshotOf(t, remote))
+	if err != nil {
+		t.Fatalf("MergeSnapshot() error = %v", err)
+	}
+
+	if len(report.Conflicts) != 2 || report.EntriesUpdated != 1 {
+		t.Fatalf("Expected 2 conflicts and 1 updated entry, got %+v", report)
+	}
+
+	resolutions := map[string]string{}
+	for _, conflict := range report.Conflicts {
+		resolutions[conflict.ID] = conflict.Resolution
+	}
+	if resolutions[newer.ID] != SyncTookIncoming {
+		t.Errorf("Expected the newer incoming entry to win, got %q", resolutions[newer.ID])
+	}
+	if resolutions[older.ID] != SyncKeptLocal {
+		t.Errorf("Expected the local entry to be kept over an older incoming one, got %q", resolutions[older.ID])
+	}
+
+	if entry, _ := local.GetEntry(newer.ID); entry.Message != "Remote edit" || entry.Duration != 60 {
+		t.Errorf("Expected the remote edit to be applied, got %+v", entry)
+	}
+	if entry, _ := local.GetEntry(older.ID); entry.Message != "Later local edit" || entry.Duration != 55 {
+		t.Errorf("Expected the local edit to be kept, got %+v", entry)
+	}
+}
+
+func TestMergeSnapshotRejectsBadInput(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	if _, err := store.MergeSnapshot(bytes.NewBufferString(`{"version": 99}`)); err == nil {
+		t.Error("Expected error for an unsupported version")
+	}
+	if _, err := store.MergeSnapshot(bytes.NewBufferString(`not json`)); err == nil {
+		t.Error("Expected error for invalid JSON")
+	}
+}
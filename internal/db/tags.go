@@ -0,0 +1,99 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// BulkTag adds and removes tags on every entry matching the filters in one transaction
+// Adds are deduplicated and removing an absent tag is a no-op; removes win when a tag is in both.
+// Locked entries are skipped. Returns the number of entries whose tags changed.
+func (s *Store) BulkTag(projectID string, startDate, endDate *time.Time, addTags, removeTags []string) (int, error) {
+	addTags = models.NormalizeTags(addTags)
+	removeTags = models.NormalizeTags(removeTags)
+	if len(addTags) == 0 && len(removeTags) == 0 {
+		return 0, fmt.Errorf("no tags to add or remove")
+	}
+
+	updated := 0
+	now := time.Now()
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(entriesBucket))
+
+		// Collect changes first; the bucket must not be modified while iterating
+		var keys [][]byte
+		var originals, changed []models.Entry
+		err := b.ForEach(func(k, v []byte) error {
+			var entry models.Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			if entry.Locked || !matchesFilter(&entry, projectID, startDate, endDate, nil) {
+				return nil
+			}
+
+			tags := retag(entry.Tags, addTags, removeTags)
+			if strings.Join(tags, ",") == strings.Join(models.NormalizeTags(entry.Tags), ",") {
+				return nil
+			}
+
+			originals = append(originals, entry)
+			entry.Tags = tags
+			entry.UpdatedAt = now
+			keys = append(keys, append([]byte(nil), k...))
+			changed = append(changed, entry)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for i, k := range keys {
+			data, err := json.Marshal(changed[i])
+			if err != nil {
+				return err
+			}
+			if err := b.Put(k, data); err != nil {
+				return err
+			}
+			if err := recordEntryUpdate(tx, &originals[i], &changed[i]); err != nil {
+				return err
+			}
+		}
+
+		updated = len(keys)
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to tag entries: %w", err)
+	}
+
+	return updated, nil
+}
+
+// retag returns the normalized tags with adds applied, then removes
+func retag(tags, addTags, removeTags []string) []string {
+	remove := make(map[string]bool, len(removeTags))
+	for _, tag := range removeTags {
+		remove[tag] = true
+	}
+
+	merged := make([]string, 0, len(tags)+len(addTags))
+	merged = append(merged, tags...)
+	merged = append(merged, addTags...)
+
+	result := make([]string, 0, len(merged))
+	for _, tag := range models.NormalizeTags(merged) {
+		if !remove[tag] {
+			result = append(result, tag)
+		}
+	}
+	return result
+}
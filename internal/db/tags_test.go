@@ -0,0 +1,116 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBulkTag(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	other, _ := store.CreateProject("Other", "/other")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	tagged, _ := store.CreateEntry(project.ID, 30, "Tagged", "", false, base)
+	store.SetEntryTags(tagged.ID, []string{"dev", "meeting"})
+	plain, _ := store.CreateEntry(project.ID, 30, "Plain", "", false, base.Add(time.Hour))
+	locked, _ := store.CreateEntry(project.ID, 30, "Locked", "", false, base)
+	store.SetEntryLocked(locked.ID, true)
+	early, _ := store.CreateEntry(project.ID, 30, "Before range", "", false, base.Add(-48*time.Hour))
+	elsewhere, _ := store.CreateEntry(other.ID, 30, "Other project", "", false, base)
+
+	start := base.Add(-time.Hour)
+	end := base.Add(24 * time.Hour)
+
+	// Adds dedupe against existing tags and each other
+	updated, err := store.BulkTag(project.ID, &start, &end, []string{"Review", "dev", "review"}, nil)
+	if err != nil {
+		t.Fatalf("BulkTag() error = %v", err)
+	}
+	if updated != 2 {
+		t.Errorf("Expected 2 entries updated, got %d", updated)
+	}
+
+	tags := func(id string) string {
+		entry, _ := store.GetEntry(id)
+		return strings.Join(entry.Tags, ",")
+	}
+	if got := tags(tagged.ID); got != "dev,meeting,review" {
+		t.Errorf("Expected tagged entry 'dev,meeting,review', got %q", got)
+	}
+	if got := tags(plain.ID); got != "dev,review" {
+		t.Errorf("Expected plain entry 'dev,review', got %q", got)
+	}
+	for _, id := range []string{locked.ID, early.ID, elsewhere.ID} {
+		if got := tags(id); got != "" {
+			t.Errorf("Expected entry %s outside the filter or locked to stay untagged, got %q", id, got)
+		}
+	}
+
+	// Repeating the add changes nothing
+	if updated, _ := store.BulkTag(project.ID, &start, &end, []string{"review"}, nil); updated != 0 {
+		t.Errorf("Expected no updates for an existing tag, got %d", updated)
+	}
+
+	// Removes no-op on absent tags and only count changed entries
+	updated, err = store.BulkTag(project.ID, nil, nil, nil, []string{"MEETING", "absent"})
+	if err != nil {
+		t.Fatalf("BulkTag() error = %v", err)
+	}
+	if updated != 1 || tags(tagged.ID) != "dev,review" {
+		t.Errorf("Expected meeting removed from 1 entry, got %d: %q", updated, tags(tagged.ID))
+	}
+
+	// Add and remove together across all projects; removes win
+	updated, _ = store.BulkTag("", nil, nil, []string{"billable", "dev"}, []string{"dev"})
+	if updated != 4 || tags(elsewhere.ID) != "billable" || tags(plain.ID) != "billable,review" {
+		t.Errorf("Expected 4 updated with dev removed, got %d: %q %q", updated, tags(elsewhere.ID), tags(plain.ID))
+	}
+
+	if _, err := store.BulkTag("", nil, nil, []string{" "}, nil); err == nil {
+		t.Error("Expected error without tags")
+	}
+}
+
+func TestTagFilterAndBreakdown(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	now := time.Now()
+
+	bugfix, _ := store.CreateEntry(project.ID, 30, "Fix crash", "", false, now)
+	store.SetEntryTags(bugfix.ID, []string{"Bugfix"})
+	both, _ := store.CreateEntry(project.ID, 60, "Pairing on a fix", "", false, now)
+	store.SetEntryTags(both.ID, []string{"bugfix", "meeting"})
+	store.CreateEntry(project.ID, 45, "Untagged", "", false, now)
+
+	entries, err := store.ListEntriesFiltered(project.ID, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("ListEntriesFiltered() error = %v", err)
+	}
+
+	if got := FilterByTag(entries, " BUGFIX "); len(got) != 2 {
+		t.Errorf("Expected 2 bugfix entries, got %d", len(got))
+	}
+	if got := FilterByTag(entries, "meeting"); len(got) != 1 || got[0].ID != both.ID {
+		t.Errorf("Expected only the meeting entry, got %v", got)
+	}
+	if got := FilterByTag(entries, "research"); len(got) != 0 {
+		t.Errorf("Expected no research entries, got %v", got)
+	}
+	if got := FilterByTag(entries, ""); len(got) != 3 {
+		t.Errorf("Expected empty tag to match every entry, got %d", len(got))
+	}
+
+	stats, err := store.GetStatistics(project.ID, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("GetStatistics() error = %v", err)
+	}
+	if len(stats.TagBreakdown) != 2 || stats.TagBreakdown["bugfix"] != 90 || stats.TagBreakdown["meeting"] != 60 {
+		t.Errorf("Expected bugfix 90 and meeting 60 minutes, got %v", stats.TagBreakdown)
+	}
+}
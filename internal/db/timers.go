@@ -0,0 +1,342 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/utils"
+	bolt "go.etcd.io/bbolt"
+)
+
+// timersBucket holds active timers keyed by project ID, one per project
+const timersBucket = "timers"
+
+// StartTimer starts a timer for a project
+// Fails if the project already has an active timer
+func (s *Store) StartTimer(projectID string, now time.Time) (*models.Timer, error) {
+	timer := &models.Timer{
+		ProjectID: projectID,
+		StartedAt: now,
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(projectsBucket)).Get([]byte(projectID)) == nil {
+			return fmt.Errorf("project not found")
+		}
+
+		b := tx.Bucket([]byte(timersBucket))
+		if b.Get([]byte(projectID)) != nil {
+			return fmt.Errorf("a timer is already running for this project")
+		}
+
+		data, err := json.Marshal(timer)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte(projectID), data)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	return timer, nil
+}
+
+// GetTimer returns the active timer for a project, or nil if none is running
+func (s *Store) GetTimer(projectID string) (*models.Timer, error) {
+	var timer *models.Timer
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket([]byte(timersBucket)).Get([]byte(projectID))
+		if data == nil {
+			return nil
+		}
+		timer = &models.Timer{}
+		return json.Unmarshal(data, timer)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timer: %w", err)
+	}
+
+	return timer, nil
+}
+
+// ListTimers returns all active timers, oldest first
+func (s *Store) ListTimers() ([]*models.Timer, error) {
+	var timers []*models.Timer
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(timersBucket)).ForEach(func(k, v []byte) error {
+			var timer models.Timer
+			if err := json.Unmarshal(v, &timer); err != nil {
+				return err
+			}
+			timers = append(timers, &timer)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timers: %w", err)
+	}
+
+	sort.Slice(timers, func(i, j int) bool {
+		return timers[i].StartedAt.Before(timers[j].StartedAt)
+	})
+
+	return timers, nil
+}
+
+// PauseTimer pauses a project's running timer
+func (s *Store) PauseTimer(projectID string, now time.Time) (*models.Timer, error) {
+	return s.modifyTimer(projectID, func(timer *models.Timer) error {
+		if timer.Paused() {
+			return fmt.Errorf("timer is already paused")
+		}
+		timer.Pauses = append(timer.Pauses, models.TimerPause{Start: now})
+		return nil
+	})
+}
+
+// ResumeTimer resumes a project's paused timer
+func (s *Store) ResumeTimer(projectID string, now time.Time) (*models.Timer, error) {
+	return s.modifyTimer(projectID, func(timer *models.Timer) error {
+		if !timer.Paused() {
+			return fmt.Errorf("timer is not paused")
+		}
+		timer.Pauses[len(timer.Pauses)-1].End = &now
+		return nil
+	})
+}
+
+// StopTimer stops a project's timer and records the tracked time as an entry
+// The timer is removed and the entry created in a single transaction.
+// Elapsed time is rounded using the timer_rounding setting and capped at max_timer_minutes;
+// a capped entry is flagged as needing adjustment so the real time gets filled in.
+func (s *Store) StopTimer(projectID, message string, invoiced bool, now time.Time) (*models.Entry, error) {
+	var entry *models.Entry
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		entry, err = stopTimer(tx, projectID, message, invoiced, now, "stopped timer")
+		return err
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+// CloseStaleTimers stops every timer that has run past max_timer_minutes, logging the capped
+// time as flagged entries (see StopTimer). Does nothing when no cap is configured.
+func (s *Store) CloseStaleTimers(now time.Time) ([]*models.Entry, error) {
+	var closed []*models.Entry
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		limit, err := maxTimerMinutes(tx)
+		if err != nil || limit <= 0 {
+			return err
+		}
+
+		// Collect first; the bucket must not be modified while iterating
+		var stale []string
+		err = tx.Bucket([]byte(timersBucket)).ForEach(func(k, v []byte) error {
+			var timer models.Timer
+			if err := json.Unmarshal(v, &timer); err != nil {
+				return err
+			}
+			if int64(timer.Elapsed(now)/time.Minute) > limit {
+				stale = append(stale, string(k))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, projectID := range stale {
+			entry, err := stopTimer(tx, projectID, "", false, now, "closed stale timer")
+			if err != nil {
+				return err
+			}
+			closed = append(closed, entry)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to close stale timers: %w", err)
+	}
+
+	return closed, nil
+}
+
+// maxTimerMinutes reads the max_timer_minutes setting within a transaction (0 = no cap)
+func maxTimerMinutes(tx *bolt.Tx) (int64, error) {
+	value := string(tx.Bucket([]byte(settingsBucket)).Get([]byte(SettingMaxTimerMinutes)))
+	if value == "" {
+		return 0, nil
+	}
+	minutes, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", SettingMaxTimerMinutes, value, err)
+	}
+	return minutes, nil
+}
+
+// stopTimer implements StopTimer within a write transaction; detail is recorded in the audit log
+func stopTimer(tx *bolt.Tx, projectID, message string, invoiced bool, now time.Time, detail string) (*models.Entry, error) {
+	tb := tx.Bucket([]byte(timersBucket))
+	data := tb.Get([]byte(projectID))
+	if data == nil {
+		return nil, fmt.Errorf("no timer running for this project")
+	}
+
+	var timer models.Timer
+	if err := json.Unmarshal(data, &timer); err != nil {
+		return nil, err
+	}
+	if message == "" {
+		message = timer.Message
+	}
+	lengthLimit, err := maxMessageLength(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMessageLength(message, lengthLimit); err != nil {
+		return nil, err
+	}
+
+	policy := string(tx.Bucket([]byte(settingsBucket)).Get([]byte(SettingTimerRounding)))
+	minutes, err := utils.RoundToMinutes(timer.Elapsed(now), policy)
+	if err != nil {
+		return nil, err
+	}
+	if minutes < 1 {
+		return nil, fmt.Errorf("timer has run for less than a minute, discard it instead")
+	}
+
+	entry := &models.Entry{
+		ID:        uuid.New().String(),
+		ProjectID: projectID,
+		Duration:  minutes,
+		Message:   message,
+		Source:    hostname(),
+		Invoiced:  invoiced,
+		CreatedAt: timer.StartedAt,
+		UpdatedAt: now,
+	}
+
+	// A forgotten timer logs the cap, flagged for review, instead of days of work
+	limit, err := maxTimerMinutes(tx)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && minutes > limit {
+		entry.Duration = limit
+		entry.NeedsAdjustment = true
+		entry.AdjustmentNote = fmt.Sprintf("timer ran %d minutes, capped at %d by %s", minutes, limit, SettingMaxTimerMinutes)
+	}
+
+	eb := tx.Bucket([]byte(entriesBucket))
+	seq, err := eb.NextSequence()
+	if err != nil {
+		return nil, err
+	}
+	entry.Seq = seq
+
+	entryData, err := json.Marshal(entry)
+	if err != nil {
+		return nil, err
+	}
+	if err := eb.Put([]byte(entry.ID), entryData); err != nil {
+		return nil, err
+	}
+	if err := indexEntry(tx, projectID, entry.ID); err != nil {
+		return nil, err
+	}
+	if err := recordAudit(tx, AuditCreate, AuditTargetEntry, entry.ID, diffEntry(&models.Entry{}, entry), detail); err != nil {
+		return nil, err
+	}
+
+	return entry, tb.Delete([]byte(projectID))
+}
+
+// DiscardTimer removes a project's timer without recording an entry
+func (s *Store) DiscardTimer(projectID string) error {
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(timersBucket))
+		if b.Get([]byte(projectID)) == nil {
+			return fmt.Errorf("no timer running for this project")
+		}
+		return b.Delete([]byte(projectID))
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to discard timer: %w", err)
+	}
+
+	return nil
+}
+
+// SetTimerMessage notes what a running timer is for; StopTimer uses it when given no message
+// The message becomes an entry's, so it must fit max_message_length.
+func (s *Store) SetTimerMessage(projectID, message string) (*models.Timer, error) {
+	message = strings.TrimSpace(message)
+	limit, err := s.GetMaxMessageLength()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMessageLength(message, limit); err != nil {
+		return nil, err
+	}
+
+	return s.modifyTimer(projectID, func(timer *models.Timer) error {
+		timer.Message = message
+		return nil
+	})
+}
+
+// modifyTimer loads a project's timer, applies mutate, and saves it in one transaction
+func (s *Store) modifyTimer(projectID string, mutate func(timer *models.Timer) error) (*models.Timer, error) {
+	var timer models.Timer
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(timersBucket))
+		data := b.Get([]byte(projectID))
+		if data == nil {
+			return fmt.Errorf("no timer running for this project")
+		}
+
+		if err := json.Unmarshal(data, &timer); err != nil {
+			return err
+		}
+
+		if err := mutate(&timer); err != nil {
+			return err
+		}
+
+		updatedData, err := json.Marshal(timer)
+		if err != nil {
+			return err
+		}
+
+		return b.Put([]byte(projectID), updatedData)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update timer: %w", err)
+	}
+
+	return &timer, nil
+}
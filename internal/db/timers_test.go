@@ -0,0 +1,290 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimerSurvivesRestart(t *testing.T) {
+	store, dbPath := setupTestDB(t)
+
+	project, _ := store.CreateProject("Test", "/path")
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if _, err := store.StartTimer(project.ID, start); err != nil {
+		t.Fatalf("Failed to start timer: %v", err)
+	}
+	store.PauseTimer(project.ID, start.Add(30*time.Minute))
+	store.ResumeTimer(project.ID, start.Add(45*time.Minute))
+
+	// Simulate a restart by reopening the same database
+	store.Close()
+	store, err := New(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to reopen database: %v", err)
+	}
+	defer store.Close()
+
+	timers, err := store.ListTimers()
+	if err != nil {
+		t.Fatalf("Failed to list timers: %v", err)
+	}
+	if len(timers) != 1 {
+		t.Fatalf("Expected 1 recovered timer, got %d", len(timers))
+	}
+
+	timer := timers[0]
+	if timer.ProjectID != project.ID {
+		t.Errorf("Expected timer for project %s, got %s", project.ID, timer.ProjectID)
+	}
+	if !timer.StartedAt.Equal(start) {
+		t.Errorf("Expected start %v, got %v", start, timer.StartedAt)
+	}
+	if timer.Paused() {
+		t.Error("Expected recovered timer to be running")
+	}
+
+	// 1h wall time minus the 15m pause
+	if elapsed := timer.Elapsed(start.Add(time.Hour)); elapsed != 45*time.Minute {
+		t.Errorf("Expected 45m elapsed, got %v", elapsed)
+	}
+}
+
+func TestTimerOnePerProject(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	now := time.Now()
+
+	if _, err := store.StartTimer(project.ID, now); err != nil {
+		t.Fatalf("Failed to start timer: %v", err)
+	}
+	if _, err := store.StartTimer(project.ID, now); err == nil {
+		t.Error("Expected error starting a second timer for the same project")
+	}
+	if _, err := store.StartTimer("missing", now); err == nil {
+		t.Error("Expected error starting a timer for a nonexistent project")
+	}
+}
+
+func TestTimerPausedElapsed(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	store.StartTimer(project.ID, start)
+	timer, err := store.PauseTimer(project.ID, start.Add(20*time.Minute))
+	if err != nil {
+		t.Fatalf("Failed to pause timer: %v", err)
+	}
+
+	if !timer.Paused() {
+		t.Error("Expected timer to be paused")
+	}
+	// Time spent paused does not count
+	if elapsed := timer.Elapsed(start.Add(2 * time.Hour)); elapsed != 20*time.Minute {
+		t.Errorf("Expected 20m elapsed while paused, got %v", elapsed)
+	}
+	if _, err := store.PauseTimer(project.ID, start.Add(time.Hour)); err == nil {
+		t.Error("Expected error pausing an already paused timer")
+	}
+}
+
+func TestStopTimer(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	store.StartTimer(project.ID, start)
+	store.SetSetting(SettingTimerRounding, "up")
+
+	entry, err := store.StopTimer(project.ID, "Pairing", false, start.Add(90*time.Minute+10*time.Second))
+	if err != nil {
+		t.Fatalf("Failed to stop timer: %v", err)
+	}
+
+	if entry.Duration != 91 {
+		t.Errorf("Expected 91 minutes with 'up' rounding, got %d", entry.Duration)
+	}
+	if !entry.CreatedAt.Equal(start) {
+		t.Errorf("Expected entry dated at timer start, got %v", entry.CreatedAt)
+	}
+
+	timer, _ := store.GetTimer(project.ID)
+	if timer != nil {
+		t.Error("Expected timer to be removed after stopping")
+	}
+
+	entries, _ := store.ListEntries(project.ID)
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(entries))
+	}
+
+	if _, err := store.StopTimer(project.ID, "", false, time.Now()); err == nil {
+		t.Error("Expected error stopping a timer that is not running")
+	}
+}
+
+func TestStopTimerUnderAMinute(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	start := time.Now()
+	store.StartTimer(project.ID, start)
+
+	if _, err := store.StopTimer(project.ID, "", false, start.Add(10*time.Second)); err == nil {
+		t.Error("Expected error stopping a timer under a minute")
+	}
+
+	// The timer is kept so it can be discarded explicitly
+	if err := store.DiscardTimer(project.ID); err != nil {
+		t.Fatalf("Failed to discard timer: %v", err)
+	}
+	entries, _ := store.ListEntries(project.ID)
+	if len(entries) != 0 {
+		t.Errorf("Expected no entries after discard, got %d", len(entries))
+	}
+}
+
+func TestTimerMessage(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	if _, err := store.SetTimerMessage(project.ID, "Nothing running"); err == nil {
+		t.Error("Expected error setting a message without a timer")
+	}
+
+	store.StartTimer(project.ID, start)
+	timer, err := store.SetTimerMessage(project.ID, "  Reviewing PR 42 ")
+	if err != nil {
+		t.Fatalf("SetTimerMessage() error = %v", err)
+	}
+	if timer.Message != "Reviewing PR 42" {
+		t.Errorf("Expected trimmed message, got %q", timer.Message)
+	}
+
+	// A second start is refused and keeps the running timer
+	if _, err := store.StartTimer(project.ID, start.Add(time.Hour)); err == nil {
+		t.Error("Expected error starting a second timer")
+	}
+
+	entry, err := store.StopTimer(project.ID, "", false, start.Add(30*time.Minute))
+	if err != nil {
+		t.Fatalf("StopTimer() error = %v", err)
+	}
+	if entry.Message != "Reviewing PR 42" || entry.Duration != 30 {
+		t.Errorf("Expected 30 minutes with the start message, got %d %q", entry.Duration, entry.Message)
+	}
+
+	// An explicit stop message wins
+	store.StartTimer(project.ID, start)
+	store.SetTimerMessage(project.ID, "Planned")
+	entry, _ = store.StopTimer(project.ID, "Actual", false, start.Add(time.Hour))
+	if entry.Message != "Actual" {
+		t.Errorf("Expected the stop message to win, got %q", entry.Message)
+	}
+}
+
+func TestStopTimerCapped(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	start := time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC)
+	store.SetSetting(SettingMaxTimerMinutes, "480")
+
+	// Forgotten overnight: 16 hours
+	store.StartTimer(project.ID, start)
+	entry, err := store.StopTimer(project.ID, "Forgot to stop", false, start.Add(16*time.Hour))
+	if err != nil {
+		t.Fatalf("StopTimer() error = %v", err)
+	}
+	if entry.Duration != 480 {
+		t.Errorf("Expected duration capped at 480, got %d", entry.Duration)
+	}
+	if !entry.NeedsAdjustment || !strings.Contains(entry.AdjustmentNote, "960 minutes") {
+		t.Errorf("Expected capped entry flagged with the real time, got %v %q", entry.NeedsAdjustment, entry.AdjustmentNote)
+	}
+
+	// Within the cap nothing is flagged
+	store.StartTimer(project.ID, start)
+	entry, _ = store.StopTimer(project.ID, "Normal", false, start.Add(2*time.Hour))
+	if entry.Duration != 120 || entry.NeedsAdjustment {
+		t.Errorf("Expected an unflagged 120 minute entry, got %d (flagged %v)", entry.Duration, entry.NeedsAdjustment)
+	}
+}
+
+func TestCloseStaleTimers(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	stale, _ := store.CreateProject("Stale", "/stale")
+	fresh, _ := store.CreateProject("Fresh", "/fresh")
+	now := time.Date(2026, 3, 3, 9, 0, 0, 0, time.UTC)
+
+	store.StartTimer(stale.ID, now.Add(-15*time.Hour))
+	store.StartTimer(fresh.ID, now.Add(-time.Hour))
+
+	// No cap configured: nothing is closed
+	closed, err := store.CloseStaleTimers(now)
+	if err != nil || len(closed) != 0 {
+		t.Fatalf("Expected no timers closed without a cap, got %d (%v)", len(closed), err)
+	}
+
+	store.SetSetting(SettingMaxTimerMinutes, "600")
+	closed, err = store.CloseStaleTimers(now)
+	if err != nil {
+		t.Fatalf("CloseStaleTimers() error = %v", err)
+	}
+	if len(closed) != 1 || closed[0].ProjectID != stale.ID || closed[0].Duration != 600 || !closed[0].NeedsAdjustment {
+		t.Fatalf("Expected the stale timer closed at 600 minutes and flagged, got %+v", closed)
+	}
+
+	if timer, _ := store.GetTimer(stale.ID); timer != nil {
+		t.Error("Expected the stale timer to be removed")
+	}
+	if timer, _ := store.GetTimer(fresh.ID); timer == nil {
+		t.Error("Expected the fresh timer to keep running")
+	}
+}
+
+func TestTimerMessageLength(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	store.SetSetting(SettingMaxMessageLength, "10")
+
+	store.StartTimer(project.ID, start)
+	if _, err := store.SetTimerMessage(project.ID, strings.Repeat("x", 11)); err == nil {
+		t.Error("Expected a timer message over max_message_length to be rejected")
+	}
+	if _, err := store.StopTimer(project.ID, strings.Repeat("x", 11), false, start.Add(time.Hour)); err == nil {
+		t.Error("Expected a stop message over max_message_length to be rejected")
+	}
+
+	// A message set before the limit was lowered cannot reach an entry either
+	store.SetSetting(SettingMaxMessageLength, "")
+	store.SetTimerMessage(project.ID, "Reviewing PR 42")
+	store.SetSetting(SettingMaxMessageLength, "10")
+	if _, err := store.StopTimer(project.ID, "", false, start.Add(time.Hour)); err == nil {
+		t.Error("Expected the stored timer message to be checked on stop")
+	}
+
+	// The timer is kept, so it can be stopped with a shorter message
+	entry, err := store.StopTimer(project.ID, "Review", false, start.Add(time.Hour))
+	if err != nil || entry.Message != "Review" {
+		t.Errorf("Expected the timer to stop with a short message, got %v (err %v)", entry, err)
+	}
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+	bolt "go.etcd.io/bbolt"
+)
+
+// trashBucket holds entries removed by bulk operations, keyed by entry ID
+const trashBucket = "trash"
+
+// DeleteEntriesFiltered moves all entries matching the filters to the trash in one transaction
+// Locked entries are skipped. Returns the number of entries moved.
+func (s *Store) DeleteEntriesFiltered(projectID string, startDate, endDate *time.Time, invoicedFilter *bool) (int, error) {
+	deleted := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		deleted, err = trashEntries(tx, projectID, func(entry *models.Entry) bool {
+			return matchesFilter(entry, projectID, startDate, endDate, invoicedFilter)
+		}, "bulk delete")
+		return err
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete entries: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// ClearProjectEntries moves every unlocked entry of a project to the trash in one transaction,
+// so tracking can restart without deleting the project; the project and its settings are kept.
+// Returns the number of entries moved.
+func (s *Store) ClearProjectEntries(projectID string) (int, error) {
+	cleared := 0
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket([]byte(projectsBucket)).Get([]byte(projectID)) == nil {
+			return fmt.Errorf("project not found")
+		}
+
+		var err error
+		cleared, err = trashEntries(tx, projectID, func(entry *models.Entry) bool {
+			return entry.ProjectID == projectID
+		}, "clear project")
+		return err
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear project entries: %w", err)
+	}
+
+	return cleared, nil
+}
+
+// trashEntries moves the unlocked entries of projectID ("" = all projects) accepted by match to
+// the trash, auditing each with detail. Returns the number of entries moved.
+func trashEntries(tx *bolt.Tx, projectID string, match func(entry *models.Entry) bool, detail string) (int, error) {
+	eb := tx.Bucket([]byte(entriesBucket))
+	tb := tx.Bucket([]byte(trashBucket))
+	now := time.Now()
+
+	// Collect keys first; deleting while iterating a cursor skips items
+	var keys [][]byte
+	var trashed []models.TrashedEntry
+	err := forEachEntry(tx, projectID, func(k, v []byte) error {
+		var entry models.Entry
+		if err := json.Unmarshal(v, &entry); err != nil {
+			return err
+		}
+		if entry.Locked || !match(&entry) {
+			return nil
+		}
+		keys = append(keys, append([]byte(nil), k...))
+		trashed = append(trashed, models.TrashedEntry{Entry: entry, DeletedAt: now})
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i, k := range keys {
+		data, err := json.Marshal(trashed[i])
+		if err != nil {
+			return 0, err
+		}
+		if err := tb.Put(k, data); err != nil {
+			return 0, err
+		}
+		if err := eb.Delete(k); err != nil {
+			return 0, err
+		}
+		if err := unindexEntry(tx, trashed[i].ProjectID, trashed[i].ID); err != nil {
+			return 0, err
+		}
+		if err := recordAudit(tx, AuditTrash, AuditTargetEntry, string(k), nil, detail); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(keys), nil
+}
+
+// ListTrash returns trashed entries, most recently deleted first
+func (s *Store) ListTrash() ([]*models.TrashedEntry, error) {
+	var trashed []*models.TrashedEntry
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket([]byte(trashBucket)).ForEach(func(k, v []byte) error {
+			var entry models.TrashedEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return err
+			}
+			trashed = append(trashed, &entry)
+			return nil
+		})
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	sort.Slice(trashed, func(i, j int) bool {
+		return trashed[i].DeletedAt.After(trashed[j].DeletedAt)
+	})
+
+	return trashed, nil
+}
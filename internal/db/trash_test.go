@@ -0,0 +1,133 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeleteEntriesFiltered(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project1, _ := store.CreateProject("Project 1", "/path1")
+	project2, _ := store.CreateProject("Project 2", "/path2")
+
+	jan := time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC)
+	feb := time.Date(2026, 2, 15, 10, 0, 0, 0, time.UTC)
+
+	store.CreateEntry(project1.ID, 60, "Jan uninvoiced", "", false, jan)
+	store.CreateEntry(project1.ID, 60, "Jan invoiced", "", true, jan)
+	store.CreateEntry(project1.ID, 60, "Feb uninvoiced", "", false, feb)
+	locked, _ := store.CreateEntry(project1.ID, 60, "Jan locked", "", false, jan)
+	store.CreateEntry(project2.ID, 60, "Other project", "", false, jan)
+
+	store.SetEntryLocked(locked.ID, true)
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 1, 31, 23, 59, 59, 0, time.UTC)
+	uninvoiced := false
+
+	deleted, err := store.DeleteEntriesFiltered(project1.ID, &start, &end, &uninvoiced)
+	if err != nil {
+		t.Fatalf("Failed to delete entries: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 deleted entry, got %d", deleted)
+	}
+
+	remaining, _ := store.ListEntries(project1.ID)
+	if len(remaining) != 3 {
+		t.Fatalf("Expected 3 remaining entries, got %d", len(remaining))
+	}
+	for _, entry := range remaining {
+		if entry.Message == "Jan uninvoiced" {
+			t.Error("Expected matching entry to be deleted")
+		}
+	}
+
+	if other, _ := store.ListEntries(project2.ID); len(other) != 1 {
+		t.Errorf("Expected other project's entry to survive, got %d", len(other))
+	}
+
+	trashed, _ := store.ListTrash()
+	if len(trashed) != 1 || trashed[0].Message != "Jan uninvoiced" {
+		t.Errorf("Expected deleted entry in trash, got %+v", trashed)
+	}
+}
+
+func TestDeleteEntriesFilteredSkipsLocked(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Test", "/path")
+	now := time.Now()
+
+	store.CreateEntry(project.ID, 30, "Unlocked", "", false, now)
+	locked, _ := store.CreateEntry(project.ID, 30, "Locked", "", true, now)
+	store.SetEntryLocked(locked.ID, true)
+
+	deleted, err := store.DeleteEntriesFiltered(project.ID, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("Failed to delete entries: %v", err)
+	}
+	if deleted != 1 {
+		t.Errorf("Expected 1 deleted entry, got %d", deleted)
+	}
+
+	survivor, err := store.GetEntry(locked.ID)
+	if err != nil {
+		t.Fatalf("Expected locked entry to survive: %v", err)
+	}
+	if !survivor.Locked {
+		t.Error("Expected surviving entry to remain locked")
+	}
+}
+
+func TestClearProjectEntries(t *testing.T) {
+	store, _ := setupTestDB(t)
+	defer store.Close()
+
+	project, _ := store.CreateProject("Restart", "/path")
+	store.SetProjectRate(project.ID, 90, "EUR")
+	other, _ := store.CreateProject("Other", "/other")
+
+	store.CreateEntry(project.ID, 30, "First", "", false, time.Now())
+	store.CreateEntry(project.ID, 60, "Second", "", true, time.Now())
+	locked, _ := store.CreateEntry(project.ID, 15, "Locked", "", false, time.Now())
+	store.SetEntryLocked(locked.ID, true)
+	kept, _ := store.CreateEntry(other.ID, 45, "Other project", "", false, time.Now())
+
+	cleared, err := store.ClearProjectEntries(project.ID)
+	if err != nil {
+		t.Fatalf("ClearProjectEntries() error = %v", err)
+	}
+	if cleared != 2 {
+		t.Errorf("Expected 2 entries cleared, got %d", cleared)
+	}
+
+	// The project and its settings survive
+	survivor, err := store.GetProject(project.ID)
+	if err != nil {
+		t.Fatalf("Project should survive: %v", err)
+	}
+	if survivor.HourlyRate != 90 || survivor.Currency != "EUR" {
+		t.Errorf("Project settings should be kept, got %+v", survivor)
+	}
+
+	remaining, _ := store.ListEntries(project.ID)
+	if len(remaining) != 1 || remaining[0].ID != locked.ID {
+		t.Errorf("Only the locked entry should remain, got %d entries", len(remaining))
+	}
+	if _, err := store.GetEntry(kept.ID); err != nil {
+		t.Errorf("Other project's entry should be untouched: %v", err)
+	}
+
+	trashed, _ := store.ListTrash()
+	if len(trashed) != 2 {
+		t.Errorf("Expected 2 trashed entries, got %d", len(trashed))
+	}
+
+	if _, err := store.ClearProjectEntries("missing"); err == nil {
+		t.Error("Expected an error for an unknown project")
+	}
+}
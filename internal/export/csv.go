@@ -0,0 +1,116 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// WriteCSV writes entries as CSV with a header row
+// projectNames maps project IDs to display names; unknown IDs fall back to the raw ID
+func WriteCSV(w io.Writer, entries []*models.Entry, projectNames map[string]string) error {
+	encoder, err := db.NewCSVEncoder(w, projectNames)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if err := encoder.Encode(entry); err != nil {
+			return err
+		}
+	}
+
+	return encoder.Close()
+}
+
+// UntaggedFile is the file name used by ExportByTag for entries matching none of the tags
+const UntaggedFile = "untagged.csv"
+
+var unsafeFilenameChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// TagFilename returns a path-safe CSV file name for a tag
+func TagFilename(tag string) string {
+	name := strings.Trim(unsafeFilenameChars.ReplaceAllString(tag, "_"), "._")
+	if name == "" {
+		name = "_"
+	}
+	return "tag_" + name + ".csv"
+}
+
+// ExportByTag writes one CSV per tag plus UntaggedFile into dir
+// If tags is empty, every tag found on the entries is used. Entries carrying several
+// of the tags appear in each matching file; entries matching none go to UntaggedFile.
+// Returns the paths of the files written.
+func ExportByTag(dir string, entries []*models.Entry, tags []string, projectNames map[string]string) ([]string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	tags = models.NormalizeTags(tags)
+	if len(tags) == 0 {
+		var all []string
+		for _, entry := range entries {
+			all = append(all, entry.Tags...)
+		}
+		tags = models.NormalizeTags(all)
+	}
+
+	byFile := make(map[string][]*models.Entry)
+	files := make([]string, 0, len(tags)+1)
+	for _, tag := range tags {
+		name := TagFilename(tag)
+		if _, exists := byFile[name]; !exists {
+			byFile[name] = []*models.Entry{}
+			files = append(files, name)
+		}
+	}
+	byFile[UntaggedFile] = []*models.Entry{}
+	files = append(files, UntaggedFile)
+
+	for _, entry := range entries {
+		matched := make(map[string]bool)
+		for _, tag := range tags {
+			if entry.HasTag(tag) {
+				name := TagFilename(tag)
+				if !matched[name] {
+					matched[name] = true
+					byFile[name] = append(byFile[name], entry)
+				}
+			}
+		}
+		if len(matched) == 0 {
+			byFile[UntaggedFile] = append(byFile[UntaggedFile], entry)
+		}
+	}
+
+	paths := make([]string, 0, len(files))
+	for _, name := range files {
+		path := filepath.Join(dir, name)
+		if err := writeCSVFile(path, byFile[name], projectNames); err != nil {
+			return paths, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+func writeCSVFile(path string, entries []*models.Entry, projectNames map[string]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if err := WriteCSV(file, entries, projectNames); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,112 @@
+package export
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	return records
+}
+
+func TestTagFilename(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want string
+	}{
+		{"backend", "tag_backend.csv"},
+		{"client/acme", "tag_client_acme.csv"},
+		{"../etc", "tag_etc.csv"},
+		{"a b:c", "tag_a_b_c.csv"},
+		{"///", "tag__.csv"},
+	}
+
+	for _, tt := range tests {
+		if got := TagFilename(tt.tag); got != tt.want {
+			t.Errorf("TagFilename(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestExportByTag(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	entries := []*models.Entry{
+		{ID: "e1", ProjectID: "p1", Duration: 60, Message: "API", Tags: []string{"backend"}, CreatedAt: now},
+		{ID: "e2", ProjectID: "p1", Duration: 30, Message: "UI", Tags: []string{"frontend"}, CreatedAt: now},
+		{ID: "e3", ProjectID: "p1", Duration: 90, Message: "Full stack", Tags: []string{"backend", "frontend"}, CreatedAt: now},
+		{ID: "e4", ProjectID: "p1", Duration: 15, Message: "Call", CreatedAt: now},
+	}
+
+	paths, err := ExportByTag(dir, entries, nil, map[string]string{"p1": "Project 1"})
+	if err != nil {
+		t.Fatalf("ExportByTag failed: %v", err)
+	}
+
+	if len(paths) != 3 {
+		t.Fatalf("Expected 3 files, got %d: %v", len(paths), paths)
+	}
+
+	expected := map[string][]string{
+		"tag_backend.csv":  {"e1", "e3"},
+		"tag_frontend.csv": {"e2", "e3"},
+		UntaggedFile:       {"e4"},
+	}
+
+	for name, ids := range expected {
+		records := readCSV(t, filepath.Join(dir, name))
+		if len(records) != len(ids)+1 {
+			t.Errorf("Expected %d rows in %s, got %d", len(ids)+1, name, len(records))
+			continue
+		}
+		for i, id := range ids {
+			if records[i+1][0] != id {
+				t.Errorf("Expected entry %s in %s row %d, got %s", id, name, i+1, records[i+1][0])
+			}
+			if records[i+1][1] != "Project 1" {
+				t.Errorf("Expected project name 'Project 1', got '%s'", records[i+1][1])
+			}
+		}
+	}
+}
+
+func TestExportByTagSubset(t *testing.T) {
+	dir := t.TempDir()
+
+	entries := []*models.Entry{
+		{ID: "e1", Tags: []string{"backend"}},
+		{ID: "e2", Tags: []string{"frontend"}},
+	}
+
+	paths, err := ExportByTag(dir, entries, []string{"Backend"}, nil)
+	if err != nil {
+		t.Fatalf("ExportByTag failed: %v", err)
+	}
+
+	if len(paths) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(paths))
+	}
+
+	// Entries outside the requested tag set land in the untagged file
+	records := readCSV(t, filepath.Join(dir, UntaggedFile))
+	if len(records) != 2 || records[1][0] != "e2" {
+		t.Errorf("Expected e2 in untagged file, got %v", records)
+	}
+}
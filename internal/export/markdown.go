@@ -0,0 +1,117 @@
+package export
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/techthos/clockwork/internal/db"
+)
+
+// AnnualSummaryMarkdown renders an annual summary as a Markdown report
+// projectNames maps project IDs to display names; unknown IDs are shown as "Unknown Project"
+func AnnualSummaryMarkdown(summary *db.AnnualSummary, projectNames map[string]string) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("# Annual Summary %d\n\n", summary.Year))
+
+	if summary.EntryCount == 0 {
+		builder.WriteString("No entries recorded for this year.\n")
+		return builder.String()
+	}
+
+	// Amount columns only when some of the time is priced
+	billed := len(summary.Amounts) > 0
+
+	builder.WriteString(fmt.Sprintf("- **Total:** %s (%d entries)\n", formatHours(summary.TotalMinutes), summary.EntryCount))
+	builder.WriteString(fmt.Sprintf("- **Invoiced:** %s%s\n", formatHours(summary.InvoicedMinutes), inParentheses(formatAmounts(summary.InvoicedAmounts))))
+	builder.WriteString(fmt.Sprintf("- **Outstanding:** %s%s\n", formatHours(summary.UninvoicedMinutes), inParentheses(formatAmounts(summary.OutstandingAmounts))))
+	if billed {
+		builder.WriteString(fmt.Sprintf("- **Billed:** %s\n", formatAmounts(summary.Amounts)))
+		if summary.UnpricedMinutes > 0 {
+			builder.WriteString(fmt.Sprintf("- **Without a rate:** %s\n", formatHours(summary.UnpricedMinutes)))
+		}
+	}
+	builder.WriteString("\n")
+
+	builder.WriteString("## Monthly Breakdown\n\n")
+	if billed {
+		builder.WriteString("| Month | Hours | Entries | Invoiced | Outstanding | Amount |\n")
+		builder.WriteString("|-------|------:|--------:|---------:|------------:|-------:|\n")
+	} else {
+		builder.WriteString("| Month | Hours | Entries | Invoiced | Outstanding |\n")
+		builder.WriteString("|-------|------:|--------:|---------:|------------:|\n")
+	}
+	for _, month := range summary.Months {
+		builder.WriteString(fmt.Sprintf("| %s | %s | %d | %s | %s |",
+			month.Month,
+			formatHours(month.TotalMinutes),
+			month.EntryCount,
+			formatHours(month.InvoicedMinutes),
+			formatHours(month.UninvoicedMinutes)))
+		if billed {
+			builder.WriteString(fmt.Sprintf(" %s |", formatAmounts(month.Amounts)))
+		}
+		builder.WriteString("\n")
+	}
+
+	builder.WriteString("\n## Project Breakdown\n\n")
+	if billed {
+		builder.WriteString("| Project | Hours | Amount |\n")
+		builder.WriteString("|---------|------:|-------:|\n")
+	} else {
+		builder.WriteString("| Project | Hours |\n")
+		builder.WriteString("|---------|------:|\n")
+	}
+
+	// Sort projects by time (descending)
+	projectIDs := make([]string, 0, len(summary.ProjectBreakdown))
+	for id := range summary.ProjectBreakdown {
+		projectIDs = append(projectIDs, id)
+	}
+	sort.Slice(projectIDs, func(i, j int) bool {
+		return summary.ProjectBreakdown[projectIDs[i]] > summary.ProjectBreakdown[projectIDs[j]]
+	})
+
+	for _, id := range projectIDs {
+		name, ok := projectNames[id]
+		if !ok {
+			name = "Unknown Project"
+		}
+		builder.WriteString(fmt.Sprintf("| %s | %s |", name, formatHours(summary.ProjectBreakdown[id])))
+		if billed {
+			builder.WriteString(fmt.Sprintf(" %s |", formatAmounts(summary.ProjectAmounts[id])))
+		}
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// formatAmounts formats amounts per currency, e.g. "450.00 EUR + 120.00 USD"; "" when there are none
+func formatAmounts(amounts map[string]float64) string {
+	currencies := make([]string, 0, len(amounts))
+	for currency := range amounts {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	parts := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		parts = append(parts, strings.TrimSpace(fmt.Sprintf("%.2f %s", amounts[currency], currency)))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// inParentheses wraps text as " (text)", or returns "" for empty text
+func inParentheses(text string) string {
+	if text == "" {
+		return ""
+	}
+	return " (" + text + ")"
+}
+
+// formatHours formats minutes as decimal hours
+func formatHours(minutes int64) string {
+	return fmt.Sprintf("%.2f h", float64(minutes)/60.0)
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/db"
+)
+
+func TestAnnualSummaryMarkdownAmounts(t *testing.T) {
+	summary := &db.AnnualSummary{
+		Year:               2025,
+		TotalMinutes:       150,
+		EntryCount:         2,
+		InvoicedMinutes:    60,
+		UninvoicedMinutes:  90,
+		Months:             make([]db.MonthSummary, 12),
+		ProjectBreakdown:   map[string]int64{"p1": 150},
+		Amounts:            map[string]float64{"EUR": 250},
+		InvoicedAmounts:    map[string]float64{"EUR": 100},
+		OutstandingAmounts: map[string]float64{"EUR": 150},
+		ProjectAmounts:     map[string]map[string]float64{"p1": {"EUR": 250}},
+	}
+	for i := range summary.Months {
+		summary.Months[i].Month = time.Month(i + 1)
+	}
+	summary.Months[time.March-1].Amounts = map[string]float64{"EUR": 250}
+
+	report := AnnualSummaryMarkdown(summary, map[string]string{"p1": "Client"})
+
+	for _, want := range []string{
+		"- **Invoiced:** 1.00 h (100.00 EUR)",
+		"- **Outstanding:** 1.50 h (150.00 EUR)",
+		"- **Billed:** 250.00 EUR",
+		"| Month | Hours | Entries | Invoiced | Outstanding | Amount |",
+		"| March | 0.00 h | 0 | 0.00 h | 0.00 h | 250.00 EUR |",
+		"| Client | 2.50 h | 250.00 EUR |",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("Expected report to contain %q, got:\n%s", want, report)
+		}
+	}
+
+	// Without any rate the report keeps its time-only layout
+	summary.Amounts, summary.InvoicedAmounts, summary.OutstandingAmounts, summary.ProjectAmounts = nil, nil, nil, nil
+	report = AnnualSummaryMarkdown(summary, map[string]string{"p1": "Client"})
+	if strings.Contains(report, "Amount") || strings.Contains(report, "Billed") {
+		t.Errorf("Expected no amounts without rates, got:\n%s", report)
+	}
+}
@@ -4,42 +4,67 @@ import (
 	"fmt"
 	"os/exec"
 	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/utils"
 )
 
 // GetAuthor retrieves the git author name from git config
+// Returns "" when no name is configured
 func GetAuthor(repoPath string) (string, error) {
 	cmd := exec.Command("git", "config", "user.name")
 	cmd.Dir = repoPath
 	output, err := cmd.Output()
 	if err != nil {
+		// git config exits with 1 when the key is unset
+		if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+			return "", nil
+		}
 		return "", fmt.Errorf("failed to get git author: %w", err)
 	}
 	return strings.TrimSpace(string(output)), nil
 }
 
+// GetCommitsSinceOptions controls which commits GetCommitsSinceWithOptions returns
+type GetCommitsSinceOptions struct {
+	NoMerges bool   // Leave out merge commits, whose subjects and timestamps skew the summary
+	Author   string // Only list commits whose author matches this git log --author pattern ("" = everyone)
+	// CountLines fills each commit's LinesChanged from git log --numstat, for FilterTrivialCommits
+	// and strategies weighing commits by size
+	CountLines bool
+}
+
 // GetCommitsSince retrieves commits from the repository since a specific commit hash
-// If sinceHash is empty, retrieves all commits from HEAD
+// If sinceHash is empty, retrieves all commits from HEAD. Merge commits are included
 func GetCommitsSince(repoPath, sinceHash string) ([]models.CommitInfo, error) {
+	return GetCommitsSinceWithOptions(repoPath, sinceHash, GetCommitsSinceOptions{})
+}
+
+// GetCommitsSinceWithOptions is GetCommitsSince with control over which commits are listed
+func GetCommitsSinceWithOptions(repoPath, sinceHash string, opts GetCommitsSinceOptions) ([]models.CommitInfo, error) {
 	absPath, err := filepath.Abs(repoPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to resolve repo path: %w", err)
 	}
 
-	// Build git log command
-	args := []string{
-		"log",
-		"--pretty=format:%H|%an|%s|%at",
+	// Filters and range shared by the commit listing and the line counts
+	var selection []string
+	if opts.NoMerges {
+		selection = append(selection, "--no-merges")
+	}
+	if opts.Author != "" {
+		selection = append(selection, "--author="+opts.Author)
 	}
-
 	if sinceHash != "" {
-		args = append(args, fmt.Sprintf("%s..HEAD", sinceHash))
+		selection = append(selection, fmt.Sprintf("%s..HEAD", sinceHash))
 	}
 
-	cmd := exec.Command("git", args...)
+	cmd := exec.Command("git", append(logArgs("--pretty=format:"+commitLogFormat), selection...)...)
 	cmd.Dir = absPath
 
 	output, err := cmd.Output()
@@ -47,16 +72,104 @@ func GetCommitsSince(repoPath, sinceHash string) ([]models.CommitInfo, error) {
 		return nil, fmt.Errorf("failed to get git commits: %w", err)
 	}
 
-	if len(output) == 0 {
-		return []models.CommitInfo{}, nil
+	commits := parseCommitLog(string(output))
+	if !opts.CountLines {
+		return commits, nil
+	}
+
+	counts, err := countChangedLines(absPath, selection)
+	if err != nil {
+		return nil, err
+	}
+	for i := range commits {
+		commits[i].LinesChanged = counts[commits[i].Hash]
+	}
+
+	return commits, nil
+}
+
+// countChangedLines maps each selected commit's hash to its lines added plus removed
+// Merge commits print no numstat and count as zero
+func countChangedLines(absPath string, selection []string) (map[string]int, error) {
+	cmd := exec.Command("git", append(logArgs("--numstat", "--pretty=format:%x1e%H"), selection...)...)
+	cmd.Dir = absPath
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count changed lines: %w", err)
+	}
+
+	counts := make(map[string]int)
+	for _, record := range strings.Split(string(output), "\x1e") {
+		lines := strings.Split(strings.TrimSpace(record), "\n")
+		if lines[0] == "" {
+			continue
+		}
+
+		changed := 0
+		for _, line := range lines[1:] {
+			// "<added>\t<removed>\t<path>", with "-" for both counts of a binary file
+			fields := strings.SplitN(line, "\t", 3)
+			if len(fields) < 3 {
+				continue
+			}
+			if fields[0] == "-" {
+				changed++
+				continue
+			}
+			added, _ := strconv.Atoi(fields[0])
+			removed, _ := strconv.Atoi(fields[1])
+			changed += added + removed
+		}
+		counts[lines[0]] = changed
+	}
+
+	return counts, nil
+}
+
+// CommitAuthor returns the author pattern commits are aggregated for: explicit when set,
+// else the repo's git user.name when ownCommitsOnly, else "" for every author
+func CommitAuthor(repoPath, explicit string, ownCommitsOnly bool) (string, error) {
+	if explicit != "" || !ownCommitsOnly {
+		return explicit, nil
+	}
+	author, err := GetAuthor(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if author == "" {
+		return "", fmt.Errorf("project only aggregates its own commits but git user.name is not set")
 	}
+	return author, nil
+}
 
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	commits := make([]models.CommitInfo, 0, len(lines))
+// logArgs builds git log arguments that force UTF-8 output whatever the commit's
+// i18n.commitEncoding, with paths printed verbatim instead of octal-quoted
+func logArgs(extra ...string) []string {
+	return append([]string{"-c", "core.quotepath=false", "log", "--encoding=UTF-8"}, extra...)
+}
 
-	for _, line := range lines {
-		parts := strings.Split(line, "|")
-		if len(parts) != 4 {
+// commitLogFormat separates fields with the unit separator and commits with the
+// record separator so that multi-line bodies and pipes in subjects parse intact.
+// The last field holds the commit's trailers, unfolded to one per line
+const commitLogFormat = "%H%x1f%an%x1f%s%x1f%at%x1f%b%x1f%(trailers:only,unfold)%x1e"
+
+// parseCommitLog parses git log output produced with the record/unit separator format
+// The output is treated as UTF-8; the separators are ASCII so multi-byte characters are
+// never split, and any invalid bytes left by a mislabelled commit become U+FFFD
+func parseCommitLog(output string) []models.CommitInfo {
+	output = strings.ToValidUTF8(output, "\uFFFD")
+	records := strings.Split(output, "\x1e")
+	commits := make([]models.CommitInfo, 0, len(records))
+
+	for _, record := range records {
+		record = strings.TrimLeft(record, "\n")
+		if record == "" {
+			continue
+		}
+
+		parts := strings.SplitN(record, "\x1f", 6)
+		if len(parts) != 6 {
 			continue
 		}
 
@@ -65,15 +178,31 @@ func GetCommitsSince(repoPath, sinceHash string) ([]models.CommitInfo, error) {
 			continue
 		}
 
+		timeSpent, refs := parseTrailers(parts[5])
+
 		commits = append(commits, models.CommitInfo{
 			Hash:      parts[0],
 			Author:    parts[1],
 			Message:   parts[2],
+			Body:      strings.TrimRight(parts[4], "\n "),
 			Timestamp: timestamp,
+			TimeSpent: timeSpent,
+			Refs:      refs,
 		})
 	}
 
-	return commits, nil
+	return commits
+}
+
+// RepoRoot returns the top-level directory of the git repository containing dir
+func RepoRoot(dir string) (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not inside a git repository", dir)
+	}
+	return strings.TrimSpace(string(output)), nil
 }
 
 // GetLatestCommitHash retrieves the latest commit hash from the repository
@@ -106,7 +235,7 @@ func GetLatestCommit(repoPath string) (*models.CommitInfo, error) {
 		return nil, fmt.Errorf("failed to resolve repo path: %w", err)
 	}
 
-	cmd := exec.Command("git", "log", "-1", "--pretty=format:%H|%an|%s|%at")
+	cmd := exec.Command("git", logArgs("-1", "--pretty=format:"+commitLogFormat)...)
 	cmd.Dir = absPath
 
 	output, err := cmd.Output()
@@ -114,26 +243,12 @@ func GetLatestCommit(repoPath string) (*models.CommitInfo, error) {
 		return nil, fmt.Errorf("failed to get latest commit: %w", err)
 	}
 
-	if len(output) == 0 {
+	commits := parseCommitLog(string(output))
+	if len(commits) == 0 {
 		return nil, fmt.Errorf("no commits found")
 	}
 
-	parts := strings.Split(strings.TrimSpace(string(output)), "|")
-	if len(parts) != 4 {
-		return nil, fmt.Errorf("unexpected git log output format")
-	}
-
-	timestamp, err := parseUnixTimestamp(parts[3])
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse commit timestamp: %w", err)
-	}
-
-	return &models.CommitInfo{
-		Hash:      parts[0],
-		Author:    parts[1],
-		Message:   parts[2],
-		Timestamp: timestamp,
-	}, nil
+	return &commits[0], nil
 }
 
 // ValidateCommitHash checks if a commit hash exists in the repository
@@ -147,34 +262,516 @@ func ValidateCommitHash(repoPath, hash string) bool {
 	return cmd.Run() == nil
 }
 
+// FullHashLength is the length of a full SHA-1 commit hash, the form entries store
+const FullHashLength = 40
+
+// minHashLength is the shortest abbreviation git accepts for a commit hash
+const minHashLength = 4
+
+// IsFullHash reports whether hash is a full-length hexadecimal commit hash
+func IsFullHash(hash string) bool {
+	return len(hash) == FullHashLength && isHex(hash)
+}
+
+// ExpandCommitHash resolves a possibly abbreviated commit hash to the full hash in the repository
+// Fails for anything that is not 4-40 hexadecimal characters, or that git cannot resolve
+// to exactly one commit (unknown or ambiguous abbreviations).
+func ExpandCommitHash(repoPath, hash string) (string, error) {
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if len(hash) < minHashLength || len(hash) > FullHashLength || !isHex(hash) {
+		return "", fmt.Errorf("invalid commit hash %q: expected %d-%d hexadecimal characters", hash, minHashLength, FullHashLength)
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", hash+"^{commit}")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("commit %s not found in %s (unknown or ambiguous)", hash, repoPath)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// isHex reports whether s consists of hexadecimal digits only
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAncestor reports whether the ancestor commit is reachable from the descendant commit
+// Returns an error if either commit cannot be resolved
+func IsAncestor(repoPath, ancestor, descendant string) (bool, error) {
+	cmd := exec.Command("git", "merge-base", "--is-ancestor", ancestor, descendant)
+	cmd.Dir = repoPath
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	}
+
+	// Exit code 1 means "not an ancestor"; anything else is a real failure
+	if exitErr, ok := err.(*exec.ExitError); ok && exitErr.ExitCode() == 1 {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("failed to check commit ancestry: %w", err)
+}
+
+// FindInReflog reports whether a commit appears in HEAD's reflog
+// After a hard reset the old commits are gone from history but still listed there.
+// Abbreviated hashes match by prefix.
+func FindInReflog(repoPath, hash string) (bool, error) {
+	if hash == "" {
+		return false, nil
+	}
+
+	cmd := exec.Command("git", "reflog", "--format=%H")
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to read reflog: %w", err)
+	}
+
+	for _, line := range strings.Split(string(output), "\n") {
+		if line != "" && strings.HasPrefix(line, hash) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// CheckBaseline verifies that baseline can anchor aggregation up to HEAD
+// A baseline that is no longer in HEAD's history but is still in the reflog (e.g. after
+// git reset --hard) is accepted with a warning, since baseline..HEAD still yields only
+// the commits made since; otherwise the histories are unrelated and an error is returned.
+// A baseline missing from the repository altogether is refused the same way.
+func CheckBaseline(repoPath, baseline string) (string, error) {
+	if !ValidateCommitHash(repoPath, baseline) {
+		return "", fmt.Errorf("baseline commit %s is not in the current history (repo may have changed)", baseline)
+	}
+
+	isAncestor, err := IsAncestor(repoPath, baseline, "HEAD")
+	if err != nil {
+		return "", fmt.Errorf("failed to check baseline commit: %w", err)
+	}
+	if isAncestor {
+		return "", nil
+	}
+
+	inReflog, err := FindInReflog(repoPath, baseline)
+	if err != nil {
+		return "", err
+	}
+	if !inReflog {
+		return "", fmt.Errorf("baseline commit %s is not in the current history (repo may have changed)", baseline)
+	}
+
+	return fmt.Sprintf("baseline commit %s is no longer in the current history but was found in the reflog (history was reset); aggregating the commits made since", baseline), nil
+}
+
+// DefaultExcludePatterns are commit subject prefixes dropped from worklog messages by default
+var DefaultExcludePatterns = []string{"fixup!", "squash!"}
+
+// FilterCommits returns the commits whose subject does not start with any of the patterns
+// Matching is case-insensitive and ignores leading whitespace
+func FilterCommits(commits []models.CommitInfo, patterns []string) []models.CommitInfo {
+	filtered := make([]models.CommitInfo, 0, len(commits))
+
+	for _, commit := range commits {
+		subject := strings.ToLower(strings.TrimSpace(commit.Message))
+		excluded := false
+		for _, pattern := range patterns {
+			pattern = strings.ToLower(strings.TrimSpace(pattern))
+			if pattern != "" && strings.HasPrefix(subject, pattern) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			filtered = append(filtered, commit)
+		}
+	}
+
+	return filtered
+}
+
+// FilterTrivialCommits returns the commits that changed at least minLines lines, dropping
+// whitespace fixes, version bumps and other tiny commits. A minLines of 0 or less keeps every
+// commit. The commits must have been listed with GetCommitsSinceOptions.CountLines.
+func FilterTrivialCommits(commits []models.CommitInfo, minLines int) []models.CommitInfo {
+	if minLines <= 0 {
+		return commits
+	}
+
+	filtered := make([]models.CommitInfo, 0, len(commits))
+	for _, commit := range commits {
+		if commit.LinesChanged >= minLines {
+			filtered = append(filtered, commit)
+		}
+	}
+
+	return filtered
+}
+
+// prefixSeparators are stripped after a subject prefix, e.g. the ": " of "PROJ-123: Fix login"
+const prefixSeparators = " \t:-|"
+
+// StripSubjectPrefix removes a leading match of pattern, and the separator after it, from subject.
+// prefix is the match without surrounding brackets, e.g. "PROJ-123" for "[PROJ-123] Fix login".
+// Subjects not starting with a match, subjects that would be left empty, and invalid patterns
+// are returned unchanged with an empty prefix.
+func StripSubjectPrefix(subject, pattern string) (clean, prefix string) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return subject, ""
+	}
+	return stripSubjectPrefix(subject, re)
+}
+
+func stripSubjectPrefix(subject string, re *regexp.Regexp) (clean, prefix string) {
+	loc := re.FindStringIndex(subject)
+	if loc == nil || loc[0] != 0 || loc[1] == 0 {
+		return subject, ""
+	}
+
+	clean = strings.TrimLeft(subject[loc[1]:], prefixSeparators)
+	if clean == "" {
+		return subject, ""
+	}
+	return clean, strings.Trim(subject[:loc[1]], "[]() \t:")
+}
+
+// stripSubjectPrefixes strips pattern from every subject, returning the cleaned commits and the
+// distinct prefixes in order of first appearance. An invalid pattern leaves the commits as they are.
+func stripSubjectPrefixes(commits []models.CommitInfo, pattern string) ([]models.CommitInfo, []string) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return commits, nil
+	}
+
+	stripped := make([]models.CommitInfo, len(commits))
+	seen := make(map[string]bool)
+	var prefixes []string
+	for i, commit := range commits {
+		var prefix string
+		commit.Message, prefix = stripSubjectPrefix(commit.Message, re)
+		if prefix != "" && !seen[prefix] {
+			seen[prefix] = true
+			prefixes = append(prefixes, prefix)
+		}
+		stripped[i] = commit
+	}
+	return stripped, prefixes
+}
+
+// SummarizeOptions controls how commits are turned into an entry message and duration
+type SummarizeOptions struct {
+	ExcludePatterns     []string         // Subject prefixes left out of the message
+	ExcludeFromDuration bool             // Also leave excluded and trivial commits out of the duration estimate
+	MinChangedLines     int              // Leave commits changing fewer lines out of the message (0 = off)
+	Strategy            DurationStrategy // Duration estimation strategy (nil = span)
+	IncludeBodies       bool             // Append commit bodies beneath each subject
+	GroupConventional   bool             // Group Conventional Commit subjects by type (Features, Fixes, ...)
+	DailySubtotals      bool             // List commits under a heading per day with that day's estimated time
+	StripPrefixPattern  string           // Regular expression stripped from the start of each subject ("" = off)
+	CollectPrefixes     bool             // List the stripped prefixes on a Refs line after the commits
+	ShortHashLength     int              // Characters of each hash shown in the message (0 = DefaultShortHashLength)
+	UseTrailers         bool             // Prefer Time-Spent trailers over the strategy's estimate
+	MaxSessionMinutes   int64            // Clamp each estimated stretch of work to this many minutes (0 = no cap)
+	SessionGap          time.Duration    // Idle threshold for the gap_aware strategy (0 = SessionGap)
+}
+
+// CountLines reports whether commits must be listed with line counts
+// (GetCommitsSinceOptions.CountLines): for MinChangedLines or a strategy weighing commits by size
+func (opts SummarizeOptions) CountLines() bool {
+	return opts.MinChangedLines > 0 || strategyCountsLines(opts.Strategy)
+}
+
+// SummarizeCommits builds the worklog message and estimated duration for commits.
+// Commits matching the exclude patterns or changing fewer than MinChangedLines lines are left
+// out of the message; they still count towards the duration unless ExcludeFromDuration is set.
+// If every commit is left out, all are kept.
+// With StripPrefixPattern, a matching prefix such as a ticket ID is removed from each subject.
+// With UseTrailers, commits carrying a Time-Spent trailer count for their trailer value and
+// only the remaining commits are estimated by the strategy.
+// With DailySubtotals, each calendar day is estimated on its own and the duration is the sum
+// of the days, so overnight gaps in a multi-day range are never counted as work.
+func SummarizeCommits(commits []models.CommitInfo, opts SummarizeOptions) (string, int64) {
+	strategy := opts.Strategy
+	if strategy == nil {
+		strategy = spanStrategy{}
+	}
+	strategy = WithMaxSession(WithSessionGap(strategy, opts.SessionGap), opts.MaxSessionMinutes)
+
+	kept := FilterTrivialCommits(FilterCommits(commits, opts.ExcludePatterns), opts.MinChangedLines)
+	if len(kept) == 0 {
+		kept = commits
+	}
+
+	durationCommits := commits
+	if opts.ExcludeFromDuration {
+		durationCommits = kept
+	}
+
+	// Prefixes go before grouping so "PROJ-1: feat: login" is grouped as a feature
+	var refs []string
+	if opts.StripPrefixPattern != "" {
+		kept, refs = stripSubjectPrefixes(kept, opts.StripPrefixPattern)
+	}
+
+	estimate := strategy.Estimate
+	if opts.UseTrailers {
+		estimate = func(commits []models.CommitInfo) int64 {
+			return estimateWithTrailers(commits, strategy)
+		}
+	}
+
+	var message string
+	var duration int64
+	if opts.DailySubtotals {
+		// Each day shows the estimate of all its duration commits, even those left out of the message
+		dayMinutes := make(map[time.Time]int64)
+		for _, day := range groupCommitsByDay(durationCommits, estimate) {
+			dayMinutes[day.Date] = day.Duration
+			duration += day.Duration
+		}
+		days := groupCommitsByDay(kept, nil)
+		for i := range days {
+			days[i].Duration = dayMinutes[days[i].Date]
+		}
+		message = aggregateCommitsByDay(days, opts.ShortHashLength, opts.IncludeBodies, opts.GroupConventional)
+	} else {
+		message = aggregateCommits(kept, opts.ShortHashLength, opts.IncludeBodies, opts.GroupConventional)
+		duration = estimate(durationCommits)
+	}
+
+	if opts.CollectPrefixes && len(refs) > 0 {
+		message += fmt.Sprintf("\nRefs: %s\n", strings.Join(refs, ", "))
+	}
+
+	return message, duration
+}
+
+// DefaultShortHashLength is the number of hash characters shown in aggregated messages
+const DefaultShortHashLength = 7
+
+// ShortHash abbreviates a commit hash to length characters (0 = DefaultShortHashLength)
+// Hashes shorter than the requested length are returned whole
+func ShortHash(hash string, length int) string {
+	if length <= 0 {
+		length = DefaultShortHashLength
+	}
+	if len(hash) <= length {
+		return hash
+	}
+	return hash[:length]
+}
+
 // AggregateCommits aggregates multiple commits into a summary message
 func AggregateCommits(commits []models.CommitInfo) string {
+	return aggregateCommits(commits, DefaultShortHashLength, false, false)
+}
+
+// AggregateCommitsWithBodies aggregates commits like AggregateCommits and
+// includes each commit body, indented beneath its subject
+func AggregateCommitsWithBodies(commits []models.CommitInfo) string {
+	return aggregateCommits(commits, DefaultShortHashLength, true, false)
+}
+
+// AggregateCommitsGrouped aggregates commits like AggregateCommits, grouped under headings by
+// Conventional Commit type ("Features:", "Fixes:", ...); other subjects are listed under "Other:"
+func AggregateCommitsGrouped(commits []models.CommitInfo) string {
+	return aggregateCommits(commits, DefaultShortHashLength, false, true)
+}
+
+// aggregateCommits builds the summary message with hashes shortened to hashLength
+func aggregateCommits(commits []models.CommitInfo, hashLength int, includeBodies, grouped bool) string {
 	if len(commits) == 0 {
 		return ""
 	}
 
 	var builder strings.Builder
 	builder.WriteString(fmt.Sprintf("Aggregated %d commits:\n", len(commits)))
+	writeCommitList(&builder, commits, 0, hashLength, includeBodies, grouped)
+	return builder.String()
+}
 
-	for i, commit := range commits {
-		builder.WriteString(fmt.Sprintf("%d. [%s] %s\n",
-			i+1,
-			commit.Hash[:7],
-			commit.Message))
+// aggregateCommitsByDay builds the summary message with a section per day, headed by the
+// weekday, date, and the day's estimated time, e.g. "Mon 2024-01-15 (2h 30m):"
+// Numbering runs on across days.
+func aggregateCommitsByDay(days []DailyWork, hashLength int, includeBodies, grouped bool) string {
+	count := 0
+	for _, day := range days {
+		count += len(day.Commits)
+	}
+	if count == 0 {
+		return ""
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Aggregated %d commits over %d days:\n", count, len(days)))
+
+	number := 0
+	for _, day := range days {
+		builder.WriteString(fmt.Sprintf("\n%s (%s):\n", day.Date.Format("Mon 2006-01-02"), utils.FormatDuration(day.Duration)))
+		number = writeCommitList(&builder, day.Commits, number, hashLength, includeBodies, grouped)
 	}
 
 	return builder.String()
 }
 
+// writeCommitList writes one numbered line per commit, counting on from number, and returns
+// the last number used
+func writeCommitList(builder *strings.Builder, commits []models.CommitInfo, number, hashLength int, includeBodies, grouped bool) int {
+	writeCommit := func(number int, commit models.CommitInfo, subject string) {
+		builder.WriteString(fmt.Sprintf("%d. [%s] %s\n",
+			number,
+			ShortHash(commit.Hash, hashLength),
+			subject))
+		if !includeBodies || commit.Body == "" {
+			return
+		}
+		for _, line := range strings.Split(commit.Body, "\n") {
+			builder.WriteString(strings.TrimRight("   "+line, " ") + "\n")
+		}
+	}
+
+	if !grouped {
+		for _, commit := range commits {
+			number++
+			writeCommit(number, commit, commit.Message)
+		}
+		return number
+	}
+
+	// Numbering runs on across groups so every commit keeps a unique line number
+	for _, group := range groupConventionalCommits(commits) {
+		builder.WriteString(fmt.Sprintf("\n%s:\n", group.heading))
+		for i, commit := range group.commits {
+			number++
+			writeCommit(number, commit, group.subjects[i])
+		}
+	}
+
+	return number
+}
+
+// ConventionalCommit is a commit subject parsed as a Conventional Commit,
+// e.g. "feat(api)!: add login" has type "feat", scope "api" and is breaking
+type ConventionalCommit struct {
+	Type        string
+	Scope       string
+	Breaking    bool
+	Description string
+}
+
+// conventionalCommitRegex matches "type(scope)!: description" with optional scope and "!"
+var conventionalCommitRegex = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?:\s+(\S.*)$`)
+
+// conventionalGroups lists the recognized commit types with their headings, in message order
+var conventionalGroups = []struct {
+	types   []string
+	heading string
+}{
+	{[]string{"feat", "feature"}, "Features"},
+	{[]string{"fix", "bugfix", "hotfix"}, "Fixes"},
+	{[]string{"perf"}, "Performance"},
+	{[]string{"refactor"}, "Refactoring"},
+	{[]string{"docs", "doc"}, "Documentation"},
+	{[]string{"test", "tests"}, "Tests"},
+	{[]string{"build", "ci"}, "Build & CI"},
+	{[]string{"style"}, "Style"},
+	{[]string{"chore"}, "Chores"},
+	{[]string{"revert"}, "Reverts"},
+}
+
+// ConventionalOtherHeading groups subjects without a recognized Conventional Commit type
+const ConventionalOtherHeading = "Other"
+
+// ParseConventionalCommit parses a subject like "fix(parser): handle tabs"
+// The type is lowercased; ok is false when the subject does not follow the format
+func ParseConventionalCommit(subject string) (ConventionalCommit, bool) {
+	match := conventionalCommitRegex.FindStringSubmatch(strings.TrimSpace(subject))
+	if match == nil {
+		return ConventionalCommit{}, false
+	}
+	return ConventionalCommit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       strings.TrimSpace(match[2]),
+		Breaking:    match[3] == "!",
+		Description: strings.TrimSpace(match[4]),
+	}, true
+}
+
+// commitGroup is one heading of a grouped summary, with the subjects shown for its commits
+type commitGroup struct {
+	heading  string
+	commits  []models.CommitInfo
+	subjects []string
+}
+
+// groupConventionalCommits sorts commits under their type's heading, keeping commit order within
+// a group and dropping empty groups. Grouped subjects lose the type prefix but keep the scope
+// ("api: add login") and are marked when breaking; other subjects are kept as written.
+func groupConventionalCommits(commits []models.CommitInfo) []commitGroup {
+	groups := make([]commitGroup, len(conventionalGroups)+1)
+	byType := make(map[string]int)
+	for i, group := range conventionalGroups {
+		groups[i].heading = group.heading
+		for _, commitType := range group.types {
+			byType[commitType] = i
+		}
+	}
+	other := len(conventionalGroups)
+	groups[other].heading = ConventionalOtherHeading
+
+	for _, commit := range commits {
+		parsed, ok := ParseConventionalCommit(commit.Message)
+		index, known := byType[parsed.Type]
+		if !ok || !known {
+			groups[other].commits = append(groups[other].commits, commit)
+			groups[other].subjects = append(groups[other].subjects, commit.Message)
+			continue
+		}
+
+		subject := parsed.Description
+		if parsed.Scope != "" {
+			subject = parsed.Scope + ": " + subject
+		}
+		if parsed.Breaking {
+			subject += " (breaking)"
+		}
+		groups[index].commits = append(groups[index].commits, commit)
+		groups[index].subjects = append(groups[index].subjects, subject)
+	}
+
+	nonEmpty := groups[:0]
+	for _, group := range groups {
+		if len(group.commits) > 0 {
+			nonEmpty = append(nonEmpty, group)
+		}
+	}
+	return nonEmpty
+}
+
 // CalculateDuration estimates work duration based on commit timestamps
 // Uses a simple heuristic: time between first and last commit + 30 minutes
 func CalculateDuration(commits []models.CommitInfo) int64 {
+	return CalculateSpan(commits, commitBuffer, 0)
+}
+
+// CalculateSpan estimates work duration as the time between the first and last commit plus
+// buffer minutes (just buffer for a single commit), clamped to maxMinutes when it is positive
+// The cap keeps a morning and an evening commit from being billed as a full day of work
+func CalculateSpan(commits []models.CommitInfo, buffer, maxMinutes int64) int64 {
 	if len(commits) == 0 {
 		return 0
 	}
 
 	if len(commits) == 1 {
-		return 30 // Default 30 minutes for single commit
+		return clampMinutes(buffer, maxMinutes)
 	}
 
 	// Find earliest and latest commits
@@ -191,11 +788,68 @@ func CalculateDuration(commits []models.CommitInfo) int64 {
 	}
 
 	duration := latest.Sub(earliest)
-	minutes := int64(duration.Minutes()) + 30 // Add buffer time
+	minutes := int64(duration.Minutes()) + buffer
+
+	return clampMinutes(minutes, maxMinutes)
+}
 
+// clampMinutes caps minutes at maxMinutes (maxMinutes <= 0 = no cap)
+func clampMinutes(minutes, maxMinutes int64) int64 {
+	if maxMinutes > 0 && minutes > maxMinutes {
+		return maxMinutes
+	}
 	return minutes
 }
 
+// DailyWork holds the commits and estimated duration for a single calendar day
+type DailyWork struct {
+	Date     time.Time // Midnight (local time) of the day
+	Commits  []models.CommitInfo
+	Duration int64 // Estimated minutes for the day's commits
+}
+
+// GroupCommitsByDay splits commits into calendar days (local time), oldest day first
+// Each day's duration is estimated independently with CalculateDuration
+func GroupCommitsByDay(commits []models.CommitInfo) []DailyWork {
+	return groupCommitsByDay(commits, CalculateDuration)
+}
+
+// groupCommitsByDay is GroupCommitsByDay with each day estimated by estimate (nil = no estimate)
+func groupCommitsByDay(commits []models.CommitInfo, estimate func([]models.CommitInfo) int64) []DailyWork {
+	byDay := make(map[time.Time][]models.CommitInfo)
+	for _, commit := range commits {
+		ts := commit.Timestamp.In(time.Local)
+		day := time.Date(ts.Year(), ts.Month(), ts.Day(), 0, 0, 0, 0, time.Local)
+		byDay[day] = append(byDay[day], commit)
+	}
+
+	days := make([]DailyWork, 0, len(byDay))
+	for day, dayCommits := range byDay {
+		work := DailyWork{Date: day, Commits: dayCommits}
+		if estimate != nil {
+			work.Duration = estimate(dayCommits)
+		}
+		days = append(days, work)
+	}
+
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+
+	return days
+}
+
+// LatestCommit returns the most recent commit by timestamp, or nil if there are none
+func LatestCommit(commits []models.CommitInfo) *models.CommitInfo {
+	var latest *models.CommitInfo
+	for i := range commits {
+		if latest == nil || commits[i].Timestamp.After(latest.Timestamp) {
+			latest = &commits[i]
+		}
+	}
+	return latest
+}
+
 func parseUnixTimestamp(ts string) (time.Time, error) {
 	var timestamp int64
 	_, err := fmt.Sscanf(ts, "%d", &timestamp)
@@ -1,10 +1,14 @@
 package git
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/testutil"
 )
 
 func TestAggregateCommits(t *testing.T) {
@@ -48,6 +52,109 @@ func TestAggregateCommitsEmpty(t *testing.T) {
 	}
 }
 
+func TestParseConventionalCommit(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    ConventionalCommit
+		ok      bool
+	}{
+		{"feat: add login", ConventionalCommit{Type: "feat", Description: "add login"}, true},
+		{"fix(parser): handle tabs", ConventionalCommit{Type: "fix", Scope: "parser", Description: "handle tabs"}, true},
+		{"Feat(api)!: drop v1", ConventionalCommit{Type: "feat", Scope: "api", Breaking: true, Description: "drop v1"}, true},
+		{"wip: unknown type still parses", ConventionalCommit{Type: "wip", Description: "unknown type still parses"}, true},
+		{"Fix bug in handler", ConventionalCommit{}, false},
+		{"fix:missing space", ConventionalCommit{}, false},
+		{"Merge branch 'main': conflicts", ConventionalCommit{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.subject, func(t *testing.T) {
+			got, ok := ParseConventionalCommit(tt.subject)
+			if ok != tt.ok || got != tt.want {
+				t.Errorf("ParseConventionalCommit(%q) = %+v, %v, want %+v, %v", tt.subject, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestAggregateCommitsGrouped(t *testing.T) {
+	commits := []models.CommitInfo{
+		{Hash: "aaaaaaa111", Message: "fix(auth): reject expired tokens"},
+		{Hash: "bbbbbbb222", Message: "Update README"},
+		{Hash: "ccccccc333", Message: "feat(api)!: paginate entries"},
+		{Hash: "ddddddd444", Message: "chore: bump deps"},
+		{Hash: "eeeeeee555", Message: "feat: add CSV export"},
+		{Hash: "fffffff666", Message: "wip: try things"},
+	}
+
+	want := "Aggregated 6 commits:\n" +
+		"\nFeatures:\n1. [ccccccc] api: paginate entries (breaking)\n2. [eeeeeee] add CSV export\n" +
+		"\nFixes:\n3. [aaaaaaa] auth: reject expired tokens\n" +
+		"\nChores:\n4. [ddddddd] bump deps\n" +
+		"\nOther:\n5. [bbbbbbb] Update README\n6. [fffffff] wip: try things\n"
+	if got := AggregateCommitsGrouped(commits); got != want {
+		t.Errorf("AggregateCommitsGrouped() =\n%s\nwant\n%s", got, want)
+	}
+
+	// Off by default; on through the summarize options
+	if got := AggregateCommits(commits); strings.Contains(got, "Features:") {
+		t.Errorf("Expected ungrouped message by default, got %q", got)
+	}
+	message, _ := SummarizeCommits(commits, SummarizeOptions{GroupConventional: true})
+	if message != want {
+		t.Errorf("SummarizeCommits() with GroupConventional = %q, want %q", message, want)
+	}
+
+	// Without any conventional subjects everything lands in Other
+	plain := AggregateCommitsGrouped(commits[1:2])
+	if plain != "Aggregated 1 commits:\n\nOther:\n1. [bbbbbbb] Update README\n" {
+		t.Errorf("Unexpected grouping of plain subjects: %q", plain)
+	}
+}
+
+func TestShortHash(t *testing.T) {
+	full := "0123456789abcdef0123456789abcdef01234567"
+
+	tests := []struct {
+		name   string
+		hash   string
+		length int
+		want   string
+	}{
+		{"default length", full, 0, "0123456"},
+		{"configured length", full, 12, "0123456789ab"},
+		{"short hash kept whole", "abc", 7, "abc"},
+		{"exact length", "abcdefg", 7, "abcdefg"},
+		{"empty hash", "", 7, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ShortHash(tt.hash, tt.length); got != tt.want {
+				t.Errorf("ShortHash(%q, %d) = %q, want %q", tt.hash, tt.length, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAggregateCommitsShortHashes(t *testing.T) {
+	commits := []models.CommitInfo{
+		{Hash: "abc", Message: "Stored short hash", Timestamp: time.Now()},
+		{Hash: "0123456789abcdef0123456789abcdef01234567", Message: "Full hash", Timestamp: time.Now()},
+	}
+
+	want := "Aggregated 2 commits:\n1. [abc] Stored short hash\n2. [0123456] Full hash\n"
+	if got := AggregateCommits(commits); got != want {
+		t.Errorf("AggregateCommits() = %q, want %q", got, want)
+	}
+
+	message, _ := SummarizeCommits(commits, SummarizeOptions{ShortHashLength: 10})
+	want = "Aggregated 2 commits:\n1. [abc] Stored short hash\n2. [0123456789] Full hash\n"
+	if message != want {
+		t.Errorf("SummarizeCommits() message = %q, want %q", message, want)
+	}
+}
+
 func TestCalculateDuration(t *testing.T) {
 	now := time.Now()
 
@@ -116,3 +223,684 @@ func containsHelper(s, substr string) bool {
 	}
 	return false
 }
+
+// initTestRepo creates a temporary git repository with a single commit
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	repo := testutil.NewGitRepo(t)
+	repo.Run("commit", "-q", "--allow-empty", "-m", "Initial commit")
+	return repo.Dir
+}
+
+func TestIsAncestor(t *testing.T) {
+	repo := initTestRepo(t)
+	first := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Second commit")
+
+	ok, err := IsAncestor(repo, first, "HEAD")
+	if err != nil {
+		t.Fatalf("IsAncestor failed: %v", err)
+	}
+	if !ok {
+		t.Error("Expected first commit to be an ancestor of HEAD")
+	}
+
+	// Unrelated history: orphan branch shares no commits with the baseline
+	testutil.RunGit(t, repo, "checkout", "-q", "--orphan", "unrelated")
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Unrelated root")
+
+	ok, err = IsAncestor(repo, first, "HEAD")
+	if err != nil {
+		t.Fatalf("IsAncestor failed: %v", err)
+	}
+	if ok {
+		t.Error("Expected unrelated baseline not to be an ancestor of HEAD")
+	}
+
+	// Unknown commits are reported as errors
+	if _, err := IsAncestor(repo, "0123456789012345678901234567890123456789", "HEAD"); err == nil {
+		t.Error("Expected error for unknown commit")
+	}
+}
+
+func TestFindInReflog(t *testing.T) {
+	repo := initTestRepo(t)
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Baseline")
+	baseline := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+
+	// Hard reset drops the baseline from history but not from the reflog
+	testutil.RunGit(t, repo, "reset", "-q", "--hard", "HEAD~1")
+	if ok, _ := IsAncestor(repo, baseline, "HEAD"); ok {
+		t.Fatal("Expected baseline to be gone from history after reset")
+	}
+
+	for _, hash := range []string{baseline, baseline[:7]} {
+		found, err := FindInReflog(repo, hash)
+		if err != nil {
+			t.Fatalf("FindInReflog() error = %v", err)
+		}
+		if !found {
+			t.Errorf("Expected %s to be found in the reflog", hash)
+		}
+	}
+
+	if found, _ := FindInReflog(repo, "0123456789abcdef0123456789abcdef01234567"); found {
+		t.Error("Expected unknown commit not to be found in the reflog")
+	}
+	if found, _ := FindInReflog(repo, ""); found {
+		t.Error("Expected empty hash not to be found")
+	}
+}
+
+func TestCheckBaseline(t *testing.T) {
+	repo := initTestRepo(t)
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Baseline")
+	baseline := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "After baseline")
+
+	warning, err := CheckBaseline(repo, baseline)
+	if err != nil || warning != "" {
+		t.Errorf("Expected ancestor baseline to pass silently, got %q (err %v)", warning, err)
+	}
+
+	// Reset past the baseline, then keep working
+	testutil.RunGit(t, repo, "reset", "-q", "--hard", "HEAD~2")
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Work after reset")
+
+	warning, err = CheckBaseline(repo, baseline)
+	if err != nil {
+		t.Fatalf("Expected reflog baseline to be accepted, got %v", err)
+	}
+	if !strings.Contains(warning, "reflog") {
+		t.Errorf("Expected a reflog warning, got %q", warning)
+	}
+
+	// baseline..HEAD still yields only the commit made after the reset
+	commits, err := GetCommitsSince(repo, baseline)
+	if err != nil {
+		t.Fatalf("GetCommitsSince() error = %v", err)
+	}
+	if len(commits) != 1 || commits[0].Message != "Work after reset" {
+		t.Errorf("Expected only the commit after the reset, got %+v", commits)
+	}
+
+	// Expiring the reflog loses the baseline for good
+	testutil.RunGit(t, repo, "reflog", "expire", "--expire=now", "--all")
+	if _, err := CheckBaseline(repo, baseline); err == nil {
+		t.Error("Expected error once the baseline is gone from the reflog")
+	}
+
+	// A hash the repo has never seen is refused rather than treated as no baseline
+	if _, err := CheckBaseline(repo, strings.Repeat("0", 40)); err == nil {
+		t.Error("Expected error for a baseline missing from the repo")
+	}
+}
+
+func TestGroupCommitsByDay(t *testing.T) {
+	day1 := time.Date(2026, 1, 12, 9, 0, 0, 0, time.Local)
+	day2 := time.Date(2026, 1, 13, 14, 0, 0, 0, time.Local)
+
+	commits := []models.CommitInfo{
+		{Hash: "ccc", Message: "Day 2 work", Timestamp: day2},
+		{Hash: "bbb", Message: "Day 1 more", Timestamp: day1.Add(2 * time.Hour)},
+		{Hash: "aaa", Message: "Day 1 start", Timestamp: day1},
+	}
+
+	days := GroupCommitsByDay(commits)
+
+	if len(days) != 2 {
+		t.Fatalf("Expected 2 days, got %d", len(days))
+	}
+
+	if !days[0].Date.Equal(time.Date(2026, 1, 12, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected first day 2026-01-12, got %v", days[0].Date)
+	}
+	if len(days[0].Commits) != 2 {
+		t.Errorf("Expected 2 commits on first day, got %d", len(days[0].Commits))
+	}
+	if days[0].Duration != 150 {
+		t.Errorf("Expected 150 minutes on first day, got %d", days[0].Duration)
+	}
+
+	if !days[1].Date.Equal(time.Date(2026, 1, 13, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("Expected second day 2026-01-13, got %v", days[1].Date)
+	}
+	if days[1].Duration != 30 {
+		t.Errorf("Expected 30 minutes on second day, got %d", days[1].Duration)
+	}
+
+	var total int64
+	for _, day := range days {
+		total += day.Duration
+	}
+	if total != 180 {
+		t.Errorf("Expected daily durations to sum to 180, got %d", total)
+	}
+
+	if latest := LatestCommit(days[0].Commits); latest.Hash != "bbb" {
+		t.Errorf("Expected latest commit 'bbb' on first day, got '%s'", latest.Hash)
+	}
+}
+
+func TestFilterCommits(t *testing.T) {
+	commits := []models.CommitInfo{
+		{Hash: "aaa", Message: "Add login form"},
+		{Hash: "bbb", Message: "fixup! Add login form"},
+		{Hash: "ccc", Message: "Squash! tidy"},
+		{Hash: "ddd", Message: "WIP trying things"},
+	}
+
+	filtered := FilterCommits(commits, DefaultExcludePatterns)
+	if len(filtered) != 2 {
+		t.Fatalf("Expected 2 commits after filtering, got %d", len(filtered))
+	}
+	if filtered[0].Hash != "aaa" || filtered[1].Hash != "ddd" {
+		t.Errorf("Unexpected commits kept: %v", filtered)
+	}
+
+	filtered = FilterCommits(commits, []string{"fixup!", "squash!", "wip"})
+	if len(filtered) != 1 {
+		t.Errorf("Expected 1 commit with wip pattern, got %d", len(filtered))
+	}
+
+	if len(FilterCommits(commits, nil)) != len(commits) {
+		t.Error("Expected no filtering without patterns")
+	}
+}
+
+func TestSummarizeCommitsExcludesFixups(t *testing.T) {
+	now := time.Now()
+	commits := []models.CommitInfo{
+		{Hash: "aaaaaaa1", Message: "fixup! Add login form", Timestamp: now},
+		{Hash: "bbbbbbb2", Message: "Add login form", Timestamp: now.Add(-1 * time.Hour)},
+		{Hash: "ccccccc3", Message: "Add signup form", Timestamp: now.Add(-2 * time.Hour)},
+	}
+
+	// Fixup dropped from message but its timestamp still extends the span
+	message, duration := SummarizeCommits(commits, SummarizeOptions{ExcludePatterns: DefaultExcludePatterns})
+	if contains(message, "fixup!") {
+		t.Errorf("Expected fixup commit to be dropped from message, got %q", message)
+	}
+	if !contains(message, "Aggregated 2 commits") {
+		t.Errorf("Expected 2 aggregated commits, got %q", message)
+	}
+	if duration != 150 {
+		t.Errorf("Expected duration 150 including fixup timestamp, got %d", duration)
+	}
+
+	// Excluded from duration as well when configured
+	_, duration = SummarizeCommits(commits, SummarizeOptions{ExcludePatterns: DefaultExcludePatterns, ExcludeFromDuration: true})
+	if duration != 90 {
+		t.Errorf("Expected duration 90 without fixup timestamp, got %d", duration)
+	}
+
+	// All commits excluded falls back to the full list
+	message, _ = SummarizeCommits(commits[:1], SummarizeOptions{ExcludePatterns: DefaultExcludePatterns, ExcludeFromDuration: true})
+	if !contains(message, "fixup! Add login form") {
+		t.Errorf("Expected fallback to all commits, got %q", message)
+	}
+}
+
+func TestGetCommitsSinceCapturesBody(t *testing.T) {
+	repo := initTestRepo(t)
+	base := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+
+	body := "First body line\n\n- bullet | with pipe\n- second bullet"
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Add feature | part 1", "-m", body)
+	piped := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Subject only")
+
+	commits, err := GetCommitsSince(repo, base)
+	if err != nil {
+		t.Fatalf("GetCommitsSince failed: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(commits))
+	}
+
+	// Newest first
+	if commits[0].Message != "Subject only" || commits[0].Body != "" {
+		t.Errorf("Unexpected subject-only commit: %+v", commits[0])
+	}
+	if commits[1].Hash != piped || commits[1].Message != "Add feature | part 1" {
+		t.Errorf("Expected hash %s and subject with pipe intact, got %s %q", piped, commits[1].Hash, commits[1].Message)
+	}
+	if commits[1].Body != body {
+		t.Errorf("Expected body %q, got %q", body, commits[1].Body)
+	}
+	if commits[1].Author != "Test" {
+		t.Errorf("Expected author 'Test', got %q", commits[1].Author)
+	}
+}
+
+func TestSummarizeCommitsIncludeBodies(t *testing.T) {
+	now := time.Now()
+	commits := []models.CommitInfo{
+		{Hash: "aaaaaaa1", Message: "Fix login", Body: "Closes #12\n\nSession expired too early", Timestamp: now},
+		{Hash: "bbbbbbb2", Message: "Add logout", Timestamp: now.Add(-time.Hour)},
+	}
+
+	message, _ := SummarizeCommits(commits, SummarizeOptions{})
+	if contains(message, "Closes #12") {
+		t.Errorf("Expected bodies to be omitted by default, got %q", message)
+	}
+
+	message, _ = SummarizeCommits(commits, SummarizeOptions{IncludeBodies: true})
+	expected := "Aggregated 2 commits:\n" +
+		"1. [aaaaaaa] Fix login\n" +
+		"   Closes #12\n" +
+		"\n" +
+		"   Session expired too early\n" +
+		"2. [bbbbbbb] Add logout\n"
+	if message != expected {
+		t.Errorf("Expected %q, got %q", expected, message)
+	}
+}
+
+func TestGetCommitsSinceParsesTrailers(t *testing.T) {
+	repo := initTestRepo(t)
+	base := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Add export", "-m", "Time-Spent: 1h 30m\nRefs: PROJ-1, PROJ-2\ntime-spent: 15m")
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "No trailers", "-m", "Just a body")
+
+	commits, err := GetCommitsSince(repo, base)
+	if err != nil {
+		t.Fatalf("GetCommitsSince failed: %v", err)
+	}
+	if len(commits) != 2 {
+		t.Fatalf("Expected 2 commits, got %d", len(commits))
+	}
+
+	// Newest first
+	if commits[0].TimeSpent != 0 || len(commits[0].Refs) != 0 {
+		t.Errorf("Expected no trailer data, got TimeSpent=%d Refs=%v", commits[0].TimeSpent, commits[0].Refs)
+	}
+	if commits[0].Body != "Just a body" {
+		t.Errorf("Expected body 'Just a body', got %q", commits[0].Body)
+	}
+	if commits[1].TimeSpent != 105 {
+		t.Errorf("Expected summed Time-Spent of 105 minutes, got %d", commits[1].TimeSpent)
+	}
+	if len(commits[1].Refs) != 2 || commits[1].Refs[0] != "PROJ-1" || commits[1].Refs[1] != "PROJ-2" {
+		t.Errorf("Expected refs [PROJ-1 PROJ-2], got %v", commits[1].Refs)
+	}
+}
+
+func TestParseTrailers(t *testing.T) {
+	timeSpent, refs := parseTrailers("Signed-off-by: Test <test@example.com>\nTime-Spent: soon\nRefs: ,\n")
+	if timeSpent != 0 || len(refs) != 0 {
+		t.Errorf("Expected unknown and invalid trailers to be ignored, got TimeSpent=%d Refs=%v", timeSpent, refs)
+	}
+
+	timeSpent, _ = parseTrailers("Time-Spent: 2h\nTime-Spent: 45")
+	if timeSpent != 165 {
+		t.Errorf("Expected 165 minutes, got %d", timeSpent)
+	}
+}
+
+func TestSummarizeCommitsUseTrailers(t *testing.T) {
+	now := time.Now()
+	commits := []models.CommitInfo{
+		{Hash: "aaaaaaa1", Message: "Tracked", Timestamp: now, TimeSpent: 120},
+		{Hash: "bbbbbbb2", Message: "Untracked", Timestamp: now.Add(-3 * time.Hour)},
+	}
+
+	// Without the option the heuristic covers every commit
+	_, duration := SummarizeCommits(commits, SummarizeOptions{})
+	if duration != 210 {
+		t.Errorf("Expected span estimate of 210 minutes, got %d", duration)
+	}
+
+	// Trailer value plus the estimate for the commit without one
+	_, duration = SummarizeCommits(commits, SummarizeOptions{UseTrailers: true})
+	if duration != 150 {
+		t.Errorf("Expected 120 trailer minutes + 30 estimated, got %d", duration)
+	}
+
+	_, duration = SummarizeCommits(commits[:1], SummarizeOptions{UseTrailers: true})
+	if duration != 120 {
+		t.Errorf("Expected trailer duration of 120 minutes, got %d", duration)
+	}
+
+	// No trailers at all falls back to the strategy
+	_, duration = SummarizeCommits(commits[1:], SummarizeOptions{UseTrailers: true})
+	if duration != 30 {
+		t.Errorf("Expected estimated duration of 30 minutes, got %d", duration)
+	}
+}
+
+func TestGetCommitsSinceNonASCII(t *testing.T) {
+	repo := initTestRepo(t)
+	base := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "--author", "José Müller <jose@example.com>", "-m", "Ajout de la fonctionnalité café")
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "修复登录错误", "-m", "详细说明：会话过早过期")
+	// A commit stored in Latin-1 must still come back as UTF-8
+	testutil.RunGit(t, repo, "-c", "i18n.commitEncoding=ISO-8859-1", "commit", "-q", "--allow-empty", "-m", "Caf\xe9 cr\xe8me")
+
+	commits, err := GetCommitsSince(repo, base)
+	if err != nil {
+		t.Fatalf("GetCommitsSince failed: %v", err)
+	}
+	if len(commits) != 3 {
+		t.Fatalf("Expected 3 commits, got %d", len(commits))
+	}
+
+	// Newest first
+	if commits[0].Message != "Café crème" {
+		t.Errorf("Expected Latin-1 subject re-encoded as 'Café crème', got %q", commits[0].Message)
+	}
+	if commits[1].Message != "修复登录错误" || commits[1].Body != "详细说明：会话过早过期" {
+		t.Errorf("Expected CJK subject and body intact, got %q / %q", commits[1].Message, commits[1].Body)
+	}
+	if commits[2].Author != "José Müller" {
+		t.Errorf("Expected author 'José Müller', got %q", commits[2].Author)
+	}
+	if commits[2].Message != "Ajout de la fonctionnalité café" {
+		t.Errorf("Expected accented subject intact, got %q", commits[2].Message)
+	}
+
+	latest, err := GetLatestCommit(repo)
+	if err != nil {
+		t.Fatalf("GetLatestCommit failed: %v", err)
+	}
+	if latest.Message != "Café crème" {
+		t.Errorf("Expected latest subject 'Café crème', got %q", latest.Message)
+	}
+}
+
+func TestParseCommitLogInvalidUTF8(t *testing.T) {
+	output := "abc123\x1fJos\xe9\x1fFix \xff bug\x1f1700000000\x1f\x1f\x1e"
+	commits := parseCommitLog(output)
+	if len(commits) != 1 {
+		t.Fatalf("Expected 1 commit, got %d", len(commits))
+	}
+	if commits[0].Author != "Jos�" || commits[0].Message != "Fix � bug" {
+		t.Errorf("Expected invalid bytes replaced, got %q / %q", commits[0].Author, commits[0].Message)
+	}
+}
+
+func TestGetCommitsSinceNoMerges(t *testing.T) {
+	repo := initTestRepo(t)
+	base := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+	trunk := testutil.RunGit(t, repo, "rev-parse", "--abbrev-ref", "HEAD")
+
+	testutil.RunGit(t, repo, "checkout", "-q", "-b", "feature")
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Feature work")
+	testutil.RunGit(t, repo, "checkout", "-q", trunk)
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "Trunk work")
+	testutil.RunGit(t, repo, "merge", "-q", "--no-ff", "--no-edit", "-m", "Merge branch 'feature'", "feature")
+
+	all, err := GetCommitsSince(repo, base)
+	if err != nil {
+		t.Fatalf("GetCommitsSince failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 commits including the merge, got %d", len(all))
+	}
+
+	noMerges, err := GetCommitsSinceWithOptions(repo, base, GetCommitsSinceOptions{NoMerges: true})
+	if err != nil {
+		t.Fatalf("GetCommitsSinceWithOptions failed: %v", err)
+	}
+	if len(noMerges) != len(all)-1 {
+		t.Fatalf("Expected %d commits without the merge, got %d", len(all)-1, len(noMerges))
+	}
+	for _, commit := range noMerges {
+		if strings.HasPrefix(commit.Message, "Merge") {
+			t.Errorf("Expected merge commit to be excluded, got %q", commit.Message)
+		}
+	}
+}
+
+func TestGetCommitsSinceAuthor(t *testing.T) {
+	repo := initTestRepo(t)
+	base := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+
+	// runGit commits as "Test"; seed a teammate's commits in between
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "My first change")
+	testutil.RunGit(t, repo, "-c", "user.name=Teammate", "-c", "user.email=mate@example.com",
+		"commit", "-q", "--allow-empty", "--author=Teammate <mate@example.com>", "-m", "Teammate change")
+	testutil.RunGit(t, repo, "commit", "-q", "--allow-empty", "-m", "My second change")
+
+	all, err := GetCommitsSince(repo, base)
+	if err != nil {
+		t.Fatalf("GetCommitsSince failed: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Expected 3 commits from both authors, got %d", len(all))
+	}
+
+	mine, err := GetCommitsSinceWithOptions(repo, base, GetCommitsSinceOptions{Author: "Test"})
+	if err != nil {
+		t.Fatalf("GetCommitsSinceWithOptions failed: %v", err)
+	}
+	if len(mine) != 2 {
+		t.Fatalf("Expected 2 commits by Test, got %d", len(mine))
+	}
+	for _, commit := range mine {
+		if commit.Author != "Test" {
+			t.Errorf("Expected only Test's commits, got %q by %q", commit.Message, commit.Author)
+		}
+	}
+
+	theirs, _ := GetCommitsSinceWithOptions(repo, base, GetCommitsSinceOptions{Author: "Teammate"})
+	if len(theirs) != 1 || theirs[0].Message != "Teammate change" {
+		t.Errorf("Expected only the teammate's commit, got %+v", theirs)
+	}
+}
+
+func TestCommitAuthor(t *testing.T) {
+	repo := initTestRepo(t)
+	testutil.RunGit(t, repo, "config", "user.name", "Local User")
+
+	tests := []struct {
+		name     string
+		explicit string
+		ownOnly  bool
+		want     string
+	}{
+		{"everyone by default", "", false, ""},
+		{"own commits resolve user.name", "", true, "Local User"},
+		{"explicit author wins", "Someone", true, "Someone"},
+		{"explicit author without own-only", "Someone", false, "Someone"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CommitAuthor(repo, tt.explicit, tt.ownOnly)
+			if err != nil {
+				t.Fatalf("CommitAuthor() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CommitAuthor() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRepoRoot(t *testing.T) {
+	repo := initTestRepo(t)
+	sub := filepath.Join(repo, "nested", "dir")
+	if err := os.MkdirAll(sub, 0755); err != nil {
+		t.Fatalf("Failed to create subdirectory: %v", err)
+	}
+
+	want, _ := filepath.EvalSymlinks(repo)
+	root, err := RepoRoot(sub)
+	if err != nil {
+		t.Fatalf("RepoRoot() error = %v", err)
+	}
+	if got, _ := filepath.EvalSymlinks(root); got != want {
+		t.Errorf("RepoRoot() = %q, want %q", got, want)
+	}
+
+	if _, err := RepoRoot(t.TempDir()); err == nil {
+		t.Error("Expected error outside a git repository")
+	}
+}
+
+func TestFilterTrivialCommits(t *testing.T) {
+	repo := initTestRepo(t)
+	base := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+
+	commitFile := func(name, content, subject string) {
+		if err := os.WriteFile(filepath.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		testutil.RunGit(t, repo, "add", name)
+		testutil.RunGit(t, repo, "commit", "-q", "-m", subject)
+	}
+
+	commitFile("main.go", "package main\n\nfunc main() {\n\tprintln(\"hello\")\n}\n", "Add main")
+	commitFile("VERSION", "1.0.1\n", "Bump version")
+	commitFile("main.go", "package main\n\nfunc main() {\n\tprintln(\"hello\")\n\tprintln(\"world\")\n}\n", "Print world")
+
+	commits, err := GetCommitsSinceWithOptions(repo, base, GetCommitsSinceOptions{CountLines: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	changed := make(map[string]int)
+	for _, commit := range commits {
+		changed[commit.Message] = commit.LinesChanged
+	}
+	if changed["Add main"] != 5 || changed["Bump version"] != 1 || changed["Print world"] != 1 {
+		t.Fatalf("unexpected line counts: %v", changed)
+	}
+
+	if got := FilterTrivialCommits(commits, 0); len(got) != 3 {
+		t.Errorf("threshold 0 should keep every commit, got %d", len(got))
+	}
+	kept := FilterTrivialCommits(commits, 2)
+	if len(kept) != 1 || kept[0].Message != "Add main" {
+		t.Errorf("expected only Add main to be kept, got %v", kept)
+	}
+
+	message, _ := SummarizeCommits(commits, SummarizeOptions{MinChangedLines: 2})
+	if !strings.Contains(message, "Add main") || strings.Contains(message, "Bump version") || strings.Contains(message, "Print world") {
+		t.Errorf("trivial commits should be left out of the message:\n%s", message)
+	}
+
+	// Without line counts every commit looks trivial, so all are kept rather than none
+	uncounted, err := GetCommitsSince(repo, base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	message, _ = SummarizeCommits(uncounted, SummarizeOptions{MinChangedLines: 2})
+	if !strings.Contains(message, "Bump version") {
+		t.Errorf("expected every commit when all would be dropped:\n%s", message)
+	}
+}
+
+func TestStripSubjectPrefix(t *testing.T) {
+	const pattern = `\[?[A-Z][A-Z0-9]+-\d+\]?`
+
+	tests := []struct {
+		subject string
+		clean   string
+		prefix  string
+	}{
+		{"PROJ-123: Fix login redirect", "Fix login redirect", "PROJ-123"},
+		{"[PROJ-7] Add export", "Add export", "PROJ-7"},
+		{"OPS-42 - Rotate keys", "Rotate keys", "OPS-42"},
+		{"Fix PROJ-123 regression", "Fix PROJ-123 regression", ""},
+		{"Update readme", "Update readme", ""},
+		{"PROJ-9", "PROJ-9", ""}, // Nothing would be left
+	}
+	for _, tt := range tests {
+		clean, prefix := StripSubjectPrefix(tt.subject, pattern)
+		if clean != tt.clean || prefix != tt.prefix {
+			t.Errorf("StripSubjectPrefix(%q) = (%q, %q), want (%q, %q)", tt.subject, clean, prefix, tt.clean, tt.prefix)
+		}
+	}
+
+	if clean, prefix := StripSubjectPrefix("PROJ-1: Fix", "("); clean != "PROJ-1: Fix" || prefix != "" {
+		t.Errorf("invalid pattern should leave the subject unchanged, got (%q, %q)", clean, prefix)
+	}
+
+	commits := []models.CommitInfo{
+		{Hash: "ccc", Message: "PROJ-2: feat: add export"},
+		{Hash: "bbb", Message: "PROJ-1: fix: login redirect"},
+		{Hash: "aaa", Message: "PROJ-2: feat: export filters"},
+		{Hash: "999", Message: "Tidy imports"},
+	}
+	message, _ := SummarizeCommits(commits, SummarizeOptions{StripPrefixPattern: pattern, CollectPrefixes: true, GroupConventional: true})
+	for _, want := range []string{"Features:", "add export", "Fixes:", "login redirect", "Tidy imports", "Refs: PROJ-2, PROJ-1"} {
+		if !strings.Contains(message, want) {
+			t.Errorf("expected %q in message:\n%s", want, message)
+		}
+	}
+	if strings.Contains(message, "PROJ-2: ") {
+		t.Errorf("prefixes should be stripped from subjects:\n%s", message)
+	}
+
+	message, _ = SummarizeCommits(commits, SummarizeOptions{})
+	if !strings.Contains(message, "PROJ-1: fix: login redirect") || strings.Contains(message, "Refs:") {
+		t.Errorf("subjects should be unchanged by default:\n%s", message)
+	}
+}
+
+func TestExpandCommitHash(t *testing.T) {
+	repo := initTestRepo(t)
+	full := testutil.RunGit(t, repo, "rev-parse", "HEAD")
+
+	for _, input := range []string{full[:7], strings.ToUpper(full[:10]), full} {
+		got, err := ExpandCommitHash(repo, input)
+		if err != nil {
+			t.Fatalf("ExpandCommitHash(%q) failed: %v", input, err)
+		}
+		if got != full {
+			t.Errorf("ExpandCommitHash(%q) = %q, want %q", input, got, full)
+		}
+	}
+	if !IsFullHash(full) || IsFullHash(full[:7]) {
+		t.Error("IsFullHash misclassified the full and abbreviated hash")
+	}
+
+	for _, input := range []string{"", "abc", "not-a-hash", "0000000", full + "0"} {
+		if _, err := ExpandCommitHash(repo, input); err == nil {
+			t.Errorf("ExpandCommitHash(%q) succeeded, want error", input)
+		}
+	}
+}
+
+func TestSummarizeCommitsDailySubtotals(t *testing.T) {
+	day1 := time.Date(2026, 1, 12, 9, 0, 0, 0, time.Local)
+	day3 := time.Date(2026, 1, 14, 14, 0, 0, 0, time.Local)
+
+	commits := []models.CommitInfo{
+		{Hash: "ccc", Message: "Wednesday work", Timestamp: day3},
+		{Hash: "bbb", Message: "Monday more", Timestamp: day1.Add(2 * time.Hour)},
+		{Hash: "aaa", Message: "Monday start", Timestamp: day1},
+	}
+
+	message, duration := SummarizeCommits(commits, SummarizeOptions{DailySubtotals: true})
+
+	// Each day is estimated on its own: 2h span + 30m buffer, and a lone commit's 30m buffer
+	if duration != 180 {
+		t.Errorf("Expected 180 minutes summed over days, got %d", duration)
+	}
+
+	for _, want := range []string{
+		"Aggregated 3 commits over 2 days:",
+		"Mon 2026-01-12 (2h 30m):",
+		"Wed 2026-01-14 (30m):",
+		"3. [ccc] Wednesday work",
+	} {
+		if !strings.Contains(message, want) {
+			t.Errorf("Expected %q in message:\n%s", want, message)
+		}
+	}
+	if strings.Contains(message, "Tue 2026-01-13") {
+		t.Errorf("Expected no section for a day without commits:\n%s", message)
+	}
+	if strings.Index(message, "Mon 2026-01-12") > strings.Index(message, "Wed 2026-01-14") {
+		t.Errorf("Expected days oldest first:\n%s", message)
+	}
+
+	// Without the option the whole range is one span
+	if _, plain := SummarizeCommits(commits, SummarizeOptions{}); plain == duration {
+		t.Errorf("Expected the plain span estimate to differ from the daily sum, both %d", plain)
+	}
+}
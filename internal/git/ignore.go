@@ -0,0 +1,136 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// IgnoreFile is the repo-local file listing commits to leave out of aggregation
+const IgnoreFile = ".clockworkignore"
+
+// IgnoreRules holds the exclusion rules read from a .clockworkignore file
+//
+// File format, one rule per line:
+//
+//	# comment
+//	wip              commit subject prefix (case-insensitive)
+//	path:docs/**     path glob; commits touching only matching files are ignored
+type IgnoreRules struct {
+	Subjects []string // Commit subject prefixes, merged with configured exclude patterns
+	Paths    []string // Path globs relative to the repo root
+}
+
+// LoadIgnore reads the .clockworkignore file at the root of the repository
+// A missing file yields empty rules
+func LoadIgnore(repoPath string) (IgnoreRules, error) {
+	var rules IgnoreRules
+
+	file, err := os.Open(filepath.Join(repoPath, IgnoreFile))
+	if os.IsNotExist(err) {
+		return rules, nil
+	}
+	if err != nil {
+		return rules, fmt.Errorf("failed to open %s: %w", IgnoreFile, err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if glob, ok := strings.CutPrefix(line, "path:"); ok {
+			if glob = strings.TrimSpace(glob); glob != "" {
+				rules.Paths = append(rules.Paths, glob)
+			}
+			continue
+		}
+		rules.Subjects = append(rules.Subjects, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return rules, fmt.Errorf("failed to read %s: %w", IgnoreFile, err)
+	}
+
+	return rules, nil
+}
+
+// MatchPath reports whether a repo-relative file path matches any path glob
+// A trailing "/**" matches everything below a directory
+func (r IgnoreRules) MatchPath(file string) bool {
+	for _, glob := range r.Paths {
+		if dir, ok := strings.CutSuffix(glob, "/**"); ok {
+			if file == dir || strings.HasPrefix(file, dir+"/") {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(glob, file); matched {
+			return true
+		}
+		// Patterns without a slash match the file name in any directory
+		if !strings.Contains(glob, "/") {
+			if matched, _ := path.Match(glob, path.Base(file)); matched {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DropIgnoredPaths removes commits whose changed files all match the path rules
+// Such commits are left out of aggregation entirely (message and duration)
+func DropIgnoredPaths(repoPath string, commits []models.CommitInfo, rules IgnoreRules) ([]models.CommitInfo, error) {
+	if len(rules.Paths) == 0 {
+		return commits, nil
+	}
+
+	kept := make([]models.CommitInfo, 0, len(commits))
+	for _, commit := range commits {
+		files, err := changedFiles(repoPath, commit.Hash)
+		if err != nil {
+			return nil, err
+		}
+
+		ignored := len(files) > 0
+		for _, file := range files {
+			if !rules.MatchPath(file) {
+				ignored = false
+				break
+			}
+		}
+
+		if !ignored {
+			kept = append(kept, commit)
+		}
+	}
+
+	return kept, nil
+}
+
+// changedFiles lists the files touched by a commit
+// Non-ASCII paths are printed verbatim so they match ignore patterns
+func changedFiles(repoPath, hash string) ([]string, error) {
+	cmd := exec.Command("git", "-c", "core.quotepath=false", "diff-tree", "--no-commit-id", "--name-only", "-r", "--root", hash)
+	cmd.Dir = repoPath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files for commit %s: %w", hash, err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
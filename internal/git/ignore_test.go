@@ -0,0 +1,106 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/testutil"
+)
+
+func TestLoadIgnoreMissingFile(t *testing.T) {
+	rules, err := LoadIgnore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Expected no error for missing file, got %v", err)
+	}
+	if len(rules.Subjects) != 0 || len(rules.Paths) != 0 {
+		t.Errorf("Expected empty rules, got %+v", rules)
+	}
+}
+
+func TestLoadIgnoreSubjectPatterns(t *testing.T) {
+	dir := t.TempDir()
+	content := "# Local rules\nwip\n\nchore(deps):\npath:vendor/**\n"
+	if err := os.WriteFile(filepath.Join(dir, IgnoreFile), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write ignore file: %v", err)
+	}
+
+	rules, err := LoadIgnore(dir)
+	if err != nil {
+		t.Fatalf("Failed to load ignore file: %v", err)
+	}
+
+	if len(rules.Subjects) != 2 || rules.Subjects[0] != "wip" || rules.Subjects[1] != "chore(deps):" {
+		t.Errorf("Unexpected subject rules: %v", rules.Subjects)
+	}
+	if len(rules.Paths) != 1 || rules.Paths[0] != "vendor/**" {
+		t.Errorf("Unexpected path rules: %v", rules.Paths)
+	}
+
+	commits := []models.CommitInfo{
+		{Hash: "aaa", Message: "Add parser"},
+		{Hash: "bbb", Message: "WIP parser"},
+		{Hash: "ccc", Message: "chore(deps): bump uuid"},
+	}
+
+	filtered := FilterCommits(commits, append(DefaultExcludePatterns, rules.Subjects...))
+	if len(filtered) != 1 || filtered[0].Hash != "aaa" {
+		t.Errorf("Expected only 'aaa' to remain, got %v", filtered)
+	}
+}
+
+func TestIgnoreRulesMatchPath(t *testing.T) {
+	rules := IgnoreRules{Paths: []string{"vendor/**", "*.lock", "docs/*.md"}}
+
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"vendor/github.com/x/y.go", true},
+		{"vendor", true},
+		{"Cargo.lock", true},
+		{"sub/go.lock", true},
+		{"docs/readme.md", true},
+		{"docs/api/readme.md", false},
+		{"main.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := rules.MatchPath(tt.file); got != tt.want {
+			t.Errorf("MatchPath(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
+
+func TestDropIgnoredPaths(t *testing.T) {
+	repo := initTestRepo(t)
+
+	writeAndCommit := func(file, message string) string {
+		path := filepath.Join(repo, file)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(message), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", file, err)
+		}
+		testutil.RunGit(t, repo, "add", file)
+		testutil.RunGit(t, repo, "commit", "-q", "-m", message)
+		return testutil.RunGit(t, repo, "rev-parse", "HEAD")
+	}
+
+	codeHash := writeAndCommit("main.go", "Add main")
+	vendorHash := writeAndCommit("vendor/lib.go", "Update vendored lib")
+
+	commits := []models.CommitInfo{
+		{Hash: vendorHash, Message: "Update vendored lib"},
+		{Hash: codeHash, Message: "Add main"},
+	}
+
+	kept, err := DropIgnoredPaths(repo, commits, IgnoreRules{Paths: []string{"vendor/**"}})
+	if err != nil {
+		t.Fatalf("DropIgnoredPaths failed: %v", err)
+	}
+
+	if len(kept) != 1 || kept[0].Hash != codeHash {
+		t.Errorf("Expected only the code commit to remain, got %v", kept)
+	}
+}
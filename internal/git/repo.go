@@ -0,0 +1,98 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// RepoStatus is what ValidateRepo found at a repository path
+type RepoStatus struct {
+	Path     string // Absolute path
+	Exists   bool
+	IsRepo   bool
+	GitDir   string // As printed by git rev-parse --git-dir, relative to Path when inside it
+	HeadHash string // "" for a repository without commits
+	Err      error  // Why the path cannot be used as a project repository, nil when it can
+	// Unreachable is set when the path is missing or cannot be accessed, as with a repo on an
+	// unmounted network drive, rather than reachable but not a repository
+	Unreachable bool
+}
+
+// RepoAvailability classifies a repository path for callers that degrade gracefully
+type RepoAvailability string
+
+const (
+	RepoAvailable   RepoAvailability = "available"
+	RepoUnavailable RepoAvailability = "repo_unavailable" // Missing or inaccessible, possibly only for now
+	RepoNotARepo    RepoAvailability = "not_a_repo"       // Reachable, but not a git repository
+)
+
+// Availability tells a repository that is temporarily out of reach from a path that is not one
+// Only the former is worth retrying later, or logging manually in the meantime.
+func (s RepoStatus) Availability() RepoAvailability {
+	switch {
+	case s.Err == nil:
+		return RepoAvailable
+	case s.Unreachable:
+		return RepoUnavailable
+	default:
+		return RepoNotARepo
+	}
+}
+
+// ValidateRepo checks that path exists and is a git repository, resolving its HEAD
+// Callers decide what to do with a missing path; Err explains any failure
+func ValidateRepo(path string) RepoStatus {
+	status := RepoStatus{Path: path}
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		status.Err = fmt.Errorf("failed to resolve %s: %w", path, err)
+		status.Unreachable = true
+		return status
+	}
+	status.Path = absPath
+
+	info, err := os.Stat(absPath)
+	if os.IsNotExist(err) {
+		status.Err = fmt.Errorf("%s does not exist", absPath)
+		status.Unreachable = true
+		return status
+	}
+	if err != nil {
+		status.Err = fmt.Errorf("cannot access %s: %w", absPath, err)
+		status.Unreachable = true
+		return status
+	}
+	status.Exists = true
+	if !info.IsDir() {
+		status.Err = fmt.Errorf("%s is not a directory", absPath)
+		return status
+	}
+
+	cmd := exec.Command("git", "rev-parse", "--git-dir")
+	cmd.Dir = absPath
+	output, err := cmd.Output()
+	if err != nil {
+		// git cannot tell an unreadable directory from a plain one
+		if _, readErr := os.ReadDir(absPath); readErr != nil {
+			status.Err = fmt.Errorf("cannot access %s: %w", absPath, readErr)
+			status.Unreachable = true
+			return status
+		}
+		status.Err = fmt.Errorf("%s is not a git repository (run git init there first)", absPath)
+		return status
+	}
+	status.IsRepo = true
+	status.GitDir = strings.TrimSpace(string(output))
+
+	// An empty repository has no HEAD yet, which is fine for a new project
+	if hash, err := GetLatestCommitHash(absPath); err == nil {
+		status.HeadHash = hash
+	}
+
+	return status
+}
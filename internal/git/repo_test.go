@@ -0,0 +1,75 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/techthos/clockwork/internal/testutil"
+)
+
+func TestValidateRepo(t *testing.T) {
+	repo := initTestRepo(t)
+	status := ValidateRepo(repo)
+	if status.Err != nil || !status.Exists || !status.IsRepo {
+		t.Fatalf("expected a valid repository, got %+v", status)
+	}
+	if want := testutil.RunGit(t, repo, "rev-parse", "HEAD"); status.HeadHash != want {
+		t.Errorf("expected HEAD %s, got %s", want, status.HeadHash)
+	}
+
+	// A repository without commits is valid but has no HEAD yet
+	empty := t.TempDir()
+	testutil.RunGit(t, empty, "init", "-q")
+	status = ValidateRepo(empty)
+	if status.Err != nil || !status.IsRepo || status.HeadHash != "" {
+		t.Errorf("expected an empty repository without HEAD, got %+v", status)
+	}
+
+	plain := t.TempDir()
+	status = ValidateRepo(plain)
+	if status.Err == nil || !status.Exists || status.IsRepo {
+		t.Errorf("expected an existing non-repository, got %+v", status)
+	}
+
+	file := filepath.Join(plain, "notes.txt")
+	if err := os.WriteFile(file, []byte("notes"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if status = ValidateRepo(file); status.Err == nil || status.IsRepo {
+		t.Errorf("expected a file to be rejected, got %+v", status)
+	}
+
+	status = ValidateRepo(filepath.Join(plain, "missing"))
+	if status.Err == nil || status.Exists {
+		t.Errorf("expected a missing path, got %+v", status)
+	}
+}
+
+func TestRepoAvailability(t *testing.T) {
+	if got := ValidateRepo(initTestRepo(t)).Availability(); got != RepoAvailable {
+		t.Errorf("expected a repository to be available, got %s", got)
+	}
+
+	plain := t.TempDir()
+	if got := ValidateRepo(plain).Availability(); got != RepoNotARepo {
+		t.Errorf("expected a plain directory to be not_a_repo, got %s", got)
+	}
+
+	// A repo on an unmounted drive looks like a missing path
+	if got := ValidateRepo(filepath.Join(plain, "unmounted", "repo")).Availability(); got != RepoUnavailable {
+		t.Errorf("expected a missing path to be repo_unavailable, got %s", got)
+	}
+
+	if os.Geteuid() == 0 {
+		t.Skip("root reads directories regardless of permissions")
+	}
+	locked := filepath.Join(plain, "locked")
+	if err := os.Mkdir(locked, 0); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chmod(locked, 0755)
+	if got := ValidateRepo(locked).Availability(); got != RepoUnavailable {
+		t.Errorf("expected an unreadable directory to be repo_unavailable, got %s", got)
+	}
+}
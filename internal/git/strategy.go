@@ -0,0 +1,242 @@
+package git
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// DurationStrategy estimates work duration in minutes from a set of commits
+type DurationStrategy interface {
+	// Name returns the identifier used to select the strategy
+	Name() string
+	// Estimate returns the estimated duration in minutes
+	Estimate(commits []models.CommitInfo) int64
+}
+
+// DefaultStrategy is the strategy used when none is configured
+const DefaultStrategy = "span"
+
+// SessionGap is the default idle time after which the gap_aware strategy starts a new session
+const SessionGap = 90 * time.Minute
+
+// commitBuffer is the time credited for the work leading up to a commit
+const commitBuffer = 30
+
+// DailyCap is the most the capped strategy credits for one calendar day
+const DailyCap = 8 * 60
+
+// intervalLimit is the most the interval strategy credits for the time between two commits
+const intervalLimit = 2 * commitBuffer
+
+// Commit size weighting of the weighted strategy
+const (
+	weightedBaseMinutes    = 15  // Credited for every commit, however small
+	weightedLinesPerMinute = 5   // Changed lines per extra minute
+	weightedMaxMinutes     = 120 // Most a single commit is credited
+)
+
+var strategies = map[string]DurationStrategy{
+	"span":       spanStrategy{},
+	"gap_aware":  gapAwareStrategy{gap: SessionGap},
+	"per_commit": perCommitStrategy{},
+	"interval":   intervalStrategy{},
+	"weighted":   weightedStrategy{},
+	"capped":     cappedStrategy{},
+}
+
+// lineCounter is implemented by strategies that need CommitInfo.LinesChanged
+type lineCounter interface {
+	countsLines() bool
+}
+
+// strategyCountsLines reports whether strategy needs commits listed with line counts
+func strategyCountsLines(strategy DurationStrategy) bool {
+	counter, ok := strategy.(lineCounter)
+	return ok && counter.countsLines()
+}
+
+// GetStrategy returns the registered strategy with the given name
+// An empty name returns the default strategy
+func GetStrategy(name string) (DurationStrategy, error) {
+	if name == "" {
+		name = DefaultStrategy
+	}
+	strategy, ok := strategies[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown duration method %q (available: %s)", name, strings.Join(StrategyNames(), ", "))
+	}
+	return strategy, nil
+}
+
+// StrategyNames returns the names of all registered strategies, sorted
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WithMaxSession returns strategy with each continuous stretch of work clamped to maxMinutes:
+// the whole span for span, every session for gap_aware, every day for capped (instead of
+// DailyCap). Other strategies are returned unchanged, as is any strategy when maxMinutes <= 0
+func WithMaxSession(strategy DurationStrategy, maxMinutes int64) DurationStrategy {
+	if maxMinutes <= 0 {
+		return strategy
+	}
+	switch s := strategy.(type) {
+	case spanStrategy:
+		s.maxMinutes = maxMinutes
+		return s
+	case gapAwareStrategy:
+		s.maxMinutes = maxMinutes
+		return s
+	case cappedStrategy:
+		s.maxMinutes = maxMinutes
+		return s
+	}
+	return strategy
+}
+
+// spanStrategy uses the time between first and last commit plus a buffer
+type spanStrategy struct {
+	maxMinutes int64 // 0 = no cap
+}
+
+func (spanStrategy) Name() string { return "span" }
+
+func (s spanStrategy) Estimate(commits []models.CommitInfo) int64 {
+	return CalculateSpan(commits, commitBuffer, s.maxMinutes)
+}
+
+// gapAwareStrategy splits commits into sessions at idle gaps and sums each session's span
+type gapAwareStrategy struct {
+	gap        time.Duration
+	maxMinutes int64 // Cap per session, 0 = no cap
+}
+
+func (gapAwareStrategy) Name() string { return "gap_aware" }
+
+func (s gapAwareStrategy) Estimate(commits []models.CommitInfo) int64 {
+	return CalculateSessions(commits, s.gap, commitBuffer, s.maxMinutes)
+}
+
+// CalculateSessions estimates work duration with idle gaps left out. Commits are sorted by
+// timestamp and adjacent commits no more than idle apart form one session; each session counts
+// its span plus buffer minutes, clamped to maxMinutes when it is positive. Breaks between
+// sessions (a long lunch, morning and evening work) are not counted
+func CalculateSessions(commits []models.CommitInfo, idle time.Duration, buffer, maxMinutes int64) int64 {
+	if len(commits) == 0 {
+		return 0
+	}
+
+	timestamps := make([]time.Time, len(commits))
+	for i, commit := range commits {
+		timestamps[i] = commit.Timestamp
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i].Before(timestamps[j])
+	})
+
+	var total int64
+	sessionStart := timestamps[0]
+	for i := 1; i < len(timestamps); i++ {
+		if timestamps[i].Sub(timestamps[i-1]) > idle {
+			total += clampMinutes(int64(timestamps[i-1].Sub(sessionStart).Minutes())+buffer, maxMinutes)
+			sessionStart = timestamps[i]
+		}
+	}
+	total += clampMinutes(int64(timestamps[len(timestamps)-1].Sub(sessionStart).Minutes())+buffer, maxMinutes)
+
+	return total
+}
+
+// WithSessionGap returns the gap_aware strategy with gap as its idle threshold
+// Other strategies are returned unchanged, as is any strategy when gap <= 0
+func WithSessionGap(strategy DurationStrategy, gap time.Duration) DurationStrategy {
+	if s, ok := strategy.(gapAwareStrategy); ok && gap > 0 {
+		s.gap = gap
+		return s
+	}
+	return strategy
+}
+
+// perCommitStrategy credits a fixed buffer for every commit
+type perCommitStrategy struct{}
+
+func (perCommitStrategy) Name() string { return "per_commit" }
+
+func (perCommitStrategy) Estimate(commits []models.CommitInfo) int64 {
+	return int64(len(commits)) * commitBuffer
+}
+
+// intervalStrategy credits each commit the time since the previous commit, up to
+// intervalLimit, and the first commit the buffer. Unlike gap_aware, a long gap still counts
+// for the work leading up to the next commit, but never for more than intervalLimit
+type intervalStrategy struct{}
+
+func (intervalStrategy) Name() string { return "interval" }
+
+func (intervalStrategy) Estimate(commits []models.CommitInfo) int64 {
+	if len(commits) == 0 {
+		return 0
+	}
+
+	timestamps := make([]time.Time, len(commits))
+	for i, commit := range commits {
+		timestamps[i] = commit.Timestamp
+	}
+	sort.Slice(timestamps, func(i, j int) bool {
+		return timestamps[i].Before(timestamps[j])
+	})
+
+	total := int64(commitBuffer)
+	for i := 1; i < len(timestamps); i++ {
+		total += clampMinutes(int64(timestamps[i].Sub(timestamps[i-1]).Minutes()), intervalLimit)
+	}
+	return total
+}
+
+// weightedStrategy credits each commit by its size: weightedBaseMinutes plus a minute per
+// weightedLinesPerMinute changed lines, up to weightedMaxMinutes. Commits without line
+// counts are credited the base only
+type weightedStrategy struct{}
+
+func (weightedStrategy) Name() string { return "weighted" }
+
+func (weightedStrategy) countsLines() bool { return true }
+
+func (weightedStrategy) Estimate(commits []models.CommitInfo) int64 {
+	var total int64
+	for _, commit := range commits {
+		minutes := weightedBaseMinutes + int64(commit.LinesChanged/weightedLinesPerMinute)
+		total += clampMinutes(minutes, weightedMaxMinutes)
+	}
+	return total
+}
+
+// cappedStrategy estimates each calendar day (local time) as its span plus buffer, clamped to
+// DailyCap, so a range over several days never counts nights or more than a working day each
+type cappedStrategy struct {
+	maxMinutes int64 // Cap per day, 0 = DailyCap
+}
+
+func (cappedStrategy) Name() string { return "capped" }
+
+func (s cappedStrategy) Estimate(commits []models.CommitInfo) int64 {
+	limit := s.maxMinutes
+	if limit <= 0 {
+		limit = DailyCap
+	}
+
+	var total int64
+	for _, day := range groupCommitsByDay(commits, nil) {
+		total += CalculateSpan(day.Commits, commitBuffer, limit)
+	}
+	return total
+}
@@ -0,0 +1,230 @@
+package git
+
+import (
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func TestStrategies(t *testing.T) {
+	base := time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC)
+
+	// Two sessions: 09:00-10:00 and 15:00-15:30 with a 5h gap in between
+	commits := []models.CommitInfo{
+		{Hash: "a", Timestamp: base},
+		{Hash: "b", Timestamp: base.Add(1 * time.Hour)},
+		{Hash: "c", Timestamp: base.Add(6 * time.Hour)},
+		{Hash: "d", Timestamp: base.Add(6*time.Hour + 30*time.Minute)},
+	}
+
+	tests := []struct {
+		name     string
+		commits  []models.CommitInfo
+		expected int64
+	}{
+		{"span", commits, 420},      // 6h30m span + 30 buffer
+		{"gap_aware", commits, 150}, // (60 + 30) + (30 + 30)
+		{"per_commit", commits, 120},
+		{"interval", commits, 180}, // 30 + 60 + 60 (5h gap limited) + 30
+		{"weighted", commits, 60},  // 4 x 15 without line counts
+		{"capped", commits, 420},   // one day under the daily cap
+		{"span", commits[:1], 30},
+		{"gap_aware", commits[:1], 30},
+		{"per_commit", commits[:1], 30},
+		{"interval", commits[:1], 30},
+		{"weighted", commits[:1], 15},
+		{"capped", commits[:1], 30},
+		{"span", nil, 0},
+		{"gap_aware", nil, 0},
+		{"per_commit", nil, 0},
+		{"interval", nil, 0},
+		{"weighted", nil, 0},
+		{"capped", nil, 0},
+	}
+
+	for _, tt := range tests {
+		strategy, err := GetStrategy(tt.name)
+		if err != nil {
+			t.Fatalf("GetStrategy(%q) failed: %v", tt.name, err)
+		}
+		if strategy.Name() != tt.name {
+			t.Errorf("Expected strategy name %q, got %q", tt.name, strategy.Name())
+		}
+		if got := strategy.Estimate(tt.commits); got != tt.expected {
+			t.Errorf("%s.Estimate(%d commits) = %d, want %d", tt.name, len(tt.commits), got, tt.expected)
+		}
+	}
+}
+
+func TestWeightedStrategy(t *testing.T) {
+	commits := []models.CommitInfo{
+		{Hash: "a", LinesChanged: 2},    // 15
+		{Hash: "b", LinesChanged: 100},  // 15 + 20
+		{Hash: "c", LinesChanged: 5000}, // limited to 120
+	}
+
+	strategy, _ := GetStrategy("weighted")
+	if got := strategy.Estimate(commits); got != 170 {
+		t.Errorf("weighted.Estimate() = %d, want 170", got)
+	}
+
+	// Only the weighted strategy needs commits listed with line counts
+	if !(SummarizeOptions{Strategy: strategy}).CountLines() {
+		t.Error("Expected weighted to need line counts")
+	}
+	span, _ := GetStrategy("span")
+	if (SummarizeOptions{Strategy: span}).CountLines() || !(SummarizeOptions{Strategy: span, MinChangedLines: 3}).CountLines() {
+		t.Error("Expected span to need line counts only for MinChangedLines")
+	}
+}
+
+func TestCappedStrategy(t *testing.T) {
+	day := time.Date(2026, 1, 12, 7, 0, 0, 0, time.Local)
+
+	// A ten-hour first day and a short second day; the night in between never counts
+	commits := []models.CommitInfo{
+		{Hash: "a", Timestamp: day},
+		{Hash: "b", Timestamp: day.Add(10 * time.Hour)},
+		{Hash: "c", Timestamp: day.Add(26 * time.Hour)},
+		{Hash: "d", Timestamp: day.Add(27 * time.Hour)},
+	}
+
+	strategy, _ := GetStrategy("capped")
+	if got := strategy.Estimate(commits); got != DailyCap+90 {
+		t.Errorf("capped.Estimate() = %d, want %d", got, DailyCap+90)
+	}
+	if got := WithMaxSession(strategy, 60).Estimate(commits); got != 120 {
+		t.Errorf("capped with max_session_minutes 60 = %d, want 120", got)
+	}
+}
+
+func TestGetStrategyDefault(t *testing.T) {
+	strategy, err := GetStrategy("")
+	if err != nil {
+		t.Fatalf("GetStrategy(\"\") failed: %v", err)
+	}
+	if strategy.Name() != DefaultStrategy {
+		t.Errorf("Expected default strategy %q, got %q", DefaultStrategy, strategy.Name())
+	}
+}
+
+func TestGetStrategyUnknown(t *testing.T) {
+	if _, err := GetStrategy("astrology"); err == nil {
+		t.Error("Expected error for unknown strategy")
+	}
+}
+
+func TestStrategyNamesRegistered(t *testing.T) {
+	for _, name := range StrategyNames() {
+		if _, err := GetStrategy(name); err != nil {
+			t.Errorf("Registered strategy %q not resolvable: %v", name, err)
+		}
+	}
+}
+
+func TestCalculateSpanCap(t *testing.T) {
+	base := time.Date(2026, 1, 12, 8, 0, 0, 0, time.UTC)
+
+	// Morning and evening commit with nothing in between: a 10-hour gap
+	commits := []models.CommitInfo{
+		{Hash: "a", Timestamp: base},
+		{Hash: "b", Timestamp: base.Add(10 * time.Hour)},
+	}
+
+	if got := CalculateSpan(commits, 30, 0); got != 630 {
+		t.Errorf("CalculateSpan() uncapped = %d, want 630", got)
+	}
+	if got := CalculateSpan(commits, 30, 240); got != 240 {
+		t.Errorf("CalculateSpan() capped at 240 = %d, want 240", got)
+	}
+	if got := CalculateSpan(commits, 15, 0); got != 615 {
+		t.Errorf("CalculateSpan() with 15m buffer = %d, want 615", got)
+	}
+	if got := CalculateSpan(commits[:1], 30, 240); got != 30 {
+		t.Errorf("CalculateSpan() single commit = %d, want 30", got)
+	}
+	if got := CalculateDuration(commits); got != 630 {
+		t.Errorf("CalculateDuration() = %d, want 630 (uncapped)", got)
+	}
+}
+
+func TestWithMaxSession(t *testing.T) {
+	base := time.Date(2026, 1, 12, 8, 0, 0, 0, time.UTC)
+
+	// A 90-minute session, then a long gap, then a single commit
+	commits := []models.CommitInfo{
+		{Hash: "a", Timestamp: base},
+		{Hash: "b", Timestamp: base.Add(90 * time.Minute)},
+		{Hash: "c", Timestamp: base.Add(13 * time.Hour)},
+	}
+
+	tests := []struct {
+		name     string
+		max      int64
+		expected int64
+	}{
+		{"span", 240, 240},     // 13h span + 30 clamped
+		{"span", 0, 810},       // uncapped
+		{"gap_aware", 60, 90},  // (90 + 30) clamped to 60, plus 30
+		{"gap_aware", 0, 150},  // (90 + 30) + 30
+		{"per_commit", 60, 90}, // not span-based, unaffected
+	}
+
+	for _, tt := range tests {
+		strategy, _ := GetStrategy(tt.name)
+		if got := WithMaxSession(strategy, tt.max).Estimate(commits); got != tt.expected {
+			t.Errorf("WithMaxSession(%s, %d).Estimate() = %d, want %d", tt.name, tt.max, got, tt.expected)
+		}
+	}
+
+	// Applied through SummarizeCommits, including the default span strategy
+	_, duration := SummarizeCommits(commits, SummarizeOptions{MaxSessionMinutes: 240})
+	if duration != 240 {
+		t.Errorf("SummarizeCommits() with MaxSessionMinutes = %d, want 240", duration)
+	}
+}
+
+func TestCalculateSessionsExcludesBreaks(t *testing.T) {
+	base := time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC)
+
+	// 09:00 and 09:40 are one session; the 3h20m lunch gap before 13:00 is a break
+	commits := []models.CommitInfo{
+		{Hash: "c", Timestamp: base.Add(4 * time.Hour)},
+		{Hash: "a", Timestamp: base},
+		{Hash: "b", Timestamp: base.Add(40 * time.Minute)},
+	}
+
+	// (40 + 30) + (0 + 30): the break is excluded
+	if got := CalculateSessions(commits, 90*time.Minute, 30, 0); got != 100 {
+		t.Errorf("CalculateSessions() = %d, want 100", got)
+	}
+
+	// The simple span still counts the break
+	if got := CalculateDuration(commits); got != 270 {
+		t.Errorf("CalculateDuration() = %d, want 270", got)
+	}
+
+	// A threshold longer than the break joins everything into one session
+	if got := CalculateSessions(commits, 4*time.Hour, 30, 0); got != 270 {
+		t.Errorf("CalculateSessions() with 4h threshold = %d, want 270", got)
+	}
+
+	// Configured through SummarizeOptions for the gap_aware strategy only
+	gapAware, _ := GetStrategy("gap_aware")
+	if _, got := SummarizeCommits(commits, SummarizeOptions{Strategy: gapAware, SessionGap: 30 * time.Minute}); got != 90 {
+		t.Errorf("gap_aware with 30m gap = %d, want 90", got)
+	}
+	if _, got := SummarizeCommits(commits, SummarizeOptions{SessionGap: 30 * time.Minute}); got != 270 {
+		t.Errorf("span ignores SessionGap, got %d, want 270", got)
+	}
+
+	// The default 90 minute gap splits at a 100 minute pause
+	pause := []models.CommitInfo{
+		{Hash: "a", Timestamp: base},
+		{Hash: "b", Timestamp: base.Add(100 * time.Minute)},
+	}
+	if got := gapAware.Estimate(pause); got != 60 {
+		t.Errorf("gap_aware with the default gap = %d, want 60", got)
+	}
+}
@@ -0,0 +1,64 @@
+package git
+
+import (
+	"strings"
+
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/utils"
+)
+
+// Trailer keys recognised on commits (matched case-insensitively, as git does)
+const (
+	TrailerTimeSpent = "Time-Spent" // Duration in ParseDuration format, e.g. "2h" or "1h 30m"
+	TrailerRefs      = "Refs"       // Comma-separated references, e.g. "PROJ-1, PROJ-2"
+)
+
+// parseTrailers extracts known trailers from unfolded "Key: value" lines
+// Multiple Time-Spent trailers are summed; unparseable durations are ignored
+func parseTrailers(raw string) (int64, []string) {
+	var timeSpent int64
+	var refs []string
+
+	for _, line := range strings.Split(raw, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		switch {
+		case strings.EqualFold(key, TrailerTimeSpent):
+			if minutes, err := utils.ParseDuration(value); err == nil {
+				timeSpent += minutes
+			}
+		case strings.EqualFold(key, TrailerRefs):
+			for _, ref := range strings.Split(value, ",") {
+				if ref = strings.TrimSpace(ref); ref != "" {
+					refs = append(refs, ref)
+				}
+			}
+		}
+	}
+
+	return timeSpent, refs
+}
+
+// estimateWithTrailers sums the Time-Spent trailers and estimates commits without one using strategy
+func estimateWithTrailers(commits []models.CommitInfo, strategy DurationStrategy) int64 {
+	var total int64
+	var untracked []models.CommitInfo
+	for _, commit := range commits {
+		if commit.TimeSpent > 0 {
+			total += commit.TimeSpent
+		} else {
+			untracked = append(untracked, commit)
+		}
+	}
+
+	if len(untracked) > 0 {
+		total += strategy.Estimate(untracked)
+	}
+
+	return total
+}
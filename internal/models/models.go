@@ -1,26 +1,165 @@
 package models
 
-import "time"
+import (
+	"sort"
+	"strings"
+	"time"
+)
 
 // Project represents a project with associated git repository
 type Project struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	GitRepoPath string    `json:"git_repo_path"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID                  string    `json:"id"`
+	Name                string    `json:"name"`
+	GitRepoPath         string    `json:"git_repo_path"`
+	DurationMethod      string    `json:"duration_method,omitempty"`        // Duration estimation strategy (empty = default)
+	RecordHeadForManual *bool     `json:"record_head_for_manual,omitempty"` // Store HEAD on manual entries (nil = true)
+	OwnCommitsOnly      bool      `json:"own_commits_only,omitempty"`       // Aggregate only commits by the repo's git user.name
+	HourlyRate          float64   `json:"hourly_rate,omitempty"`            // Billing rate per hour (0 = not billed)
+	Currency            string    `json:"currency,omitempty"`               // Currency code of HourlyRate, optional
+	BudgetMinutes       int64     `json:"budget_minutes,omitempty"`         // Fixed-bid time budget (0 = none)
+	Archived            bool      `json:"archived,omitempty"`               // Hidden from project lists, entries kept
+	AutoSchedule        string    `json:"auto_schedule,omitempty"`          // When the server logs a git entry by itself (utils.ParseSchedule, "" = never)
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// RecordsHeadForManual reports whether manual entries should store the repo's HEAD
+// Projects created before the option existed keep recording it
+func (p *Project) RecordsHeadForManual() bool {
+	return p.RecordHeadForManual == nil || *p.RecordHeadForManual
+}
+
+// FieldChange records a single field's value before and after an edit
+type FieldChange struct {
+	Field    string `json:"field"`
+	OldValue string `json:"old_value"`
+	NewValue string `json:"new_value"`
+}
+
+// ProjectChange represents one recorded edit to a project
+type ProjectChange struct {
+	ID        string        `json:"id"`
+	ProjectID string        `json:"project_id"`
+	Changes   []FieldChange `json:"changes"`
+	ChangedAt time.Time     `json:"changed_at"`
+}
+
+// AuditEvent records one create, update, or delete of a project or entry
+type AuditEvent struct {
+	Seq       uint64        `json:"seq"`
+	Timestamp time.Time     `json:"timestamp"`
+	Operation string        `json:"operation"` // create, update, delete, trash, or reopen
+	Target    string        `json:"target"`    // project or entry
+	TargetID  string        `json:"target_id"`
+	Changes   []FieldChange `json:"changes,omitempty"` // Brief diff, long values truncated
+	Detail    string        `json:"detail,omitempty"`
 }
 
 // Entry represents a time tracking worklog entry
 type Entry struct {
-	ID         string    `json:"id"`
-	ProjectID  string    `json:"project_id"`
-	Duration   int64     `json:"duration"` // Duration in minutes
-	Message    string    `json:"message"`
-	CommitHash string    `json:"commit_hash,omitempty"` // Optional
-	Invoiced   bool      `json:"invoiced"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID              string    `json:"id"`
+	ProjectID       string    `json:"project_id"`
+	Duration        int64     `json:"duration"`                   // Duration in minutes
+	EstimateMinutes int64     `json:"estimate_minutes,omitempty"` // Estimated duration in minutes; 0 means no estimate
+	Message         string    `json:"message"`
+	Author          string    `json:"author,omitempty"`
+	CommitHash      string    `json:"commit_hash,omitempty"` // Optional
+	Mode            string    `json:"mode,omitempty"`        // EntryModeGit for entries aggregated from commits, empty otherwise
+	Reference       string    `json:"reference,omitempty"`   // Ticket or issue reference, optional
+	Category        string    `json:"category,omitempty"`    // Work category, optional
+	Location        string    `json:"location,omitempty"`    // Where the work happened (e.g. on-site, remote), optional
+	Source          string    `json:"source,omitempty"`      // Hostname of the machine that logged the entry
+	Invoiced        bool      `json:"invoiced"`
+	InvoiceNumber   string    `json:"invoice_number,omitempty"`   // Set when invoiced through an issued invoice
+	Locked          bool      `json:"locked,omitempty"`           // Locked entries are protected from bulk operations
+	Pinned          bool      `json:"pinned,omitempty"`           // Kept at the top of entry lists, independent of billing
+	NeedsAdjustment bool      `json:"needs_adjustment,omitempty"` // Flagged for a post-invoice correction
+	AdjustmentNote  string    `json:"adjustment_note,omitempty"`  // What needs correcting, optional
+	Tags            []string  `json:"tags,omitempty"`
+	Seq             uint64    `json:"seq,omitempty"` // Creation order; 0 for entries created before sequencing
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// EntryModeGit marks entries created by aggregating git commits
+const EntryModeGit = "git"
+
+// TrashedEntry represents an entry moved to the trash by a bulk operation
+type TrashedEntry struct {
+	Entry
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TimerPause represents an interval during which a timer was paused
+// End is nil while the pause is ongoing
+type TimerPause struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end,omitempty"`
+}
+
+// Timer represents a running time tracker for a project
+// At most one timer exists per project
+type Timer struct {
+	ProjectID string       `json:"project_id"`
+	StartedAt time.Time    `json:"started_at"`
+	Message   string       `json:"message,omitempty"` // What the timer is for; the entry message when stopped without one
+	Pauses    []TimerPause `json:"pauses,omitempty"`
+}
+
+// Paused reports whether the timer is currently paused
+func (t *Timer) Paused() bool {
+	return len(t.Pauses) > 0 && t.Pauses[len(t.Pauses)-1].End == nil
+}
+
+// Elapsed returns the tracked time up to now, excluding pauses
+func (t *Timer) Elapsed(now time.Time) time.Duration {
+	elapsed := now.Sub(t.StartedAt)
+	for _, pause := range t.Pauses {
+		end := now
+		if pause.End != nil {
+			end = *pause.End
+		}
+		elapsed -= end.Sub(pause.Start)
+	}
+	if elapsed < 0 {
+		return 0
+	}
+	return elapsed
+}
+
+// HasTag reports whether the entry carries the given tag (case-insensitive)
+func (e *Entry) HasTag(tag string) bool {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	for _, t := range e.Tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeTags lowercases and trims tags, dropping empty values and duplicates
+// The result is sorted for stable storage and display
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	sort.Strings(normalized)
+	return normalized
+}
+
+// ParseTags splits a comma-separated tag list and normalizes it
+func ParseTags(input string) []string {
+	return NormalizeTags(strings.Split(input, ","))
 }
 
 // CommitInfo holds information about a git commit
@@ -28,5 +167,11 @@ type CommitInfo struct {
 	Hash      string
 	Author    string
 	Message   string
+	Body      string // Commit body after the subject line, may span multiple lines
 	Timestamp time.Time
+	TimeSpent int64    // Minutes from Time-Spent trailers (0 = none)
+	Refs      []string // Values of Refs trailers, e.g. ticket IDs
+	// LinesChanged is the lines added plus removed, counted only when listed with
+	// GetCommitsSinceOptions.CountLines; binary files count as one line each
+	LinesChanged int
 }
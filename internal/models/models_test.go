@@ -60,3 +60,26 @@ func TestCommitInfo(t *testing.T) {
 		t.Errorf("Expected author 'John Doe', got '%s'", commit.Author)
 	}
 }
+
+func TestNormalizeTags(t *testing.T) {
+	tags := NormalizeTags([]string{" Backend", "frontend", "backend", "", "  "})
+
+	if len(tags) != 2 {
+		t.Fatalf("Expected 2 tags, got %d: %v", len(tags), tags)
+	}
+	if tags[0] != "backend" || tags[1] != "frontend" {
+		t.Errorf("Expected [backend frontend], got %v", tags)
+	}
+
+	entry := &Entry{Tags: tags}
+	if !entry.HasTag("BACKEND") {
+		t.Error("Expected entry to have tag 'backend'")
+	}
+	if entry.HasTag("meeting") {
+		t.Error("Expected entry not to have tag 'meeting'")
+	}
+
+	if parsed := ParseTags("a, b,,A"); len(parsed) != 2 {
+		t.Errorf("Expected 2 parsed tags, got %v", parsed)
+	}
+}
@@ -0,0 +1,108 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/techthos/clockwork/internal/git"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// schedulerInterval is how often the server checks for projects due an automatic git entry
+const schedulerInterval = time.Minute
+
+// scheduledRun is the outcome of one project's scheduled git entry
+type scheduledRun struct {
+	ProjectID   string
+	ProjectName string
+	Created     bool // False on days without new commits and on failure
+	Err         error
+}
+
+// runScheduler creates the git entries of projects whose auto_schedule is due until ctx is done
+// Failures go to stderr, the only output a stdio MCP server has besides the protocol.
+func (s *ClockworkServer) runScheduler(ctx context.Context) {
+	ticker := time.NewTicker(schedulerInterval)
+	defer ticker.Stop()
+
+	for {
+		for _, run := range s.runDueSchedules(ctx, time.Now()) {
+			if run.Err != nil {
+				fmt.Fprintf(os.Stderr, "auto_schedule %s: %v\n", run.ProjectName, run.Err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runDueSchedules calls create_entry in git mode, with the project's defaults, for each project
+// due at now. Days without new commits to log (pendingCommits reports errNoNewCommits) are
+// skipped. A due project's run is recorded even when it fails, so a broken or unreachable repo
+// is retried at its next scheduled time rather than every minute.
+func (s *ClockworkServer) runDueSchedules(ctx context.Context, now time.Time) []scheduledRun {
+	// Tool calls are serialized by middleware, which calling the handler directly bypasses
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	projects, err := s.store.DueAutoSchedules(now)
+	if err != nil {
+		return []scheduledRun{{Err: err}}
+	}
+
+	var runs []scheduledRun
+	for _, project := range projects {
+		run := scheduledRun{ProjectID: project.ID, ProjectName: project.Name}
+		run.Created, run.Err = s.runSchedule(ctx, project)
+
+		if err := s.store.SetLastAutoRun(project.ID, now); err != nil && run.Err == nil {
+			run.Err = err
+		}
+		runs = append(runs, run)
+	}
+
+	return runs
+}
+
+// runSchedule creates a project's scheduled git entry through create_entry
+// Returns false without an error when there are no new commits to log.
+func (s *ClockworkServer) runSchedule(ctx context.Context, project *models.Project) (bool, error) {
+	_, _, err := s.pendingCommits(project, "", false, &git.SummarizeOptions{})
+	if errors.Is(err, errNoNewCommits) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var request mcp.CallToolRequest
+	request.Params.Name = "create_entry"
+	request.Params.Arguments = map[string]interface{}{"project_id": project.ID}
+
+	result, err := s.mcp.GetTool("create_entry").Handler(ctx, request)
+	if err != nil {
+		return false, err
+	}
+	if result.IsError {
+		return false, errors.New(toolResultText(result))
+	}
+	return true, nil
+}
+
+// toolResultText returns the text of a tool result
+func toolResultText(result *mcp.CallToolResult) string {
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			return text.Text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/git"
+	"github.com/techthos/clockwork/internal/testutil"
+)
+
+func TestRunDueSchedules(t *testing.T) {
+	s := setupToolServer(t)
+	repo := testutil.NewGitRepo(t)
+
+	os.WriteFile(filepath.Join(repo.Dir, git.IgnoreFile), []byte("path:docs/**\n"), 0644)
+	repo.Run("add", git.IgnoreFile)
+	repo.Run("commit", "-q", "-m", "Ignore docs")
+	project, _ := s.store.CreateProject("Test", repo.Dir)
+	s.store.CreateEntry(project.ID, 30, "Baseline", repo.Run("rev-parse", "HEAD"), false, time.Now().Add(-time.Hour))
+	s.store.SetProjectAutoSchedule(project.ID, "00:00")
+
+	// Only ignored commits: a skipped day, not an error
+	os.MkdirAll(filepath.Join(repo.Dir, "docs"), 0755)
+	os.WriteFile(filepath.Join(repo.Dir, "docs", "guide.md"), []byte("# Guide\n"), 0644)
+	repo.Run("add", "docs")
+	repo.Run("commit", "-q", "-m", "Write the guide")
+
+	day := time.Now().Add(25 * time.Hour)
+	runs := s.runDueSchedules(context.Background(), day)
+	if len(runs) != 1 || runs[0].Created || runs[0].Err != nil {
+		t.Fatalf("Expected one skipped run, got %+v", runs)
+	}
+	if lastRun, _ := s.store.GetLastAutoRun(project.ID); !lastRun.Equal(day) {
+		t.Errorf("Expected the skipped run to be recorded at %v, got %v", day, lastRun)
+	}
+
+	// Not due again until the next scheduled time
+	if runs := s.runDueSchedules(context.Background(), day); len(runs) != 0 {
+		t.Errorf("Expected nothing due after the run, got %+v", runs)
+	}
+
+	repo.Run("commit", "-q", "--allow-empty", "-m", "Add feature")
+	head := repo.Run("rev-parse", "HEAD")
+
+	runs = s.runDueSchedules(context.Background(), day.Add(24*time.Hour))
+	if len(runs) != 1 || !runs[0].Created || runs[0].Err != nil {
+		t.Fatalf("Expected one created entry, got %+v", runs)
+	}
+	if baseline, _ := s.store.GetLastCommitHash(project.ID); baseline != head {
+		t.Errorf("Expected baseline %s after the scheduled entry, got %s", head, baseline)
+	}
+}
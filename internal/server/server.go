@@ -1,29 +1,66 @@
 package server
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/export"
 	"github.com/techthos/clockwork/internal/git"
 	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/stats"
 	"github.com/techthos/clockwork/internal/utils"
-	"github.com/mark3labs/mcp-go/mcp"
-	"github.com/mark3labs/mcp-go/server"
 )
 
 // ClockworkServer represents the MCP server for time tracking
 type ClockworkServer struct {
 	store *db.Store
 	mcp   *server.MCPServer
+
+	// replica, when set, serves the reporting and export tools instead of store (see NewWithReplica)
+	replica *db.Store
+
+	// mu serializes tool calls: handlers read and write the store in several transactions,
+	// which would interleave when the HTTP transport serves several clients at once
+	mu sync.Mutex
 }
 
+// DefaultHTTPAddr is where ServeHTTP listens unless told otherwise; loopback only, since the
+// tools read and write local repositories without authentication
+const DefaultHTTPAddr = "127.0.0.1:8421"
+
+// HTTPEndpoint is the path the HTTP transport serves MCP requests on
+const HTTPEndpoint = "/mcp"
+
+// httpShutdownTimeout bounds how long ServeHTTP waits for in-flight requests on shutdown
+const httpShutdownTimeout = 10 * time.Second
+
 // New creates a new Clockwork MCP server
 func New() (*ClockworkServer, error) {
+	return NewWithReplica("")
+}
+
+// NewWithReplica creates a Clockwork MCP server whose reporting and export tools (list_entries,
+// get_statistics, annual_summary, the exports, audit_log, ...) read from the database copy at
+// replicaPath, opened read-only, while every write still goes to the live database. The reports
+// are only as fresh as the copy. An empty replicaPath reads everything from the live database.
+func NewWithReplica(replicaPath string) (*ClockworkServer, error) {
 	// Initialize database
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -36,8 +73,17 @@ func New() (*ClockworkServer, error) {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
 	}
 
+	cs := &ClockworkServer{store: store}
+	if replicaPath != "" {
+		cs.replica, err = db.NewReadOnly(replicaPath)
+		if err != nil {
+			store.Close()
+			return nil, fmt.Errorf("failed to open replica: %w", err)
+		}
+	}
+
 	// Create MCP server
-	mcpServer := server.NewMCPServer(
+	cs.mcp = server.NewMCPServer(
 		"clockwork",
 		"1.0.0",
 		server.WithInstructions(`Automatically track work time based on git commits of a project.
@@ -46,13 +92,9 @@ Examples:
 - "track 2h" - Create entry with 2 hours from recent git commits
 - "clockwork 1h" - Create entry with 1 hour from recent commits
 - "book 1h meeting with alex" - Manual entry without git commit aggregation`),
+		server.WithToolHandlerMiddleware(cs.serialize),
 	)
 
-	cs := &ClockworkServer{
-		store: store,
-		mcp:   mcpServer,
-	}
-
 	// Register tools
 	cs.registerTools()
 
@@ -61,9 +103,21 @@ Examples:
 
 // Close closes the server and database connection
 func (s *ClockworkServer) Close() error {
+	if s.replica != nil {
+		s.replica.Close()
+	}
 	return s.store.Close()
 }
 
+// reader returns the store the reporting and export tools read from: the replica when one is
+// open, the live database otherwise
+func (s *ClockworkServer) reader() *db.Store {
+	if s.replica != nil {
+		return s.replica
+	}
+	return s.store
+}
+
 // Helper function to get required string argument
 func getRequiredString(request mcp.CallToolRequest, key string) (string, error) {
 	args, ok := request.Params.Arguments.(map[string]interface{})
@@ -81,24 +135,113 @@ func getRequiredString(request mcp.CallToolRequest, key string) (string, error)
 	return str, nil
 }
 
-// Serve starts the MCP server using stdio transport
+// Serve starts the MCP server using stdio transport, with the auto_schedule scheduler
 func (s *ClockworkServer) Serve() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go s.runScheduler(ctx)
+
 	return server.ServeStdio(s.mcp)
 }
 
+// ServeHTTP serves MCP over streamable HTTP (with SSE streaming) at HTTPEndpoint on addr, so
+// several editors can share one clockwork. It returns after SIGINT or SIGTERM once in-flight
+// requests have finished, or when the listener fails.
+func (s *ClockworkServer) ServeHTTP(addr string) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go s.runScheduler(ctx)
+
+	httpServer := s.httpServer()
+	errs := make(chan error, 1)
+	go func() {
+		errs <- httpServer.Start(addr)
+	}()
+
+	select {
+	case err := <-errs:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), httpShutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down HTTP server: %w", err)
+	}
+	return nil
+}
+
+// httpServer builds the HTTP transport; it is also an http.Handler for tests
+func (s *ClockworkServer) httpServer() *server.StreamableHTTPServer {
+	return server.NewStreamableHTTPServer(s.mcp, server.WithEndpointPath(HTTPEndpoint))
+}
+
+// serialize runs one tool call at a time
+func (s *ClockworkServer) serialize(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		return next(ctx, request)
+	}
+}
+
 func (s *ClockworkServer) registerTools() {
 	// Project tools
 	s.registerCreateProject()
 	s.registerUpdateProject()
 	s.registerDeleteProject()
+	s.registerArchiveProject()
 	s.registerListProjects()
+	s.registerProjectHistory()
 
 	// Entry tools
 	s.registerCreateEntry()
 	s.registerUpdateEntry()
 	s.registerDeleteEntry()
 	s.registerListEntries()
+	s.registerBulkDeleteEntries()
+	s.registerClearProjectEntries()
+	s.registerBulkTag()
+	s.registerMarkInvoiced()
+	s.registerRepairBaseline()
 	s.registerGetStatistics()
+	s.registerAnnualSummary()
+	s.registerEstimateInvoice()
+	s.registerByTicket()
+	s.registerListAdjustments()
+	s.registerDetectOverlaps()
+	s.registerReopenEntry()
+
+	// Timer tools
+	s.registerStartTimer()
+	s.registerPauseTimer()
+	s.registerResumeTimer()
+	s.registerStopTimer()
+	s.registerDiscardTimer()
+	s.registerTimerStatus()
+
+	// Export tools
+	s.registerExportEntriesCSV()
+	s.registerExportEntriesByTag()
+	s.registerExportNewEntries()
+	s.registerExportData()
+	s.registerImportData()
+
+	// Settings tools
+	s.registerGetSettings()
+	s.registerSetSetting()
+	s.registerSetAuthorRate()
+
+	// Maintenance tools
+	s.registerDBHealth()
+	s.registerValidateAllCommits()
+	s.registerExpandCommitHashes()
+	s.registerRepairOrphanEntries()
+	s.registerAuditLog()
 }
 
 func (s *ClockworkServer) registerCreateProject() {
@@ -106,6 +249,15 @@ func (s *ClockworkServer) registerCreateProject() {
 		mcp.WithDescription("Create a new project for time tracking"),
 		mcp.WithString("name", mcp.Required(), mcp.Description("Project name")),
 		mcp.WithString("git_repo_path", mcp.Required(), mcp.Description("Path to git repository")),
+		mcp.WithString("duration_method", mcp.Description("Default duration estimation method for git entries (optional): "+strings.Join(git.StrategyNames(), ", "))),
+		mcp.WithBoolean("record_head_for_manual", mcp.Description("Store the repo's HEAD commit on manual entries (optional, default: true; false for non-code work)")),
+		mcp.WithBoolean("own_commits_only", mcp.Description("Aggregate only commits by the repo's git user.name in git entries (optional, default: false; for shared repos)")),
+		mcp.WithNumber("hourly_rate", mcp.Description("Hourly billing rate used to price the project's time in statistics (optional, default: not billed)")),
+		mcp.WithString("currency", mcp.Description("Currency code of hourly_rate, e.g. 'EUR' (optional)")),
+		mcp.WithNumber("budget_hours", mcp.Description("Time budget in hours for fixed-bid projects, shown in the burn-down (optional, default: none)")),
+		mcp.WithString("auto_schedule", mcp.Description("When the server logs a git entry for the project by itself, skipping days without new commits (optional, default: never): "+utils.ScheduleHelp)),
+		mcp.WithBoolean("force", mcp.Description("Create even if git_repo_path is the same as, inside, or a parent of another project's repo (default: false)")),
+		mcp.WithBoolean("allow_missing_path", mcp.Description("Create even if git_repo_path does not exist yet, e.g. before cloning; an existing directory must still be a git repository (default: false)")),
 	)
 
 	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -118,7 +270,19 @@ func (s *ClockworkServer) registerCreateProject() {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		project, err := s.store.CreateProject(name, gitRepoPath)
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		opts, err := projectOptions(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		force, _ := args["force"].(bool)
+		if err := s.checkRepoOverlap(gitRepoPath, "", force); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		allowMissing, _ := args["allow_missing_path"].(bool)
+		project, err := s.store.CreateProjectWithOptions(name, gitRepoPath, repoChecker(allowMissing), opts)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -128,12 +292,97 @@ func (s *ClockworkServer) registerCreateProject() {
 	})
 }
 
+// projectOptions reads the optional project settings of create_project and update_project,
+// validating them all so a bad one fails before the project is written
+// hourly_rate and currency are independent: whichever is not given is kept
+func projectOptions(args map[string]interface{}) (db.ProjectOptions, error) {
+	var opts db.ProjectOptions
+
+	if method, ok := args["duration_method"].(string); ok {
+		if _, err := git.GetStrategy(method); err != nil {
+			return opts, err
+		}
+		opts.DurationMethod = &method
+	}
+	if recordHead, ok := args["record_head_for_manual"].(bool); ok {
+		opts.RecordHeadForManual = &recordHead
+	}
+	if ownOnly, ok := args["own_commits_only"].(bool); ok {
+		opts.OwnCommitsOnly = &ownOnly
+	}
+	if rate, ok := args["hourly_rate"].(float64); ok {
+		if rate < 0 || math.IsNaN(rate) || math.IsInf(rate, 0) {
+			return opts, fmt.Errorf("hourly_rate must be a non-negative number")
+		}
+		opts.HourlyRate = &rate
+	}
+	if currency, ok := args["currency"].(string); ok {
+		opts.Currency = &currency
+	}
+	if hours, ok := args["budget_hours"].(float64); ok {
+		if hours < 0 || math.IsNaN(hours) || math.IsInf(hours, 0) {
+			return opts, fmt.Errorf("budget_hours must be a non-negative number")
+		}
+		minutes := int64(math.Round(hours * 60))
+		opts.BudgetMinutes = &minutes
+	}
+	if schedule, ok := args["auto_schedule"].(string); ok {
+		if strings.TrimSpace(schedule) != "" {
+			if _, err := utils.ParseSchedule(schedule); err != nil {
+				return opts, err
+			}
+		}
+		opts.AutoSchedule = &schedule
+	}
+	return opts, nil
+}
+
+// checkRepoOverlap rejects a repo path that is the same as, inside, or a parent of another
+// project's repo, since nested repos make commit ranges overlap. force skips the check.
+func (s *ClockworkServer) checkRepoOverlap(path, projectID string, force bool) error {
+	if force {
+		return nil
+	}
+	project, relation, err := s.store.FindOverlappingProject(path, projectID)
+	if err != nil {
+		return err
+	}
+	if project != nil {
+		return fmt.Errorf("%s; pass force=true to use it anyway", db.OverlapMessage(path, project, relation))
+	}
+	return nil
+}
+
+// repoChecker rejects project paths that are not git repositories, so a bad path fails when the
+// project is saved rather than at its first entry. allowMissing accepts paths that do not exist yet.
+func repoChecker(allowMissing bool) db.RepoChecker {
+	return func(path string) error {
+		status := git.ValidateRepo(path)
+		if status.Err == nil || (allowMissing && !status.Exists) {
+			return nil
+		}
+		if !status.Exists {
+			return fmt.Errorf("%w; pass allow_missing_path=true to add the project before the repo exists", status.Err)
+		}
+		return status.Err
+	}
+}
+
 func (s *ClockworkServer) registerUpdateProject() {
 	tool := mcp.NewTool("update_project",
 		mcp.WithDescription("Update an existing project"),
 		mcp.WithString("id", mcp.Required(), mcp.Description("Project ID")),
 		mcp.WithString("name", mcp.Description("New project name (optional)")),
 		mcp.WithString("git_repo_path", mcp.Description("New git repository path (optional)")),
+		mcp.WithString("duration_method", mcp.Description("Default duration estimation method for git entries (optional, empty string resets): "+strings.Join(git.StrategyNames(), ", "))),
+		mcp.WithBoolean("record_head_for_manual", mcp.Description("Store the repo's HEAD commit on manual entries (optional)")),
+		mcp.WithBoolean("own_commits_only", mcp.Description("Aggregate only commits by the repo's git user.name in git entries (optional)")),
+		mcp.WithNumber("hourly_rate", mcp.Description("Hourly billing rate (optional, 0 stops pricing the project's time)")),
+		mcp.WithString("currency", mcp.Description("Currency code of hourly_rate (optional, empty string clears)")),
+		mcp.WithNumber("budget_hours", mcp.Description("Time budget in hours for fixed-bid projects (optional, 0 removes it)")),
+		mcp.WithString("auto_schedule", mcp.Description("When the server logs a git entry for the project by itself, skipping days without new commits (optional, empty string turns it off): "+utils.ScheduleHelp)),
+		mcp.WithBoolean("force", mcp.Description("Update even if git_repo_path is the same as, inside, or a parent of another project's repo (default: false)")),
+		mcp.WithBoolean("allow_missing_path", mcp.Description("Accept a git_repo_path that does not exist yet; an existing directory must still be a git repository (default: false)")),
 	)
 
 	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -146,7 +395,18 @@ func (s *ClockworkServer) registerUpdateProject() {
 		name, _ := args["name"].(string)
 		gitRepoPath, _ := args["git_repo_path"].(string)
 
-		project, err := s.store.UpdateProject(id, name, gitRepoPath)
+		opts, err := projectOptions(args)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		force, _ := args["force"].(bool)
+		if err := s.checkRepoOverlap(gitRepoPath, id, force); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		allowMissing, _ := args["allow_missing_path"].(bool)
+		project, err := s.store.UpdateProjectWithOptions(id, name, gitRepoPath, repoChecker(allowMissing), opts)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -158,7 +418,7 @@ func (s *ClockworkServer) registerUpdateProject() {
 
 func (s *ClockworkServer) registerDeleteProject() {
 	tool := mcp.NewTool("delete_project",
-		mcp.WithDescription("Delete a project and all its entries"),
+		mcp.WithDescription("Permanently delete a project and all its entries (archive_project hides it instead)"),
 		mcp.WithString("id", mcp.Required(), mcp.Description("Project ID")),
 	)
 
@@ -176,13 +436,51 @@ func (s *ClockworkServer) registerDeleteProject() {
 	})
 }
 
+func (s *ClockworkServer) registerArchiveProject() {
+	tool := mcp.NewTool("archive_project",
+		mcp.WithDescription("Archive a project, hiding it from project lists while keeping it and its entries, or unarchive it"),
+		mcp.WithString("id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithBoolean("archived", mcp.Description("false to unarchive the project (default: true)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := getRequiredString(request, "id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		archived := true
+		if a, ok := args["archived"].(bool); ok {
+			archived = a
+		}
+
+		var project *models.Project
+		if archived {
+			project, err = s.store.ArchiveProject(id)
+		} else {
+			project, err = s.store.UnarchiveProject(id)
+		}
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(project, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
 func (s *ClockworkServer) registerListProjects() {
 	tool := mcp.NewTool("list_projects",
 		mcp.WithDescription("List all projects"),
+		mcp.WithBoolean("include_archived", mcp.Description("Also list archived projects (default: false)")),
 	)
 
 	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		projects, err := s.store.ListProjects()
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		includeArchived, _ := args["include_archived"].(bool)
+
+		projects, err := s.store.ListProjects(includeArchived)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
@@ -192,36 +490,114 @@ func (s *ClockworkServer) registerListProjects() {
 	})
 }
 
+func (s *ClockworkServer) registerProjectHistory() {
+	tool := mcp.NewTool("project_history",
+		mcp.WithDescription("Show the timeline of changes made to a project, oldest first"),
+		mcp.WithString("id", mcp.Required(), mcp.Description("Project ID")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := getRequiredString(request, "id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if _, err := s.reader().GetProject(id); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		history, err := s.reader().ProjectHistory(id)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(history, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
 func (s *ClockworkServer) registerCreateEntry() {
 	tool := mcp.NewTool("create_entry",
 		mcp.WithDescription("Create a worklog entry with automatic commit aggregation or manual entry"),
-		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional when a default project is configured)")),
 		mcp.WithString("message", mcp.Description("Custom message (optional, will auto-generate from commits if not provided)")),
 		mcp.WithBoolean("invoiced", mcp.Description("Whether the entry has been invoiced (default: false)")),
 		mcp.WithBoolean("manual", mcp.Description("Skip git commit aggregation (default: false)")),
 		mcp.WithString("duration", mcp.Description("Duration in format '1h 30m' or '90m' (required when manual=true, optional override otherwise)")),
-		mcp.WithString("created_at", mcp.Description("Entry creation datetime in RFC3339 format (optional, e.g., '2026-01-15T14:30:00Z')")),
+		mcp.WithString("created_at", mcp.Description("Entry creation datetime (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("method", mcp.Description("Duration estimation method for git mode (optional, default: project setting or 'span'): "+strings.Join(git.StrategyNames(), ", "))),
+		mcp.WithString("fallback_manual_duration", mcp.Description("Duration to log at the current HEAD when git mode finds no new commits (including when .clockworkignore drops them all), or as a manual entry when the repo is unreachable (repo_unavailable), e.g. '1h' (optional)")),
+		mcp.WithBoolean("split_by_day", mcp.Description("Create one entry per calendar day of commits, dated by that day's last commit (git mode only, default: false)")),
+		mcp.WithString("author", mcp.Description("Author the entry is attributed to; in git mode only commits matching this author are aggregated (optional, manual entries default to the repo's git user.name, git entries cover every author unless the project sets own_commits_only)")),
+		mcp.WithBoolean("include_bodies", mcp.Description("Include commit bodies beneath each subject in the message (git mode only, default: include_commit_bodies setting)")),
+		mcp.WithBoolean("daily_subtotals", mcp.Description("List commits under a heading per day with that day's estimated time, and log the sum of the days (git mode only, default: daily_subtotals setting)")),
+		mcp.WithBoolean("exclude_merges", mcp.Description("Leave merge commits out of the message and duration (git mode only, default: false)")),
+		mcp.WithBoolean("auto_merge_same_day", mcp.Description("Extend the last git entry instead of creating a new one when it is from the same calendar day (git mode only, default: false)")),
+		mcp.WithBoolean("force", mcp.Description("Create a git entry even within min_entry_interval of the project's last one (default: false)")),
+		mcp.WithNumber("round_to", mcp.Description("Round the duration to a multiple of this many minutes, e.g. 15 (optional, not applied to fallback_manual_duration)")),
+		mcp.WithString("round_mode", mcp.Description("Rounding direction for round_to: 'up', 'nearest', or 'down' (default: 'up'); a duration never rounds below one round_to")),
+		mcp.WithString("tags", mcp.Description("Comma-separated tags, e.g. 'bugfix,meeting' (optional; added to an entry extended by auto_merge_same_day)")),
+		mcp.WithString("estimate", mcp.Description("Estimated duration in format '1h 30m' or '90m', compared with the actual duration in statistics (optional, not applied with split_by_day)")),
 	)
 
 	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		projectID, err := getRequiredString(request, "project_id")
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		projectID, _ := args["project_id"].(string)
+		projectID, err := s.store.ResolveProjectID(projectID)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
-		args, _ := request.Params.Arguments.(map[string]interface{})
 
 		customMessage, _ := args["message"].(string)
 		invoiced, _ := args["invoiced"].(bool)
 		manual, _ := args["manual"].(bool)
 		durationStr, _ := args["duration"].(string)
 		createdAtStr, _ := args["created_at"].(string)
+		splitByDay, _ := args["split_by_day"].(bool)
+		fallbackDurationStr, _ := args["fallback_manual_duration"].(string)
+		method, _ := args["method"].(string)
+		author, _ := args["author"].(string)
+		autoMerge, _ := args["auto_merge_same_day"].(bool)
+		excludeMerges, _ := args["exclude_merges"].(bool)
+		force, _ := args["force"].(bool)
+		roundTo, _ := args["round_to"].(float64)
+		roundMode, _ := args["round_mode"].(string)
+		tagsStr, _ := args["tags"].(string)
+		tags := models.ParseTags(tagsStr)
+		estimateStr, _ := args["estimate"].(string)
+
+		var estimate int64
+		if estimateStr != "" {
+			estimate, err = utils.ParseDuration(estimateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid estimate: %v", err)), nil
+			}
+			if estimate < 0 {
+				return mcp.NewToolResultError("estimate cannot be negative"), nil
+			}
+		}
+
+		// Author, tags and estimate are stored with the entry in one transaction
+		entryOpts := db.EntryOptions{Author: author, Tags: tags, Estimate: estimate}
+
+		// Optional rounding of the final duration to a billing increment
+		if roundTo < 0 {
+			return mcp.NewToolResultError("round_to must not be negative"), nil
+		}
+		if err := utils.ValidateRoundMode(roundMode); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		round := func(minutes int64) int64 {
+			return utils.RoundMinutes(minutes, int64(roundTo), roundMode)
+		}
 
 		// Parse created_at if provided, otherwise use current time
 		createdAt := time.Now()
 		if createdAtStr != "" {
-			parsed, err := time.Parse(time.RFC3339, createdAtStr)
+			parsed, err := utils.ParseFlexibleDate(createdAtStr)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("invalid created_at format (use RFC3339, e.g., '2026-01-15T14:30:00Z'): %v", err)), nil
+				return mcp.NewToolResultError(fmt.Sprintf("invalid created_at: %v", err)), nil
 			}
 			createdAt = parsed
 		}
@@ -242,21 +618,10 @@ func (s *ClockworkServer) registerCreateEntry() {
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("invalid duration: %v", err)), nil
 			}
+			duration = round(duration)
 
-			message := customMessage
-			if message == "" {
-				message = "Manual entry"
-			}
-
-			// For manual entries, always store current HEAD commit hash (even if duplicate)
 			project, _ := s.store.GetProject(projectID)
-			currentHash, err := git.GetLatestCommitHash(project.GitRepoPath)
-			if err != nil {
-				// If we can't get HEAD hash, just store empty string
-				currentHash = ""
-			}
-
-			entry, err := s.store.CreateEntry(projectID, duration, message, currentHash, invoiced, createdAt)
+			entry, err := s.createManualEntry(project, duration, customMessage, invoiced, createdAt, entryOpts)
 			if err != nil {
 				return mcp.NewToolResultError(err.Error()), nil
 			}
@@ -271,34 +636,73 @@ func (s *ClockworkServer) registerCreateEntry() {
 		// Git-based entry path
 		project, _ := s.store.GetProject(projectID)
 
-		// Find the most recent commit hash across all entries (skips manual entries without one)
-		sinceHash, err := s.store.GetLastCommitHash(projectID)
+		// A repo out of reach (e.g. on an unmounted network drive) cannot be aggregated: log
+		// fallback_manual_duration as a manual entry, or report repo_unavailable so the caller
+		// can log manually instead of retrying blindly
+		if status := git.ValidateRepo(project.GitRepoPath); status.Availability() == git.RepoUnavailable {
+			if fallbackDurationStr == "" {
+				return mcp.NewToolResultError(fmt.Sprintf("%s: %v; retry once it is reachable, or pass manual=true with a duration to log the time now", git.RepoUnavailable, status.Err)), nil
+			}
+
+			duration, err := utils.ParseDuration(fallbackDurationStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid fallback_manual_duration: %v", err)), nil
+			}
+			entry, err := s.createManualEntry(project, duration, customMessage, invoiced, createdAt, entryOpts)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+
+			result, _ := json.MarshalIndent(map[string]interface{}{
+				"entry":  entry,
+				"mode":   "manual",
+				"status": git.RepoUnavailable,
+				"note":   fmt.Sprintf("%v; logged fallback_manual_duration as a manual entry", status.Err),
+			}, "", "  ")
+			return mcp.NewToolResultText(string(result)), nil
+		}
+
+		// Guard against running create_entry twice in quick succession
+		if err := s.checkEntryInterval(projectID, createdAt, autoMerge && !splitByDay, force); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		summarizeOpts, err := s.store.GetSummarizeOptions()
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		// Validate that the commit hash still exists in the repository
-		if sinceHash != "" && !git.ValidateCommitHash(project.GitRepoPath, sinceHash) {
-			sinceHash = ""
+		// Duration estimation strategy: explicit method, then project default
+		if method == "" {
+			method = project.DurationMethod
+		}
+		summarizeOpts.Strategy, err = git.GetStrategy(method)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		var commits []models.CommitInfo
-		if sinceHash != "" {
-			commits, err = git.GetCommitsSince(project.GitRepoPath, sinceHash)
-			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get commits: %v", err)), nil
+		commits, baselineWarning, err := s.pendingCommits(project, author, excludeMerges, &summarizeOpts)
+		if errors.Is(err, errNoNewCommits) {
+			if fallbackDurationStr == "" {
+				return mcp.NewToolResultError(err.Error()), nil
 			}
-		} else {
-			// No baseline — just grab HEAD as a single commit
-			commit, err := git.GetLatestCommit(project.GitRepoPath)
+			reason := err
+
+			entry, err := s.createFallbackEntry(project, fallbackDurationStr, customMessage, invoiced, createdAt, entryOpts)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("failed to get latest commit: %v", err)), nil
+				return mcp.NewToolResultError(err.Error()), nil
 			}
-			commits = []models.CommitInfo{*commit}
-		}
 
-		if len(commits) == 0 {
-			return mcp.NewToolResultError("no new commits found since last entry"), nil
+			result, _ := json.MarshalIndent(withWarning(map[string]interface{}{
+				"entry":         entry,
+				"commits_found": 0,
+				"mode":          "git",
+				"note":          fmt.Sprintf("%v; logged fallback_manual_duration at current HEAD", reason),
+			}, baselineWarning), "", "  ")
+			return mcp.NewToolResultText(string(result)), nil
+		}
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
 		}
 
 		// Get latest commit hash
@@ -307,101 +711,480 @@ func (s *ClockworkServer) registerCreateEntry() {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		// Calculate duration (use override if provided)
-		var duration int64
+		// Commit bodies and per-day subtotals: explicit argument, then setting
+		if includeBodies, ok := args["include_bodies"].(bool); ok {
+			summarizeOpts.IncludeBodies = includeBodies
+		}
+		if dailySubtotals, ok := args["daily_subtotals"].(bool); ok {
+			summarizeOpts.DailySubtotals = dailySubtotals
+		}
+
+		// One entry per calendar day, each with its own estimated duration
+		if splitByDay {
+			if durationStr != "" {
+				return mcp.NewToolResultError("duration override cannot be combined with split_by_day"), nil
+			}
+			if autoMerge {
+				return mcp.NewToolResultError("auto_merge_same_day cannot be combined with split_by_day"), nil
+			}
+
+			days := git.GroupCommitsByDay(commits)
+			entries := make([]*models.Entry, 0, len(days))
+			var totalDuration int64
+
+			for i, day := range days {
+				message, duration := git.SummarizeCommits(day.Commits, summarizeOpts)
+				duration = round(duration)
+				if customMessage != "" {
+					message = customMessage
+				}
+
+				// The last day carries HEAD so it becomes the next baseline
+				latest := git.LatestCommit(day.Commits)
+				commitHash := latest.Hash
+				if i == len(days)-1 {
+					commitHash = latestHash
+				}
+
+				entry, _, err := s.createGitEntry(projectID, duration, message, commitHash, invoiced, latest.Timestamp, false, entryOpts)
+				if err != nil {
+					return mcp.NewToolResultError(err.Error()), nil
+				}
+				entries = append(entries, entry)
+				totalDuration += duration
+			}
+
+			result, _ := json.MarshalIndent(withWarning(map[string]interface{}{
+				"entries":        entries,
+				"commits_found":  len(commits),
+				"total_duration": totalDuration,
+				"mode":           "git",
+			}, baselineWarning), "", "  ")
+			return mcp.NewToolResultText(string(result)), nil
+		}
+
+		// Generate message and estimate duration
+		message, duration := git.SummarizeCommits(commits, summarizeOpts)
+
+		// Use overrides if provided
 		if durationStr != "" {
 			duration, err = utils.ParseDuration(durationStr)
 			if err != nil {
 				return mcp.NewToolResultError(fmt.Sprintf("invalid duration: %v", err)), nil
 			}
-		} else {
-			duration = git.CalculateDuration(commits)
 		}
-
-		// Generate message
-		message := customMessage
-		if message == "" {
-			message = git.AggregateCommits(commits)
+		duration = round(duration)
+		if customMessage != "" {
+			message = customMessage
 		}
 
-		// Create entry
-		entry, err := s.store.CreateEntry(projectID, duration, message, latestHash, invoiced, createdAt)
+		// Create entry, or extend today's git entry when requested
+		entry, merged, err := s.createGitEntry(projectID, duration, message, latestHash, invoiced, createdAt, autoMerge, entryOpts)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		result, _ := json.MarshalIndent(map[string]interface{}{
+		result, _ := json.MarshalIndent(withWarning(map[string]interface{}{
 			"entry":         entry,
 			"commits_found": len(commits),
 			"mode":          "git",
-		}, "", "  ")
+			"merged":        merged,
+		}, baselineWarning), "", "  ")
 		return mcp.NewToolResultText(string(result)), nil
 	})
 }
 
-func (s *ClockworkServer) registerUpdateEntry() {
-	tool := mcp.NewTool("update_entry",
-		mcp.WithDescription("Update an existing worklog entry"),
-		mcp.WithString("id", mcp.Required(), mcp.Description("Entry ID")),
-		mcp.WithNumber("duration", mcp.Description("New duration in minutes (optional)")),
-		mcp.WithString("duration_string", mcp.Description("Duration in format '1h 30m' or '90m' (overrides numeric duration)")),
-		mcp.WithString("message", mcp.Description("New message (optional)")),
-		mcp.WithString("commit_hash", mcp.Description("New commit hash (optional)")),
-		mcp.WithBoolean("invoiced", mcp.Description("Update invoiced status (optional)")),
-		mcp.WithString("created_at", mcp.Description("Update entry creation datetime in RFC3339 format (optional, e.g., '2026-01-15T14:30:00Z')")),
-	)
+// entryCommitHash expands a commit hash given for an entry to the full hash in its project's repo
+// An empty hash is passed through to clear the entry's hash
+func (s *ClockworkServer) entryCommitHash(entryID, hash string) (string, error) {
+	if strings.TrimSpace(hash) == "" {
+		return "", nil
+	}
+	entry, err := s.store.GetEntry(entryID)
+	if err != nil {
+		return "", err
+	}
+	project, err := s.store.GetProject(entry.ProjectID)
+	if err != nil {
+		return "", fmt.Errorf("project not found: %w", err)
+	}
+	return git.ExpandCommitHash(project.GitRepoPath, hash)
+}
 
-	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
-		id, err := getRequiredString(request, "id")
+// withWarning adds a "warning" field to a tool result when warning is set
+func withWarning(result map[string]interface{}, warning string) map[string]interface{} {
+	if warning != "" {
+		result["warning"] = warning
+	}
+	return result
+}
+
+// createGitEntry stores an aggregated git entry with opts and reports whether it was merged.
+// With autoMerge, a baseline entry that is a git entry from the same calendar day (and is
+// neither locked nor invoiced) is extended with the new duration, message, and hash instead.
+func (s *ClockworkServer) createGitEntry(projectID string, duration int64, message, commitHash string, invoiced bool, createdAt time.Time, autoMerge bool, opts db.EntryOptions) (*models.Entry, bool, error) {
+	if autoMerge {
+		last, err := s.store.GetLastCommitEntry(projectID)
 		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
+			return nil, false, err
 		}
-		args, _ := request.Params.Arguments.(map[string]interface{})
-
-		var duration *int64
-		var message, commitHash *string
-		var invoiced *bool
-		var createdAt *time.Time
-
-		// Parse duration_string first (takes priority over numeric duration)
-		if durationStr, ok := args["duration_string"].(string); ok && durationStr != "" {
-			parsed, err := utils.ParseDuration(durationStr)
+		if last != nil && canMergeSameDay(last, createdAt) {
+			entry, err := s.store.ExtendEntry(last.ID, duration, message, commitHash, opts)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("invalid duration_string: %v", err)), nil
+				return nil, false, err
 			}
-			duration = &parsed
-		} else if d, ok := args["duration"].(float64); ok {
-			dInt := int64(d)
-			duration = &dInt
+			return entry, true, nil
 		}
+	}
 
-		if m, ok := args["message"].(string); ok {
-			message = &m
-		}
-		if c, ok := args["commit_hash"].(string); ok {
-			commitHash = &c
-		}
-		if i, ok := args["invoiced"].(bool); ok {
-			invoiced = &i
-		}
+	opts.Mode = models.EntryModeGit
+	entry, err := s.store.CreateEntryWithOptions(projectID, duration, message, commitHash, invoiced, createdAt, opts)
+	if err != nil {
+		return nil, false, err
+	}
+	return entry, false, nil
+}
 
-		// Parse created_at if provided
-		if createdAtStr, ok := args["created_at"].(string); ok && createdAtStr != "" {
-			parsed, err := time.Parse(time.RFC3339, createdAtStr)
-			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("invalid created_at format (use RFC3339, e.g., '2026-01-15T14:30:00Z'): %v", err)), nil
-			}
-			createdAt = &parsed
-		}
+// checkEntryInterval rejects a git entry dated within the min_entry_interval setting of the
+// project's last git entry, which usually means create_entry ran twice by accident.
+// Entries that autoMerge would fold into the last one are allowed; force skips the check.
+func (s *ClockworkServer) checkEntryInterval(projectID string, createdAt time.Time, autoMerge, force bool) error {
+	if force {
+		return nil
+	}
+	interval, err := s.store.GetMinEntryInterval()
+	if err != nil || interval <= 0 {
+		return err
+	}
 
-		entry, err := s.store.UpdateEntry(id, duration, message, commitHash, invoiced, createdAt)
-		if err != nil {
-			return mcp.NewToolResultError(err.Error()), nil
-		}
+	last, err := s.store.GetLastCommitEntry(projectID)
+	if err != nil {
+		return err
+	}
+	if last == nil || last.Mode != models.EntryModeGit {
+		return nil
+	}
+	if autoMerge && canMergeSameDay(last, createdAt) {
+		return nil
+	}
 
-		result, _ := json.MarshalIndent(entry, "", "  ")
-		return mcp.NewToolResultText(string(result)), nil
-	})
+	gap := createdAt.Sub(last.CreatedAt)
+	if gap < 0 {
+		gap = -gap
+	}
+	if gap < interval {
+		return fmt.Errorf("last git entry %s is only %s away, within min_entry_interval of %d minutes; pass force=true to create another",
+			last.ID, gap.Round(time.Second), int64(interval/time.Minute))
+	}
+	return nil
+}
+
+// canMergeSameDay reports whether a new git entry at createdAt may be folded into entry
+func canMergeSameDay(entry *models.Entry, createdAt time.Time) bool {
+	if entry.Mode != models.EntryModeGit || entry.Locked || entry.Invoiced {
+		return false
+	}
+	y1, m1, d1 := entry.CreatedAt.In(time.Local).Date()
+	y2, m2, d2 := createdAt.In(time.Local).Date()
+	return y1 == y2 && m1 == m2 && d1 == d2
+}
+
+// createManualEntry logs a manual entry at the current HEAD (even if already used),
+// or without a commit hash when the project doesn't record HEAD for manual entries
+// An empty message uses the manual message template; an empty opts.Author defaults to the repo author
+func (s *ClockworkServer) createManualEntry(project *models.Project, duration int64, message string, invoiced bool, createdAt time.Time, opts db.EntryOptions) (*models.Entry, error) {
+	if message == "" {
+		template, err := s.store.GetSetting(db.SettingManualMessageTemplate)
+		if err != nil {
+			return nil, err
+		}
+		message = utils.RenderMessageTemplate(template, project.Name, createdAt)
+	}
+
+	if opts.Author == "" {
+		opts.Author = s.defaultAuthor(project)
+	}
+
+	// Non-code projects can opt out of tying manual entries to HEAD
+	currentHash := ""
+	if project.RecordsHeadForManual() {
+		// If we can't get HEAD hash, just store empty string
+		if hash, err := git.GetLatestCommitHash(project.GitRepoPath); err == nil {
+			currentHash = hash
+		}
+	}
+
+	return s.store.CreateEntryWithOptions(project.ID, duration, message, currentHash, invoiced, createdAt, opts)
+}
+
+// defaultAuthor returns the project repo's git user.name, or "" when disabled or unavailable
+func (s *ClockworkServer) defaultAuthor(project *models.Project) string {
+	enabled, err := s.store.GetSetting(db.SettingDefaultAuthorFromRepo)
+	if err != nil || enabled == "false" {
+		return ""
+	}
+
+	author, err := git.GetAuthor(project.GitRepoPath)
+	if err != nil {
+		return ""
+	}
+	return author
+}
+
+// errNoNewCommits means git mode found nothing to log since the project's baseline
+var errNoNewCommits = errors.New("no new commits found since last entry")
+
+// pendingCommits returns the commits git mode logs for a project: those since its baseline
+// (HEAD alone without one) by the aggregated author, less those the repo's .clockworkignore
+// drops, whose subject rules it adds to opts. The warning is set when the baseline was
+// recovered from the reflog. When no commits are left the error wraps errNoNewCommits, so
+// ignored commits are handled like having none and fallback_manual_duration can move the
+// baseline past them.
+func (s *ClockworkServer) pendingCommits(project *models.Project, author string, excludeMerges bool, opts *git.SummarizeOptions) ([]models.CommitInfo, string, error) {
+	// Find the most recent commit hash across all entries (skips manual entries without one)
+	sinceHash, err := s.store.GetLastCommitHash(project.ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Refuse to aggregate across unrelated histories (e.g. repo path repointed) or from a
+	// baseline the repo doesn't have; only a project without any baseline logs HEAD alone.
+	// a baseline lost to a reset but still in the reflog is used with a warning
+	var warning string
+	if sinceHash != "" {
+		warning, err = git.CheckBaseline(project.GitRepoPath, sinceHash)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w; run repair_baseline to reset it to HEAD", err)
+		}
+	}
+
+	// Shared repos: limit aggregation to one author's commits
+	commitAuthor, err := git.CommitAuthor(project.GitRepoPath, author, project.OwnCommitsOnly)
+	if err != nil {
+		return nil, warning, err
+	}
+
+	var commits []models.CommitInfo
+	if sinceHash != "" {
+		// Trivial commits need line counts, which cost a second git log
+		commits, err = git.GetCommitsSinceWithOptions(project.GitRepoPath, sinceHash, git.GetCommitsSinceOptions{NoMerges: excludeMerges, Author: commitAuthor, CountLines: opts.CountLines()})
+		if err != nil {
+			return nil, warning, fmt.Errorf("failed to get commits: %w", err)
+		}
+	} else {
+		// No baseline — just grab HEAD as a single commit
+		commit, err := git.GetLatestCommit(project.GitRepoPath)
+		if err != nil {
+			return nil, warning, fmt.Errorf("failed to get latest commit: %w", err)
+		}
+		commits = []models.CommitInfo{*commit}
+	}
+	if len(commits) == 0 {
+		return nil, warning, errNoNewCommits
+	}
+
+	// Repo-local .clockworkignore rules extend the configured exclusions
+	ignoreRules, err := git.LoadIgnore(project.GitRepoPath)
+	if err != nil {
+		return nil, warning, err
+	}
+	opts.ExcludePatterns = append(opts.ExcludePatterns, ignoreRules.Subjects...)
+	commits, err = git.DropIgnoredPaths(project.GitRepoPath, commits, ignoreRules)
+	if err != nil {
+		return nil, warning, err
+	}
+	if len(commits) == 0 {
+		return nil, warning, fmt.Errorf("%w: all of them are ignored by %s", errNoNewCommits, git.IgnoreFile)
+	}
+
+	return commits, warning, nil
+}
+
+// createFallbackEntry logs a manual duration attributed to the current HEAD when git mode
+// finds no new commits, so the baseline is still recorded; it is a git entry like the ones
+// it stands in for, so min_entry_interval and auto_merge_same_day see it
+func (s *ClockworkServer) createFallbackEntry(project *models.Project, durationStr, message string, invoiced bool, createdAt time.Time, opts db.EntryOptions) (*models.Entry, error) {
+	duration, err := utils.ParseDuration(durationStr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fallback_manual_duration: %w", err)
+	}
+
+	headHash, err := git.GetLatestCommitHash(project.GitRepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if message == "" {
+		message = "Work without new commits"
+	}
+
+	opts.Mode = models.EntryModeGit
+	return s.store.CreateEntryWithOptions(project.ID, duration, message, headHash, invoiced, createdAt, opts)
+}
+
+func (s *ClockworkServer) registerUpdateEntry() {
+	tool := mcp.NewTool("update_entry",
+		mcp.WithDescription("Update an existing worklog entry"),
+		mcp.WithString("id", mcp.Required(), mcp.Description("Entry ID")),
+		mcp.WithNumber("duration", mcp.Description("New duration in minutes (optional)")),
+		mcp.WithString("duration_string", mcp.Description("Duration in format '1h 30m' or '90m' (overrides numeric duration)")),
+		mcp.WithString("message", mcp.Description("New message (optional)")),
+		mcp.WithString("commit_hash", mcp.Description("New commit hash, abbreviated or full; stored as the full hash resolved in the project's repo (optional, empty string clears)")),
+		mcp.WithBoolean("invoiced", mcp.Description("Update invoiced status (optional)")),
+		mcp.WithString("created_at", mcp.Description("Update entry creation datetime (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("tags", mcp.Description("Comma-separated tags replacing the current ones (optional, empty string clears)")),
+		mcp.WithString("estimate", mcp.Description("Estimated duration in format '1h 30m' or '90m' (optional, empty string clears)")),
+		mcp.WithBoolean("locked", mcp.Description("Lock or unlock the entry; locked entries are skipped by bulk deletes (optional)")),
+		mcp.WithBoolean("pinned", mcp.Description("Pin or unpin the entry; pinned entries stay at the top of the TUI entry list whatever the sort, without affecting billing (optional)")),
+		mcp.WithString("author", mcp.Description("Author the entry is attributed to (optional, empty string clears)")),
+		mcp.WithString("reference", mcp.Description("Ticket or issue reference (optional, empty string clears)")),
+		mcp.WithString("category", mcp.Description("Work category (optional, empty string clears)")),
+		mcp.WithString("location", mcp.Description("Where the work happened, e.g. 'on-site' or 'remote' (optional, empty string clears)")),
+		mcp.WithString("source", mcp.Description("Machine the entry is attributed to, overriding the hostname recorded at creation (optional, empty string clears)")),
+		mcp.WithBoolean("needs_adjustment", mcp.Description("Flag or clear the entry as needing an invoice adjustment (optional; clearing also clears the note)")),
+		mcp.WithString("adjustment_note", mcp.Description("What needs correcting (optional; setting a note flags the entry)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := getRequiredString(request, "id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		var duration *int64
+		var message, commitHash *string
+		var invoiced *bool
+		var createdAt *time.Time
+
+		// Parse duration_string first (takes priority over numeric duration)
+		if durationStr, ok := args["duration_string"].(string); ok && durationStr != "" {
+			parsed, err := utils.ParseDuration(durationStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid duration_string: %v", err)), nil
+			}
+			duration = &parsed
+		} else if d, ok := args["duration"].(float64); ok {
+			dInt := int64(d)
+			duration = &dInt
+		}
+
+		if m, ok := args["message"].(string); ok {
+			message = &m
+		}
+		if c, ok := args["commit_hash"].(string); ok {
+			full, err := s.entryCommitHash(id, c)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			commitHash = &full
+		}
+		if i, ok := args["invoiced"].(bool); ok {
+			invoiced = &i
+		}
+
+		// Parse created_at if provided
+		if createdAtStr, ok := args["created_at"].(string); ok && createdAtStr != "" {
+			parsed, err := utils.ParseFlexibleDate(createdAtStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid created_at: %v", err)), nil
+			}
+			createdAt = &parsed
+		}
+
+		// Parse estimate up front so a bad value doesn't leave a half-applied update
+		var estimate *int64
+		if estimateStr, ok := args["estimate"].(string); ok {
+			var minutes int64
+			if estimateStr != "" {
+				minutes, err = utils.ParseDuration(estimateStr)
+				if err != nil {
+					return mcp.NewToolResultError(fmt.Sprintf("invalid estimate: %v", err)), nil
+				}
+			}
+			estimate = &minutes
+		}
+
+		entry, err := s.store.UpdateEntry(id, duration, message, commitHash, invoiced, createdAt)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if estimate != nil {
+			entry, err = s.store.SetEntryEstimate(id, *estimate)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if tags, ok := args["tags"].(string); ok {
+			entry, err = s.store.SetEntryTags(id, models.ParseTags(tags))
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if locked, ok := args["locked"].(bool); ok {
+			entry, err = s.store.SetEntryLocked(id, locked)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if pinned, ok := args["pinned"].(bool); ok {
+			entry, err = s.store.SetEntryPinned(id, pinned)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if author, ok := args["author"].(string); ok {
+			entry, err = s.store.SetEntryAuthor(id, author)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if reference, ok := args["reference"].(string); ok {
+			entry, err = s.store.SetEntryReference(id, reference)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if category, ok := args["category"].(string); ok {
+			entry, err = s.store.SetEntryCategory(id, category)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if location, ok := args["location"].(string); ok {
+			entry, err = s.store.SetEntryLocation(id, location)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if source, ok := args["source"].(string); ok {
+			entry, err = s.store.SetEntrySource(id, source)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		note, hasNote := args["adjustment_note"].(string)
+		needsAdjustment, hasFlag := args["needs_adjustment"].(bool)
+		if hasFlag || hasNote {
+			entry, err = s.store.SetEntryAdjustment(id, needsAdjustment || !hasFlag, note)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		result, _ := json.MarshalIndent(entry, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
 }
 
 func (s *ClockworkServer) registerDeleteEntry() {
@@ -428,9 +1211,13 @@ func (s *ClockworkServer) registerListEntries() {
 	tool := mcp.NewTool("list_entries",
 		mcp.WithDescription("List entries with optional filtering"),
 		mcp.WithString("project_id", mcp.Description("Project ID (optional, omit for all projects)")),
-		mcp.WithString("start_date", mcp.Description("RFC3339 format (optional, e.g., '2026-01-01T00:00:00Z')")),
-		mcp.WithString("end_date", mcp.Description("RFC3339 format (optional)")),
+		mcp.WithString("start_date", mcp.Description("Range start (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("end_date", mcp.Description("Range end (optional, dates without a time include the whole day): "+utils.DateFormatsHelp)),
 		mcp.WithString("invoiced", mcp.Description("Filter: 'true', 'false', or 'all' (default: 'all')")),
+		mcp.WithString("location", mcp.Description("Only entries with this location, case-insensitive (optional)")),
+		mcp.WithString("source", mcp.Description("Only entries logged on this machine (hostname), case-insensitive (optional)")),
+		mcp.WithString("tag", mcp.Description("Only entries carrying this tag, case-insensitive (optional)")),
+		mcp.WithBoolean("pinned", mcp.Description("Only pinned entries (optional)")),
 	)
 
 	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -440,13 +1227,17 @@ func (s *ClockworkServer) registerListEntries() {
 		startDateStr, _ := args["start_date"].(string)
 		endDateStr, _ := args["end_date"].(string)
 		invoicedStr, _ := args["invoiced"].(string)
+		location, _ := args["location"].(string)
+		source, _ := args["source"].(string)
+		tag, _ := args["tag"].(string)
+		pinnedOnly, _ := args["pinned"].(bool)
 
 		// Parse start date
 		var startDate *time.Time
 		if startDateStr != "" {
-			parsed, err := time.Parse(time.RFC3339, startDateStr)
+			parsed, err := utils.ParseFlexibleDate(startDateStr)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date format (use RFC3339): %v", err)), nil
+				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
 			}
 			startDate = &parsed
 		}
@@ -454,9 +1245,9 @@ func (s *ClockworkServer) registerListEntries() {
 		// Parse end date
 		var endDate *time.Time
 		if endDateStr != "" {
-			parsed, err := time.Parse(time.RFC3339, endDateStr)
+			parsed, err := utils.ParseFlexibleEndDate(endDateStr)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date format (use RFC3339): %v", err)), nil
+				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date: %v", err)), nil
 			}
 			endDate = &parsed
 		}
@@ -476,23 +1267,30 @@ func (s *ClockworkServer) registerListEntries() {
 			invoicedFilter = &val
 		}
 
-		entries, err := s.store.ListEntriesFiltered(projectID, startDate, endDate, invoicedFilter)
+		entries, err := s.reader().ListEntriesFiltered(projectID, startDate, endDate, invoicedFilter)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
+		entries = db.FilterByLocation(entries, location)
+		entries = db.FilterBySource(entries, source)
+		entries = db.FilterByTag(entries, tag)
+		if pinnedOnly {
+			entries = db.FilterPinned(entries)
+		}
 
 		result, _ := json.MarshalIndent(entries, "", "  ")
 		return mcp.NewToolResultText(string(result)), nil
 	})
 }
 
-func (s *ClockworkServer) registerGetStatistics() {
-	tool := mcp.NewTool("get_statistics",
-		mcp.WithDescription("Get aggregated time tracking statistics"),
-		mcp.WithString("project_id", mcp.Description("Filter by project (optional)")),
-		mcp.WithString("start_date", mcp.Description("RFC3339 format (optional, e.g., '2026-01-01T00:00:00Z')")),
-		mcp.WithString("end_date", mcp.Description("RFC3339 format (optional)")),
+func (s *ClockworkServer) registerBulkDeleteEntries() {
+	tool := mcp.NewTool("bulk_delete_entries",
+		mcp.WithDescription("Move all entries matching the filters to the trash (locked entries are skipped)"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional, omit for all projects)")),
+		mcp.WithString("start_date", mcp.Description("Range start (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("end_date", mcp.Description("Range end (optional, dates without a time include the whole day): "+utils.DateFormatsHelp)),
 		mcp.WithString("invoiced", mcp.Description("Filter: 'true', 'false', or 'all' (default: 'all')")),
+		mcp.WithBoolean("confirm", mcp.Required(), mcp.Description("Must be true to delete; otherwise only the number of matching entries is reported")),
 	)
 
 	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
@@ -502,13 +1300,14 @@ func (s *ClockworkServer) registerGetStatistics() {
 		startDateStr, _ := args["start_date"].(string)
 		endDateStr, _ := args["end_date"].(string)
 		invoicedStr, _ := args["invoiced"].(string)
+		confirm, _ := args["confirm"].(bool)
 
 		// Parse start date
 		var startDate *time.Time
 		if startDateStr != "" {
-			parsed, err := time.Parse(time.RFC3339, startDateStr)
+			parsed, err := utils.ParseFlexibleDate(startDateStr)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date format (use RFC3339): %v", err)), nil
+				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
 			}
 			startDate = &parsed
 		}
@@ -516,9 +1315,9 @@ func (s *ClockworkServer) registerGetStatistics() {
 		// Parse end date
 		var endDate *time.Time
 		if endDateStr != "" {
-			parsed, err := time.Parse(time.RFC3339, endDateStr)
+			parsed, err := utils.ParseFlexibleEndDate(endDateStr)
 			if err != nil {
-				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date format (use RFC3339): %v", err)), nil
+				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date: %v", err)), nil
 			}
 			endDate = &parsed
 		}
@@ -538,13 +1337,1517 @@ func (s *ClockworkServer) registerGetStatistics() {
 			invoicedFilter = &val
 		}
 
-		// Get statistics
-		stats, err := s.store.GetStatistics(projectID, startDate, endDate, invoicedFilter)
+		if !confirm {
+			entries, err := s.store.ListEntriesFiltered(projectID, startDate, endDate, invoicedFilter)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			matching := 0
+			for _, entry := range entries {
+				if !entry.Locked {
+					matching++
+				}
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("%d entries match; pass confirm=true to move them to the trash", matching)), nil
+		}
+
+		deleted, err := s.store.DeleteEntriesFiltered(projectID, startDate, endDate, invoicedFilter)
 		if err != nil {
 			return mcp.NewToolResultError(err.Error()), nil
 		}
 
-		result, _ := json.MarshalIndent(stats, "", "  ")
+		result, _ := json.MarshalIndent(map[string]int{"deleted": deleted}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerClearProjectEntries() {
+	tool := mcp.NewTool("clear_project_entries",
+		mcp.WithDescription("Move all of a project's entries to the trash to restart tracking, keeping the project and its settings (locked entries are skipped)"),
+		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("backup_path", mcp.Description("Write the project's entries to this CSV file before clearing (optional); nothing is cleared if the backup fails")),
+		mcp.WithBoolean("confirm", mcp.Required(), mcp.Description("Must be true to clear; otherwise only the number of entries is reported")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := getRequiredString(request, "project_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		backupPath, _ := args["backup_path"].(string)
+		confirm, _ := args["confirm"].(bool)
+
+		project, err := s.store.GetProject(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if !confirm {
+			entries, err := s.store.ListEntries(projectID)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			matching := 0
+			for _, entry := range entries {
+				if !entry.Locked {
+					matching++
+				}
+			}
+			return mcp.NewToolResultError(fmt.Sprintf("%s has %d unlocked entries; pass confirm=true to move them to the trash", project.Name, matching)), nil
+		}
+
+		if backupPath != "" {
+			file, err := os.Create(backupPath)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to write backup: %v", err)), nil
+			}
+			if err := s.store.StreamExport(file, db.ExportCSV, db.EntryFilter{ProjectID: projectID}); err != nil {
+				file.Close()
+				os.Remove(backupPath)
+				return mcp.NewToolResultError(fmt.Sprintf("failed to write backup: %v", err)), nil
+			}
+			if err := file.Close(); err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("failed to write backup: %v", err)), nil
+			}
+		}
+
+		cleared, err := s.store.ClearProjectEntries(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"cleared":     cleared,
+			"backup_path": backupPath,
+		}, "", "  ")
 		return mcp.NewToolResultText(string(result)), nil
 	})
 }
+
+func (s *ClockworkServer) registerBulkTag() {
+	tool := mcp.NewTool("bulk_tag",
+		mcp.WithDescription("Add and remove tags on all entries matching the filters in one step (locked entries are skipped)"),
+		mcp.WithString("add", mcp.Description("Comma-separated tags to add (optional; existing tags are not duplicated)")),
+		mcp.WithString("remove", mcp.Description("Comma-separated tags to remove (optional; absent tags are ignored, removes win over adds)")),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional, omit for all projects)")),
+		mcp.WithString("start_date", mcp.Description("Range start (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("end_date", mcp.Description("Range end (optional, dates without a time include the whole day): "+utils.DateFormatsHelp)),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		addStr, _ := args["add"].(string)
+		removeStr, _ := args["remove"].(string)
+		projectID, _ := args["project_id"].(string)
+		startDateStr, _ := args["start_date"].(string)
+		endDateStr, _ := args["end_date"].(string)
+
+		// Parse start date
+		var startDate *time.Time
+		if startDateStr != "" {
+			parsed, err := utils.ParseFlexibleDate(startDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
+			}
+			startDate = &parsed
+		}
+
+		// Parse end date
+		var endDate *time.Time
+		if endDateStr != "" {
+			parsed, err := utils.ParseFlexibleEndDate(endDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date: %v", err)), nil
+			}
+			endDate = &parsed
+		}
+
+		// Validate date range
+		if startDate != nil && endDate != nil && startDate.After(*endDate) {
+			return mcp.NewToolResultError("start_date must be before end_date"), nil
+		}
+
+		updated, err := s.store.BulkTag(projectID, startDate, endDate, models.ParseTags(addStr), models.ParseTags(removeStr))
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(map[string]int{"updated": updated}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerMarkInvoiced() {
+	tool := mcp.NewTool("mark_invoiced",
+		mcp.WithDescription("Mark all entries matching the filters as invoiced (or not) in one atomic step (locked entries are skipped)"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional, omit for all projects)")),
+		mcp.WithString("start_date", mcp.Description("Range start (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("end_date", mcp.Description("Range end (optional, dates without a time include the whole day): "+utils.DateFormatsHelp)),
+		mcp.WithBoolean("invoiced", mcp.Description("Invoiced state to set (default: true)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		projectID, _ := args["project_id"].(string)
+		startDateStr, _ := args["start_date"].(string)
+		endDateStr, _ := args["end_date"].(string)
+		invoiced := true
+		if value, ok := args["invoiced"].(bool); ok {
+			invoiced = value
+		}
+
+		// Parse start date
+		var startDate *time.Time
+		if startDateStr != "" {
+			parsed, err := utils.ParseFlexibleDate(startDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
+			}
+			startDate = &parsed
+		}
+
+		// Parse end date
+		var endDate *time.Time
+		if endDateStr != "" {
+			parsed, err := utils.ParseFlexibleEndDate(endDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date: %v", err)), nil
+			}
+			endDate = &parsed
+		}
+
+		// Validate date range
+		if startDate != nil && endDate != nil && startDate.After(*endDate) {
+			return mcp.NewToolResultError("start_date must be before end_date"), nil
+		}
+
+		updated, err := s.store.MarkInvoiced(projectID, startDate, endDate, invoiced)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(map[string]int{"updated": updated}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerRepairBaseline() {
+	tool := mcp.NewTool("repair_baseline",
+		mcp.WithDescription("Reset a project's commit baseline to the current HEAD (use when the repository history changed)"),
+		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := getRequiredString(request, "project_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		project, err := s.store.GetProject(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("project not found: %v", err)), nil
+		}
+
+		baseline, err := s.store.GetLastCommitEntry(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if baseline == nil {
+			return mcp.NewToolResultError("project has no baseline commit to repair"), nil
+		}
+
+		headHash, err := git.GetLatestCommitHash(project.GitRepoPath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		entry, err := s.store.UpdateEntry(baseline.ID, nil, nil, &headHash, nil, nil)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"entry":         entry,
+			"previous_hash": baseline.CommitHash,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerGetStatistics() {
+	tool := mcp.NewTool("get_statistics",
+		mcp.WithDescription("Get aggregated time tracking statistics"),
+		mcp.WithString("project_id", mcp.Description("Filter by project (optional)")),
+		mcp.WithString("start_date", mcp.Description("Range start (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("end_date", mcp.Description("Range end (optional, dates without a time include the whole day): "+utils.DateFormatsHelp)),
+		mcp.WithString("invoiced", mcp.Description("Filter: 'true', 'false', or 'all' (default: 'all')")),
+		mcp.WithString("group_by", mcp.Description("Also return time per period as 'periods': 'day', 'week' (ISO weeks, Monday start), or 'month'; periods without entries are included with zero minutes (optional)")),
+		mcp.WithBoolean("by_weekday", mcp.Description("Also return time per day of the week, Monday first, as 'weekdays' (optional, default: false)")),
+		mcp.WithString("timezone", mcp.Description("IANA time zone the periods and weekdays are cut in, e.g. 'Europe/Berlin' (optional, default: local time)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		projectID, _ := args["project_id"].(string)
+		startDateStr, _ := args["start_date"].(string)
+		endDateStr, _ := args["end_date"].(string)
+		invoicedStr, _ := args["invoiced"].(string)
+		groupBy, _ := args["group_by"].(string)
+		byWeekday, _ := args["by_weekday"].(bool)
+		timezone, _ := args["timezone"].(string)
+
+		if groupBy != "" && !db.ValidGroupBy(groupBy) {
+			return mcp.NewToolResultError("group_by must be 'day', 'week', or 'month'"), nil
+		}
+		loc := time.Local
+		if timezone != "" {
+			var err error
+			loc, err = time.LoadLocation(timezone)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid timezone: %v", err)), nil
+			}
+		}
+
+		// Parse start date
+		var startDate *time.Time
+		if startDateStr != "" {
+			parsed, err := utils.ParseFlexibleDate(startDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
+			}
+			startDate = &parsed
+		}
+
+		// Parse end date
+		var endDate *time.Time
+		if endDateStr != "" {
+			parsed, err := utils.ParseFlexibleEndDate(endDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date: %v", err)), nil
+			}
+			endDate = &parsed
+		}
+
+		// Validate date range
+		if startDate != nil && endDate != nil && startDate.After(*endDate) {
+			return mcp.NewToolResultError("start_date must be before end_date"), nil
+		}
+
+		// Parse invoiced filter
+		var invoicedFilter *bool
+		if invoicedStr == "true" {
+			val := true
+			invoicedFilter = &val
+		} else if invoicedStr == "false" {
+			val := false
+			invoicedFilter = &val
+		}
+
+		// Get statistics
+		stats, err := s.reader().GetStatistics(projectID, startDate, endDate, invoicedFilter)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if groupBy != "" {
+			stats.Periods, err = s.reader().GetPeriodTotals(projectID, startDate, endDate, invoicedFilter, groupBy, loc)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		if byWeekday {
+			breakdown, err := s.reader().WeekdayBreakdown(projectID, startDate, endDate, invoicedFilter, loc)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			stats.Weekdays = db.WeekdayTotals(breakdown)
+		}
+
+		result, _ := json.MarshalIndent(stats, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerAnnualSummary() {
+	tool := mcp.NewTool("annual_summary",
+		mcp.WithDescription("Get a year-end report with monthly and per-project breakdowns, invoiced vs outstanding time, and billed amounts for projects with an hourly rate"),
+		mcp.WithNumber("year", mcp.Description("Calendar year (optional, default: current year)")),
+		mcp.WithString("project_id", mcp.Description("Filter by project (optional)")),
+		mcp.WithString("format", mcp.Description("Output format: 'json' or 'markdown' (default: 'json')")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		projectID, _ := args["project_id"].(string)
+		format, _ := args["format"].(string)
+
+		year := time.Now().Year()
+		if y, ok := args["year"].(float64); ok {
+			year = int(y)
+		}
+
+		if format != "" && format != "json" && format != "markdown" {
+			return mcp.NewToolResultError("format must be 'json' or 'markdown'"), nil
+		}
+
+		summary, err := s.reader().GetAnnualSummary(year, projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if format == "markdown" {
+			projects, err := s.reader().ListProjects(true)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			projectNames := make(map[string]string, len(projects))
+			for _, project := range projects {
+				projectNames[project.ID] = project.Name
+			}
+			return mcp.NewToolResultText(export.AnnualSummaryMarkdown(summary, projectNames)), nil
+		}
+
+		result, _ := json.MarshalIndent(summary, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+// invoiceEstimate is the token-light answer to "how much is left to invoice"
+type invoiceEstimate struct {
+	ProjectID  string  `json:"project_id,omitempty"`
+	EntryCount int     `json:"entry_count"`
+	Minutes    int64   `json:"uninvoiced_minutes"`
+	Hours      float64 `json:"uninvoiced_hours"`
+	Rate       float64 `json:"rate"`
+	Currency   string  `json:"currency,omitempty"`
+	Amount     float64 `json:"amount"` // Hours times rate, rounded to cents
+
+	Invoice *db.Invoice `json:"invoice,omitempty"` // Set when the estimate was committed as an invoice
+}
+
+// estimateInvoice totals the uninvoiced time in the range and prices it at rate
+func (s *ClockworkServer) estimateInvoice(projectID string, startDate, endDate *time.Time, rate float64, currency string) (*invoiceEstimate, error) {
+	if rate < 0 {
+		return nil, fmt.Errorf("rate must not be negative")
+	}
+	if projectID != "" {
+		if _, err := s.store.GetProject(projectID); err != nil {
+			return nil, err
+		}
+	}
+
+	uninvoiced := false
+	statistics, err := s.store.GetStatistics(projectID, startDate, endDate, &uninvoiced)
+	if err != nil {
+		return nil, err
+	}
+
+	return &invoiceEstimate{
+		ProjectID:  projectID,
+		EntryCount: statistics.EntryCount,
+		Minutes:    statistics.TotalMinutes,
+		Hours:      statistics.TotalHours,
+		Rate:       rate,
+		Currency:   strings.ToUpper(strings.TrimSpace(currency)),
+		Amount:     math.Round(float64(statistics.TotalMinutes)/60*rate*100) / 100,
+	}, nil
+}
+
+// issueInvoice commits an estimate as the project's next numbered invoice, repricing it
+// from the entries actually invoiced
+func (s *ClockworkServer) issueInvoice(estimate *invoiceEstimate, startDate, endDate *time.Time) (*invoiceEstimate, error) {
+	invoice, err := s.store.IssueInvoice(estimate.ProjectID, startDate, endDate, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	estimate.Invoice = invoice
+	estimate.EntryCount = len(invoice.EntryIDs)
+	estimate.Minutes = invoice.Minutes
+	estimate.Hours = float64(invoice.Minutes) / 60
+	estimate.Amount = math.Round(float64(invoice.Minutes)/60*estimate.Rate*100) / 100
+	return estimate, nil
+}
+
+func (s *ClockworkServer) registerEstimateInvoice() {
+	tool := mcp.NewTool("estimate_invoice",
+		mcp.WithDescription("Estimate what is left to invoice: uninvoiced hours and amount at an hourly rate, without listing entries"),
+		mcp.WithNumber("rate", mcp.Required(), mcp.Description("Hourly rate")),
+		mcp.WithString("project_id", mcp.Description("Filter by project (optional, default: all projects)")),
+		mcp.WithString("start_date", mcp.Description("Range start (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("end_date", mcp.Description("Range end (optional, dates without a time include the whole day): "+utils.DateFormatsHelp)),
+		mcp.WithString("currency", mcp.Description("Currency code echoed in the result (optional)")),
+		mcp.WithBoolean("commit", mcp.Description("Issue the invoice: assign the project's next invoice number and mark the entries invoiced under it (requires project_id, default: false)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		rate, ok := args["rate"].(float64)
+		if !ok {
+			return mcp.NewToolResultError("missing required argument: rate"), nil
+		}
+		projectID, _ := args["project_id"].(string)
+		startDateStr, _ := args["start_date"].(string)
+		endDateStr, _ := args["end_date"].(string)
+		currency, _ := args["currency"].(string)
+
+		// Parse start date
+		var startDate *time.Time
+		if startDateStr != "" {
+			parsed, err := utils.ParseFlexibleDate(startDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
+			}
+			startDate = &parsed
+		}
+
+		// Parse end date
+		var endDate *time.Time
+		if endDateStr != "" {
+			parsed, err := utils.ParseFlexibleEndDate(endDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date: %v", err)), nil
+			}
+			endDate = &parsed
+		}
+
+		// Validate date range
+		if startDate != nil && endDate != nil && startDate.After(*endDate) {
+			return mcp.NewToolResultError("start_date must be before end_date"), nil
+		}
+
+		estimate, err := s.estimateInvoice(projectID, startDate, endDate, rate, currency)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if commit, _ := args["commit"].(bool); commit {
+			if projectID == "" {
+				return mcp.NewToolResultError("commit requires project_id; invoices are numbered per project"), nil
+			}
+			estimate, err = s.issueInvoice(estimate, startDate, endDate)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		result, _ := json.MarshalIndent(estimate, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+// ticketReport sums the filtered entries' time per ticket ID using the ticket_pattern setting
+func (s *ClockworkServer) ticketReport(projectID string, startDate, endDate *time.Time, invoicedFilter *bool) ([]stats.TicketTotal, error) {
+	value, err := s.reader().GetSetting(db.SettingTicketPattern)
+	if err != nil {
+		return nil, err
+	}
+	pattern, err := stats.ParseTicketPattern(value)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.reader().ListEntriesFiltered(projectID, startDate, endDate, invoicedFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	return stats.ByTicket(entries, pattern), nil
+}
+
+func (s *ClockworkServer) registerReopenEntry() {
+	tool := mcp.NewTool("reopen_entry",
+		mcp.WithDescription("Reopen an invoiced or locked entry for correction: marks it uninvoiced, clears its invoice number, and unlocks it. The reason is recorded in the audit log."),
+		mcp.WithString("id", mcp.Required(), mcp.Description("Entry ID")),
+		mcp.WithString("reason", mcp.Required(), mcp.Description("Why the entry is reopened, e.g. 'client disputed hours on ACME-0003'")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		id, err := getRequiredString(request, "id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		reason, err := getRequiredString(request, "reason")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		entry, err := s.store.ReopenEntry(id, reason)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(entry, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerListAdjustments() {
+	tool := mcp.NewTool("list_adjustments",
+		mcp.WithDescription("List entries flagged as needing an invoice adjustment, oldest first, for bookkeeping follow-up"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional, omit for all projects)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		projectID, _ := args["project_id"].(string)
+
+		entries, err := s.reader().FindAdjustmentEntries(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var totalMinutes int64
+		for _, entry := range entries {
+			totalMinutes += entry.Duration
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"entries":       entries,
+			"count":         len(entries),
+			"total_minutes": totalMinutes,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerDetectOverlaps() {
+	tool := mcp.NewTool("detect_overlaps",
+		mcp.WithDescription("Find pairs of entries by the same author whose time intervals (created_at plus duration) intersect, to catch double-logged time before invoicing"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional, omit to compare entries across all projects)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		projectID, _ := args["project_id"].(string)
+
+		overlaps, err := s.reader().DetectOverlaps(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		var overlapMinutes int64
+		for _, overlap := range overlaps {
+			overlapMinutes += overlap.OverlapMinutes
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"overlaps":        overlaps,
+			"count":           len(overlaps),
+			"overlap_minutes": overlapMinutes,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerByTicket() {
+	tool := mcp.NewTool("by_ticket",
+		mcp.WithDescription("Sum time per ticket ID found in entry messages and references (ticket_pattern setting). An entry naming several tickets counts in full towards each; entries without one are grouped under '(none)'"),
+		mcp.WithString("project_id", mcp.Description("Filter by project (optional)")),
+		mcp.WithString("start_date", mcp.Description("Range start (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("end_date", mcp.Description("Range end (optional, dates without a time include the whole day): "+utils.DateFormatsHelp)),
+		mcp.WithString("invoiced", mcp.Description("Filter: 'true', 'false', or 'all' (default: 'all')")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		projectID, _ := args["project_id"].(string)
+		startDateStr, _ := args["start_date"].(string)
+		endDateStr, _ := args["end_date"].(string)
+		invoicedStr, _ := args["invoiced"].(string)
+
+		// Parse start date
+		var startDate *time.Time
+		if startDateStr != "" {
+			parsed, err := utils.ParseFlexibleDate(startDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
+			}
+			startDate = &parsed
+		}
+
+		// Parse end date
+		var endDate *time.Time
+		if endDateStr != "" {
+			parsed, err := utils.ParseFlexibleEndDate(endDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date: %v", err)), nil
+			}
+			endDate = &parsed
+		}
+
+		// Validate date range
+		if startDate != nil && endDate != nil && startDate.After(*endDate) {
+			return mcp.NewToolResultError("start_date must be before end_date"), nil
+		}
+
+		// Parse invoiced filter
+		var invoicedFilter *bool
+		if invoicedStr == "true" {
+			val := true
+			invoicedFilter = &val
+		} else if invoicedStr == "false" {
+			val := false
+			invoicedFilter = &val
+		}
+
+		tickets, err := s.ticketReport(projectID, startDate, endDate, invoicedFilter)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"tickets": tickets,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+// timerStatus describes an active timer for tool output
+type timerStatus struct {
+	ProjectID      string    `json:"project_id"`
+	ProjectName    string    `json:"project_name"`
+	StartedAt      time.Time `json:"started_at"`
+	Message        string    `json:"message,omitempty"`
+	Paused         bool      `json:"paused"`
+	ElapsedMinutes int64     `json:"elapsed_minutes"`
+}
+
+// newTimerStatus builds the tool output for a timer at the given time
+func (s *ClockworkServer) newTimerStatus(timer *models.Timer, now time.Time) timerStatus {
+	status := timerStatus{
+		ProjectID:      timer.ProjectID,
+		StartedAt:      timer.StartedAt,
+		Message:        timer.Message,
+		Paused:         timer.Paused(),
+		ElapsedMinutes: int64(timer.Elapsed(now) / time.Minute),
+	}
+	if project, err := s.store.GetProject(timer.ProjectID); err == nil {
+		status.ProjectName = project.Name
+	}
+	return status
+}
+
+func (s *ClockworkServer) registerStartTimer() {
+	tool := mcp.NewTool("start_timer",
+		mcp.WithDescription("Start a running timer for a project (one active timer per project)"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional when a default project is configured)")),
+		mcp.WithString("message", mcp.Description("What the timer is for; becomes the entry message unless stop_timer is given one (optional)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		projectID, _ := args["project_id"].(string)
+		projectID, err := s.store.ResolveProjectID(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		now := time.Now()
+		timer, err := s.store.StartTimer(projectID, now)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if message, _ := args["message"].(string); message != "" {
+			timer, err = s.store.SetTimerMessage(projectID, message)
+			if err != nil {
+				// Don't leave a timer running that the caller was told failed to start
+				s.store.DiscardTimer(projectID)
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+		}
+
+		result, _ := json.MarshalIndent(s.newTimerStatus(timer, now), "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerPauseTimer() {
+	tool := mcp.NewTool("pause_timer",
+		mcp.WithDescription("Pause a project's running timer"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional when a default project is configured)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		projectID, _ := args["project_id"].(string)
+		projectID, err := s.store.ResolveProjectID(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		now := time.Now()
+		timer, err := s.store.PauseTimer(projectID, now)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(s.newTimerStatus(timer, now), "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerResumeTimer() {
+	tool := mcp.NewTool("resume_timer",
+		mcp.WithDescription("Resume a project's paused timer"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional when a default project is configured)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		projectID, _ := args["project_id"].(string)
+		projectID, err := s.store.ResolveProjectID(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		now := time.Now()
+		timer, err := s.store.ResumeTimer(projectID, now)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(s.newTimerStatus(timer, now), "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerStopTimer() {
+	tool := mcp.NewTool("stop_timer",
+		mcp.WithDescription("Stop a project's timer and log the tracked time as an entry (rounded per the timer_rounding setting)"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional when a default project is configured)")),
+		mcp.WithString("message", mcp.Description("Entry message (optional, defaults to the start_timer message, then the manual message template)")),
+		mcp.WithBoolean("invoiced", mcp.Description("Whether the entry has been invoiced (default: false)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		projectID, _ := args["project_id"].(string)
+		projectID, err := s.store.ResolveProjectID(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		message, _ := args["message"].(string)
+		invoiced, _ := args["invoiced"].(bool)
+
+		if message == "" {
+			project, err := s.store.GetProject(projectID)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			timer, err := s.store.GetTimer(projectID)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if timer == nil {
+				return mcp.NewToolResultError("no timer running for this project"), nil
+			}
+			template, err := s.store.GetSetting(db.SettingManualMessageTemplate)
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			message = timer.Message
+			if message == "" {
+				message = utils.RenderMessageTemplate(template, project.Name, timer.StartedAt)
+			}
+		}
+
+		entry, err := s.store.StopTimer(projectID, message, invoiced, time.Now())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(entry, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerDiscardTimer() {
+	tool := mcp.NewTool("discard_timer",
+		mcp.WithDescription("Discard a project's timer without logging an entry"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional when a default project is configured)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		projectID, _ := args["project_id"].(string)
+		projectID, err := s.store.ResolveProjectID(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := s.store.DiscardTimer(projectID); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Timer for project %s discarded", projectID)), nil
+	})
+}
+
+func (s *ClockworkServer) registerTimerStatus() {
+	tool := mcp.NewTool("timer_status",
+		mcp.WithDescription("List active timers with their elapsed time, including timers recovered after a restart"),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timers, err := s.store.ListTimers()
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		now := time.Now()
+		statuses := make([]timerStatus, 0, len(timers))
+		for _, timer := range timers {
+			statuses = append(statuses, s.newTimerStatus(timer, now))
+		}
+
+		result, _ := json.MarshalIndent(statuses, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerExportEntriesCSV() {
+	tool := mcp.NewTool("export_entries_csv",
+		mcp.WithDescription("Export entries as CSV text for spreadsheets (one row per entry beneath a header row, newest first)"),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional, omit for all projects)")),
+		mcp.WithString("start_date", mcp.Description("Range start (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("end_date", mcp.Description("Range end (optional, dates without a time include the whole day): "+utils.DateFormatsHelp)),
+		mcp.WithString("invoiced", mcp.Description("Filter: 'true', 'false', or 'all' (default: 'all')")),
+		mcp.WithString("location", mcp.Description("Only entries with this location, case-insensitive (optional)")),
+		mcp.WithString("source", mcp.Description("Only entries logged on this machine (hostname), case-insensitive (optional)")),
+		mcp.WithString("tag", mcp.Description("Only entries carrying this tag, case-insensitive (optional)")),
+		mcp.WithString("sort_by", mcp.Description("Row order: "+strings.Join(db.SortKeys, ", ")+" (default: 'date')")),
+		mcp.WithString("format", mcp.Description("Column layout: 'csv', 'harvest' (Harvest time import), or 'clockify' (Clockify import) (default: 'csv')")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		projectID, _ := args["project_id"].(string)
+		startDateStr, _ := args["start_date"].(string)
+		endDateStr, _ := args["end_date"].(string)
+		invoicedStr, _ := args["invoiced"].(string)
+		location, _ := args["location"].(string)
+		source, _ := args["source"].(string)
+		tag, _ := args["tag"].(string)
+		sortBy, _ := args["sort_by"].(string)
+		format, _ := args["format"].(string)
+
+		// Parse start date
+		var startDate *time.Time
+		if startDateStr != "" {
+			parsed, err := utils.ParseFlexibleDate(startDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
+			}
+			startDate = &parsed
+		}
+
+		// Parse end date
+		var endDate *time.Time
+		if endDateStr != "" {
+			parsed, err := utils.ParseFlexibleEndDate(endDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date: %v", err)), nil
+			}
+			endDate = &parsed
+		}
+
+		// Validate date range
+		if startDate != nil && endDate != nil && startDate.After(*endDate) {
+			return mcp.NewToolResultError("start_date must be before end_date"), nil
+		}
+
+		// Parse invoiced filter
+		var invoicedFilter *bool
+		if invoicedStr == "true" {
+			val := true
+			invoicedFilter = &val
+		} else if invoicedStr == "false" {
+			val := false
+			invoicedFilter = &val
+		}
+
+		if !db.ValidSortKey(sortBy) {
+			return mcp.NewToolResultError("sort_by must be one of: " + strings.Join(db.SortKeys, ", ")), nil
+		}
+		if format == "" {
+			format = db.ExportCSV
+		}
+		if format == db.ExportJSON || !db.ValidExportFormat(format) {
+			return mcp.NewToolResultError("format must be 'csv', 'harvest', or 'clockify'"), nil
+		}
+
+		var buf bytes.Buffer
+		err := s.reader().StreamExport(&buf, format, db.EntryFilter{
+			ProjectID:      projectID,
+			StartDate:      startDate,
+			EndDate:        endDate,
+			InvoicedFilter: invoicedFilter,
+			Location:       location,
+			Source:         source,
+			Tag:            tag,
+			SortBy:         sortBy,
+		})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(buf.String()), nil
+	})
+}
+
+func (s *ClockworkServer) registerExportEntriesByTag() {
+	tool := mcp.NewTool("export_entries_by_tag",
+		mcp.WithDescription("Export entries into one CSV file per tag plus an untagged.csv for entries matching none of the tags"),
+		mcp.WithString("output_dir", mcp.Required(), mcp.Description("Directory to write the CSV files into (created if missing)")),
+		mcp.WithString("tags", mcp.Description("Comma-separated tags to export (optional, default: all tags in use)")),
+		mcp.WithString("project_id", mcp.Description("Project ID (optional, omit for all projects)")),
+		mcp.WithString("start_date", mcp.Description("Range start (optional): "+utils.DateFormatsHelp)),
+		mcp.WithString("end_date", mcp.Description("Range end (optional, dates without a time include the whole day): "+utils.DateFormatsHelp)),
+		mcp.WithString("invoiced", mcp.Description("Filter: 'true', 'false', or 'all' (default: 'all')")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		outputDir, err := getRequiredString(request, "output_dir")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		tagsStr, _ := args["tags"].(string)
+		projectID, _ := args["project_id"].(string)
+		startDateStr, _ := args["start_date"].(string)
+		endDateStr, _ := args["end_date"].(string)
+		invoicedStr, _ := args["invoiced"].(string)
+
+		// Parse start date
+		var startDate *time.Time
+		if startDateStr != "" {
+			parsed, err := utils.ParseFlexibleDate(startDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid start_date: %v", err)), nil
+			}
+			startDate = &parsed
+		}
+
+		// Parse end date
+		var endDate *time.Time
+		if endDateStr != "" {
+			parsed, err := utils.ParseFlexibleEndDate(endDateStr)
+			if err != nil {
+				return mcp.NewToolResultError(fmt.Sprintf("invalid end_date: %v", err)), nil
+			}
+			endDate = &parsed
+		}
+
+		// Parse invoiced filter
+		var invoicedFilter *bool
+		if invoicedStr == "true" {
+			val := true
+			invoicedFilter = &val
+		} else if invoicedStr == "false" {
+			val := false
+			invoicedFilter = &val
+		}
+
+		entries, err := s.reader().ListEntriesFiltered(projectID, startDate, endDate, invoicedFilter)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		projects, err := s.reader().ListProjects(true)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		projectNames := make(map[string]string, len(projects))
+		for _, project := range projects {
+			projectNames[project.ID] = project.Name
+		}
+
+		files, err := export.ExportByTag(outputDir, entries, models.ParseTags(tagsStr), projectNames)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"files":            files,
+			"entries_exported": len(entries),
+		}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+// exportNewEntries writes the project's entries created or modified since its last export to path
+// The file is removed and the export marker left in place when the export fails
+func (s *ClockworkServer) exportNewEntries(projectID, format, path string) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	count, err := s.store.ExportNew(file, format, projectID, db.SortByDate)
+	if err != nil {
+		file.Close()
+		os.Remove(path)
+		return 0, err
+	}
+
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write export: %w", err)
+	}
+
+	return count, nil
+}
+
+func (s *ClockworkServer) registerExportNewEntries() {
+	tool := mcp.NewTool("export_new_entries",
+		mcp.WithDescription("Export a project's entries created or modified since its last export_new_entries call, then move the project's export marker forward (avoids billing entries twice)"),
+		mcp.WithString("project_id", mcp.Required(), mcp.Description("Project ID")),
+		mcp.WithString("output_path", mcp.Required(), mcp.Description("File to write the export to")),
+		mcp.WithString("format", mcp.Description("Output format: 'csv', 'json', 'harvest' (Harvest time import CSV), or 'clockify' (Clockify import CSV) (default: 'csv')")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		projectID, err := getRequiredString(request, "project_id")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		outputPath, err := getRequiredString(request, "output_path")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		format, _ := args["format"].(string)
+		if format == "" {
+			format = db.ExportCSV
+		}
+		if !db.ValidExportFormat(format) {
+			return mcp.NewToolResultError("format must be one of: " + strings.Join(db.ExportFormats, ", ")), nil
+		}
+
+		since, err := s.store.GetLastExport(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		count, err := s.exportNewEntries(projectID, format, outputPath)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"path":             outputPath,
+			"entries_exported": count,
+			"since":            since,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerExportData() {
+	tool := mcp.NewTool("export_data",
+		mcp.WithDescription("Back up every project and entry to a versioned JSON file for restoring with import_data, e.g. on another machine"),
+		mcp.WithString("output_path", mcp.Required(), mcp.Description("File to write the backup to")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		outputPath, err := getRequiredString(request, "output_path")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		file, err := os.Create(outputPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to write backup: %v", err)), nil
+		}
+		if err := s.reader().ExportJSON(file); err != nil {
+			file.Close()
+			os.Remove(outputPath)
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if err := file.Close(); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to write backup: %v", err)), nil
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"path": outputPath,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerImportData() {
+	tool := mcp.NewTool("import_data",
+		mcp.WithDescription("Restore projects and entries from an export_data backup without overwriting existing records. Entries whose project is missing or whose commit hash is corrupted are rejected and reported."),
+		mcp.WithString("input_path", mcp.Required(), mcp.Description("Backup file to import")),
+		mcp.WithBoolean("preserve_ids", mcp.Description("Keep the backup's IDs and skip records that already exist (default: true); false imports everything under new IDs")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		inputPath, err := getRequiredString(request, "input_path")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		preserveIDs := true
+		if value, ok := args["preserve_ids"].(bool); ok {
+			preserveIDs = value
+		}
+
+		file, err := os.Open(inputPath)
+		if err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("failed to open backup: %v", err)), nil
+		}
+		defer file.Close()
+
+		report, err := s.store.ImportJSON(file, db.ImportOptions{PreserveIDs: preserveIDs})
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(report, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerGetSettings() {
+	tool := mcp.NewTool("get_settings",
+		mcp.WithDescription("List all configured settings"),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		settings, err := s.store.ListSettings()
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(settings, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerSetAuthorRate() {
+	tool := mcp.NewTool("set_author_rate",
+		mcp.WithDescription("Set the hourly rate billed for one author's entries in a shared database. It overrides the project rate in statistics amounts, in the project's currency; entries without an author or by authors without a rate use the project rate."),
+		mcp.WithString("author", mcp.Required(), mcp.Description("Author name as recorded on entries (matched case-insensitively)")),
+		mcp.WithNumber("hourly_rate", mcp.Required(), mcp.Description("Hourly rate (0 removes the author's rate)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		author, err := getRequiredString(request, "author")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		args, _ := request.Params.Arguments.(map[string]interface{})
+		rate, ok := args["hourly_rate"].(float64)
+		if !ok {
+			return mcp.NewToolResultError("hourly_rate is required"), nil
+		}
+
+		if err := s.store.SetAuthorRate(author, rate); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		rates, err := s.store.GetAuthorRates()
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"author_rates": rates,
+		}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerSetSetting() {
+	tool := mcp.NewTool("set_setting",
+		mcp.WithDescription(`Set a configuration value (empty value resets it to the default)
+
+Available settings:
+- manual_message_template: message for manual entries without one, supports {project} and {date} (default: "Manual entry")
+- timer_rounding: how timer durations are rounded to whole minutes, 'up' or 'nearest' (default: "nearest")
+- default_project: project ID used when create_entry omits project_id (default: none)
+- commit_exclude_patterns: comma-separated commit subject prefixes left out of messages, or 'none' (default: "fixup!,squash!")
+- exclude_from_duration: 'true' to also leave excluded and trivial commits out of duration estimates (default: "false")
+- min_commit_lines: leave commits changing fewer lines (added plus removed) out of git entry messages, e.g. '3'; '0' disables (default: off)
+- default_author_from_repo: 'false' to stop defaulting manual entry authors to the repo's git user.name (default: "true")
+- currency_rates: conversion table for totalling amounts across currencies, e.g. 'EUR=1,USD=1.08' (default: none, amounts stay per currency)
+- focus_mapping: tag to category mapping for the focus split in stats, e.g. 'dev=focus,meeting=overhead' (default: dev/development/coding/review=focus, meeting/admin/email=overhead)
+- ticket_pattern: regular expression finding ticket IDs in entry messages and references for by_ticket (default: "[A-Z][A-Z0-9]+-\d+")
+- include_commit_bodies: 'true' to include commit bodies beneath each subject in git entries (default: "false")
+- group_conventional_commits: 'true' to group git entry messages by Conventional Commit type (Features, Fixes, ..., Other) (default: "false")
+- daily_subtotals: 'true' to list git entry commits under a heading per day with that day's estimated time, logging the sum of the days; create_entry's daily_subtotals overrides it (default: "false")
+- strip_subject_prefix: regular expression removed from the start of each commit subject in git entry messages, e.g. '\[?[A-Z]+-\d+\]?' for 'PROJ-123: ' (default: off)
+- collect_subject_prefixes: 'true' to list the stripped prefixes on a 'Refs:' line after the commits (default: "false")
+- track_project_history: 'false' to stop recording project edits in the project history (default: "true")
+- short_hash_length: number of hash characters shown in aggregated commit messages, 4-40 (default: "7")
+- min_entry_interval: minutes that must pass after a project's last git entry before create_entry logs another, unless force=true; '0' disables (default: off)
+- max_session_minutes: cap in minutes on each estimated stretch of git work (the whole span for 'span', each session for 'gap_aware', each day for 'capped' instead of 8 hours), e.g. '240'; '0' disables (default: off)
+- max_timer_minutes: most minutes a stopped timer logs, e.g. '480'; longer timers log the cap and are flagged as needing adjustment, and stale ones are closed when the TUI starts; '0' disables (default: off)
+- session_gap_minutes: idle gap between commits, in minutes, after which the 'gap_aware' method starts a new session so the break is not counted, e.g. '60' (default: 90)
+- max_message_length: largest entry message in bytes the store accepts on create, update, and merge; '0' disables the limit (default: 8192)
+- use_commit_trailers: 'true' to count commits with a 'Time-Spent: 2h' trailer for the trailer value instead of estimating them (default: "false")
+- default_manual_duration: duration pre-filled in the TUI manual entry form, e.g. '30m' (default: none)
+- duration_display: how the TUI shows durations, 'clock' (1h 30m) or 'decimal' (1.50h) (default: "clock")
+- project_order: how TUI project dropdowns are ordered, 'name' or 'recent' to list the most recently used projects first (default: "name")
+- require_reference: 'true' to list entries without a ticket reference in the TUI review queue (default: "false")
+- require_category: 'true' to list entries without a category in the TUI review queue (default: "false")`),
+		mcp.WithString("key", mcp.Required(), mcp.Description("Setting key")),
+		mcp.WithString("value", mcp.Required(), mcp.Description("Setting value")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		key, err := getRequiredString(request, "key")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		value, err := getRequiredString(request, "value")
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		if err := validateSetting(key, value); err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		// Store the conversion table in canonical form
+		if key == db.SettingCurrencyRates {
+			rates, _ := utils.ParseRates(value)
+			value = utils.FormatRates(rates)
+		}
+
+		if key == db.SettingDefaultProject {
+			err = s.store.SetDefaultProject(value)
+		} else {
+			err = s.store.SetSetting(key, value)
+		}
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		return mcp.NewToolResultText(fmt.Sprintf("Setting %s updated successfully", key)), nil
+	})
+}
+
+func (s *ClockworkServer) registerDBHealth() {
+	tool := mcp.NewTool("db_health",
+		mcp.WithDescription("Check database integrity and report record counts and file size"),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		report, err := s.reader().Integrity()
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(report, "", "  ")
+		if !report.OK {
+			return mcp.NewToolResultError(fmt.Sprintf("database consistency check failed:\n%s", string(result))), nil
+		}
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerValidateAllCommits() {
+	tool := mcp.NewTool("validate_all_commits",
+		mcp.WithDescription("Check every entry's commit hash against its project's repo and report stale ones grouped by project (read-only; fix them with fix-commits or repair_baseline)"),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		report, err := s.reader().ValidateCommits(git.ValidateCommitHash)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(report, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerExpandCommitHashes() {
+	tool := mcp.NewTool("expand_commit_hashes",
+		mcp.WithDescription("Replace abbreviated commit hashes stored on entries with the full hash resolved in each project's repo; unresolvable ones are left as they are and listed under 'unresolved'"),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		report, err := s.store.ExpandShortHashes(git.ExpandCommitHash)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		result, _ := json.MarshalIndent(report, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerRepairOrphanEntries() {
+	tool := mcp.NewTool("repair_orphan_entries",
+		mcp.WithDescription("List entries whose project no longer exists, and reassign them to a project or move them to the trash"),
+		mcp.WithString("project_id", mcp.Description("Project ID to reassign orphan entries to (optional)")),
+		mcp.WithBoolean("trash", mcp.Description("Move orphan entries to the trash instead (locked entries are skipped)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		projectID, _ := args["project_id"].(string)
+		trash, _ := args["trash"].(bool)
+
+		if projectID != "" && trash {
+			return mcp.NewToolResultError("project_id cannot be combined with trash"), nil
+		}
+
+		// Without a repair action, only list the orphans
+		if projectID == "" && !trash {
+			orphans, err := s.store.FindOrphanEntries()
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			if orphans == nil {
+				orphans = []*models.Entry{}
+			}
+
+			result, _ := json.MarshalIndent(map[string]interface{}{
+				"orphan_entries": orphans,
+				"count":          len(orphans),
+			}, "", "  ")
+			return mcp.NewToolResultText(string(result)), nil
+		}
+
+		if trash {
+			trashed, err := s.store.TrashOrphanEntries()
+			if err != nil {
+				return mcp.NewToolResultError(err.Error()), nil
+			}
+			result, _ := json.MarshalIndent(map[string]int{"trashed": trashed}, "", "  ")
+			return mcp.NewToolResultText(string(result)), nil
+		}
+
+		reassigned, err := s.store.ReassignOrphanEntries(projectID)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		result, _ := json.MarshalIndent(map[string]int{"reassigned": reassigned}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+func (s *ClockworkServer) registerAuditLog() {
+	tool := mcp.NewTool("audit_log",
+		mcp.WithDescription(fmt.Sprintf("Show recent creates, updates, and deletes of projects and entries with brief diffs, oldest first (the last %d are kept)", db.MaxAuditEvents)),
+		mcp.WithNumber("limit", mcp.Description("Number of most recent events to return (optional, default: 50)")),
+	)
+
+	s.mcp.AddTool(tool, func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		args, _ := request.Params.Arguments.(map[string]interface{})
+
+		limit := 50
+		if l, ok := args["limit"].(float64); ok {
+			limit = int(l)
+		}
+		if limit < 1 {
+			return mcp.NewToolResultError("limit must be at least 1"), nil
+		}
+
+		events, err := s.reader().AuditLog(limit)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+		if events == nil {
+			events = []*models.AuditEvent{}
+		}
+
+		result, _ := json.MarshalIndent(map[string]interface{}{
+			"events": events,
+			"count":  len(events),
+		}, "", "  ")
+		return mcp.NewToolResultText(string(result)), nil
+	})
+}
+
+// validateSetting checks values of settings that only accept specific formats
+func validateSetting(key, value string) error {
+	if value == "" {
+		return nil
+	}
+
+	switch key {
+	case db.SettingTimerRounding:
+		if _, err := utils.RoundToMinutes(0, value); err != nil {
+			return err
+		}
+	case db.SettingCurrencyRates:
+		if _, err := utils.ParseRates(value); err != nil {
+			return err
+		}
+	case db.SettingFocusMapping:
+		if _, err := stats.ParseFocusMapping(value); err != nil {
+			return err
+		}
+	case db.SettingTicketPattern:
+		if _, err := stats.ParseTicketPattern(value); err != nil {
+			return err
+		}
+	case db.SettingMinEntryInterval, db.SettingMaxSessionMinutes, db.SettingMaxTimerMinutes:
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes < 0 {
+			return fmt.Errorf("%s must be a non-negative number of minutes", key)
+		}
+	case db.SettingDefaultManualDuration:
+		if _, err := utils.ParseDuration(value); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+	case db.SettingSessionGapMinutes:
+		minutes, err := strconv.Atoi(value)
+		if err != nil || minutes < 1 {
+			return fmt.Errorf("%s must be a positive number of minutes", key)
+		}
+	case db.SettingStripSubjectPrefix:
+		if _, err := regexp.Compile(value); err != nil {
+			return fmt.Errorf("%s: invalid regular expression: %w", key, err)
+		}
+	case db.SettingMinCommitLines:
+		lines, err := strconv.Atoi(value)
+		if err != nil || lines < 0 {
+			return fmt.Errorf("%s must be a non-negative number of lines", key)
+		}
+	case db.SettingMaxMessageLength:
+		length, err := strconv.Atoi(value)
+		if err != nil || length < 0 {
+			return fmt.Errorf("%s must be a non-negative number of bytes", key)
+		}
+	case db.SettingShortHashLength:
+		length, err := strconv.Atoi(value)
+		if err != nil || length < 4 || length > 40 {
+			return fmt.Errorf("%s must be a number between 4 and 40", key)
+		}
+	case db.SettingProjectOrder:
+		if value != db.ProjectOrderName && value != db.ProjectOrderRecent {
+			return fmt.Errorf("%s must be '%s' or '%s'", key, db.ProjectOrderName, db.ProjectOrderRecent)
+		}
+	case db.SettingDurationDisplay:
+		if value != utils.DisplayClock && value != utils.DisplayDecimal {
+			return fmt.Errorf("%s must be '%s' or '%s'", key, utils.DisplayClock, utils.DisplayDecimal)
+		}
+	case db.SettingExcludeFromDuration, db.SettingTrackProjectHistory, db.SettingIncludeCommitBodies, db.SettingGroupConventionalCommits, db.SettingDailySubtotals, db.SettingCollectSubjectPrefixes, db.SettingDefaultAuthorFromRepo,
+		db.SettingRequireReference, db.SettingRequireCategory, db.SettingUseCommitTrailers:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%s must be 'true' or 'false'", key)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,753 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	mcpserver "github.com/mark3labs/mcp-go/server"
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/git"
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/stats"
+	"github.com/techthos/clockwork/internal/testutil"
+)
+
+// setupTestServer creates a server backed by a temporary database (no MCP transport)
+func setupTestServer(t *testing.T) *ClockworkServer {
+	t.Helper()
+	store, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return &ClockworkServer{store: store}
+}
+
+// initTestRepo creates a temporary git repository with a single commit and returns its path and HEAD
+func initTestRepo(t *testing.T) (string, string) {
+	t.Helper()
+	repo := testutil.NewGitRepo(t)
+	repo.Run("commit", "-q", "--allow-empty", "-m", "Initial commit")
+	return repo.Dir, repo.Run("rev-parse", "HEAD")
+}
+
+func TestCreateFallbackEntry(t *testing.T) {
+	s := setupTestServer(t)
+	repo, head := initTestRepo(t)
+
+	project, _ := s.store.CreateProject("Test", repo)
+
+	entry, err := s.createFallbackEntry(project, "1h 15m", "", false, time.Now(), db.EntryOptions{Author: "Jane Doe"})
+	if err != nil {
+		t.Fatalf("Failed to create fallback entry: %v", err)
+	}
+
+	if entry.Mode != models.EntryModeGit || entry.Author != "Jane Doe" {
+		t.Errorf("Expected a git entry by Jane Doe, got mode %q author %q", entry.Mode, entry.Author)
+	}
+
+	if entry.CommitHash != head {
+		t.Errorf("Expected entry at HEAD %s, got %s", head, entry.CommitHash)
+	}
+	if entry.Duration != 75 {
+		t.Errorf("Expected duration 75, got %d", entry.Duration)
+	}
+	if entry.Message != "Work without new commits" {
+		t.Errorf("Unexpected default message '%s'", entry.Message)
+	}
+
+	// The fallback entry becomes the new baseline
+	baseline, _ := s.store.GetLastCommitHash(project.ID)
+	if baseline != head {
+		t.Errorf("Expected baseline %s, got %s", head, baseline)
+	}
+
+	if _, err := s.createFallbackEntry(project, "soon", "", false, time.Now(), db.EntryOptions{}); err == nil {
+		t.Error("Expected error for invalid fallback duration")
+	}
+}
+
+func TestCreateManualEntryDefaultsAuthor(t *testing.T) {
+	s := setupTestServer(t)
+	repo, head := initTestRepo(t)
+
+	cmd := exec.Command("git", "config", "user.name", "Jane Doe")
+	cmd.Dir = repo
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git config failed: %v\n%s", err, output)
+	}
+
+	project, _ := s.store.CreateProject("Test", repo)
+
+	entry, err := s.createManualEntry(project, 30, "", false, time.Now(), db.EntryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create manual entry: %v", err)
+	}
+	if entry.Author != "Jane Doe" {
+		t.Errorf("Expected repo author 'Jane Doe', got '%s'", entry.Author)
+	}
+	if entry.CommitHash != head {
+		t.Errorf("Expected entry at HEAD %s, got %s", head, entry.CommitHash)
+	}
+
+	// The persisted entry carries the author too
+	stored, _ := s.store.GetEntry(entry.ID)
+	if stored.Author != "Jane Doe" {
+		t.Errorf("Expected stored author 'Jane Doe', got '%s'", stored.Author)
+	}
+
+	// An explicit author wins
+	entry, _ = s.createManualEntry(project, 30, "", false, time.Now(), db.EntryOptions{Author: "John Roe"})
+	if entry.Author != "John Roe" {
+		t.Errorf("Expected explicit author 'John Roe', got '%s'", entry.Author)
+	}
+
+	// Defaulting can be switched off
+	s.store.SetSetting(db.SettingDefaultAuthorFromRepo, "false")
+	entry, _ = s.createManualEntry(project, 30, "", false, time.Now(), db.EntryOptions{})
+	if entry.Author != "" {
+		t.Errorf("Expected no author when defaulting is disabled, got '%s'", entry.Author)
+	}
+}
+
+func TestCreateManualEntryRecordHeadForManual(t *testing.T) {
+	s := setupTestServer(t)
+	repo, head := initTestRepo(t)
+
+	project, _ := s.store.CreateProject("Test", repo)
+
+	tests := []struct {
+		name   string
+		record *bool
+		want   string
+	}{
+		{"default records HEAD", nil, head},
+		{"enabled records HEAD", boolPtr(true), head},
+		{"disabled stores no hash", boolPtr(false), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.record != nil {
+				var err error
+				project, err = s.store.SetProjectRecordHeadForManual(project.ID, *tt.record)
+				if err != nil {
+					t.Fatalf("Failed to set record_head_for_manual: %v", err)
+				}
+			}
+
+			entry, err := s.createManualEntry(project, 30, "Notes", false, time.Now(), db.EntryOptions{})
+			if err != nil {
+				t.Fatalf("Failed to create manual entry: %v", err)
+			}
+			stored, _ := s.store.GetEntry(entry.ID)
+			if stored.CommitHash != tt.want {
+				t.Errorf("Expected stored commit hash %q, got %q", tt.want, stored.CommitHash)
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestCreateManualEntryUnreachableRepo(t *testing.T) {
+	s := setupTestServer(t)
+
+	project, _ := s.store.CreateProject("Test", filepath.Join(t.TempDir(), "missing"))
+
+	entry, err := s.createManualEntry(project, 30, "", false, time.Now(), db.EntryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create manual entry: %v", err)
+	}
+	if entry.Author != "" {
+		t.Errorf("Expected empty author for unreachable repo, got '%s'", entry.Author)
+	}
+	if entry.Message != "Manual entry" {
+		t.Errorf("Expected default manual message, got '%s'", entry.Message)
+	}
+}
+
+// setupToolServer creates a test server with its MCP tools registered
+func setupToolServer(t *testing.T) *ClockworkServer {
+	t.Helper()
+	s := setupTestServer(t)
+	s.mcp = mcpserver.NewMCPServer("clockwork", "test", mcpserver.WithToolHandlerMiddleware(s.serialize))
+	s.registerTools()
+	return s
+}
+
+// callTool calls a registered tool's handler and returns its result text and whether it is an error
+func callTool(t *testing.T, s *ClockworkServer, name string, args map[string]interface{}) (string, bool) {
+	t.Helper()
+	var request mcp.CallToolRequest
+	request.Params.Name = name
+	request.Params.Arguments = args
+
+	result, err := s.mcp.GetTool(name).Handler(context.Background(), request)
+	if err != nil {
+		t.Fatalf("%s failed: %v", name, err)
+	}
+	return toolResultText(result), result.IsError
+}
+
+func TestCreateEntryRepoUnavailableFallback(t *testing.T) {
+	s := setupToolServer(t)
+
+	project, _ := s.store.CreateProject("Test", filepath.Join(t.TempDir(), "missing"))
+
+	text, isError := callTool(t, s, "create_entry", map[string]interface{}{"project_id": project.ID})
+	if !isError || !strings.HasPrefix(text, "repo_unavailable:") {
+		t.Errorf("Expected a repo_unavailable error, got %q", text)
+	}
+
+	// round_to applies to the git duration, not to fallback_manual_duration
+	text, isError = callTool(t, s, "create_entry", map[string]interface{}{
+		"project_id":               project.ID,
+		"fallback_manual_duration": "10m",
+		"round_to":                 float64(15),
+	})
+	if isError {
+		t.Fatalf("Expected the fallback to be logged, got %q", text)
+	}
+	entries, _ := s.store.ListEntries(project.ID)
+	if len(entries) != 1 || entries[0].Duration != 10 {
+		t.Errorf("Expected one unrounded 10m entry, got %+v", entries)
+	}
+}
+
+func TestCreateEntryAllCommitsIgnored(t *testing.T) {
+	s := setupToolServer(t)
+	repo := testutil.NewGitRepo(t)
+
+	os.WriteFile(filepath.Join(repo.Dir, git.IgnoreFile), []byte("path:docs/**\n"), 0644)
+	repo.Run("add", git.IgnoreFile)
+	repo.Run("commit", "-q", "-m", "Ignore docs")
+	project, _ := s.store.CreateProject("Test", repo.Dir)
+	s.store.CreateEntry(project.ID, 30, "Baseline", repo.Run("rev-parse", "HEAD"), false, time.Now().Add(-time.Hour))
+
+	os.MkdirAll(filepath.Join(repo.Dir, "docs"), 0755)
+	os.WriteFile(filepath.Join(repo.Dir, "docs", "guide.md"), []byte("# Guide\n"), 0644)
+	repo.Run("add", "docs")
+	repo.Run("commit", "-q", "-m", "Write the guide")
+	head := repo.Run("rev-parse", "HEAD")
+
+	text, isError := callTool(t, s, "create_entry", map[string]interface{}{"project_id": project.ID})
+	if !isError || !strings.Contains(text, "ignored by "+git.IgnoreFile) {
+		t.Fatalf("Expected an all-ignored error, got %q", text)
+	}
+
+	// fallback_manual_duration moves the baseline past the ignored commits
+	text, isError = callTool(t, s, "create_entry", map[string]interface{}{
+		"project_id":               project.ID,
+		"fallback_manual_duration": "20m",
+	})
+	if isError {
+		t.Fatalf("Expected the fallback to be logged, got %q", text)
+	}
+	if baseline, _ := s.store.GetLastCommitHash(project.ID); baseline != head {
+		t.Errorf("Expected baseline %s, got %s", head, baseline)
+	}
+
+	text, _ = callTool(t, s, "create_entry", map[string]interface{}{"project_id": project.ID})
+	if text != errNoNewCommits.Error() {
+		t.Errorf("Expected no new commits after the fallback, got %q", text)
+	}
+}
+
+func TestCreateEntryForeignBaseline(t *testing.T) {
+	s := setupToolServer(t)
+	repo := testutil.NewGitRepo(t)
+	other := testutil.NewGitRepo(t)
+
+	repo.Run("commit", "-q", "--allow-empty", "-m", "Local work")
+	other.Run("commit", "-q", "--allow-empty", "-m", "Work elsewhere")
+
+	// The last entry's hash comes from another repo, as after repointing the project
+	project, _ := s.store.CreateProject("Test", repo.Dir)
+	s.store.CreateEntry(project.ID, 30, "Baseline", other.Run("rev-parse", "HEAD"), false, time.Now().Add(-time.Hour))
+
+	text, isError := callTool(t, s, "create_entry", map[string]interface{}{"project_id": project.ID})
+	if !isError || !strings.Contains(text, "not in the current history") || !strings.Contains(text, "repair_baseline") {
+		t.Fatalf("Expected the foreign baseline to be refused, got %q", text)
+	}
+	if entries, _ := s.store.ListEntries(project.ID); len(entries) != 1 {
+		t.Errorf("Expected no entry to be created, got %d entries", len(entries))
+	}
+}
+
+func TestCreateProjectOptionsValidatedFirst(t *testing.T) {
+	s := setupToolServer(t)
+	repo := testutil.NewGitRepo(t)
+
+	// A bad option after valid ones must not leave a half-configured project behind
+	text, isError := callTool(t, s, "create_project", map[string]interface{}{
+		"name":          "Test",
+		"git_repo_path": repo.Dir,
+		"hourly_rate":   float64(90),
+		"budget_hours":  float64(-1),
+	})
+	if !isError || !strings.Contains(text, "budget_hours") {
+		t.Fatalf("Expected a budget_hours error, got %q", text)
+	}
+	if projects, _ := s.store.ListProjects(true); len(projects) != 0 {
+		t.Errorf("Expected no project to be created, got %d", len(projects))
+	}
+
+	text, isError = callTool(t, s, "create_project", map[string]interface{}{
+		"name":          "Test",
+		"git_repo_path": repo.Dir,
+		"hourly_rate":   float64(90),
+		"currency":      "eur",
+		"budget_hours":  float64(40),
+		"auto_schedule": "mon-fri 18:00",
+	})
+	if isError {
+		t.Fatalf("create_project failed: %s", text)
+	}
+	projects, _ := s.store.ListProjects(true)
+	if len(projects) != 1 {
+		t.Fatalf("Expected one project, got %d", len(projects))
+	}
+	project := projects[0]
+	if project.HourlyRate != 90 || project.Currency != "EUR" || project.BudgetMinutes != 40*60 || project.AutoSchedule != "mon-fri 18:00" {
+		t.Errorf("Unexpected project %+v", project)
+	}
+
+	// The update is one change: a single history and audit record, none when it is refused
+	callTool(t, s, "update_project", map[string]interface{}{"id": project.ID, "hourly_rate": float64(100), "budget_hours": float64(50)})
+	if _, isError := callTool(t, s, "update_project", map[string]interface{}{"id": project.ID, "currency": "usd", "auto_schedule": "someday"}); !isError {
+		t.Error("Expected an invalid auto_schedule to be refused")
+	}
+	if history, _ := s.store.ProjectHistory(project.ID); len(history) != 1 {
+		t.Errorf("Expected one history record, got %+v", history)
+	}
+	events, _ := s.store.AuditLog(0)
+	if len(events) != 2 || events[0].Operation != db.AuditCreate || events[1].Operation != db.AuditUpdate {
+		t.Errorf("Expected one create and one update event, got %+v", events)
+	}
+	if project, _ = s.store.GetProject(project.ID); project.Currency != "EUR" {
+		t.Errorf("Expected the refused update to leave currency EUR, got %q", project.Currency)
+	}
+}
+
+func TestCreateEntryRoundDownBelowIncrement(t *testing.T) {
+	s := setupToolServer(t)
+	project, _ := s.store.CreateProject("Test", "/path")
+
+	// 10 minutes rounded down to 15 would log no time at all
+	text, isError := callTool(t, s, "create_entry", map[string]interface{}{
+		"project_id": project.ID,
+		"manual":     true,
+		"duration":   "10m",
+		"round_to":   float64(15),
+		"round_mode": "down",
+	})
+	if isError {
+		t.Fatalf("create_entry failed: %s", text)
+	}
+	entries, _ := s.store.ListEntries(project.ID)
+	if len(entries) != 1 || entries[0].Duration != 15 {
+		t.Errorf("Expected one 15 minute entry, got %+v", entries)
+	}
+}
+
+func TestCreateGitEntryMergesSameDay(t *testing.T) {
+	s := setupTestServer(t)
+	project, _ := s.store.CreateProject("Test", "/path")
+	morning := time.Date(2026, time.October, 15, 9, 0, 0, 0, time.Local)
+
+	first, merged, err := s.createGitEntry(project.ID, 60, "Aggregated 1 commits:\n1. [aaa1111] Morning work\n", "aaa1111", false, morning, true, db.EntryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create git entry: %v", err)
+	}
+	if merged {
+		t.Error("Expected the first entry to be created, not merged")
+	}
+	if first.Mode != models.EntryModeGit {
+		t.Errorf("Expected mode %q, got %q", models.EntryModeGit, first.Mode)
+	}
+
+	second, merged, err := s.createGitEntry(project.ID, 45, "Aggregated 1 commits:\n1. [bbb2222] Afternoon work\n", "bbb2222", false, morning.Add(6*time.Hour), true, db.EntryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to merge git entry: %v", err)
+	}
+	if !merged {
+		t.Fatal("Expected the same-day entry to be merged")
+	}
+	if second.ID != first.ID {
+		t.Errorf("Expected entry %s to be extended, got %s", first.ID, second.ID)
+	}
+	if second.Duration != 105 {
+		t.Errorf("Expected duration 105, got %d", second.Duration)
+	}
+	if !strings.Contains(second.Message, "Morning work") || !strings.Contains(second.Message, "Afternoon work") {
+		t.Errorf("Expected both messages, got %q", second.Message)
+	}
+
+	entries, _ := s.store.ListEntries(project.ID)
+	if len(entries) != 1 {
+		t.Errorf("Expected 1 entry after merge, got %d", len(entries))
+	}
+	baseline, _ := s.store.GetLastCommitHash(project.ID)
+	if baseline != "bbb2222" {
+		t.Errorf("Expected baseline to advance to bbb2222, got %s", baseline)
+	}
+}
+
+func TestCreateGitEntryDifferentDayCreates(t *testing.T) {
+	s := setupTestServer(t)
+	project, _ := s.store.CreateProject("Test", "/path")
+	yesterday := time.Date(2026, time.October, 14, 17, 0, 0, 0, time.Local)
+
+	first, _, _ := s.createGitEntry(project.ID, 60, "Yesterday", "aaa1111", false, yesterday, true, db.EntryOptions{})
+
+	second, merged, err := s.createGitEntry(project.ID, 30, "Today", "bbb2222", false, yesterday.Add(16*time.Hour), true, db.EntryOptions{})
+	if err != nil {
+		t.Fatalf("Failed to create git entry: %v", err)
+	}
+	if merged {
+		t.Error("Expected a new entry for a different day")
+	}
+	if second.ID == first.ID || second.Duration != 30 {
+		t.Errorf("Expected a separate 30 minute entry, got %+v", second)
+	}
+
+	// Without auto merge, same-day entries stay separate
+	_, merged, _ = s.createGitEntry(project.ID, 15, "Later today", "ccc3333", false, yesterday.Add(17*time.Hour), false, db.EntryOptions{})
+	if merged {
+		t.Error("Expected no merge when auto merge is off")
+	}
+
+	// Manual entries are never merged into
+	manual, _ := s.store.CreateEntry(project.ID, 20, "Manual", "ddd4444", false, yesterday.Add(18*time.Hour))
+	entry, merged, _ := s.createGitEntry(project.ID, 10, "After manual", "eee5555", false, yesterday.Add(19*time.Hour), true, db.EntryOptions{})
+	if merged || entry.ID == manual.ID {
+		t.Error("Expected no merge into a manual entry")
+	}
+
+	entries, _ := s.store.ListEntries(project.ID)
+	if len(entries) != 5 {
+		t.Errorf("Expected 5 entries, got %d", len(entries))
+	}
+}
+
+func TestEstimateInvoice(t *testing.T) {
+	s := setupTestServer(t)
+
+	project, _ := s.store.CreateProject("Client", "")
+	other, _ := s.store.CreateProject("Other", "")
+	day := time.Date(2026, time.October, 10, 12, 0, 0, 0, time.Local)
+
+	billable := []int64{90, 45, 20}
+	for _, minutes := range billable {
+		s.store.CreateEntry(project.ID, minutes, "Work", "", false, day)
+	}
+	s.store.CreateEntry(project.ID, 60, "Already invoiced", "", true, day)
+	s.store.CreateEntry(project.ID, 120, "Last month", "", false, day.AddDate(0, -1, 0))
+	s.store.CreateEntry(other.ID, 30, "Other project", "", false, day)
+
+	start := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.Local)
+	end := time.Date(2026, time.October, 31, 23, 59, 59, 0, time.Local)
+	estimate, err := s.estimateInvoice(project.ID, &start, &end, 85, "eur")
+	if err != nil {
+		t.Fatalf("estimateInvoice() error = %v", err)
+	}
+
+	var minutes int64
+	for _, m := range billable {
+		minutes += m
+	}
+	wantAmount := float64(minutes) / 60 * 85
+	if estimate.Minutes != minutes || estimate.EntryCount != len(billable) {
+		t.Errorf("Expected %d minutes in %d entries, got %d in %d", minutes, len(billable), estimate.Minutes, estimate.EntryCount)
+	}
+	if estimate.Amount != 219.58 || estimate.Amount-wantAmount > 0.005 || wantAmount-estimate.Amount > 0.005 {
+		t.Errorf("Expected amount %.2f, got %.2f", wantAmount, estimate.Amount)
+	}
+	if estimate.Currency != "EUR" {
+		t.Errorf("Expected currency 'EUR', got %q", estimate.Currency)
+	}
+
+	// Without a range every uninvoiced entry of the project counts
+	estimate, _ = s.estimateInvoice(project.ID, nil, nil, 100, "")
+	if estimate.Minutes != minutes+120 || estimate.Amount != 458.33 {
+		t.Errorf("Expected %d minutes for 458.33, got %d for %.2f", minutes+120, estimate.Minutes, estimate.Amount)
+	}
+
+	if _, err := s.estimateInvoice(project.ID, nil, nil, -1, ""); err == nil {
+		t.Error("Expected negative rate to be rejected")
+	}
+	if _, err := s.estimateInvoice("missing", nil, nil, 85, ""); err == nil {
+		t.Error("Expected unknown project to be rejected")
+	}
+
+	// Committing numbers the invoice and leaves nothing in the range to invoice
+	estimate, _ = s.estimateInvoice(project.ID, &start, &end, 85, "eur")
+	estimate, err = s.issueInvoice(estimate, &start, &end)
+	if err != nil {
+		t.Fatalf("issueInvoice() error = %v", err)
+	}
+	if estimate.Invoice == nil || estimate.Invoice.Number != "CLIENT-0001" || estimate.Amount != 219.58 {
+		t.Errorf("Expected invoice CLIENT-0001 for 219.58, got %+v", estimate)
+	}
+	if estimate, _ = s.estimateInvoice(project.ID, &start, &end, 85, ""); estimate.EntryCount != 0 {
+		t.Errorf("Expected no uninvoiced entries left in the range, got %d", estimate.EntryCount)
+	}
+}
+
+func TestExportNewEntries(t *testing.T) {
+	s := setupTestServer(t)
+
+	project, _ := s.store.CreateProject("Test", "")
+	s.store.CreateEntry(project.ID, 60, "First", "", false, time.Now())
+
+	path := filepath.Join(t.TempDir(), "new.csv")
+	count, err := s.exportNewEntries(project.ID, db.ExportCSV, path)
+	if err != nil || count != 1 {
+		t.Fatalf("Expected first export of 1 entry, got %d (err %v)", count, err)
+	}
+
+	time.Sleep(time.Millisecond)
+	s.store.CreateEntry(project.ID, 30, "Second", "", false, time.Now())
+
+	count, err = s.exportNewEntries(project.ID, db.ExportCSV, path)
+	if err != nil || count != 1 {
+		t.Fatalf("Expected second export of 1 entry, got %d (err %v)", count, err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "Second") || strings.Contains(string(data), "First") {
+		t.Errorf("Expected only the second entry in the export, got:\n%s", data)
+	}
+
+	// A failed export removes the file
+	failed := filepath.Join(t.TempDir(), "failed.xml")
+	if _, err := s.exportNewEntries(project.ID, "xml", failed); err == nil {
+		t.Error("Expected unknown format to fail")
+	}
+	if _, err := os.Stat(failed); !os.IsNotExist(err) {
+		t.Error("Expected failed export file to be removed")
+	}
+}
+
+func TestCheckRepoOverlap(t *testing.T) {
+	s := setupTestServer(t)
+
+	project, _ := s.store.CreateProject("App", "/work/app")
+
+	err := s.checkRepoOverlap("/work/app/api", "", false)
+	if err == nil || !strings.Contains(err.Error(), project.ID) || !strings.Contains(err.Error(), "force=true") {
+		t.Errorf("Expected overlap error naming the project and force, got %v", err)
+	}
+	if err := s.checkRepoOverlap("/work/app/api", "", true); err != nil {
+		t.Errorf("Expected force to skip the check, got %v", err)
+	}
+	if err := s.checkRepoOverlap("/work/app", project.ID, false); err != nil {
+		t.Errorf("Expected a project's own path to pass, got %v", err)
+	}
+	if err := s.checkRepoOverlap("/work/other", "", false); err != nil {
+		t.Errorf("Expected unrelated path to pass, got %v", err)
+	}
+}
+
+func TestCheckEntryInterval(t *testing.T) {
+	s := setupTestServer(t)
+
+	project, _ := s.store.CreateProject("Test", "")
+	last := time.Date(2026, time.October, 10, 14, 0, 0, 0, time.Local)
+	s.createGitEntry(project.ID, 60, "Work", "aaaaaaa", false, last, false, db.EntryOptions{})
+
+	// Off by default
+	if err := s.checkEntryInterval(project.ID, last.Add(time.Minute), false, false); err != nil {
+		t.Errorf("Expected no guard without min_entry_interval, got %v", err)
+	}
+
+	s.store.SetSetting(db.SettingMinEntryInterval, "10")
+
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		autoMerge bool
+		force     bool
+		blocked   bool
+	}{
+		{"within interval", last.Add(3 * time.Minute), false, false, true},
+		{"backdated within interval", last.Add(-5 * time.Minute), false, false, true},
+		{"after interval", last.Add(10 * time.Minute), false, false, false},
+		{"forced", last.Add(3 * time.Minute), false, true, false},
+		{"merged into last entry", last.Add(3 * time.Minute), true, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.checkEntryInterval(project.ID, tt.createdAt, tt.autoMerge, tt.force)
+			if tt.blocked && (err == nil || !strings.Contains(err.Error(), "force=true")) {
+				t.Errorf("Expected entry to be blocked, got %v", err)
+			}
+			if !tt.blocked && err != nil {
+				t.Errorf("Expected entry to be allowed, got %v", err)
+			}
+		})
+	}
+
+	// Manual entries at HEAD don't count as the last git entry
+	s.store.CreateEntry(project.ID, 30, "Meeting", "bbbbbbb", false, last.Add(20*time.Minute))
+	if err := s.checkEntryInterval(project.ID, last.Add(22*time.Minute), false, false); err != nil {
+		t.Errorf("Expected manual entry to be ignored by the guard, got %v", err)
+	}
+}
+
+func TestTicketReport(t *testing.T) {
+	s := setupTestServer(t)
+
+	project, _ := s.store.CreateProject("Test", "")
+	now := time.Now()
+	s.store.CreateEntry(project.ID, 60, "Fix PROJ-1", "", false, now)
+	s.store.CreateEntry(project.ID, 30, "PROJ-1 and OPS-2", "", false, now)
+	s.store.CreateEntry(project.ID, 20, "Standup #7", "", true, now)
+
+	tickets, err := s.ticketReport(project.ID, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("ticketReport() error = %v", err)
+	}
+	if len(tickets) != 3 || tickets[0].Ticket != "PROJ-1" || tickets[0].Minutes != 90 || tickets[2].Ticket != stats.NoTicket {
+		t.Errorf("Unexpected default report: %+v", tickets)
+	}
+
+	// A custom pattern and the invoiced filter apply
+	s.store.SetSetting(db.SettingTicketPattern, `#\d+`)
+	invoiced := true
+	tickets, _ = s.ticketReport(project.ID, nil, nil, &invoiced)
+	if len(tickets) != 1 || tickets[0].Ticket != "#7" || tickets[0].Minutes != 20 {
+		t.Errorf("Unexpected custom pattern report: %+v", tickets)
+	}
+
+	s.store.SetSetting(db.SettingTicketPattern, "(")
+	if _, err := s.ticketReport(project.ID, nil, nil, nil); err == nil {
+		t.Error("Expected invalid ticket_pattern to fail")
+	}
+}
+
+func TestEntryCommitHash(t *testing.T) {
+	s := setupTestServer(t)
+	repo, head := initTestRepo(t)
+
+	project, _ := s.store.CreateProject("Repo", repo)
+	entry, _ := s.store.CreateEntry(project.ID, 60, "Work", "", false, time.Now())
+
+	full, err := s.entryCommitHash(entry.ID, head[:7])
+	if err != nil {
+		t.Fatalf("entryCommitHash failed: %v", err)
+	}
+	if full != head {
+		t.Errorf("Expected %s expanded to %s, got %s", head[:7], head, full)
+	}
+
+	if cleared, err := s.entryCommitHash(entry.ID, ""); err != nil || cleared != "" {
+		t.Errorf("Expected an empty hash to pass through, got %q (%v)", cleared, err)
+	}
+	if _, err := s.entryCommitHash(entry.ID, "0000000"); err == nil {
+		t.Error("Expected error for a hash not in the repo")
+	}
+	if _, err := s.entryCommitHash(entry.ID, "xyz"); err == nil {
+		t.Error("Expected error for a malformed hash")
+	}
+}
+
+func TestReplicaServesReports(t *testing.T) {
+	s := setupToolServer(t)
+	replicaPath := filepath.Join(t.TempDir(), "replica.db")
+
+	// Seed the replica, then close it as a copied database would be
+	seed, err := db.New(replicaPath)
+	if err != nil {
+		t.Fatalf("Failed to create replica: %v", err)
+	}
+	replicaProject, _ := seed.CreateProject("Replica", "/replica")
+	seed.CreateEntry(replicaProject.ID, 60, "Copied entry", "", false, time.Now())
+	seed.Close()
+
+	s.replica, err = db.NewReadOnly(replicaPath)
+	if err != nil {
+		t.Fatalf("NewReadOnly() error = %v", err)
+	}
+	t.Cleanup(func() { s.replica.Close() })
+
+	// Writes go to the live database
+	text, isError := callTool(t, s, "create_project", map[string]interface{}{"name": "Live", "git_repo_path": "/live", "allow_missing_path": true})
+	if isError {
+		t.Fatalf("create_project failed: %s", text)
+	}
+	if projects, _ := s.store.ListProjects(false); len(projects) != 1 || projects[0].Name != "Live" {
+		t.Errorf("Expected the project in the live database, got %v", projects)
+	}
+
+	// Reports read the replica, which doesn't see the new project
+	text, isError = callTool(t, s, "list_entries", map[string]interface{}{})
+	if isError || !strings.Contains(text, "Copied entry") {
+		t.Errorf("Expected list_entries to read the replica, got %s", text)
+	}
+	text, isError = callTool(t, s, "audit_log", map[string]interface{}{})
+	if isError || strings.Contains(text, "Live") {
+		t.Errorf("Expected audit_log to read the stale replica, got %s", text)
+	}
+}
+
+func TestServeHTTPToolsList(t *testing.T) {
+	s := setupTestServer(t)
+	s.mcp = mcpserver.NewMCPServer("clockwork", "test", mcpserver.WithToolHandlerMiddleware(s.serialize))
+	s.registerTools()
+
+	httpServer := httptest.NewServer(s.httpServer())
+	defer httpServer.Close()
+	endpoint := httpServer.URL + HTTPEndpoint
+
+	post := func(sessionID, body string) *http.Response {
+		t.Helper()
+		request, err := http.NewRequest(http.MethodPost, endpoint, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		request.Header.Set("Content-Type", "application/json")
+		request.Header.Set("Accept", "application/json, text/event-stream")
+		if sessionID != "" {
+			request.Header.Set("Mcp-Session-Id", sessionID)
+		}
+		response, err := http.DefaultClient.Do(request)
+		if err != nil {
+			t.Fatalf("POST %s failed: %v", endpoint, err)
+		}
+		if response.StatusCode != http.StatusOK && response.StatusCode != http.StatusAccepted {
+			t.Fatalf("POST %s: unexpected status %s", endpoint, response.Status)
+		}
+		return response
+	}
+
+	initialize := post("", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26","capabilities":{},"clientInfo":{"name":"test","version":"1"}}}`)
+	initialize.Body.Close()
+	sessionID := initialize.Header.Get("Mcp-Session-Id")
+
+	post(sessionID, `{"jsonrpc":"2.0","method":"notifications/initialized"}`).Body.Close()
+
+	response := post(sessionID, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range []string{"create_project", "create_entry", "get_statistics"} {
+		if !strings.Contains(string(body), `"`+tool+`"`) {
+			t.Errorf("tools/list response is missing %s:\n%s", tool, body)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// DayTotal holds the time logged on one calendar day
+type DayTotal struct {
+	Date    time.Time `json:"date"` // Midnight in the entries' time zone
+	Minutes int64     `json:"minutes"`
+}
+
+// BurnDownDay is one day of a burn-down, with the time logged up to and including it
+type BurnDownDay struct {
+	Date       time.Time `json:"date"`
+	Minutes    int64     `json:"minutes"`
+	Cumulative int64     `json:"cumulative"`
+}
+
+// BurnDown tracks a project's logged time against its budget over the project's active period
+type BurnDown struct {
+	BudgetMinutes    int64         `json:"budget_minutes"` // 0 = no budget; only the cumulative series is meaningful
+	TotalMinutes     int64         `json:"total_minutes"`
+	RemainingMinutes int64         `json:"remaining_minutes"` // Negative once the budget is overrun
+	BurnRate         float64       `json:"burn_rate"`         // Average minutes per calendar day of the active period
+	Days             []BurnDownDay `json:"days"`
+	// ProjectedExhaustion is the day the budget runs out at the current burn rate, or the day
+	// it was used up when it already is. Nil without a budget or without any logged time.
+	ProjectedExhaustion *time.Time `json:"projected_exhaustion,omitempty"`
+	Exhausted           bool       `json:"exhausted"`
+}
+
+// startOfDay returns midnight of t's calendar day in t's time zone
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// DailyTotals sums entry time per calendar day, oldest first; days without entries are omitted
+func DailyTotals(entries []*models.Entry) []DayTotal {
+	totals := make(map[time.Time]int64)
+	for _, entry := range entries {
+		totals[startOfDay(entry.CreatedAt)] += entry.Duration
+	}
+
+	days := make([]DayTotal, 0, len(totals))
+	for date, minutes := range totals {
+		days = append(days, DayTotal{Date: date, Minutes: minutes})
+	}
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].Date.Before(days[j].Date)
+	})
+	return days
+}
+
+// ComputeBurnDown builds the burn-down of entries against budgetMinutes. The active period runs
+// from the first entry's day through today (or the last entry's day, if later), with every
+// calendar day included so idle days show as flat stretches. The projection assumes the
+// average burn rate over that period continues.
+func ComputeBurnDown(entries []*models.Entry, budgetMinutes int64, today time.Time) *BurnDown {
+	burnDown := &BurnDown{BudgetMinutes: budgetMinutes, Days: []BurnDownDay{}}
+
+	totals := DailyTotals(entries)
+	if len(totals) == 0 {
+		burnDown.RemainingMinutes = budgetMinutes
+		return burnDown
+	}
+
+	byDay := make(map[time.Time]int64, len(totals))
+	for _, total := range totals {
+		byDay[total.Date] = total.Minutes
+	}
+
+	first := totals[0].Date
+	last := totals[len(totals)-1].Date
+	if end := startOfDay(today.In(first.Location())); end.After(last) {
+		last = end
+	}
+
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		minutes := byDay[day]
+		burnDown.TotalMinutes += minutes
+		burnDown.Days = append(burnDown.Days, BurnDownDay{
+			Date:       day,
+			Minutes:    minutes,
+			Cumulative: burnDown.TotalMinutes,
+		})
+
+		if budgetMinutes > 0 && !burnDown.Exhausted && burnDown.TotalMinutes >= budgetMinutes {
+			exhausted := day
+			burnDown.ProjectedExhaustion = &exhausted
+			burnDown.Exhausted = true
+		}
+	}
+
+	burnDown.RemainingMinutes = budgetMinutes - burnDown.TotalMinutes
+	burnDown.BurnRate = float64(burnDown.TotalMinutes) / float64(len(burnDown.Days))
+
+	if budgetMinutes > 0 && !burnDown.Exhausted && burnDown.BurnRate > 0 {
+		daysLeft := int(math.Ceil(float64(burnDown.RemainingMinutes) / burnDown.BurnRate))
+		projected := last.AddDate(0, 0, daysLeft)
+		burnDown.ProjectedExhaustion = &projected
+	}
+
+	return burnDown
+}
@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// day returns 10:00 UTC on the given day of March 2026
+func day(d int) time.Time {
+	return time.Date(2026, time.March, d, 10, 0, 0, 0, time.UTC)
+}
+
+func TestDailyTotals(t *testing.T) {
+	entries := []*models.Entry{
+		{Duration: 60, CreatedAt: day(3)},
+		{Duration: 30, CreatedAt: day(1)},
+		{Duration: 45, CreatedAt: day(3).Add(8 * time.Hour)},
+	}
+
+	totals := DailyTotals(entries)
+	if len(totals) != 2 {
+		t.Fatalf("Expected 2 days, got %v", totals)
+	}
+	if !totals[0].Date.Equal(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)) || totals[0].Minutes != 30 {
+		t.Errorf("Expected 30 minutes on March 1, got %+v", totals[0])
+	}
+	if totals[1].Minutes != 105 {
+		t.Errorf("Expected 105 minutes on March 3, got %+v", totals[1])
+	}
+}
+
+func TestComputeBurnDown(t *testing.T) {
+	entries := []*models.Entry{
+		{Duration: 120, CreatedAt: day(1)},
+		{Duration: 240, CreatedAt: day(2)},
+		{Duration: 180, CreatedAt: day(4)},
+	}
+
+	// Four days, 540 minutes: 135 per day, 1460 left of a 2000 minute budget
+	burnDown := ComputeBurnDown(entries, 2000, day(4))
+
+	wantCumulative := []int64{120, 360, 360, 540}
+	if len(burnDown.Days) != len(wantCumulative) {
+		t.Fatalf("Expected %d days including the idle one, got %d", len(wantCumulative), len(burnDown.Days))
+	}
+	for i, want := range wantCumulative {
+		if burnDown.Days[i].Cumulative != want {
+			t.Errorf("Day %d: expected cumulative %d, got %d", i, want, burnDown.Days[i].Cumulative)
+		}
+	}
+	if burnDown.Days[2].Minutes != 0 {
+		t.Errorf("Expected the idle day to log nothing, got %d", burnDown.Days[2].Minutes)
+	}
+
+	if burnDown.TotalMinutes != 540 || burnDown.RemainingMinutes != 1460 || burnDown.BurnRate != 135 {
+		t.Errorf("Unexpected totals: %+v", burnDown)
+	}
+
+	// ceil(1460 / 135) = 11 days after March 4
+	want := time.Date(2026, time.March, 15, 0, 0, 0, 0, time.UTC)
+	if burnDown.Exhausted || burnDown.ProjectedExhaustion == nil || !burnDown.ProjectedExhaustion.Equal(want) {
+		t.Errorf("Expected projected exhaustion on %v, got %v", want, burnDown.ProjectedExhaustion)
+	}
+
+	// Idle days up to today lower the burn rate and push the projection out
+	later := ComputeBurnDown(entries, 2000, day(9))
+	if len(later.Days) != 9 || later.BurnRate != 60 {
+		t.Errorf("Expected 9 days at 60 minutes per day, got %d at %v", len(later.Days), later.BurnRate)
+	}
+}
+
+func TestComputeBurnDownExhaustedAndNoBudget(t *testing.T) {
+	entries := []*models.Entry{
+		{Duration: 300, CreatedAt: day(1)},
+		{Duration: 300, CreatedAt: day(2)},
+		{Duration: 300, CreatedAt: day(3)},
+	}
+
+	burnDown := ComputeBurnDown(entries, 500, day(3))
+	want := time.Date(2026, time.March, 2, 0, 0, 0, 0, time.UTC)
+	if !burnDown.Exhausted || burnDown.ProjectedExhaustion == nil || !burnDown.ProjectedExhaustion.Equal(want) {
+		t.Errorf("Expected the budget exhausted on %v, got %v", want, burnDown.ProjectedExhaustion)
+	}
+	if burnDown.RemainingMinutes != -400 {
+		t.Errorf("Expected 400 minutes over budget, got %d", burnDown.RemainingMinutes)
+	}
+
+	// Without a budget only the cumulative series is computed
+	noBudget := ComputeBurnDown(entries, 0, day(3))
+	if noBudget.ProjectedExhaustion != nil || noBudget.Exhausted || noBudget.Days[2].Cumulative != 900 {
+		t.Errorf("Expected cumulative time only, got %+v", noBudget)
+	}
+
+	empty := ComputeBurnDown(nil, 500, day(3))
+	if len(empty.Days) != 0 || empty.RemainingMinutes != 500 || empty.ProjectedExhaustion != nil {
+		t.Errorf("Expected an empty burn-down with the whole budget left, got %+v", empty)
+	}
+}
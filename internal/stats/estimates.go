@@ -0,0 +1,47 @@
+package stats
+
+import "github.com/techthos/clockwork/internal/models"
+
+// EstimateVariance returns how far an entry's actual time is off its estimate
+// Variance is actual minus estimate in minutes (positive = overrun) and ratio is
+// actual divided by estimate; ok is false for entries without an estimate.
+func EstimateVariance(entry *models.Entry) (variance int64, ratio float64, ok bool) {
+	if entry.EstimateMinutes <= 0 {
+		return 0, 0, false
+	}
+	return entry.Duration - entry.EstimateMinutes, float64(entry.Duration) / float64(entry.EstimateMinutes), true
+}
+
+// EstimateSummary compares estimated and actual time over entries with an estimate
+type EstimateSummary struct {
+	EntryCount      int     `json:"entry_count"` // Entries with an estimate
+	EstimateMinutes int64   `json:"estimate_minutes"`
+	ActualMinutes   int64   `json:"actual_minutes"`
+	VarianceMinutes int64   `json:"variance_minutes"` // ActualMinutes - EstimateMinutes
+	AverageRatio    float64 `json:"average_ratio"`    // Mean of actual/estimate per entry, 0 without estimates
+}
+
+// SummarizeEstimates compares estimates with actual time, ignoring entries without an estimate
+// AverageRatio weighs every entry equally, so one badly missed small task shows up
+// even when the total variance is small.
+func SummarizeEstimates(entries []*models.Entry) EstimateSummary {
+	var summary EstimateSummary
+	var ratioSum float64
+
+	for _, entry := range entries {
+		variance, ratio, ok := EstimateVariance(entry)
+		if !ok {
+			continue
+		}
+		summary.EntryCount++
+		summary.EstimateMinutes += entry.EstimateMinutes
+		summary.ActualMinutes += entry.Duration
+		summary.VarianceMinutes += variance
+		ratioSum += ratio
+	}
+
+	if summary.EntryCount > 0 {
+		summary.AverageRatio = ratioSum / float64(summary.EntryCount)
+	}
+	return summary
+}
@@ -0,0 +1,52 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func TestEstimateVariance(t *testing.T) {
+	variance, ratio, ok := EstimateVariance(&models.Entry{Duration: 90, EstimateMinutes: 60})
+	if !ok || variance != 30 || ratio != 1.5 {
+		t.Errorf("Expected +30m at 1.5, got %d at %.2f (ok=%v)", variance, ratio, ok)
+	}
+
+	variance, ratio, ok = EstimateVariance(&models.Entry{Duration: 45, EstimateMinutes: 60})
+	if !ok || variance != -15 || ratio != 0.75 {
+		t.Errorf("Expected -15m at 0.75, got %d at %.2f (ok=%v)", variance, ratio, ok)
+	}
+
+	if _, _, ok := EstimateVariance(&models.Entry{Duration: 45}); ok {
+		t.Error("Expected an entry without estimate to have no variance")
+	}
+}
+
+func TestSummarizeEstimates(t *testing.T) {
+	entries := []*models.Entry{
+		{Duration: 120, EstimateMinutes: 60},
+		{Duration: 30, EstimateMinutes: 60},
+		{Duration: 500},
+		{Duration: 60, EstimateMinutes: 60},
+	}
+
+	summary := SummarizeEstimates(entries)
+
+	if summary.EntryCount != 3 {
+		t.Errorf("Expected 3 estimated entries, got %d", summary.EntryCount)
+	}
+	if summary.EstimateMinutes != 180 || summary.ActualMinutes != 210 || summary.VarianceMinutes != 30 {
+		t.Errorf("Unexpected totals: %+v", summary)
+	}
+	// (2.0 + 0.5 + 1.0) / 3
+	if diff := summary.AverageRatio - 3.5/3; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("Expected average ratio %.4f, got %.4f", 3.5/3, summary.AverageRatio)
+	}
+}
+
+func TestSummarizeEstimatesWithoutEstimates(t *testing.T) {
+	summary := SummarizeEstimates([]*models.Entry{{Duration: 60}, {Duration: 30}})
+	if summary.EntryCount != 0 || summary.AverageRatio != 0 || summary.ActualMinutes != 0 {
+		t.Errorf("Expected an empty summary, got %+v", summary)
+	}
+}
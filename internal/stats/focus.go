@@ -0,0 +1,119 @@
+// Package stats provides derived statistics computed from entries without extra storage
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// Focus split categories
+const (
+	CategoryFocus    = "focus"
+	CategoryOverhead = "overhead"
+	CategoryOther    = "other"
+)
+
+// DefaultFocusMapping maps common tags to categories when no mapping is configured
+var DefaultFocusMapping = map[string]string{
+	"dev":         CategoryFocus,
+	"development": CategoryFocus,
+	"coding":      CategoryFocus,
+	"review":      CategoryFocus,
+	"meeting":     CategoryOverhead,
+	"admin":       CategoryOverhead,
+	"email":       CategoryOverhead,
+}
+
+// Split holds time per focus category
+type Split struct {
+	FocusMinutes    int64 `json:"focus_minutes"`
+	OverheadMinutes int64 `json:"overhead_minutes"`
+	OtherMinutes    int64 `json:"other_minutes"`
+	TotalMinutes    int64 `json:"total_minutes"`
+}
+
+// FocusPercent returns the share of focus time (0-100)
+func (s Split) FocusPercent() float64 {
+	return percent(s.FocusMinutes, s.TotalMinutes)
+}
+
+// OverheadPercent returns the share of overhead time (0-100)
+func (s Split) OverheadPercent() float64 {
+	return percent(s.OverheadMinutes, s.TotalMinutes)
+}
+
+// OtherPercent returns the share of unmapped time (0-100)
+func (s Split) OtherPercent() float64 {
+	return percent(s.OtherMinutes, s.TotalMinutes)
+}
+
+// FocusSplit splits entry time into focus, overhead, and other by tag mapping
+// The first mapped tag of an entry (tags are stored sorted) decides its category;
+// entries without a mapped tag count as other.
+func FocusSplit(entries []*models.Entry, mapping map[string]string) Split {
+	var split Split
+
+	for _, entry := range entries {
+		split.TotalMinutes += entry.Duration
+
+		switch categorize(entry, mapping) {
+		case CategoryFocus:
+			split.FocusMinutes += entry.Duration
+		case CategoryOverhead:
+			split.OverheadMinutes += entry.Duration
+		default:
+			split.OtherMinutes += entry.Duration
+		}
+	}
+
+	return split
+}
+
+// ParseFocusMapping parses a mapping in the form "dev=focus,meeting=overhead"
+// An empty string yields DefaultFocusMapping
+func ParseFocusMapping(input string) (map[string]string, error) {
+	if strings.TrimSpace(input) == "" {
+		return DefaultFocusMapping, nil
+	}
+
+	mapping := make(map[string]string)
+	for _, pair := range strings.Split(input, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		tag, category, ok := strings.Cut(pair, "=")
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		category = strings.ToLower(strings.TrimSpace(category))
+		if !ok || tag == "" {
+			return nil, fmt.Errorf("invalid mapping %q (use tag=category, e.g. 'meeting=overhead')", pair)
+		}
+		if category != CategoryFocus && category != CategoryOverhead {
+			return nil, fmt.Errorf("invalid category %q for tag %s (use '%s' or '%s')", category, tag, CategoryFocus, CategoryOverhead)
+		}
+
+		mapping[tag] = category
+	}
+
+	return mapping, nil
+}
+
+// categorize returns the focus category for an entry
+func categorize(entry *models.Entry, mapping map[string]string) string {
+	for _, tag := range entry.Tags {
+		if category, ok := mapping[tag]; ok {
+			return category
+		}
+	}
+	return CategoryOther
+}
+
+func percent(value, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(value) / float64(total) * 100
+}
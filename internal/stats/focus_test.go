@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func TestFocusSplit(t *testing.T) {
+	mapping := map[string]string{
+		"dev":     CategoryFocus,
+		"meeting": CategoryOverhead,
+		"admin":   CategoryOverhead,
+	}
+
+	entries := []*models.Entry{
+		{Duration: 300, Tags: []string{"dev"}},
+		{Duration: 60, Tags: []string{"meeting"}},
+		{Duration: 40, Tags: []string{"admin", "billing"}},
+		{Duration: 60, Tags: []string{"travel"}},
+		{Duration: 40},
+	}
+
+	split := FocusSplit(entries, mapping)
+
+	if split.TotalMinutes != 500 {
+		t.Errorf("Expected 500 total minutes, got %d", split.TotalMinutes)
+	}
+	if split.FocusMinutes != 300 || split.OverheadMinutes != 100 || split.OtherMinutes != 100 {
+		t.Errorf("Unexpected split: %+v", split)
+	}
+	if split.FocusPercent() != 60 || split.OverheadPercent() != 20 || split.OtherPercent() != 20 {
+		t.Errorf("Unexpected percentages: %.1f/%.1f/%.1f",
+			split.FocusPercent(), split.OverheadPercent(), split.OtherPercent())
+	}
+}
+
+func TestFocusSplitEmpty(t *testing.T) {
+	split := FocusSplit(nil, DefaultFocusMapping)
+	if split.TotalMinutes != 0 || split.FocusPercent() != 0 {
+		t.Errorf("Expected empty split, got %+v", split)
+	}
+}
+
+func TestParseFocusMapping(t *testing.T) {
+	mapping, err := ParseFocusMapping(" Dev=focus, standup = Overhead ")
+	if err != nil {
+		t.Fatalf("ParseFocusMapping() error = %v", err)
+	}
+	if mapping["dev"] != CategoryFocus || mapping["standup"] != CategoryOverhead || len(mapping) != 2 {
+		t.Errorf("Unexpected mapping: %v", mapping)
+	}
+
+	if mapping, _ := ParseFocusMapping(""); mapping["meeting"] != CategoryOverhead {
+		t.Error("Expected default mapping for empty input")
+	}
+
+	for _, input := range []string{"dev", "=focus", "dev=other", "dev=deep"} {
+		if _, err := ParseFocusMapping(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
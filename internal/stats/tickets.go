@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// DefaultTicketPattern matches Jira-style ticket IDs such as "PROJ-123"
+const DefaultTicketPattern = `[A-Z][A-Z0-9]+-\d+`
+
+// NoTicket labels time from entries without a ticket ID
+const NoTicket = "(none)"
+
+// TicketTotal holds the time attributed to one ticket
+type TicketTotal struct {
+	Ticket     string `json:"ticket"`
+	Minutes    int64  `json:"minutes"`
+	EntryCount int    `json:"entry_count"`
+}
+
+// ParseTicketPattern compiles a ticket ID pattern
+// An empty string yields DefaultTicketPattern
+func ParseTicketPattern(input string) (*regexp.Regexp, error) {
+	if strings.TrimSpace(input) == "" {
+		input = DefaultTicketPattern
+	}
+	pattern, err := regexp.Compile(input)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket pattern %q: %w", input, err)
+	}
+	return pattern, nil
+}
+
+// ExtractTickets returns the distinct ticket IDs in an entry's reference and message,
+// in order of first appearance (reference first)
+func ExtractTickets(entry *models.Entry, pattern *regexp.Regexp) []string {
+	var tickets []string
+	seen := make(map[string]bool)
+	for _, text := range []string{entry.Reference, entry.Message} {
+		for _, ticket := range pattern.FindAllString(text, -1) {
+			if !seen[ticket] {
+				seen[ticket] = true
+				tickets = append(tickets, ticket)
+			}
+		}
+	}
+	return tickets
+}
+
+// ByTicket sums entry time per ticket ID, most time first (ties by ticket, NoTicket last)
+// An entry mentioning several tickets counts in full towards each of them, so the
+// ticket totals can add up to more than the tracked time. Entries without a ticket
+// are grouped under NoTicket.
+func ByTicket(entries []*models.Entry, pattern *regexp.Regexp) []TicketTotal {
+	totals := make(map[string]*TicketTotal)
+	add := func(ticket string, minutes int64) {
+		total, ok := totals[ticket]
+		if !ok {
+			total = &TicketTotal{Ticket: ticket}
+			totals[ticket] = total
+		}
+		total.Minutes += minutes
+		total.EntryCount++
+	}
+
+	for _, entry := range entries {
+		tickets := ExtractTickets(entry, pattern)
+		if len(tickets) == 0 {
+			add(NoTicket, entry.Duration)
+			continue
+		}
+		for _, ticket := range tickets {
+			add(ticket, entry.Duration)
+		}
+	}
+
+	result := make([]TicketTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if (result[i].Ticket == NoTicket) != (result[j].Ticket == NoTicket) {
+			return result[j].Ticket == NoTicket
+		}
+		if result[i].Minutes != result[j].Minutes {
+			return result[i].Minutes > result[j].Minutes
+		}
+		return result[i].Ticket < result[j].Ticket
+	})
+
+	return result
+}
@@ -0,0 +1,71 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func TestExtractTickets(t *testing.T) {
+	pattern, _ := ParseTicketPattern("")
+
+	tests := []struct {
+		name  string
+		entry *models.Entry
+		want  []string
+	}{
+		{"no ticket", &models.Entry{Message: "Team meeting"}, nil},
+		{"one ticket", &models.Entry{Message: "Fix login (PROJ-12)"}, []string{"PROJ-12"}},
+		{"multiple tickets", &models.Entry{Message: "OPS-7: deploy PROJ-12 and PROJ-13"}, []string{"OPS-7", "PROJ-12", "PROJ-13"}},
+		{"duplicates counted once", &models.Entry{Message: "PROJ-12 follow-up for PROJ-12"}, []string{"PROJ-12"}},
+		{"reference first", &models.Entry{Reference: "OPS-1", Message: "Review PROJ-2"}, []string{"OPS-1", "PROJ-2"}},
+		{"lowercase ignored", &models.Entry{Message: "proj-12 is not a ticket"}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractTickets(tt.entry, pattern); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExtractTickets() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestByTicket(t *testing.T) {
+	pattern, _ := ParseTicketPattern("")
+
+	entries := []*models.Entry{
+		{Duration: 60, Message: "Fix login PROJ-1"},
+		{Duration: 30, Message: "Pair on PROJ-1 and PROJ-2"},
+		{Duration: 45, Message: "Standup"},
+		{Duration: 15, Reference: "PROJ-2", Message: "Review"},
+	}
+
+	want := []TicketTotal{
+		{Ticket: "PROJ-1", Minutes: 90, EntryCount: 2},
+		{Ticket: "PROJ-2", Minutes: 45, EntryCount: 2},
+		{Ticket: NoTicket, Minutes: 45, EntryCount: 1},
+	}
+	if got := ByTicket(entries, pattern); !reflect.DeepEqual(got, want) {
+		t.Errorf("ByTicket() = %+v, want %+v", got, want)
+	}
+
+	if got := ByTicket(nil, pattern); len(got) != 0 {
+		t.Errorf("ByTicket(nil) = %+v, want none", got)
+	}
+}
+
+func TestParseTicketPattern(t *testing.T) {
+	pattern, err := ParseTicketPattern(`#\d+`)
+	if err != nil {
+		t.Fatalf("ParseTicketPattern() error = %v", err)
+	}
+	if got := ExtractTickets(&models.Entry{Message: "Closes #42"}, pattern); !reflect.DeepEqual(got, []string{"#42"}) {
+		t.Errorf("Expected custom pattern to match '#42', got %v", got)
+	}
+
+	if _, err := ParseTicketPattern("PROJ-("); err == nil {
+		t.Error("Expected invalid pattern to fail")
+	}
+}
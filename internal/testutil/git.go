@@ -0,0 +1,51 @@
+// Package testutil holds test fixtures shared across packages
+package testutil
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// GitRepo is a temporary git repository for tests
+type GitRepo struct {
+	Dir string
+	t   testing.TB
+}
+
+// NewGitRepo initializes an empty git repository in a temporary directory
+// Skips the test when git is not installed.
+func NewGitRepo(t testing.TB) *GitRepo {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+
+	repo := &GitRepo{Dir: t.TempDir(), t: t}
+	repo.Run("init", "-q")
+	return repo
+}
+
+// Run runs git in the repository and returns its trimmed output
+func (r *GitRepo) Run(args ...string) string {
+	r.t.Helper()
+	return RunGit(r.t, r.Dir, args...)
+}
+
+// RunGit runs git in dir as a fixed test author and committer and returns its trimmed output
+// Fails the test when git exits with an error.
+func RunGit(t testing.TB, dir string, args ...string) string {
+	t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com",
+	)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %v\n%s", args, err, output)
+	}
+	return strings.TrimSpace(string(output))
+}
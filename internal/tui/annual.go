@@ -0,0 +1,185 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/export"
+)
+
+func (a *App) createAnnualView(projectID string, year int) tview.Primitive {
+	// Create text view for the summary
+	textView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+
+	// Create flex layout
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow)
+
+	// Header with title and instructions
+	header := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	header.SetBorderPadding(1, 1, 0, 0)
+
+	flex.AddItem(header, 4, 0, false)
+	flex.AddItem(textView, 0, 1, true)
+
+	// Resolve project names once for the breakdown and export
+	projectNames := make(map[string]string)
+	if projects, err := a.store.ListProjects(true); err == nil {
+		for _, project := range projects {
+			projectNames[project.ID] = project.Name
+		}
+	}
+
+	// Load and display the summary
+	loadSummary := func() {
+		textView.Clear()
+
+		scope := "All Projects"
+		if name, ok := projectNames[projectID]; ok {
+			scope = name
+		}
+		header.SetText(fmt.Sprintf("[::b]Annual Summary %d - %s[::-]\n", year, scope) +
+			"[gray]←/→: Change Year | x: Export Markdown | q: Back")
+
+		summary, err := a.store.GetAnnualSummary(year, projectID)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load annual summary: %v", err), nil)
+			return
+		}
+
+		var builder strings.Builder
+
+		if summary.EntryCount == 0 {
+			builder.WriteString(fmt.Sprintf("No entries recorded in %d\n", year))
+			textView.SetText(builder.String())
+			return
+		}
+
+		// Totals
+		builder.WriteString("[::b]Totals[::-]\n\n")
+		builder.WriteString(fmt.Sprintf("Total Time:          %s (%.2f hours)\n",
+			FormatDuration(summary.TotalMinutes), summary.TotalHours))
+		if amount := formatCurrencyAmounts(summary.Amounts); amount != "" {
+			if summary.UnpricedMinutes > 0 {
+				amount += fmt.Sprintf(" (%s without a rate)", FormatDuration(summary.UnpricedMinutes))
+			}
+			builder.WriteString(fmt.Sprintf("Billed Amount:       %s\n", amount))
+		}
+		builder.WriteString(fmt.Sprintf("Entry Count:         %d\n", summary.EntryCount))
+		builder.WriteString(fmt.Sprintf("[green]Invoiced:[::-]            %s - %s%s\n",
+			FormatDuration(summary.InvoicedMinutes),
+			FormatPercentage(float64(summary.InvoicedMinutes), float64(summary.TotalMinutes)),
+			amountSuffix(summary.InvoicedAmounts)))
+		builder.WriteString(fmt.Sprintf("[yellow]Outstanding:[::-]         %s - %s%s\n\n",
+			FormatDuration(summary.UninvoicedMinutes),
+			FormatPercentage(float64(summary.UninvoicedMinutes), float64(summary.TotalMinutes)),
+			amountSuffix(summary.OutstandingAmounts)))
+
+		// Monthly breakdown
+		builder.WriteString("[::b]Monthly Breakdown[::-]\n\n")
+		for _, month := range summary.Months {
+			builder.WriteString(fmt.Sprintf("%-12s %10s  %3d entries  [green]%s[::-] / [yellow]%s[::-]%s\n",
+				month.Month,
+				FormatDuration(month.TotalMinutes),
+				month.EntryCount,
+				FormatDuration(month.InvoicedMinutes),
+				FormatDuration(month.UninvoicedMinutes),
+				amountSuffix(month.Amounts)))
+		}
+
+		// Project breakdown
+		builder.WriteString("\n[::b]Project Breakdown[::-]\n\n")
+		projectIDs := make([]string, 0, len(summary.ProjectBreakdown))
+		for id := range summary.ProjectBreakdown {
+			projectIDs = append(projectIDs, id)
+		}
+		sort.Slice(projectIDs, func(i, j int) bool {
+			return summary.ProjectBreakdown[projectIDs[i]] > summary.ProjectBreakdown[projectIDs[j]]
+		})
+		for _, id := range projectIDs {
+			name, ok := projectNames[id]
+			if !ok {
+				name = "Unknown Project"
+			}
+			minutes := summary.ProjectBreakdown[id]
+			builder.WriteString(fmt.Sprintf("%s %s (%.2f hours) - %s%s\n",
+				PadRight(TruncateString(name, 30), 30),
+				FormatDuration(minutes),
+				float64(minutes)/60.0,
+				FormatPercentage(float64(minutes), float64(summary.TotalMinutes)),
+				amountSuffix(summary.ProjectAmounts[id])))
+		}
+
+		textView.SetText(builder.String())
+	}
+
+	// Export the current summary as Markdown into the working directory
+	exportMarkdown := func() {
+		summary, err := a.store.GetAnnualSummary(year, projectID)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load annual summary: %v", err), nil)
+			return
+		}
+
+		path, err := filepath.Abs(fmt.Sprintf("clockwork-annual-%d.md", year))
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to resolve export path: %v", err), nil)
+			return
+		}
+
+		if err := os.WriteFile(path, []byte(export.AnnualSummaryMarkdown(summary, projectNames)), 0644); err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to export summary: %v", err), nil)
+			return
+		}
+
+		a.ShowInfoModal(fmt.Sprintf("Annual summary exported to %s", path), nil)
+	}
+
+	// Set up keyboard shortcuts
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'q':
+			a.ShowStatsView(projectID, &FilterOptions{ProjectID: projectID})
+			return nil
+		case 'x':
+			exportMarkdown()
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyLeft:
+			year--
+			loadSummary()
+			return nil
+		case tcell.KeyRight:
+			year++
+			loadSummary()
+			return nil
+		case tcell.KeyCtrlC, tcell.KeyCtrlQ:
+			a.Stop()
+			return nil
+		}
+
+		return event
+	})
+
+	loadSummary()
+	return flex
+}
+
+// amountSuffix renders amounts as "  450.00 EUR" to follow a breakdown line, or "" when unpriced
+func amountSuffix(amounts map[string]float64) string {
+	if amount := formatCurrencyAmounts(amounts); amount != "" {
+		return "  " + amount
+	}
+	return ""
+}
@@ -1,64 +1,186 @@
 package tui
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/models"
 )
 
 // App represents the main TUI application
 type App struct {
-	app   *tview.Application
-	pages *tview.Pages
-	store *db.Store
+	app        *tview.Application
+	pages      *tview.Pages
+	store      *db.Store
+	monthBadge *tview.TextView // Global header with the current month's totals
+	statusBar  *tview.TextView // Global footer naming the open database
 
 	// Current state
-	currentProjectID string // Used when filtering entries by project
+	currentProjectID string    // Used when filtering entries by project
+	lastDeletion     *deletion // Most recent deletion, undoable until the next page switch
+}
+
+// deletion captures what the last TUI delete removed, so it can be restored by ID
+type deletion struct {
+	entry   *models.Entry   // Deleted entry, nil for a project deletion
+	project *models.Project // Deleted project, nil for an entry deletion
+	entries []*models.Entry // Entries deleted along with project
 }
 
 // New creates a new TUI application instance
 func New(store *db.Store) *App {
 	tuiApp := &App{
-		app:   tview.NewApplication(),
-		pages: tview.NewPages(),
-		store: store,
+		app:        tview.NewApplication(),
+		pages:      tview.NewPages(),
+		store:      store,
+		monthBadge: tview.NewTextView().SetDynamicColors(true).SetTextAlign(tview.AlignRight),
+		statusBar:  tview.NewTextView().SetDynamicColors(true),
 	}
+	tuiApp.statusBar.SetText(statusBarText(shortenHome(store.Path()), store.ReadOnly()))
 
-	// Set up the application
-	tuiApp.app.SetRoot(tuiApp.pages, true)
+	// Set up the application: global header above the page stack, status bar below it
+	root := tview.NewFlex().
+		SetDirection(tview.FlexRow).
+		AddItem(tuiApp.monthBadge, 1, 0, false).
+		AddItem(tuiApp.pages, 0, 1, true).
+		AddItem(tuiApp.statusBar, 1, 0, false)
+	tuiApp.app.SetRoot(root, true)
 
 	return tuiApp
 }
 
+// statusBarText describes the open database for the status bar: its profile (the file name
+// without extension), its path, and a read-only marker, so edits never go to the wrong one
+func statusBarText(path string, readOnly bool) string {
+	profile := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	text := "[gray]Profile:[-] " + tview.Escape(profile) + " [gray]|[-] " + tview.Escape(path)
+	if readOnly {
+		text += " [gray]|[-] [red::b]READ-ONLY[-::-]"
+	}
+	return text
+}
+
+// shortenHome replaces the user's home directory at the start of path with ~
+func shortenHome(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
 // Run starts the TUI application
 func (a *App) Run() error {
 	// Show the projects view as the default page
 	a.ShowProjectsView()
+	if isFirstRun(a.store) {
+		a.showOnboarding()
+	} else {
+		a.notifyRecoveredTimers()
+	}
 	return a.app.Run()
 }
 
 // Stop stops the TUI application
 func (a *App) Stop() {
+	a.lastDeletion = nil
 	a.app.Stop()
 }
 
+// switchToPage replaces and shows a page; navigating away forgets the undoable deletion
+func (a *App) switchToPage(name string, view tview.Primitive) {
+	a.lastDeletion = nil
+	a.pages.AddAndSwitchToPage(name, view, true)
+}
+
+// undoNoticeTimeout is how long the restore confirmation stays up
+const undoNoticeTimeout = 3 * time.Second
+
+// undoLastDeletion restores the most recently deleted entry or project under its original ID
+func (a *App) undoLastDeletion(onComplete func()) {
+	last := a.lastDeletion
+	if last == nil {
+		a.ShowInfoModal("Nothing to undo", nil)
+		return
+	}
+
+	var err error
+	var message string
+	if last.project != nil {
+		err = a.store.RestoreProject(last.project, last.entries)
+		message = fmt.Sprintf("Restored project '%s' with %d entries", last.project.Name, len(last.entries))
+	} else {
+		err = a.store.RestoreEntry(last.entry)
+		message = fmt.Sprintf("Restored entry from %s", FormatDate(last.entry.CreatedAt))
+	}
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to undo: %v", err), nil)
+		return
+	}
+
+	a.lastDeletion = nil
+	if onComplete != nil {
+		onComplete()
+	}
+	a.ShowTransientInfoModal(message, undoNoticeTimeout)
+}
+
 // ShowProjectsView displays the projects list view
 func (a *App) ShowProjectsView() {
 	view := a.createProjectsView()
-	a.pages.AddAndSwitchToPage("projects", view, true)
+	a.switchToPage("projects", view)
 }
 
 // ShowEntriesView displays the entries view for a specific project
 func (a *App) ShowEntriesView(projectID string) {
 	a.currentProjectID = projectID
 	view := a.createEntriesView(projectID)
-	a.pages.AddAndSwitchToPage("entries", view, true)
+	a.switchToPage("entries", view)
 }
 
 // ShowStatsView displays the statistics view
 func (a *App) ShowStatsView(projectID string, filterOptions *FilterOptions) {
 	view := a.createStatsView(projectID, filterOptions)
-	a.pages.AddAndSwitchToPage("stats", view, true)
+	a.switchToPage("stats", view)
+}
+
+// ShowAnnualView displays the annual summary for a year
+func (a *App) ShowAnnualView(projectID string, year int) {
+	view := a.createAnnualView(projectID, year)
+	a.switchToPage("annual", view)
+}
+
+// ShowBurnDownView displays a project's logged time against its budget
+func (a *App) ShowBurnDownView(projectID string) {
+	view := a.createBurnDownView(projectID)
+	a.switchToPage("burndown", view)
+}
+
+// ShowTicketsView displays time per ticket ID for a stats filter
+func (a *App) ShowTicketsView(filterOptions *FilterOptions) {
+	view := a.createTicketsView(filterOptions)
+	a.switchToPage("tickets", view)
+}
+
+// ShowProjectHistoryView displays the change timeline for a project
+func (a *App) ShowProjectHistoryView(project *models.Project) {
+	view := a.createProjectHistoryView(project)
+	a.switchToPage("history", view)
+}
+
+// ShowReviewQueueView displays entries that fail the required-fields policy
+func (a *App) ShowReviewQueueView() {
+	view := a.createReviewQueueView()
+	a.switchToPage("review", view)
 }
 
 // ShowModal displays a modal on top of the current page
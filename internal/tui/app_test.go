@@ -0,0 +1,26 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStatusBarText(t *testing.T) {
+	text := statusBarText("~/.local/clockwork/work.db", false)
+	if !strings.Contains(text, "work") || !strings.Contains(text, "~/.local/clockwork/work.db") {
+		t.Errorf("Expected profile and path in %q", text)
+	}
+	if strings.Contains(text, "READ-ONLY") {
+		t.Errorf("Expected no read-only marker in %q", text)
+	}
+
+	text = statusBarText("/srv/replica.db", true)
+	if !strings.Contains(text, "replica") || !strings.Contains(text, "READ-ONLY") {
+		t.Errorf("Expected profile and read-only marker in %q", text)
+	}
+
+	// Brackets in a path must not be taken for color tags
+	if text := statusBarText("/tmp/[x]/a.db", false); !strings.Contains(text, "[x[]") {
+		t.Errorf("Expected escaped brackets in %q", text)
+	}
+}
@@ -0,0 +1,150 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/stats"
+)
+
+// burnDownBarWidth is the width of the longest bar in the burn-down chart
+const burnDownBarWidth = 40
+
+func (a *App) createBurnDownView(projectID string) tview.Primitive {
+	// Create text view for the chart
+	textView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+
+	// Create flex layout
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow)
+
+	// Header with title and instructions
+	header := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	header.SetBorderPadding(1, 1, 0, 0)
+
+	flex.AddItem(header, 4, 0, false)
+	flex.AddItem(textView, 0, 1, true)
+
+	// Load and draw the burn-down
+	loadBurnDown := func() {
+		textView.Clear()
+
+		project, err := a.store.GetProject(projectID)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load project: %v", err), nil)
+			return
+		}
+		header.SetText(fmt.Sprintf("[::b]Budget Burn-Down - %s[::-]\n", project.Name) +
+			"[gray]r: Refresh | q: Back")
+
+		entries, err := a.store.ListEntries(projectID)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load entries: %v", err), nil)
+			return
+		}
+
+		textView.SetText(renderBurnDown(stats.ComputeBurnDown(entries, project.BudgetMinutes, time.Now())))
+		textView.ScrollToBeginning()
+	}
+
+	// Set up keyboard shortcuts
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'q':
+			a.ShowStatsView(projectID, &FilterOptions{ProjectID: projectID})
+			return nil
+		case 'r':
+			loadBurnDown()
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyCtrlC, tcell.KeyCtrlQ:
+			a.Stop()
+			return nil
+		}
+
+		return event
+	})
+
+	loadBurnDown()
+	return flex
+}
+
+// renderBurnDown draws the summary and one cumulative bar per day, with the budget marked
+func renderBurnDown(burnDown *stats.BurnDown) string {
+	var builder strings.Builder
+
+	if len(burnDown.Days) == 0 {
+		builder.WriteString("No entries logged yet\n")
+		if burnDown.BudgetMinutes > 0 {
+			builder.WriteString(fmt.Sprintf("\nBudget:              %s\n", FormatDuration(burnDown.BudgetMinutes)))
+		}
+		return builder.String()
+	}
+
+	// Summary
+	builder.WriteString("[::b]Summary[::-]\n\n")
+	builder.WriteString(fmt.Sprintf("Logged:              %s (%.2f hours)\n",
+		FormatDuration(burnDown.TotalMinutes), float64(burnDown.TotalMinutes)/60.0))
+	builder.WriteString(fmt.Sprintf("Burn Rate:           %s per day over %d days\n",
+		FormatDuration(int64(burnDown.BurnRate+0.5)), len(burnDown.Days)))
+	if burnDown.BudgetMinutes > 0 {
+		builder.WriteString(fmt.Sprintf("Budget:              %s - %s used\n",
+			FormatDuration(burnDown.BudgetMinutes),
+			FormatPercentage(float64(burnDown.TotalMinutes), float64(burnDown.BudgetMinutes))))
+		switch {
+		case burnDown.Exhausted:
+			builder.WriteString(fmt.Sprintf("[red]Exhausted:[::-]           %s, over by %s\n",
+				burnDown.ProjectedExhaustion.Format("2006-01-02"), FormatDuration(-burnDown.RemainingMinutes)))
+		case burnDown.ProjectedExhaustion != nil:
+			builder.WriteString(fmt.Sprintf("Remaining:           %s\n", FormatDuration(burnDown.RemainingMinutes)))
+			builder.WriteString(fmt.Sprintf("[yellow]Projected Exhaustion:[::-] %s\n", burnDown.ProjectedExhaustion.Format("2006-01-02")))
+		default:
+			builder.WriteString(fmt.Sprintf("Remaining:           %s\n", FormatDuration(burnDown.RemainingMinutes)))
+		}
+	} else {
+		builder.WriteString("[gray]No budget set; showing cumulative time only[::-]\n")
+	}
+
+	// Chart, scaled so the budget or the total (whichever is larger) fills the width
+	scale := burnDown.TotalMinutes
+	if burnDown.BudgetMinutes > scale {
+		scale = burnDown.BudgetMinutes
+	}
+	budgetColumn := -1
+	if burnDown.BudgetMinutes > 0 {
+		budgetColumn = int(burnDown.BudgetMinutes * burnDownBarWidth / scale)
+	}
+
+	builder.WriteString("\n[::b]Cumulative Time[::-]\n\n")
+	for _, day := range burnDown.Days {
+		filled := int(day.Cumulative * burnDownBarWidth / scale)
+
+		var bar strings.Builder
+		for column := 0; column <= burnDownBarWidth; column++ {
+			switch {
+			case column == budgetColumn:
+				bar.WriteString("[red]│[::-]")
+			case column < filled && budgetColumn >= 0 && column > budgetColumn:
+				bar.WriteString("[red]█[::-]")
+			case column < filled:
+				bar.WriteString("[green]█[::-]")
+			default:
+				bar.WriteString(" ")
+			}
+		}
+
+		builder.WriteString(fmt.Sprintf("%s %s %s\n",
+			day.Date.Format("Mon 2006-01-02"), bar.String(), FormatDuration(day.Cumulative)))
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,248 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/git"
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/utils"
+)
+
+// catchUpProposal is a suggested entry for a project whose HEAD is ahead of its baseline
+type catchUpProposal struct {
+	Project  *models.Project
+	Commits  []models.CommitInfo // New commits left after ignore rules, newest first
+	HeadHash string              // Stored on the entry so it becomes the next baseline
+	Message  string
+	Duration int64  // Estimated minutes
+	Warning  string // Set when the baseline was recovered from the reflog
+}
+
+// proposeCatchUp builds the entry proposal for the commits between baseline and HEAD
+// Returns nil when there is nothing to catch up (no baseline, HEAD not ahead, or all commits ignored)
+func proposeCatchUp(project *models.Project, baseline string, opts git.SummarizeOptions) (*catchUpProposal, error) {
+	if baseline == "" {
+		return nil, nil
+	}
+
+	// Refuse to aggregate across unrelated histories (e.g. repo path repointed) or from a
+	// missing baseline; one lost to a reset but still in the reflog is accepted
+	warning, err := git.CheckBaseline(project.GitRepoPath, baseline)
+	if err != nil {
+		return nil, err
+	}
+
+	commitAuthor, err := git.CommitAuthor(project.GitRepoPath, "", project.OwnCommitsOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	// Estimate with the project's duration method
+	opts.Strategy, err = git.GetStrategy(project.DurationMethod)
+	if err != nil {
+		return nil, fmt.Errorf("invalid duration method: %w", err)
+	}
+
+	commits, err := git.GetCommitsSinceWithOptions(project.GitRepoPath, baseline, git.GetCommitsSinceOptions{Author: commitAuthor, CountLines: opts.CountLines()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	// HEAD rather than the newest listed commit, so ignored and other authors' commits still advance the baseline
+	headHash, err := git.GetLatestCommitHash(project.GitRepoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Repo-local .clockworkignore rules extend the configured exclusions
+	ignoreRules, err := git.LoadIgnore(project.GitRepoPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %s: %w", git.IgnoreFile, err)
+	}
+	opts.ExcludePatterns = append(append([]string{}, opts.ExcludePatterns...), ignoreRules.Subjects...)
+	commits, err = git.DropIgnoredPaths(project.GitRepoPath, commits, ignoreRules)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply %s: %w", git.IgnoreFile, err)
+	}
+	if len(commits) == 0 {
+		return nil, nil
+	}
+
+	message, duration := git.SummarizeCommits(commits, opts)
+
+	return &catchUpProposal{
+		Project:  project,
+		Commits:  commits,
+		HeadHash: headHash,
+		Message:  message,
+		Duration: duration,
+		Warning:  warning,
+	}, nil
+}
+
+// catchUpProposals returns a proposal for every git project with unlogged commits
+func (a *App) catchUpProposals() ([]*catchUpProposal, error) {
+	projects, err := a.store.ListProjects(false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load projects: %w", err)
+	}
+
+	opts, err := a.store.GetSummarizeOptions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load settings: %w", err)
+	}
+
+	var proposals []*catchUpProposal
+	for _, project := range projects {
+		if project.GitRepoPath == "" {
+			continue
+		}
+
+		baseline, err := a.store.GetLastCommitHash(project.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get last commit hash for %s: %w", project.Name, err)
+		}
+
+		proposal, err := proposeCatchUp(project, baseline, opts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", project.Name, err)
+		}
+		if proposal != nil {
+			proposals = append(proposals, proposal)
+		}
+	}
+
+	return proposals, nil
+}
+
+// formatCommitList renders commits as short hash and subject lines for display
+func formatCommitList(commits []models.CommitInfo) string {
+	var builder strings.Builder
+	for _, commit := range commits {
+		builder.WriteString(fmt.Sprintf("[yellow]%s[-] %s\n", git.ShortHash(commit.Hash, 0), tview.Escape(commit.Message)))
+	}
+	return builder.String()
+}
+
+// ShowCatchUpWizard steps through every project with unlogged commits, one entry form each
+func (a *App) ShowCatchUpWizard(onComplete func()) {
+	proposals, err := a.catchUpProposals()
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to check projects: %v", err), nil)
+		return
+	}
+
+	if len(proposals) == 0 {
+		a.ShowInfoModal("All projects are caught up", nil)
+		return
+	}
+
+	created := 0
+	var showStep func(index int)
+
+	finish := func() {
+		a.HideModal("catch_up_wizard")
+		a.ShowInfoModal(fmt.Sprintf("Caught up %d of %d projects", created, len(proposals)), nil)
+		if onComplete != nil {
+			onComplete()
+		}
+	}
+
+	next := func(index int) {
+		if index+1 < len(proposals) {
+			showStep(index + 1)
+			return
+		}
+		finish()
+	}
+
+	showStep = func(index int) {
+		proposal := proposals[index]
+		form := tview.NewForm()
+
+		durationStr := FormatDuration(proposal.Duration)
+		message := proposal.Message
+
+		form.AddTextView("Commits", formatCommitList(proposal.Commits), 60, 6, true, true)
+
+		form.AddInputField("Duration", durationStr, 20, nil, func(text string) {
+			durationStr = text
+		})
+
+		form.AddTextArea("Message", message, 60, 5, 0, func(text string) {
+			message = text
+		})
+
+		form.AddButton("Create", func() {
+			duration, err := utils.ParseDuration(durationStr)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Invalid duration: %v", err), nil)
+				return
+			}
+
+			_, err = a.store.CreateEntryWithOptions(
+				proposal.Project.ID,
+				duration,
+				message,
+				proposal.HeadHash,
+				false,
+				time.Now(),
+				db.EntryOptions{Mode: models.EntryModeGit},
+			)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to create entry: %v", err), nil)
+				return
+			}
+
+			created++
+			next(index)
+		})
+
+		form.AddButton("Skip", func() {
+			next(index)
+		})
+
+		form.AddButton("Cancel", func() {
+			finish()
+		})
+
+		title := fmt.Sprintf("Catch Up (%d/%d) - %s", index+1, len(proposals), proposal.Project.Name)
+		if proposal.Warning != "" {
+			title += " - baseline recovered from reflog"
+		}
+
+		form.SetBorder(true).
+			SetTitle(title).
+			SetTitleAlign(tview.AlignLeft).
+			SetBorderColor(ColorPrimary)
+
+		form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+			if event.Key() == tcell.KeyEsc {
+				finish()
+				return nil
+			}
+			return event
+		})
+
+		// Center the form
+		modal := tview.NewFlex().
+			AddItem(nil, 0, 1, false).
+			AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+				AddItem(nil, 0, 1, false).
+				AddItem(form, 20, 1, true).
+				AddItem(nil, 0, 1, false), 80, 1, true).
+			AddItem(nil, 0, 1, false)
+
+		a.ShowModal("catch_up_wizard", modal)
+	}
+
+	showStep(0)
+}
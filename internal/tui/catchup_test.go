@@ -0,0 +1,93 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/techthos/clockwork/internal/git"
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/testutil"
+)
+
+func TestProposeCatchUp(t *testing.T) {
+	repo := testutil.NewGitRepo(t)
+	dir, run := repo.Dir, repo.Run
+	project := &models.Project{ID: "p1", Name: "Test", GitRepoPath: dir}
+
+	run("commit", "-q", "--allow-empty", "-m", "Initial commit", "--date", "2026-10-01T09:00:00")
+	baseline := run("rev-parse", "HEAD")
+	run("commit", "-q", "--allow-empty", "-m", "Add feature", "--date", "2026-10-01T10:00:00")
+	run("commit", "-q", "--allow-empty", "-m", "fixup! Add feature", "--date", "2026-10-01T10:30:00")
+	run("commit", "-q", "--allow-empty", "-m", "Write docs", "--date", "2026-10-01T11:00:00")
+	head := run("rev-parse", "HEAD")
+
+	opts := git.SummarizeOptions{ExcludePatterns: git.DefaultExcludePatterns}
+	proposal, err := proposeCatchUp(project, baseline, opts)
+	if err != nil {
+		t.Fatalf("proposeCatchUp() error = %v", err)
+	}
+	if proposal == nil {
+		t.Fatal("proposeCatchUp() = nil, want a proposal")
+	}
+
+	if proposal.HeadHash != head {
+		t.Errorf("HeadHash = %q, want %q", proposal.HeadHash, head)
+	}
+
+	var subjects []string
+	for _, commit := range proposal.Commits {
+		subjects = append(subjects, commit.Message)
+	}
+	wantSubjects := []string{"Write docs", "fixup! Add feature", "Add feature"}
+	if strings.Join(subjects, "|") != strings.Join(wantSubjects, "|") {
+		t.Errorf("Commits = %v, want %v", subjects, wantSubjects)
+	}
+
+	// Span strategy: 10:00 to 11:00 plus the 30 minute buffer
+	if proposal.Duration != 90 {
+		t.Errorf("Duration = %d, want 90", proposal.Duration)
+	}
+	if !strings.Contains(proposal.Message, "Write docs") || strings.Contains(proposal.Message, "fixup!") {
+		t.Errorf("Message = %q, want fixup commits left out", proposal.Message)
+	}
+}
+
+func TestProposeCatchUpNothingNew(t *testing.T) {
+	repo := testutil.NewGitRepo(t)
+	dir, run := repo.Dir, repo.Run
+	project := &models.Project{ID: "p1", Name: "Test", GitRepoPath: dir}
+
+	run("commit", "-q", "--allow-empty", "-m", "Initial commit")
+	head := run("rev-parse", "HEAD")
+
+	tests := []struct {
+		name     string
+		baseline string
+	}{
+		{"baseline at HEAD", head},
+		{"no baseline", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proposal, err := proposeCatchUp(project, tt.baseline, git.SummarizeOptions{})
+			if err != nil {
+				t.Fatalf("proposeCatchUp() error = %v", err)
+			}
+			if proposal != nil {
+				t.Errorf("proposeCatchUp() = %+v, want nil", proposal)
+			}
+		})
+	}
+}
+
+func TestProposeCatchUpUnknownBaseline(t *testing.T) {
+	repo := testutil.NewGitRepo(t)
+	project := &models.Project{ID: "p1", Name: "Test", GitRepoPath: repo.Dir}
+	repo.Run("commit", "-q", "--allow-empty", "-m", "Initial commit")
+
+	// A baseline the repo doesn't have must not silently restart from HEAD
+	if _, err := proposeCatchUp(project, strings.Repeat("0", 40), git.SummarizeOptions{}); err == nil {
+		t.Error("Expected an error for a baseline missing from the repo")
+	}
+}
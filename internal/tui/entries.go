@@ -3,11 +3,14 @@ package tui
 import (
 	"fmt"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
 	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/utils"
 )
 
 // FilterOptions holds the current filter state for entries
@@ -18,6 +21,17 @@ type FilterOptions struct {
 	InvoicedFilter *bool // nil = all, true = invoiced only, false = uninvoiced only
 }
 
+// entriesPageSize is how many entries the entries view loads at a time
+const entriesPageSize = 100
+
+// sortKeyLabels names the entries view's sort orders, in the order of keys 1-4
+var sortKeyLabels = map[string]string{
+	SortByDate:     "date",
+	SortByDuration: "duration",
+	SortByProject:  "project",
+	SortByInvoiced: "invoiced",
+}
+
 func (a *App) createEntriesView(projectID string) tview.Primitive {
 	// Initialize filter options
 	filterOptions := &FilterOptions{
@@ -39,26 +53,39 @@ func (a *App) createEntriesView(projectID string) tview.Primitive {
 	flex := tview.NewFlex().
 		SetDirection(tview.FlexRow)
 
-	// Get project name for header
-	projectName := "All Projects"
-	if projectID != "" {
-		if project, err := a.store.GetProject(projectID); err == nil {
-			projectName = project.Name
-		}
-	}
-
 	// Header with title and instructions
 	header := tview.NewTextView().
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
-	header.SetText(fmt.Sprintf("[::b]Entries - %s[::-]\n", projectName) +
-		"[gray]n: New | e: Edit | d: Delete | i: Toggle Invoiced | f: Filter | s: Stats | q: Back")
 	header.SetBorderPadding(1, 1, 0, 0)
 
+	// Show the current project's name in the header
+	updateHeader := func() {
+		projectName := "All Projects"
+		if projectID != "" {
+			if project, err := a.store.GetProject(projectID); err == nil {
+				projectName = project.Name
+			}
+		}
+		header.SetText(fmt.Sprintf("[::b]Entries - %s[::-]\n", projectName) +
+			"[gray]n: New | e: Edit | d: Delete | U: Undo Delete | i: Toggle Invoiced | I: Invoice Filtered | l: Lock | P: Pin | a: Needs Adjustment | r: Reopen | g: Tag Filtered | D: Delete Filtered | f: Filter | o/1-4: Sort (date, duration, project, invoiced) | PgUp/PgDn: Page | u: Units | x: Export | s: Stats | t: Start/Stop Timer | p: Pause | T: Discard Timer | Tab/Shift+Tab: Next/Prev Project | q: Back")
+	}
+	updateHeader()
+
 	flex.AddItem(header, 4, 0, false)
 	flex.AddItem(table, 0, 1, true)
 	flex.AddItem(summaryView, 3, 0, false)
 
+	// Sort by date unless changed with o or 1-4; the store sorts and pages
+	sortKey := SortByDate
+	offset := 0
+
+	// Duration display mode (clock or decimal hours), persisted in settings
+	durationDisplay, err := a.store.GetSettingOrDefault(db.SettingDurationDisplay, utils.DisplayClock)
+	if err != nil {
+		durationDisplay = utils.DisplayClock
+	}
+
 	// Load and display entries
 	loadEntries := func() {
 		// Remember currently selected entry ID before clearing
@@ -73,21 +100,27 @@ func (a *App) createEntriesView(projectID string) tview.Primitive {
 
 		table.Clear()
 
-		entries, err := a.store.ListEntriesFiltered(
-			filterOptions.ProjectID,
-			filterOptions.StartDate,
-			filterOptions.EndDate,
-			filterOptions.InvoicedFilter,
-		)
+		formatDuration := durationFormatter(durationDisplay)
+
+		page, err := a.queryViewPage(*filterOptions, sortKey, offset, entriesPageSize)
+		if err == nil && len(page.Entries) == 0 && offset > 0 {
+			// Deleting the last entries of the last page leaves it empty; step back
+			offset = lastPageOffset(page.Total, entriesPageSize)
+			page, err = a.queryViewPage(*filterOptions, sortKey, offset, entriesPageSize)
+		}
 		if err != nil {
 			a.ShowErrorModal(fmt.Sprintf("Failed to load entries: %v", err), nil)
 			return
 		}
+		entries := page.Entries
 
-		// Sort entries by date (newest first)
-		sort.Slice(entries, func(i, j int) bool {
-			return entries[i].CreatedAt.After(entries[j].CreatedAt)
-		})
+		// Rows double-logging the same author's time are highlighted
+		overlaps, err := a.store.DetectOverlaps(filterOptions.ProjectID)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to check overlaps: %v", err), nil)
+			return
+		}
+		overlapping := db.OverlappingEntryIDs(overlaps)
 
 		// Set table headers
 		table.SetCell(0, 0, tview.NewTableCell("Date").
@@ -107,11 +140,6 @@ func (a *App) createEntriesView(projectID string) tview.Primitive {
 			SetAlign(tview.AlignCenter).
 			SetSelectable(false))
 
-		// Calculate summary
-		var totalMinutes int64
-		var invoicedMinutes int64
-		var uninvoicedMinutes int64
-
 		// Track which row contains the previously selected entry
 		rowToSelect := 1
 
@@ -119,14 +147,6 @@ func (a *App) createEntriesView(projectID string) tview.Primitive {
 		for i, entry := range entries {
 			row := i + 1
 
-			// Update summary
-			totalMinutes += entry.Duration
-			if entry.Invoiced {
-				invoicedMinutes += entry.Duration
-			} else {
-				uninvoicedMinutes += entry.Duration
-			}
-
 			// Check if this is the previously selected entry
 			if selectedEntryID != "" && entry.ID == selectedEntryID {
 				rowToSelect = row
@@ -139,25 +159,43 @@ func (a *App) createEntriesView(projectID string) tview.Primitive {
 				invoicedText = "✓"
 				invoicedColor = ColorInvoiced
 			}
+			if entry.Locked {
+				invoicedText += " 🔒"
+			}
+			if entry.Pinned {
+				invoicedText += " 📌"
+			}
+			if entry.NeedsAdjustment {
+				invoicedText += " ⚠"
+			}
+
+			rowColor := ColorTableText
+			if overlapping[entry.ID] {
+				rowColor = ColorError
+			}
 
 			table.SetCell(row, 0, tview.NewTableCell(FormatDate(entry.CreatedAt)).
-				SetTextColor(ColorTableText).
+				SetTextColor(rowColor).
 				SetReference(entry))
-			table.SetCell(row, 1, tview.NewTableCell(FormatDuration(entry.Duration)).
-				SetTextColor(ColorTableText).
+			table.SetCell(row, 1, tview.NewTableCell(formatDuration(entry.Duration)).
+				SetTextColor(rowColor).
 				SetAlign(tview.AlignRight))
 			table.SetCell(row, 2, tview.NewTableCell(TruncateString(entry.Message, 60)).
-				SetTextColor(ColorTableText))
+				SetTextColor(rowColor))
 			table.SetCell(row, 3, tview.NewTableCell(invoicedText).
 				SetTextColor(invoicedColor).
 				SetAlign(tview.AlignCenter))
 		}
 
-		// Update summary
+		// Update summary; totals cover every page
 		summaryText := fmt.Sprintf("[::b]Total: %s[::-] (%d entries) | ",
-			FormatDuration(totalMinutes), len(entries))
+			formatDuration(page.TotalMinutes), page.Total)
 		summaryText += fmt.Sprintf("[green]Invoiced: %s[::-] | [yellow]Uninvoiced: %s[::-]",
-			FormatDuration(invoicedMinutes), FormatDuration(uninvoicedMinutes))
+			formatDuration(page.InvoicedMinutes), formatDuration(page.TotalMinutes-page.InvoicedMinutes))
+		if len(overlaps) > 0 {
+			summaryText += fmt.Sprintf(" | [red]%d overlapping pairs[::-]", len(overlaps))
+		}
+		summaryText += fmt.Sprintf(" | [gray]%s, sorted by %s[::-]", pageLabel(offset, page.Total, entriesPageSize), sortKeyLabels[sortKey])
 
 		summaryView.SetText(summaryText)
 
@@ -172,6 +210,8 @@ func (a *App) createEntriesView(projectID string) tview.Primitive {
 		if len(entries) > 0 {
 			table.Select(rowToSelect, 0)
 		}
+
+		a.refreshMonthBadge()
 	}
 
 	// Set up keyboard shortcuts
@@ -183,6 +223,9 @@ func (a *App) createEntriesView(projectID string) tview.Primitive {
 		case 'n':
 			a.ShowEntryForm(nil, projectID, loadEntries)
 			return nil
+		case 'U':
+			a.undoLastDeletion(loadEntries)
+			return nil
 		case 'e':
 			row, _ := table.GetSelection()
 			if row > 0 {
@@ -210,15 +253,128 @@ func (a *App) createEntriesView(projectID string) tview.Primitive {
 				}
 			}
 			return nil
+		case 'l':
+			row, _ := table.GetSelection()
+			if row > 0 {
+				cell := table.GetCell(row, 0)
+				if entry, ok := cell.Reference.(*models.Entry); ok {
+					a.toggleLocked(entry, loadEntries)
+				}
+			}
+			return nil
+		case 'P':
+			row, _ := table.GetSelection()
+			if row > 0 {
+				cell := table.GetCell(row, 0)
+				if entry, ok := cell.Reference.(*models.Entry); ok {
+					a.togglePinned(entry, loadEntries)
+				}
+			}
+			return nil
+		case 'a':
+			row, _ := table.GetSelection()
+			if row > 0 {
+				cell := table.GetCell(row, 0)
+				if entry, ok := cell.Reference.(*models.Entry); ok {
+					a.toggleAdjustment(entry, loadEntries)
+				}
+			}
+			return nil
+		case 'r':
+			row, _ := table.GetSelection()
+			if row > 0 {
+				cell := table.GetCell(row, 0)
+				if entry, ok := cell.Reference.(*models.Entry); ok {
+					a.showReopenModal(entry, loadEntries)
+				}
+			}
+			return nil
+		case 'g':
+			a.showBulkTagModal(filterOptions, loadEntries)
+			return nil
+		case 'D':
+			a.confirmBulkDelete(filterOptions, loadEntries)
+			return nil
+		case 'I':
+			a.confirmMarkInvoiced(filterOptions, loadEntries)
+			return nil
 		case 'f':
-			a.ShowFilterModal(filterOptions, loadEntries)
+			a.ShowFilterModal(filterOptions, func() {
+				offset = 0
+				loadEntries()
+			})
+			return nil
+		case 'o':
+			sortKey = nextSortKey(sortKey)
+			offset = 0
+			loadEntries()
+			return nil
+		case '1', '2', '3', '4':
+			sortKey = db.SortKeys[event.Rune()-'1']
+			offset = 0
+			loadEntries()
+			return nil
+		case 'x':
+			a.showExportModal(*filterOptions, sortKey)
+			return nil
+		case 'u':
+			if durationDisplay == utils.DisplayDecimal {
+				durationDisplay = utils.DisplayClock
+			} else {
+				durationDisplay = utils.DisplayDecimal
+			}
+			if err := a.store.SetSetting(db.SettingDurationDisplay, durationDisplay); err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to save setting: %v", err), nil)
+			}
+			loadEntries()
 			return nil
 		case 's':
 			a.ShowStatsView(projectID, filterOptions)
 			return nil
+		case 't', 'p', 'T':
+			if projectID == "" {
+				a.ShowErrorModal("Timers require a project; open entries from the projects view", nil)
+				return nil
+			}
+			switch event.Rune() {
+			case 't':
+				a.toggleTimer(projectID, loadEntries)
+			case 'p':
+				a.togglePauseTimer(projectID, a.refreshMonthBadge)
+			case 'T':
+				a.confirmDiscardTimer(projectID, a.refreshMonthBadge)
+			}
+			return nil
 		}
 
 		switch event.Key() {
+		case tcell.KeyTab, tcell.KeyBacktab:
+			projects, err := a.store.ListProjects(false)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to load projects: %v", err), nil)
+				return nil
+			}
+			if event.Key() == tcell.KeyTab {
+				projectID = nextProject(projectID, projects)
+			} else {
+				projectID = previousProject(projectID, projects)
+			}
+			a.currentProjectID = projectID
+			filterOptions.ProjectID = projectID
+			offset = 0
+			updateHeader()
+			loadEntries()
+			return nil
+		case tcell.KeyPgDn:
+			offset += entriesPageSize
+			loadEntries()
+			return nil
+		case tcell.KeyPgUp:
+			if offset > 0 {
+				offset = max(offset-entriesPageSize, 0)
+				loadEntries()
+			}
+			return nil
 		case tcell.KeyCtrlC, tcell.KeyCtrlQ:
 			a.Stop()
 			return nil
@@ -231,13 +387,79 @@ func (a *App) createEntriesView(projectID string) tview.Primitive {
 	return flex
 }
 
+// nextSortKey returns the sort order after current in db.SortKeys, wrapping around
+func nextSortKey(current string) string {
+	for i, key := range db.SortKeys {
+		if key == current {
+			return db.SortKeys[(i+1)%len(db.SortKeys)]
+		}
+	}
+	return db.SortKeys[0]
+}
+
+// lastPageOffset returns the offset of the last page of total entries, 0 when there are none
+func lastPageOffset(total, pageSize int) int {
+	if total <= pageSize {
+		return 0
+	}
+	return (total - 1) / pageSize * pageSize
+}
+
+// pageLabel describes which page of total entries starts at offset, e.g. "Page 2/5"
+func pageLabel(offset, total, pageSize int) string {
+	pages := (total + pageSize - 1) / pageSize
+	if pages == 0 {
+		pages = 1
+	}
+	return fmt.Sprintf("Page %d/%d", offset/pageSize+1, pages)
+}
+
+// nextProject returns the project after current in name order, cycling through
+// every project and then "" (all projects) before wrapping around
+func nextProject(current string, projects []*models.Project) string {
+	return cycleProject(current, projects, 1)
+}
+
+// previousProject returns the project before current, the reverse of nextProject
+func previousProject(current string, projects []*models.Project) string {
+	return cycleProject(current, projects, -1)
+}
+
+// cycleProject steps through "" followed by the project IDs sorted by name
+// An unknown current ID is treated as "" (all projects)
+func cycleProject(current string, projects []*models.Project, step int) string {
+	sorted := make([]*models.Project, len(projects))
+	copy(sorted, projects)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	ids := []string{""}
+	position := 0
+	for _, project := range sorted {
+		ids = append(ids, project.ID)
+		if project.ID == current {
+			position = len(ids) - 1
+		}
+	}
+
+	position = (position + step + len(ids)) % len(ids)
+	return ids[position]
+}
+
 func (a *App) confirmDeleteEntry(entry *models.Entry, onComplete func()) {
 	message := fmt.Sprintf("Delete entry from %s?", FormatDate(entry.CreatedAt))
 	a.ShowConfirmModal(message,
 		func() {
-			if err := a.store.DeleteEntry(entry.ID); err != nil {
+			// Keep the stored entry, not the table's copy, for undo
+			deleted, err := a.store.GetEntry(entry.ID)
+			if err == nil {
+				err = a.store.DeleteEntry(entry.ID)
+			}
+			if err != nil {
 				a.ShowErrorModal(fmt.Sprintf("Failed to delete entry: %v", err), nil)
 			} else {
+				a.lastDeletion = &deletion{entry: deleted}
 				onComplete()
 			}
 		},
@@ -254,12 +476,309 @@ func (a *App) toggleInvoiced(entry *models.Entry, onComplete func()) {
 	}
 }
 
+func (a *App) toggleLocked(entry *models.Entry, onComplete func()) {
+	if _, err := a.store.SetEntryLocked(entry.ID, !entry.Locked); err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+	} else {
+		onComplete()
+	}
+}
+
+func (a *App) togglePinned(entry *models.Entry, onComplete func()) {
+	if _, err := a.store.SetEntryPinned(entry.ID, !entry.Pinned); err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+	} else {
+		onComplete()
+	}
+}
+
+// toggleAdjustment flags an entry as needing an invoice adjustment (asking for a note),
+// or clears the flag after confirmation
+func (a *App) toggleAdjustment(entry *models.Entry, onComplete func()) {
+	if entry.NeedsAdjustment {
+		message := "Clear the invoice adjustment flag?"
+		if entry.AdjustmentNote != "" {
+			message += fmt.Sprintf("\n\nNote: %s", entry.AdjustmentNote)
+		}
+		a.ShowConfirmModal(message, func() {
+			if _, err := a.store.SetEntryAdjustment(entry.ID, false, ""); err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+				return
+			}
+			onComplete()
+		}, nil)
+		return
+	}
+
+	form := tview.NewForm()
+	note := ""
+
+	form.AddInputField("Note (optional)", "", 40, nil, func(text string) {
+		note = text
+	})
+
+	form.AddButton("Flag", func() {
+		if _, err := a.store.SetEntryAdjustment(entry.ID, true, note); err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+			return
+		}
+		a.HideModal("adjustment_form")
+		onComplete()
+	})
+
+	form.AddButton("Cancel", func() {
+		a.HideModal("adjustment_form")
+	})
+
+	form.SetBorder(true).
+		SetTitle("Needs Invoice Adjustment").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(ColorWarning)
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			a.HideModal("adjustment_form")
+			return nil
+		}
+		return event
+	})
+
+	// Center the form
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 7, 1, true).
+			AddItem(nil, 0, 1, false), 64, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	a.ShowModal("adjustment_form", modal)
+}
+
+// showReopenModal asks for the reason before reopening an invoiced or locked entry for correction
+func (a *App) showReopenModal(entry *models.Entry, onComplete func()) {
+	if !entry.Invoiced && !entry.Locked {
+		a.ShowInfoModal("Only invoiced or locked entries need reopening; edit this one directly", nil)
+		return
+	}
+
+	form := tview.NewForm()
+	reason := ""
+
+	form.AddInputField("Reason", "", 40, nil, func(text string) {
+		reason = text
+	})
+
+	form.AddButton("Reopen", func() {
+		if strings.TrimSpace(reason) == "" {
+			a.ShowErrorModal("A reason is required to reopen an entry", nil)
+			return
+		}
+		if _, err := a.store.ReopenEntry(entry.ID, reason); err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to reopen entry: %v", err), nil)
+			return
+		}
+		a.HideModal("reopen_form")
+		onComplete()
+	})
+
+	form.AddButton("Cancel", func() {
+		a.HideModal("reopen_form")
+	})
+
+	form.SetBorder(true).
+		SetTitle("Reopen Entry (marks uninvoiced and unlocks)").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(ColorWarning)
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			a.HideModal("reopen_form")
+			return nil
+		}
+		return event
+	})
+
+	// Center the form
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 7, 1, true).
+			AddItem(nil, 0, 1, false), 64, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	a.ShowModal("reopen_form", modal)
+}
+
+// showBulkTagModal adds and removes tags on the unlocked entries of the current project and date range
+// The invoiced filter is not applied, so the scope can be wider than the table
+func (a *App) showBulkTagModal(filterOptions *FilterOptions, onComplete func()) {
+	form := tview.NewForm()
+	addTags := ""
+	removeTags := ""
+
+	scope := "Applies to unlocked entries in the current project and date range"
+	if filterOptions.InvoicedFilter != nil {
+		scope += " (invoiced filter ignored)"
+	}
+	form.AddTextView("", scope, 56, 2, true, false)
+
+	form.AddInputField("Add tags", "", 40, nil, func(text string) {
+		addTags = text
+	})
+	form.AddInputField("Remove tags", "", 40, nil, func(text string) {
+		removeTags = text
+	})
+
+	form.AddButton("Apply", func() {
+		updated, err := a.store.BulkTag(
+			filterOptions.ProjectID,
+			filterOptions.StartDate,
+			filterOptions.EndDate,
+			models.ParseTags(addTags),
+			models.ParseTags(removeTags),
+		)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to tag entries: %v", err), nil)
+			return
+		}
+		a.HideModal("bulk_tag_form")
+		a.ShowInfoModal(fmt.Sprintf("Updated tags on %d entries", updated), onComplete)
+	})
+
+	form.AddButton("Cancel", func() {
+		a.HideModal("bulk_tag_form")
+	})
+
+	form.SetBorder(true).
+		SetTitle("Tag Filtered Entries (comma-separated)").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(ColorPrimary)
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			a.HideModal("bulk_tag_form")
+			return nil
+		}
+		return event
+	})
+
+	// Center the form
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 11, 1, true).
+			AddItem(nil, 0, 1, false), 64, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	a.ShowModal("bulk_tag_form", modal)
+}
+
+// confirmBulkDelete moves every unlocked entry matching the current filter to the trash
+// confirmMarkInvoiced marks the unlocked, uninvoiced entries in the current project and date
+// range invoiced after confirmation
+func (a *App) confirmMarkInvoiced(filterOptions *FilterOptions, onComplete func()) {
+	entries, err := a.store.ListEntriesFiltered(
+		filterOptions.ProjectID,
+		filterOptions.StartDate,
+		filterOptions.EndDate,
+		filterOptions.InvoicedFilter,
+	)
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to load entries: %v", err), nil)
+		return
+	}
+
+	matching := 0
+	var minutes int64
+	for _, entry := range entries {
+		if !entry.Locked && !entry.Invoiced {
+			matching++
+			minutes += entry.Duration
+		}
+	}
+
+	if matching == 0 {
+		a.ShowInfoModal("No uninvoiced entries match the current filter", nil)
+		return
+	}
+
+	a.ShowConfirmModal(fmt.Sprintf("Mark %d entries (%s) matching the current filter as invoiced?", matching, FormatDuration(minutes)),
+		func() {
+			updated, err := a.store.MarkInvoiced(
+				filterOptions.ProjectID,
+				filterOptions.StartDate,
+				filterOptions.EndDate,
+				true,
+			)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to mark entries: %v", err), nil)
+				return
+			}
+			a.ShowInfoModal(fmt.Sprintf("Marked %d entries as invoiced", updated), onComplete)
+		},
+		nil,
+	)
+}
+
+func (a *App) confirmBulkDelete(filterOptions *FilterOptions, onComplete func()) {
+	entries, err := a.store.ListEntriesFiltered(
+		filterOptions.ProjectID,
+		filterOptions.StartDate,
+		filterOptions.EndDate,
+		filterOptions.InvoicedFilter,
+	)
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to load entries: %v", err), nil)
+		return
+	}
+
+	matching, locked := 0, 0
+	for _, entry := range entries {
+		if entry.Locked {
+			locked++
+		} else {
+			matching++
+		}
+	}
+
+	if matching == 0 {
+		a.ShowInfoModal("No unlocked entries match the current filter", nil)
+		return
+	}
+
+	message := fmt.Sprintf("Move %d entries matching the current filter to the trash?", matching)
+	if locked > 0 {
+		message += fmt.Sprintf("\n%d locked entries will be kept.", locked)
+	}
+
+	a.ShowConfirmModal(message,
+		func() {
+			deleted, err := a.store.DeleteEntriesFiltered(
+				filterOptions.ProjectID,
+				filterOptions.StartDate,
+				filterOptions.EndDate,
+				filterOptions.InvoicedFilter,
+			)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to delete entries: %v", err), nil)
+				return
+			}
+			onComplete()
+			a.ShowInfoModal(fmt.Sprintf("Moved %d entries to the trash", deleted), nil)
+		},
+		nil,
+	)
+}
+
 // ShowFilterModal displays the filter configuration modal
 func (a *App) ShowFilterModal(filterOptions *FilterOptions, onComplete func()) {
 	form := tview.NewForm()
 
 	// Get list of projects for dropdown
-	projects, err := a.store.ListProjects()
+	projects, err := a.dropdownProjects()
 	if err != nil {
 		a.ShowErrorModal(fmt.Sprintf("Failed to load projects: %v", err), nil)
 		return
@@ -331,9 +850,9 @@ func (a *App) ShowFilterModal(filterOptions *FilterOptions, onComplete func()) {
 	form.AddButton("Apply", func() {
 		// Parse dates
 		if startDateStr != "" {
-			startDate, err := time.Parse("2006-01-02", startDateStr)
+			startDate, err := utils.ParseFlexibleDate(startDateStr)
 			if err != nil {
-				a.ShowErrorModal("Invalid start date format. Use YYYY-MM-DD", nil)
+				a.ShowErrorModal(fmt.Sprintf("Invalid start date: %v", err), nil)
 				return
 			}
 			filterOptions.StartDate = &startDate
@@ -342,13 +861,12 @@ func (a *App) ShowFilterModal(filterOptions *FilterOptions, onComplete func()) {
 		}
 
 		if endDateStr != "" {
-			endDate, err := time.Parse("2006-01-02", endDateStr)
+			// Date-only values include the whole end day
+			endDate, err := utils.ParseFlexibleEndDate(endDateStr)
 			if err != nil {
-				a.ShowErrorModal("Invalid end date format. Use YYYY-MM-DD", nil)
+				a.ShowErrorModal(fmt.Sprintf("Invalid end date: %v", err), nil)
 				return
 			}
-			// Set to end of day
-			endDate = endDate.Add(23*time.Hour + 59*time.Minute + 59*time.Second)
 			filterOptions.EndDate = &endDate
 		} else {
 			filterOptions.EndDate = nil
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"testing"
+
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func TestNextProject(t *testing.T) {
+	// Deliberately unsorted; cycling follows name order
+	projects := []*models.Project{
+		{ID: "c", Name: "Charlie"},
+		{ID: "a", Name: "Alpha"},
+		{ID: "b", Name: "Bravo"},
+	}
+
+	tests := []struct {
+		name    string
+		current string
+		next    string
+		prev    string
+	}{
+		{"all projects", "", "a", "c"},
+		{"first project", "a", "b", ""},
+		{"middle project", "b", "c", "a"},
+		{"last project wraps to all", "c", "", "b"},
+		{"unknown project treated as all", "gone", "a", "c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextProject(tt.current, projects); got != tt.next {
+				t.Errorf("nextProject(%q) = %q, want %q", tt.current, got, tt.next)
+			}
+			if got := previousProject(tt.current, projects); got != tt.prev {
+				t.Errorf("previousProject(%q) = %q, want %q", tt.current, got, tt.prev)
+			}
+		})
+	}
+
+	if projects[0].ID != "c" {
+		t.Error("nextProject() should not reorder the caller's slice")
+	}
+
+	if got := nextProject("", nil); got != "" {
+		t.Errorf("nextProject() with no projects = %q, want \"\"", got)
+	}
+}
+
+func TestNextSortKey(t *testing.T) {
+	key := SortByDate
+	for _, want := range []string{SortByDuration, SortByProject, SortByInvoiced, SortByDate} {
+		key = nextSortKey(key)
+		if key != want {
+			t.Errorf("nextSortKey() = %q, want %q", key, want)
+		}
+	}
+	if got := nextSortKey("unknown"); got != SortByDate {
+		t.Errorf("nextSortKey(unknown) = %q, want %q", got, SortByDate)
+	}
+}
+
+func TestPaging(t *testing.T) {
+	tests := []struct {
+		total, offset int
+		lastOffset    int
+		label         string
+	}{
+		{0, 0, 0, "Page 1/1"},
+		{100, 0, 0, "Page 1/1"},
+		{101, 100, 100, "Page 2/2"},
+		{250, 100, 200, "Page 2/3"},
+		{300, 200, 200, "Page 3/3"},
+	}
+
+	for _, tt := range tests {
+		if got := lastPageOffset(tt.total, 100); got != tt.lastOffset {
+			t.Errorf("lastPageOffset(%d) = %d, want %d", tt.total, got, tt.lastOffset)
+		}
+		if got := pageLabel(tt.offset, tt.total, 100); got != tt.label {
+			t.Errorf("pageLabel(%d, %d) = %q, want %q", tt.offset, tt.total, got, tt.label)
+		}
+	}
+}
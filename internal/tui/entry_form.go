@@ -2,10 +2,13 @@ package tui
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
 	"github.com/techthos/clockwork/internal/git"
 	"github.com/techthos/clockwork/internal/models"
 	"github.com/techthos/clockwork/internal/utils"
@@ -24,10 +27,24 @@ func (a *App) ShowEntryForm(entry *models.Entry, defaultProjectID string, onComp
 	}
 
 	// Edit mode - show manual form with existing data
-	a.showManualEntryForm(entry, onComplete)
+	a.showManualEntryForm(entry, "", onComplete)
+}
+
+// preferredProjectID returns projectID, or the configured default project if empty
+func (a *App) preferredProjectID(projectID string) string {
+	if projectID != "" {
+		return projectID
+	}
+	defaultID, err := a.store.GetDefaultProject()
+	if err != nil {
+		return ""
+	}
+	return defaultID
 }
 
 func (a *App) showEntryModeSelection(defaultProjectID string, onComplete func()) {
+	defaultProjectID = a.preferredProjectID(defaultProjectID)
+
 	modal := tview.NewModal().
 		SetText("Select entry creation mode:").
 		AddButtons([]string{"Git (from commits)", "Manual", "Cancel"}).
@@ -37,7 +54,7 @@ func (a *App) showEntryModeSelection(defaultProjectID string, onComplete func())
 			case 0:
 				a.showGitEntryForm(defaultProjectID, onComplete)
 			case 1:
-				a.showManualEntryForm(nil, onComplete)
+				a.showManualEntryForm(nil, defaultProjectID, onComplete)
 			}
 		})
 
@@ -48,10 +65,11 @@ func (a *App) showEntryModeSelection(defaultProjectID string, onComplete func())
 }
 
 func (a *App) showGitEntryForm(defaultProjectID string, onComplete func()) {
+	const durationLabel = "Custom Duration (optional)"
 	form := tview.NewForm()
 
 	// Get list of projects
-	projects, err := a.store.ListProjects()
+	projects, err := a.dropdownProjects()
 	if err != nil {
 		a.ShowErrorModal(fmt.Sprintf("Failed to load projects: %v", err), nil)
 		return
@@ -79,20 +97,28 @@ func (a *App) showGitEntryForm(defaultProjectID string, onComplete func()) {
 	var customDuration string
 	var customMessage string
 	invoiced := false
+	excludeMerges := false
 
 	// Project dropdown
 	form.AddDropDown("Project", projectOptions, selectedIndex, func(option string, optionIndex int) {
 		selectedProject = projectMap[option]
 	})
 
-	// Optional custom duration
-	form.AddInputField("Custom Duration (optional)", "", 20,
-		func(textToCheck string, lastChar rune) bool {
-			return true // Allow any input, will validate on save
-		},
-		func(text string) {
-			customDuration = text
-		})
+	// Optional custom duration (validated live, label turns red on invalid input)
+	durationInput := tview.NewInputField().
+		SetLabel(durationLabel).
+		SetFieldWidth(20)
+	durationInput.SetChangedFunc(func(text string) {
+		customDuration = text
+		if validateDurationLive(text) {
+			durationInput.SetLabel(durationLabel).
+				SetLabelColor(tview.Styles.SecondaryTextColor)
+		} else {
+			durationInput.SetLabel(durationLabel + " (invalid)").
+				SetLabelColor(ColorError)
+		}
+	})
+	form.AddFormItem(durationInput)
 
 	// Optional custom message
 	form.AddTextArea("Custom Message (optional)", "", 60, 3, 0, func(text string) {
@@ -104,6 +130,11 @@ func (a *App) showGitEntryForm(defaultProjectID string, onComplete func()) {
 		invoiced = checked
 	})
 
+	// Merge commits add "Merge branch" noise and often carry distant timestamps
+	form.AddCheckbox("Exclude merge commits", false, func(checked bool) {
+		excludeMerges = checked
+	})
+
 	// Add buttons
 	form.AddButton("Create from Git", func() {
 		if selectedProject == nil {
@@ -111,6 +142,16 @@ func (a *App) showGitEntryForm(defaultProjectID string, onComplete func()) {
 			return
 		}
 
+		// A repo out of reach (e.g. on an unmounted network drive) cannot be aggregated;
+		// offer to log the time manually instead of failing on the first git command
+		if status := git.ValidateRepo(selectedProject.GitRepoPath); status.Availability() == git.RepoUnavailable {
+			project := selectedProject
+			a.ShowConfirmModal(fmt.Sprintf("Repository unavailable: %v\n\nLog this time manually instead?", status.Err), func() {
+				a.showManualEntryForm(nil, project.ID, onComplete)
+			}, nil)
+			return
+		}
+
 		// Find the most recent commit hash across all entries (skips manual entries without one)
 		sinceHash, err := a.store.GetLastCommitHash(selectedProject.ID)
 		if err != nil {
@@ -118,9 +159,40 @@ func (a *App) showGitEntryForm(defaultProjectID string, onComplete func()) {
 			return
 		}
 
+		// Refuse to aggregate across unrelated histories (e.g. repo path repointed) or from a
+		// missing baseline; one lost to a reset but still in the reflog is used with a warning
+		var baselineWarning string
+		if sinceHash != "" {
+			baselineWarning, err = git.CheckBaseline(selectedProject.GitRepoPath, sinceHash)
+			if err != nil {
+				a.ShowErrorModal(err.Error(), nil)
+				return
+			}
+		}
+
+		// Shared repos: limit aggregation to the local user's commits when the project asks for it
+		commitAuthor, err := git.CommitAuthor(selectedProject.GitRepoPath, "", selectedProject.OwnCommitsOnly)
+		if err != nil {
+			a.ShowErrorModal(err.Error(), nil)
+			return
+		}
+
+		opts, err := a.store.GetSummarizeOptions()
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load settings: %v", err), nil)
+			return
+		}
+
+		// Estimate with the project's duration method
+		opts.Strategy, err = git.GetStrategy(selectedProject.DurationMethod)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Invalid duration method: %v", err), nil)
+			return
+		}
+
 		var commits []models.CommitInfo
 		if sinceHash != "" {
-			commits, err = git.GetCommitsSince(selectedProject.GitRepoPath, sinceHash)
+			commits, err = git.GetCommitsSinceWithOptions(selectedProject.GitRepoPath, sinceHash, git.GetCommitsSinceOptions{NoMerges: excludeMerges, Author: commitAuthor, CountLines: opts.CountLines()})
 			if err != nil {
 				a.ShowErrorModal(fmt.Sprintf("Failed to fetch commits: %v", err), nil)
 				return
@@ -140,8 +212,28 @@ func (a *App) showGitEntryForm(defaultProjectID string, onComplete func()) {
 			return
 		}
 
-		// Calculate duration
-		duration := git.CalculateDuration(commits)
+		// Get latest commit hash (before filtering, so ignored commits still advance the baseline)
+		latestHash := commits[0].Hash
+
+		// Repo-local .clockworkignore rules extend the configured exclusions
+		ignoreRules, err := git.LoadIgnore(selectedProject.GitRepoPath)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load %s: %v", git.IgnoreFile, err), nil)
+			return
+		}
+		opts.ExcludePatterns = append(opts.ExcludePatterns, ignoreRules.Subjects...)
+		commits, err = git.DropIgnoredPaths(selectedProject.GitRepoPath, commits, ignoreRules)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to apply %s: %v", git.IgnoreFile, err), nil)
+			return
+		}
+		if len(commits) == 0 {
+			a.ShowErrorModal(fmt.Sprintf("All new commits are ignored by %s", git.IgnoreFile), nil)
+			return
+		}
+
+		// Generate message and estimate duration
+		message, duration := git.SummarizeCommits(commits, opts)
 		if customDuration != "" {
 			parsedDuration, err := utils.ParseDuration(customDuration)
 			if err != nil {
@@ -151,34 +243,50 @@ func (a *App) showGitEntryForm(defaultProjectID string, onComplete func()) {
 			duration = parsedDuration
 		}
 
-		// Generate message
-		message := git.AggregateCommits(commits)
 		if customMessage != "" {
 			message = customMessage
 		}
 
-		// Get latest commit hash
-		latestHash := commits[0].Hash
-
-		// Create entry
-		_, err = a.store.CreateEntry(
-			selectedProject.ID,
-			duration,
-			message,
-			latestHash,
-			invoiced,
-			time.Now(),
-		)
+		project := selectedProject
+		create := func() {
+			_, err := a.store.CreateEntryWithOptions(
+				project.ID,
+				duration,
+				message,
+				latestHash,
+				invoiced,
+				time.Now(),
+				db.EntryOptions{Mode: models.EntryModeGit},
+			)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to create entry: %v", err), nil)
+				return
+			}
 
-		if err != nil {
-			a.ShowErrorModal(fmt.Sprintf("Failed to create entry: %v", err), nil)
-			return
+			a.HideModal("git_entry_form")
+			if onComplete != nil {
+				onComplete()
+			}
 		}
 
-		a.HideModal("git_entry_form")
-		if onComplete != nil {
-			onComplete()
+		// Confirm before creating; Back returns to the form with its fields intact
+		summary := gitEntrySummary(project.Name, commits, duration, message)
+		if baselineWarning != "" {
+			summary = "Warning: " + baselineWarning + "\n\n" + summary
 		}
+		confirm := tview.NewModal().
+			SetText(summary).
+			AddButtons([]string{"Confirm", "Back"}).
+			SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+				a.HideModal("git_entry_confirm")
+				if buttonIndex == 0 {
+					create()
+				}
+			})
+		confirm.SetBackgroundColor(tcell.ColorDefault)
+		confirm.SetBorderColor(ColorPrimary)
+
+		a.ShowModal("git_entry_confirm", confirm)
 	})
 
 	form.AddButton("Cancel", func() {
@@ -203,14 +311,106 @@ func (a *App) showGitEntryForm(defaultProjectID string, onComplete func()) {
 		AddItem(nil, 0, 1, false).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(nil, 0, 1, false).
-			AddItem(form, 20, 1, true).
+			AddItem(form, 22, 1, true).
 			AddItem(nil, 0, 1, false), 80, 1, true).
 		AddItem(nil, 0, 1, false)
 
 	a.ShowModal("git_entry_form", modal)
 }
 
-func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
+// gitEntrySummary describes the entry the git form is about to create
+func gitEntrySummary(projectName string, commits []models.CommitInfo, duration int64, message string) string {
+	firstLine := strings.TrimSpace(strings.SplitN(message, "\n", 2)[0])
+	if firstLine == "" {
+		firstLine = "(empty)"
+	}
+
+	noun := "commits"
+	if len(commits) == 1 {
+		noun = "commit"
+	}
+
+	return fmt.Sprintf("Create git entry?\n\nProject: %s\nCommits: %d %s\nDuration: %s\nMessage: %s",
+		projectName, len(commits), noun, FormatDuration(duration), firstLine)
+}
+
+// defaultManualDuration returns the duration pre-filled for new manual entries
+// Returns "" when default_manual_duration is unset or unparseable
+func (a *App) defaultManualDuration() string {
+	value, err := a.store.GetSetting(db.SettingDefaultManualDuration)
+	if err != nil || value == "" {
+		return ""
+	}
+	minutes, err := utils.ParseDuration(value)
+	if err != nil || minutes <= 0 {
+		return ""
+	}
+	return FormatDuration(minutes)
+}
+
+// recentProjectCount is how many recently used projects float to the top of project dropdowns
+const recentProjectCount = 3
+
+// dropdownProjects lists projects for a dropdown in the order chosen by the project_order setting
+func (a *App) dropdownProjects() ([]*models.Project, error) {
+	projects, err := a.store.ListProjects(false)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := a.store.GetSetting(db.SettingProjectOrder)
+	if err != nil {
+		return nil, err
+	}
+	var lastUsed map[string]time.Time
+	if order == db.ProjectOrderRecent {
+		if lastUsed, err = a.store.ProjectLastActivity(); err != nil {
+			return nil, err
+		}
+	}
+
+	return orderProjects(projects, lastUsed, recentProjectCount), nil
+}
+
+// orderProjects sorts projects alphabetically, then moves the recent most recently used ones
+// (by lastUsed, newest first) to the top. Projects never used stay in the alphabetical part.
+func orderProjects(projects []*models.Project, lastUsed map[string]time.Time, recent int) []*models.Project {
+	ordered := append([]*models.Project(nil), projects...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return strings.ToLower(ordered[i].Name) < strings.ToLower(ordered[j].Name)
+	})
+
+	used := make([]*models.Project, 0, len(ordered))
+	for _, project := range ordered {
+		if _, ok := lastUsed[project.ID]; ok {
+			used = append(used, project)
+		}
+	}
+	sort.SliceStable(used, func(i, j int) bool {
+		return lastUsed[used[i].ID].After(lastUsed[used[j].ID])
+	})
+	if len(used) > recent {
+		used = used[:recent]
+	}
+	if len(used) == 0 {
+		return ordered
+	}
+
+	floated := make(map[string]bool, len(used))
+	result := make([]*models.Project, 0, len(ordered))
+	for _, project := range used {
+		floated[project.ID] = true
+		result = append(result, project)
+	}
+	for _, project := range ordered {
+		if !floated[project.ID] {
+			result = append(result, project)
+		}
+	}
+	return result
+}
+
+func (a *App) showManualEntryForm(entry *models.Entry, defaultProjectID string, onComplete func()) {
 	form := tview.NewForm()
 
 	isEdit := entry != nil
@@ -220,12 +420,23 @@ func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
 	}
 
 	// Get list of projects
-	projects, err := a.store.ListProjects()
+	projects, err := a.dropdownProjects()
 	if err != nil {
 		a.ShowErrorModal(fmt.Sprintf("Failed to load projects: %v", err), nil)
 		return
 	}
 
+	// An entry of an archived project keeps its project selectable
+	if isEdit {
+		found := false
+		for _, project := range projects {
+			found = found || project.ID == entry.ProjectID
+		}
+		if project, err := a.store.GetProject(entry.ProjectID); !found && err == nil {
+			projects = append(projects, project)
+		}
+	}
+
 	if len(projects) == 0 {
 		a.ShowErrorModal("No projects available. Create a project first.", nil)
 		return
@@ -241,20 +452,38 @@ func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
 		projectMap[project.Name] = project
 		if isEdit && project.ID == entry.ProjectID {
 			selectedIndex = i
+		} else if !isEdit && project.ID == defaultProjectID {
+			selectedIndex = i
 		}
 	}
 
 	var selectedProject *models.Project = projects[selectedIndex]
 	durationField := ""
+	estimateField := ""
 	messageField := ""
 	commitHashField := ""
+	authorField := ""
+	referenceField := ""
+	categoryField := ""
+	locationField := ""
+	tagsField := ""
 	invoiced := false
 
 	if isEdit {
 		durationField = FormatDuration(entry.Duration)
+		if entry.EstimateMinutes > 0 {
+			estimateField = FormatDuration(entry.EstimateMinutes)
+		}
 		messageField = entry.Message
 		commitHashField = entry.CommitHash
+		authorField = entry.Author
+		referenceField = entry.Reference
+		categoryField = entry.Category
+		locationField = entry.Location
+		tagsField = strings.Join(entry.Tags, ", ")
 		invoiced = entry.Invoiced
+	} else {
+		durationField = a.defaultManualDuration()
 	}
 
 	// Project dropdown
@@ -263,7 +492,7 @@ func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
 	})
 
 	// Duration field
-	form.AddInputField("Duration (e.g., 1h 30m, 90m)", durationField, 20,
+	form.AddInputField("Duration (e.g., 1h 30m, 1:30)", durationField, 20,
 		func(textToCheck string, lastChar rune) bool {
 			return true
 		},
@@ -271,7 +500,21 @@ func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
 			durationField = text
 		})
 
-	// Message field
+	// Estimate field (optional), with how the saved duration compared to it
+	form.AddInputField("Estimate (optional)", estimateField, 20,
+		func(textToCheck string, lastChar rune) bool {
+			return validateDurationLive(textToCheck)
+		},
+		func(text string) {
+			estimateField = text
+		})
+	if isEdit {
+		if variance := formatEstimateVariance(entry); variance != "" {
+			form.AddTextView("Variance", variance, 40, 1, true, false)
+		}
+	}
+
+	// Message field (empty uses the manual message template when creating)
 	form.AddTextArea("Message", messageField, 60, 5, 0, func(text string) {
 		messageField = text
 	})
@@ -285,6 +528,25 @@ func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
 			commitHashField = text
 		})
 
+	// Author field (empty defaults to the repo's git user.name when creating)
+	form.AddInputField("Author (optional)", authorField, 40, nil, func(text string) {
+		authorField = text
+	})
+
+	// Reference and category (required by the review policy when configured)
+	form.AddInputField("Reference (optional)", referenceField, 30, nil, func(text string) {
+		referenceField = text
+	})
+	form.AddInputField("Category (optional)", categoryField, 30, nil, func(text string) {
+		categoryField = text
+	})
+	form.AddInputField("Location (optional)", locationField, 30, nil, func(text string) {
+		locationField = text
+	})
+	form.AddInputField("Tags (comma-separated)", tagsField, 40, nil, func(text string) {
+		tagsField = text
+	})
+
 	// Invoiced checkbox
 	form.AddCheckbox("Invoiced", invoiced, func(checked bool) {
 		invoiced = checked
@@ -303,8 +565,16 @@ func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
 			return
 		}
 		if messageField == "" {
-			a.ShowErrorModal("Message cannot be empty", nil)
-			return
+			if isEdit {
+				a.ShowErrorModal("Message cannot be empty", nil)
+				return
+			}
+			template, err := a.store.GetSetting(db.SettingManualMessageTemplate)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to load settings: %v", err), nil)
+				return
+			}
+			messageField = utils.RenderMessageTemplate(template, selectedProject.Name, time.Now())
 		}
 
 		// Parse duration
@@ -313,6 +583,25 @@ func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
 			a.ShowErrorModal(fmt.Sprintf("Invalid duration: %v", err), nil)
 			return
 		}
+		var estimate int64
+		if strings.TrimSpace(estimateField) != "" {
+			estimate, err = utils.ParseDuration(estimateField)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Invalid estimate: %v", err), nil)
+				return
+			}
+		}
+
+		// Store full hashes only; an unchanged hash is kept as it is
+		commitHashField = strings.TrimSpace(commitHashField)
+		if commitHashField != "" && !(isEdit && commitHashField == entry.CommitHash) {
+			full, err := git.ExpandCommitHash(selectedProject.GitRepoPath, commitHashField)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Invalid commit hash: %v", err), nil)
+				return
+			}
+			commitHashField = full
+		}
 
 		if isEdit {
 			// Update existing entry
@@ -321,20 +610,84 @@ func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
 				a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
 				return
 			}
+			if estimate != entry.EstimateMinutes {
+				if _, err := a.store.SetEntryEstimate(entry.ID, estimate); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+					return
+				}
+			}
+			if authorField != entry.Author {
+				if _, err := a.store.SetEntryAuthor(entry.ID, authorField); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+					return
+				}
+			}
+			if referenceField != entry.Reference {
+				if _, err := a.store.SetEntryReference(entry.ID, referenceField); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+					return
+				}
+			}
+			if categoryField != entry.Category {
+				if _, err := a.store.SetEntryCategory(entry.ID, categoryField); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+					return
+				}
+			}
+			if locationField != entry.Location {
+				if _, err := a.store.SetEntryLocation(entry.ID, locationField); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+					return
+				}
+			}
+			if tags := models.ParseTags(tagsField); strings.Join(tags, ",") != strings.Join(entry.Tags, ",") {
+				if _, err := a.store.SetEntryTags(entry.ID, tags); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to update entry: %v", err), nil)
+					return
+				}
+			}
 		} else {
 			// Create new entry
-			_, err = a.store.CreateEntry(
+			if authorField == "" {
+				authorField = a.defaultAuthor(selectedProject)
+			}
+			created, err := a.store.CreateEntryWithOptions(
 				selectedProject.ID,
 				duration,
 				messageField,
 				commitHashField,
 				invoiced,
 				time.Now(),
+				db.EntryOptions{Author: authorField, Estimate: estimate},
 			)
 			if err != nil {
 				a.ShowErrorModal(fmt.Sprintf("Failed to create entry: %v", err), nil)
 				return
 			}
+			if referenceField != "" {
+				if _, err := a.store.SetEntryReference(created.ID, referenceField); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to set entry reference: %v", err), nil)
+					return
+				}
+			}
+			if categoryField != "" {
+				if _, err := a.store.SetEntryCategory(created.ID, categoryField); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to set entry category: %v", err), nil)
+					return
+				}
+			}
+			if locationField != "" {
+				if _, err := a.store.SetEntryLocation(created.ID, locationField); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to set entry location: %v", err), nil)
+					return
+				}
+			}
+			if tags := models.ParseTags(tagsField); len(tags) > 0 {
+				if _, err := a.store.SetEntryTags(created.ID, tags); err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Failed to set entry tags: %v", err), nil)
+					return
+				}
+			}
 		}
 
 		a.HideModal("manual_entry_form")
@@ -365,9 +718,23 @@ func (a *App) showManualEntryForm(entry *models.Entry, onComplete func()) {
 		AddItem(nil, 0, 1, false).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(nil, 0, 1, false).
-			AddItem(form, 20, 1, true).
+			AddItem(form, 34, 1, true).
 			AddItem(nil, 0, 1, false), 80, 1, true).
 		AddItem(nil, 0, 1, false)
 
 	a.ShowModal("manual_entry_form", modal)
 }
+
+// defaultAuthor returns the project repo's git user.name, or "" when disabled or unavailable
+func (a *App) defaultAuthor(project *models.Project) string {
+	enabled, err := a.store.GetSetting(db.SettingDefaultAuthorFromRepo)
+	if err != nil || enabled == "false" {
+		return ""
+	}
+
+	author, err := git.GetAuthor(project.GitRepoPath)
+	if err != nil {
+		return ""
+	}
+	return author
+}
@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func TestGitEntrySummary(t *testing.T) {
+	commits := []models.CommitInfo{
+		{Hash: "bbb", Message: "Add export"},
+		{Hash: "aaa", Message: "Fix parser"},
+	}
+
+	tests := []struct {
+		name     string
+		commits  []models.CommitInfo
+		duration int64
+		message  string
+		want     []string
+		notWant  []string
+	}{
+		{
+			name:     "multiple commits",
+			commits:  commits,
+			duration: 95,
+			message:  "Add export\nFix parser",
+			want:     []string{"Project: Clockwork", "Commits: 2 commits", "Duration: 1h 35m", "Message: Add export"},
+			notWant:  []string{"Fix parser"},
+		},
+		{
+			name:     "single commit",
+			commits:  commits[:1],
+			duration: 30,
+			message:  "  Add export  ",
+			want:     []string{"Commits: 1 commit\n", "Duration: 30m", "Message: Add export"},
+		},
+		{
+			name:     "empty message",
+			commits:  commits,
+			duration: 0,
+			message:  "",
+			want:     []string{"Duration: 0m", "Message: (empty)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gitEntrySummary("Clockwork", tt.commits, tt.duration, tt.message)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("gitEntrySummary() = %q, missing %q", got, w)
+				}
+			}
+			for _, w := range tt.notWant {
+				if strings.Contains(got, w) {
+					t.Errorf("gitEntrySummary() = %q, should not contain %q", got, w)
+				}
+			}
+		})
+	}
+}
+
+func TestDefaultManualDuration(t *testing.T) {
+	a := setupTestApp(t)
+
+	if got := a.defaultManualDuration(); got != "" {
+		t.Errorf("defaultManualDuration() unset = %q, want \"\"", got)
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"30m", "30m"},
+		{"1:30", "1h 30m"},
+		{"1.5h", "1h 30m"},
+		{"not a duration", ""},
+		{"0m", ""},
+	}
+
+	for _, tt := range tests {
+		a.store.SetSetting(db.SettingDefaultManualDuration, tt.value)
+		if got := a.defaultManualDuration(); got != tt.want {
+			t.Errorf("defaultManualDuration() with %q = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestOrderProjects(t *testing.T) {
+	projects := []*models.Project{
+		{ID: "d", Name: "delta"},
+		{ID: "a", Name: "Alpha"},
+		{ID: "c", Name: "charlie"},
+		{ID: "e", Name: "Echo"},
+		{ID: "b", Name: "bravo"},
+	}
+	names := func(ordered []*models.Project) string {
+		parts := make([]string, len(ordered))
+		for i, project := range ordered {
+			parts[i] = project.Name
+		}
+		return strings.Join(parts, ",")
+	}
+
+	// Without usage the list is alphabetical, ignoring case
+	if got := names(orderProjects(projects, nil, 3)); got != "Alpha,bravo,charlie,delta,Echo" {
+		t.Errorf("alphabetical order = %s", got)
+	}
+
+	day := func(d int) time.Time { return time.Date(2026, time.March, d, 9, 0, 0, 0, time.UTC) }
+	lastUsed := map[string]time.Time{"e": day(5), "c": day(9), "a": day(1), "d": day(7)}
+
+	// The three most recent float to the top newest first; the rest stay alphabetical
+	if got := names(orderProjects(projects, lastUsed, 3)); got != "charlie,delta,Echo,Alpha,bravo" {
+		t.Errorf("recent order = %s", got)
+	}
+	if got := names(orderProjects(projects, lastUsed, 1)); got != "charlie,Alpha,bravo,delta,Echo" {
+		t.Errorf("single recent order = %s", got)
+	}
+
+	// The input slice is left untouched
+	if projects[0].Name != "delta" {
+		t.Errorf("orderProjects modified its input")
+	}
+}
@@ -0,0 +1,179 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// Entry sort keys for the entries view, shared with the store's streaming export
+const (
+	SortByDate     = db.SortByDate     // Newest first
+	SortByDuration = db.SortByDuration // Longest first, newest first on ties
+	SortByProject  = db.SortByProject  // Project name A-Z, newest first within a project
+	SortByInvoiced = db.SortByInvoiced // Uninvoiced first, newest first within each group
+)
+
+// Export formats for the entries view
+const (
+	ExportCSV  = db.ExportCSV
+	ExportJSON = db.ExportJSON
+)
+
+// queryView returns the entries shown for a filter and sort key, across all pages
+// It filters and orders exactly like the store's StreamExport, so exports match the table
+func (a *App) queryView(filter FilterOptions, sortKey string) ([]*models.Entry, error) {
+	page, err := a.queryViewPage(filter, sortKey, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+	return page.Entries, nil
+}
+
+// queryViewPage returns up to limit entries of the view from offset on, sorted and sliced by the store
+func (a *App) queryViewPage(filter FilterOptions, sortKey string, offset, limit int) (*db.EntryPage, error) {
+	return a.store.ListEntriesPage(viewFilter(filter, sortKey), offset, limit)
+}
+
+// viewFilter converts the entries view state into the store's export filter
+func viewFilter(filter FilterOptions, sortKey string) db.EntryFilter {
+	return db.EntryFilter{
+		ProjectID:      filter.ProjectID,
+		StartDate:      filter.StartDate,
+		EndDate:        filter.EndDate,
+		InvoicedFilter: filter.InvoicedFilter,
+		SortBy:         sortKey,
+		PinnedFirst:    true,
+	}
+}
+
+// exportCurrentView streams exactly the entries of the current view to path
+func (a *App) exportCurrentView(filter FilterOptions, sortKey string, format string, path string) error {
+	return writeExport(format, path, func(w io.Writer) error {
+		return a.store.StreamExport(w, format, viewFilter(filter, sortKey))
+	})
+}
+
+// exportNew streams the project's entries created or modified since its last export to path
+// and returns how many were written; the project's export marker moves forward on success
+func (a *App) exportNew(projectID, sortKey, format, path string) (int, error) {
+	var count int
+	err := writeExport(format, path, func(w io.Writer) error {
+		var err error
+		count, err = a.store.ExportNew(w, format, projectID, sortKey)
+		return err
+	})
+	return count, err
+}
+
+// writeExport creates path and fills it with write, removing the file if the export fails
+func writeExport(format, path string, write func(w io.Writer) error) error {
+	if !db.ValidExportFormat(format) {
+		return fmt.Errorf("unsupported export format %q (use one of: %s)", format, strings.Join(db.ExportFormats, ", "))
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	if err := write(file); err != nil {
+		file.Close()
+		os.Remove(path)
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	return nil
+}
+
+// showExportModal asks for a format and path, then exports the current view
+func (a *App) showExportModal(filter FilterOptions, sortKey string) {
+	form := tview.NewForm()
+
+	formats := db.ExportFormats
+	format := ExportCSV
+	path := "clockwork-entries.csv"
+
+	form.AddDropDown("Format", formats, 0, func(option string, optionIndex int) {
+		format = option
+	})
+
+	form.AddInputField("Path", path, 40, nil, func(text string) {
+		path = text
+	})
+
+	// New-only exports ignore the view's other filters so nothing falls behind the marker
+	onlyNew := false
+	if filter.ProjectID != "" {
+		form.AddCheckbox("Only New Since Last Export", onlyNew, func(checked bool) {
+			onlyNew = checked
+		})
+	}
+
+	form.AddButton("Export", func() {
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to resolve export path: %v", err), nil)
+			return
+		}
+
+		if onlyNew {
+			count, err := a.exportNew(filter.ProjectID, sortKey, format, absPath)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to export entries: %v", err), nil)
+				return
+			}
+
+			a.HideModal("export_modal")
+			a.ShowInfoModal(fmt.Sprintf("%d new entries exported to %s", count, absPath), nil)
+			return
+		}
+
+		if err := a.exportCurrentView(filter, sortKey, format, absPath); err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to export entries: %v", err), nil)
+			return
+		}
+
+		a.HideModal("export_modal")
+		a.ShowInfoModal(fmt.Sprintf("Entries exported to %s", absPath), nil)
+	})
+
+	form.AddButton("Cancel", func() {
+		a.HideModal("export_modal")
+	})
+
+	form.SetBorder(true).
+		SetTitle("Export Entries").
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(ColorPrimary)
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			a.HideModal("export_modal")
+			return nil
+		}
+		return event
+	})
+
+	// Center the form
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 11, 1, true).
+			AddItem(nil, 0, 1, false), 60, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	a.ShowModal("export_modal", modal)
+}
@@ -0,0 +1,118 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// setupTestApp creates an app backed by a temporary database (no terminal)
+func setupTestApp(t *testing.T) *App {
+	t.Helper()
+	store, err := db.New(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return &App{store: store}
+}
+
+func TestQueryViewAppliesFilterAndSort(t *testing.T) {
+	a := setupTestApp(t)
+
+	project, _ := a.store.CreateProject("Test", "")
+	other, _ := a.store.CreateProject("Other", "")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+
+	short, _ := a.store.CreateEntry(project.ID, 15, "short", "", false, base.Add(48*time.Hour))
+	long, _ := a.store.CreateEntry(project.ID, 120, "long", "", false, base)
+	mid, _ := a.store.CreateEntry(project.ID, 60, "mid", "", false, base.Add(24*time.Hour))
+	a.store.CreateEntry(project.ID, 90, "invoiced", "", true, base)
+	a.store.CreateEntry(other.ID, 240, "other project", "", false, base)
+
+	uninvoiced := false
+	filter := FilterOptions{ProjectID: project.ID, InvoicedFilter: &uninvoiced}
+
+	tests := []struct {
+		name    string
+		sortKey string
+		want    []string
+	}{
+		{"by date", SortByDate, []string{short.ID, mid.ID, long.ID}},
+		{"by duration", SortByDuration, []string{long.ID, mid.ID, short.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries, err := a.queryView(filter, tt.sortKey)
+			if err != nil {
+				t.Fatalf("queryView() error = %v", err)
+			}
+			if len(entries) != len(tt.want) {
+				t.Fatalf("queryView() returned %d entries, want %d", len(entries), len(tt.want))
+			}
+			for i, entry := range entries {
+				if entry.ID != tt.want[i] {
+					t.Errorf("entries[%d] = %q (%s), want %q", i, entry.ID, entry.Message, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExportCurrentViewMatchesView(t *testing.T) {
+	a := setupTestApp(t)
+
+	project, _ := a.store.CreateProject("Test", "")
+	base := time.Date(2026, time.October, 1, 12, 0, 0, 0, time.UTC)
+	a.store.CreateEntry(project.ID, 15, "short", "", false, base.Add(24*time.Hour))
+	a.store.CreateEntry(project.ID, 120, "long", "", false, base)
+
+	filter := FilterOptions{ProjectID: project.ID}
+	path := filepath.Join(t.TempDir(), "export.json")
+	if err := a.exportCurrentView(filter, SortByDuration, ExportJSON, path); err != nil {
+		t.Fatalf("exportCurrentView() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read export: %v", err)
+	}
+	var exported []*models.Entry
+	if err := json.Unmarshal(data, &exported); err != nil {
+		t.Fatalf("Failed to decode export: %v", err)
+	}
+
+	view, _ := a.queryView(filter, SortByDuration)
+	if len(exported) != len(view) {
+		t.Fatalf("export has %d entries, view has %d", len(exported), len(view))
+	}
+	for i := range view {
+		if exported[i].ID != view[i].ID {
+			t.Errorf("exported[%d] = %q, view has %q", i, exported[i].ID, view[i].ID)
+		}
+	}
+
+	if err := a.exportCurrentView(filter, SortByDate, "xml", path); err == nil {
+		t.Error("exportCurrentView() with unknown format should fail")
+	}
+}
+
+func TestViewFilter(t *testing.T) {
+	start := time.Date(2026, time.October, 1, 0, 0, 0, 0, time.UTC)
+	invoiced := true
+	filter := FilterOptions{ProjectID: "p1", StartDate: &start, InvoicedFilter: &invoiced}
+
+	got := viewFilter(filter, SortByDuration)
+	if got.ProjectID != "p1" || got.StartDate != &start || got.EndDate != nil || got.InvoicedFilter != &invoiced {
+		t.Errorf("viewFilter() = %+v, want the view's filter", got)
+	}
+	if got.SortBy != db.SortByDuration {
+		t.Errorf("viewFilter().SortBy = %q, want %q", got.SortBy, db.SortByDuration)
+	}
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+// refreshMonthBadge updates the global header with active timers and the current month's totals
+// Views call this after loading data so create/delete/invoice actions are reflected
+func (a *App) refreshMonthBadge() {
+	now := time.Now()
+	startDate := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	endDate := startDate.AddDate(0, 1, 0).Add(-time.Nanosecond)
+
+	stats, err := a.store.GetStatistics("", &startDate, &endDate, nil)
+	if err != nil {
+		a.monthBadge.SetText("[red]Monthly totals unavailable[-] ")
+		return
+	}
+
+	timers, _ := a.store.ListTimers()
+	a.monthBadge.SetText(formatTimerBadge(timers, a.projectNames(), now) + formatMonthBadge(stats, now))
+}
+
+// projectNames maps project IDs to names for display
+func (a *App) projectNames() map[string]string {
+	names := make(map[string]string)
+	if projects, err := a.store.ListProjects(true); err == nil {
+		for _, project := range projects {
+			names[project.ID] = project.Name
+		}
+	}
+	return names
+}
+
+// formatMonthBadge builds the header text for a month's statistics snapshot
+func formatMonthBadge(stats *db.Statistics, now time.Time) string {
+	return fmt.Sprintf("[::b]%s %d:[::-] %s logged | [yellow]%s uninvoiced[-] ",
+		now.Month(), now.Year(),
+		FormatDuration(stats.TotalMinutes),
+		FormatDuration(stats.UninvoicedMinutes))
+}
+
+// formatTimerBadge builds the header text for active timers, or "" when none are running
+func formatTimerBadge(timers []*models.Timer, projectNames map[string]string, now time.Time) string {
+	if len(timers) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(timers))
+	for _, timer := range timers {
+		name, ok := projectNames[timer.ProjectID]
+		if !ok {
+			name = "Unknown Project"
+		}
+		elapsed := FormatDuration(int64(timer.Elapsed(now) / time.Minute))
+		if timer.Paused() {
+			parts = append(parts, fmt.Sprintf("[gray]⏸ %s %s[-]", tview.Escape(name), elapsed))
+		} else {
+			parts = append(parts, fmt.Sprintf("[green]⏱ %s %s[-]", tview.Escape(name), elapsed))
+		}
+	}
+
+	return strings.Join(parts, " ") + " | "
+}
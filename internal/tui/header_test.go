@@ -0,0 +1,50 @@
+package tui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func TestFormatMonthBadge(t *testing.T) {
+	stats := &db.Statistics{
+		TotalMinutes:      750,
+		InvoicedMinutes:   510,
+		UninvoicedMinutes: 240,
+	}
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+	got := formatMonthBadge(stats, now)
+	want := "[::b]October 2026:[::-] 12h 30m logged | [yellow]4h uninvoiced[-] "
+	if got != want {
+		t.Errorf("formatMonthBadge() = %q, want %q", got, want)
+	}
+
+	empty := formatMonthBadge(&db.Statistics{}, now)
+	wantEmpty := "[::b]October 2026:[::-] 0m logged | [yellow]0m uninvoiced[-] "
+	if empty != wantEmpty {
+		t.Errorf("formatMonthBadge() = %q, want %q", empty, wantEmpty)
+	}
+}
+
+func TestFormatTimerBadge(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+	pausedAt := now.Add(-20 * time.Minute)
+	timers := []*models.Timer{
+		{ProjectID: "a", StartedAt: now.Add(-65 * time.Minute)},
+		{ProjectID: "b", StartedAt: now.Add(-time.Hour), Pauses: []models.TimerPause{{Start: pausedAt}}},
+	}
+	names := map[string]string{"a": "Alpha", "b": "Beta"}
+
+	got := formatTimerBadge(timers, names, now)
+	want := "[green]⏱ Alpha 1h 5m[-] [gray]⏸ Beta 40m[-] | "
+	if got != want {
+		t.Errorf("formatTimerBadge() = %q, want %q", got, want)
+	}
+
+	if got := formatTimerBadge(nil, names, now); got != "" {
+		t.Errorf("formatTimerBadge(nil) = %q, want empty", got)
+	}
+}
@@ -2,24 +2,27 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/rivo/uniseg"
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/stats"
+	"github.com/techthos/clockwork/internal/utils"
 )
 
 // FormatDuration converts minutes to a human-readable string
 func FormatDuration(minutes int64) string {
-	if minutes == 0 {
-		return "0m"
-	}
-
-	hours := minutes / 60
-	mins := minutes % 60
+	return utils.FormatDuration(minutes)
+}
 
-	if hours > 0 && mins > 0 {
-		return fmt.Sprintf("%dh %dm", hours, mins)
-	} else if hours > 0 {
-		return fmt.Sprintf("%dh", hours)
+// durationFormatter returns the duration formatter for a display mode
+// Decimal mode shows decimal hours; anything else uses FormatDuration
+func durationFormatter(mode string) func(minutes int64) string {
+	if mode == utils.DisplayDecimal {
+		return utils.FormatDecimalHours
 	}
-	return fmt.Sprintf("%dm", mins)
+	return FormatDuration
 }
 
 // FormatDate formats a time.Time to a readable date string
@@ -32,15 +35,48 @@ func FormatDateTime(t time.Time) string {
 	return t.Format("2006-01-02 15:04")
 }
 
-// TruncateString truncates a string to maxLen and adds "..." if needed
+// DisplayWidth returns the number of terminal columns s occupies, counting wide characters
+// (CJK, most emoji) as two, the same way tview measures text
+func DisplayWidth(s string) int {
+	return uniseg.StringWidth(s)
+}
+
+// TruncateString truncates a string to maxLen display columns and adds "..." if needed
+// It only cuts between grapheme clusters, so multi-byte and wide characters are never split
 func TruncateString(s string, maxLen int) string {
-	if len(s) <= maxLen {
+	if DisplayWidth(s) <= maxLen {
 		return s
 	}
 	if maxLen <= 3 {
-		return s[:maxLen]
+		return truncateWidth(s, maxLen)
 	}
-	return s[:maxLen-3] + "..."
+	return truncateWidth(s, maxLen-3) + "..."
+}
+
+// truncateWidth returns the longest prefix of s that fits in width display columns
+func truncateWidth(s string, width int) string {
+	var builder strings.Builder
+	state := -1
+	for s != "" {
+		var cluster string
+		var clusterWidth int
+		cluster, s, clusterWidth, state = uniseg.FirstGraphemeClusterInString(s, state)
+		if clusterWidth > width {
+			break
+		}
+		width -= clusterWidth
+		builder.WriteString(cluster)
+	}
+	return builder.String()
+}
+
+// PadRight pads s with spaces to width display columns; fmt's %-30s counts runes,
+// which misaligns columns holding wide characters
+func PadRight(s string, width int) string {
+	if pad := width - DisplayWidth(s); pad > 0 {
+		return s + strings.Repeat(" ", pad)
+	}
+	return s
 }
 
 // FormatPercentage formats a float as a percentage string
@@ -51,3 +87,30 @@ func FormatPercentage(value, total float64) string {
 	pct := (value / total) * 100
 	return fmt.Sprintf("%.1f%%", pct)
 }
+
+// formatEstimateVariance describes how an entry's duration compares to its estimate,
+// e.g. "30m over (150% of 1h)"; entries without an estimate yield ""
+func formatEstimateVariance(entry *models.Entry) string {
+	variance, ratio, ok := stats.EstimateVariance(entry)
+	if !ok {
+		return ""
+	}
+	estimate := FormatDuration(entry.EstimateMinutes)
+	switch {
+	case variance > 0:
+		return fmt.Sprintf("%s over (%.0f%% of %s)", FormatDuration(variance), ratio*100, estimate)
+	case variance < 0:
+		return fmt.Sprintf("%s under (%.0f%% of %s)", FormatDuration(-variance), ratio*100, estimate)
+	}
+	return fmt.Sprintf("on estimate (%s)", estimate)
+}
+
+// validateDurationLive reports whether the current text of a duration field is acceptable
+// Empty input is valid since optional duration fields may be left blank
+func validateDurationLive(text string) bool {
+	if strings.TrimSpace(text) == "" {
+		return true
+	}
+	_, err := utils.ParseDuration(text)
+	return err == nil
+}
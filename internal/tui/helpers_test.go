@@ -0,0 +1,129 @@
+package tui
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/utils"
+)
+
+func TestValidateDurationLive(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  bool
+	}{
+		{"hours and minutes", "1h 30m", true},
+		{"empty", "", true},
+		{"whitespace only", "   ", true},
+		{"invalid text", "abc", false},
+		{"partial unit", "1x", false},
+		{"colon format", "1:30", true},
+		{"colon minutes out of range", "1:75", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validateDurationLive(tt.input); got != tt.want {
+				t.Errorf("validateDurationLive(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDurationFormatter(t *testing.T) {
+	tests := []struct {
+		mode    string
+		minutes int64
+		want    string
+	}{
+		{utils.DisplayClock, 90, "1h 30m"},
+		{utils.DisplayDecimal, 90, "1.50h"},
+		{utils.DisplayClock, 20, "20m"},
+		{utils.DisplayDecimal, 20, "0.33h"},
+		{"", 90, "1h 30m"},
+	}
+
+	for _, tt := range tests {
+		if got := durationFormatter(tt.mode)(tt.minutes); got != tt.want {
+			t.Errorf("durationFormatter(%q)(%d) = %q, want %q", tt.mode, tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateString(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		maxLen int
+		want   string
+	}{
+		{"short ascii", "Clockwork", 20, "Clockwork"},
+		{"long ascii", "Clockwork Project", 10, "Clockwo..."},
+		{"tiny limit", "Clockwork", 3, "Clo"},
+		{"multi-byte fits", "Café Müller", 11, "Café Müller"},
+		{"multi-byte cut", "Café Müller GmbH", 10, "Café Mü..."},
+		{"wide characters", "時間管理プロジェクト", 10, "時間管..."},
+		{"wide character not split", "時間管理プロジェクト", 8, "時間..."},
+		{"emoji", "🚀🚀🚀🚀🚀🚀", 7, "🚀🚀..."},
+		{"combining mark kept whole", "e\u0301e\u0301e\u0301e\u0301e\u0301", 4, "e\u0301..."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateString(tt.input, tt.maxLen)
+			if got != tt.want {
+				t.Errorf("TruncateString(%q, %d) = %q, want %q", tt.input, tt.maxLen, got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("TruncateString(%q, %d) produced invalid UTF-8", tt.input, tt.maxLen)
+			}
+			if DisplayWidth(got) > tt.maxLen {
+				t.Errorf("TruncateString(%q, %d) is %d columns wide", tt.input, tt.maxLen, DisplayWidth(got))
+			}
+		})
+	}
+}
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"Café", 4},
+		{"時間", 4},
+		{"🚀", 2},
+	}
+
+	for _, tt := range tests {
+		if got := DisplayWidth(tt.input); got != tt.want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+
+	// Padded columns line up regardless of character width
+	if a, b := PadRight("時間", 10), PadRight("time", 10); DisplayWidth(a) != 10 || DisplayWidth(b) != 10 {
+		t.Errorf("PadRight() widths = %d and %d, want 10", DisplayWidth(a), DisplayWidth(b))
+	}
+}
+
+func TestFormatEstimateVariance(t *testing.T) {
+	tests := []struct {
+		entry models.Entry
+		want  string
+	}{
+		{models.Entry{Duration: 90, EstimateMinutes: 60}, "30m over (150% of 1h)"},
+		{models.Entry{Duration: 45, EstimateMinutes: 60}, "15m under (75% of 1h)"},
+		{models.Entry{Duration: 60, EstimateMinutes: 60}, "on estimate (1h)"},
+		{models.Entry{Duration: 60}, ""},
+	}
+
+	for _, tt := range tests {
+		if got := formatEstimateVariance(&tt.entry); got != tt.want {
+			t.Errorf("formatEstimateVariance(%d/%d) = %q, want %q", tt.entry.Duration, tt.entry.EstimateMinutes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func (a *App) createProjectHistoryView(project *models.Project) tview.Primitive {
+	// Create text view for the timeline
+	textView := tview.NewTextView().
+		SetDynamicColors(true).
+		SetScrollable(true)
+
+	// Create flex layout
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow)
+
+	// Header with title and instructions
+	header := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	header.SetText(fmt.Sprintf("[::b]Project History - %s[::-]\n", project.Name) +
+		"[gray]q: Back")
+	header.SetBorderPadding(1, 1, 0, 0)
+
+	flex.AddItem(header, 4, 0, false)
+	flex.AddItem(textView, 0, 1, true)
+
+	history, err := a.store.ProjectHistory(project.ID)
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to load project history: %v", err), nil)
+	}
+
+	var builder strings.Builder
+	builder.WriteString(fmt.Sprintf("Created: %s\n\n", FormatDateTime(project.CreatedAt)))
+
+	if len(history) == 0 {
+		builder.WriteString("No changes recorded\n")
+	}
+
+	for _, change := range history {
+		builder.WriteString(fmt.Sprintf("[::b]%s[::-]\n", FormatDateTime(change.ChangedAt)))
+		for _, field := range change.Changes {
+			builder.WriteString(fmt.Sprintf("  %-16s [red]%s[-] → [green]%s[-]\n",
+				field.Field, formatHistoryValue(field.OldValue), formatHistoryValue(field.NewValue)))
+		}
+		builder.WriteString("\n")
+	}
+
+	textView.SetText(builder.String())
+
+	// Set up keyboard shortcuts
+	flex.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'q':
+			a.ShowProjectsView()
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.ShowProjectsView()
+			return nil
+		case tcell.KeyCtrlC, tcell.KeyCtrlQ:
+			a.Stop()
+			return nil
+		}
+
+		return event
+	})
+
+	return flex
+}
+
+// formatHistoryValue renders an empty value visibly and escapes tview color tags
+func formatHistoryValue(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return tview.Escape(value)
+}
@@ -1,6 +1,8 @@
 package tui
 
 import (
+	"time"
+
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
 )
@@ -41,6 +43,20 @@ func (a *App) ShowInfoModal(message string, onClose func()) {
 	a.ShowModal("info", modal)
 }
 
+// ShowTransientInfoModal displays an informational message that closes itself after timeout
+func (a *App) ShowTransientInfoModal(message string, timeout time.Duration) {
+	a.ShowInfoModal(message, nil)
+	modal := a.pages.GetPage("info")
+	time.AfterFunc(timeout, func() {
+		a.app.QueueUpdateDraw(func() {
+			// Leave a newer info modal alone
+			if a.pages.GetPage("info") == modal {
+				a.HideModal("info")
+			}
+		})
+	})
+}
+
 // ShowConfirmModal displays a confirmation dialog
 func (a *App) ShowConfirmModal(message string, onConfirm, onCancel func()) {
 	modal := tview.NewModal().
@@ -0,0 +1,81 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/git"
+)
+
+// isFirstRun reports whether the database has no projects yet
+// Errors count as not first run so the guide never hides a broken database
+func isFirstRun(store *db.Store) bool {
+	projects, err := store.ListProjects(true)
+	return err == nil && len(projects) == 0
+}
+
+// onboardingText explains clockwork and, when one was detected, the repository on offer
+func onboardingText(repo string) string {
+	text := "Welcome to Clockwork!\n\n" +
+		"Time is tracked per project, and each project points at a git repository. " +
+		"Entries are built from the commits made since the last entry, or entered manually for meetings and other work."
+	if repo == "" {
+		return text + "\n\nNo git repository was found in the current directory. Create your first project by entering its path."
+	}
+	return text + fmt.Sprintf("\n\nFound a git repository in the current directory:\n%s\n\nUse it for your first project?", repo)
+}
+
+// showOnboarding guides a new user through creating the first project and, optionally, the first git entry
+func (a *App) showOnboarding() {
+	repo := ""
+	if dir, err := os.Getwd(); err == nil {
+		repo, _ = git.RepoRoot(dir)
+	}
+
+	buttons := []string{"Create Project", "Skip"}
+	if repo != "" {
+		buttons = []string{"Use This Repository", "Enter Another Path", "Skip"}
+	}
+
+	modal := tview.NewModal().
+		SetText(onboardingText(repo)).
+		AddButtons(buttons).
+		SetDoneFunc(func(buttonIndex int, buttonLabel string) {
+			a.HideModal("onboarding")
+			switch buttonLabel {
+			case "Use This Repository":
+				a.showProjectForm(nil, filepath.Base(repo), repo, a.offerFirstEntry)
+			case "Enter Another Path", "Create Project":
+				a.showProjectForm(nil, "", "", a.offerFirstEntry)
+			}
+		})
+
+	modal.SetBackgroundColor(tcell.ColorDefault)
+	modal.SetBorderColor(ColorPrimary)
+
+	a.ShowModal("onboarding", modal)
+}
+
+// offerFirstEntry refreshes the projects view after the first project is saved and offers
+// to create its first entry from the repository's commits
+func (a *App) offerFirstEntry() {
+	a.ShowProjectsView()
+
+	projects, err := a.store.ListProjects(false)
+	if err != nil || len(projects) != 1 {
+		return
+	}
+	project := projects[0]
+
+	a.ShowConfirmModal(
+		fmt.Sprintf("Project %q created.\n\nCreate its first entry from the latest git commit now? Later entries cover the commits made since.", project.Name),
+		func() {
+			a.showGitEntryForm(project.ID, a.ShowProjectsView)
+		},
+		nil,
+	)
+}
@@ -0,0 +1,28 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIsFirstRun(t *testing.T) {
+	a := setupTestApp(t)
+
+	if !isFirstRun(a.store) {
+		t.Error("isFirstRun() = false on an empty store, want true")
+	}
+
+	a.store.CreateProject("First", "/path")
+	if isFirstRun(a.store) {
+		t.Error("isFirstRun() = true with a project, want false")
+	}
+}
+
+func TestOnboardingText(t *testing.T) {
+	if got := onboardingText("/home/dev/clockwork"); !strings.Contains(got, "/home/dev/clockwork") {
+		t.Errorf("onboardingText() should offer the detected repository, got %q", got)
+	}
+	if got := onboardingText(""); !strings.Contains(got, "No git repository was found") {
+		t.Errorf("onboardingText() should say no repository was found, got %q", got)
+	}
+}
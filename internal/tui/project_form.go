@@ -2,16 +2,24 @@ package tui
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
+	"strconv"
+	"strings"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/git"
 	"github.com/techthos/clockwork/internal/models"
+	"github.com/techthos/clockwork/internal/utils"
 )
 
 // ShowProjectForm displays the create/edit project form
 func (a *App) ShowProjectForm(project *models.Project, onComplete func()) {
+	a.showProjectForm(project, "", "", onComplete)
+}
+
+// showProjectForm displays the project form; when creating, name and repo pre-fill the fields
+func (a *App) showProjectForm(project *models.Project, name, repo string, onComplete func()) {
 	form := tview.NewForm()
 
 	// Determine if creating or editing
@@ -22,8 +30,8 @@ func (a *App) ShowProjectForm(project *models.Project, onComplete func()) {
 	}
 
 	// Set up form fields
-	nameField := ""
-	repoField := ""
+	nameField := name
+	repoField := repo
 	if isEdit {
 		nameField = project.Name
 		repoField = project.GitRepoPath
@@ -37,6 +45,96 @@ func (a *App) ShowProjectForm(project *models.Project, onComplete func()) {
 		repoField = text
 	})
 
+	// Duration estimation method for git entries
+	methodOptions := git.StrategyNames()
+	methodField := git.DefaultStrategy
+	if isEdit && project.DurationMethod != "" {
+		methodField = project.DurationMethod
+	}
+	selectedMethodIndex := 0
+	for i, method := range methodOptions {
+		if method == methodField {
+			selectedMethodIndex = i
+		}
+	}
+	form.AddDropDown("Duration Method", methodOptions, selectedMethodIndex, func(option string, optionIndex int) {
+		methodField = option
+	})
+
+	// Manual entries store HEAD unless the project opts out (non-code work)
+	recordHeadField := true
+	if isEdit {
+		recordHeadField = project.RecordsHeadForManual()
+	}
+	form.AddCheckbox("Record HEAD on Manual Entries", recordHeadField, func(checked bool) {
+		recordHeadField = checked
+	})
+
+	// Shared repos: only aggregate the local git user's commits
+	ownCommitsField := isEdit && project.OwnCommitsOnly
+	form.AddCheckbox("Only My Commits (git user.name)", ownCommitsField, func(checked bool) {
+		ownCommitsField = checked
+	})
+
+	// Hourly rate prices the project's time in the stats view
+	rateField := ""
+	currencyField := ""
+	if isEdit {
+		currencyField = project.Currency
+		if project.HourlyRate > 0 {
+			rateField = strconv.FormatFloat(project.HourlyRate, 'f', -1, 64)
+		}
+	}
+	form.AddInputField("Hourly Rate (optional)", rateField, 12, nil, func(text string) {
+		rateField = text
+	})
+	form.AddInputField("Currency (optional)", currencyField, 6, nil, func(text string) {
+		currencyField = text
+	})
+
+	// Fixed-bid budget drives the burn-down in the stats view
+	budgetField := ""
+	if isEdit && project.BudgetMinutes > 0 {
+		budgetField = FormatDuration(project.BudgetMinutes)
+	}
+	form.AddInputField("Budget, e.g. 120h (optional)", budgetField, 12, nil, func(text string) {
+		budgetField = text
+	})
+
+	// Persist the project and its options in one transaction
+	save := func() {
+		rate, _ := parseRate(rateField)
+		budget, _ := parseBudget(budgetField)
+		opts := db.ProjectOptions{
+			DurationMethod: &methodField,
+			OwnCommitsOnly: &ownCommitsField,
+			HourlyRate:     &rate,
+			Currency:       &currencyField,
+			BudgetMinutes:  &budget,
+		}
+		// Leave the HEAD setting unset while it matches the default
+		if recordHeadField != (!isEdit || project.RecordsHeadForManual()) {
+			opts.RecordHeadForManual = &recordHeadField
+		}
+
+		var err error
+		if isEdit {
+			_, err = a.store.UpdateProjectWithOptions(project.ID, nameField, repoField, nil, opts)
+		} else {
+			_, err = a.store.CreateProjectWithOptions(nameField, repoField, nil, opts)
+		}
+
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to save project: %v", err), nil)
+			return
+		}
+
+		a.HideModal("project_form")
+		if onComplete != nil {
+			onComplete()
+		}
+	}
+
 	// Add buttons
 	form.AddButton("Save", func() {
 		// Validate inputs
@@ -48,29 +146,37 @@ func (a *App) ShowProjectForm(project *models.Project, onComplete func()) {
 			a.ShowErrorModal("Git repository path cannot be empty", nil)
 			return
 		}
+		if _, err := parseRate(rateField); err != nil {
+			a.ShowErrorModal(err.Error(), nil)
+			return
+		}
+		if _, err := parseBudget(budgetField); err != nil {
+			a.ShowErrorModal(err.Error(), nil)
+			return
+		}
 
 		// Validate git repo path
-		if err := validateGitRepo(repoField); err != nil {
+		if err := git.ValidateRepo(repoField).Err; err != nil {
 			a.ShowErrorModal(fmt.Sprintf("Invalid git repository: %v", err), nil)
 			return
 		}
 
-		var err error
+		// Nested repos make commit ranges overlap; ask before saving one
+		projectID := ""
 		if isEdit {
-			_, err = a.store.UpdateProject(project.ID, nameField, repoField)
-		} else {
-			_, err = a.store.CreateProject(nameField, repoField)
+			projectID = project.ID
 		}
-
+		overlapping, relation, err := a.store.FindOverlappingProject(repoField, projectID)
 		if err != nil {
-			a.ShowErrorModal(fmt.Sprintf("Failed to save project: %v", err), nil)
+			a.ShowErrorModal(fmt.Sprintf("Failed to check project paths: %v", err), nil)
 			return
 		}
-
-		a.HideModal("project_form")
-		if onComplete != nil {
-			onComplete()
+		if overlapping != nil {
+			a.ShowConfirmModal(db.OverlapMessage(repoField, overlapping, relation)+".\n\nSave anyway?", save, nil)
+			return
 		}
+
+		save()
 	})
 
 	form.AddButton("Cancel", func() {
@@ -95,26 +201,35 @@ func (a *App) ShowProjectForm(project *models.Project, onComplete func()) {
 		AddItem(nil, 0, 1, false).
 		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
 			AddItem(nil, 0, 1, false).
-			AddItem(form, 12, 1, true).
+			AddItem(form, 24, 1, true).
 			AddItem(nil, 0, 1, false), 80, 1, true).
 		AddItem(nil, 0, 1, false)
 
 	a.ShowModal("project_form", modal)
 }
 
-// validateGitRepo checks if the path is a valid git repository
-func validateGitRepo(path string) error {
-	// Check if path exists
-	if _, err := os.Stat(path); os.IsNotExist(err) {
-		return fmt.Errorf("path does not exist")
+// parseRate parses the hourly rate field; an empty field means no rate
+func parseRate(text string) (float64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
 	}
-
-	// Check if it's a git repository
-	cmd := exec.Command("git", "rev-parse", "--git-dir")
-	cmd.Dir = path
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("not a git repository")
+	rate, err := strconv.ParseFloat(text, 64)
+	if err != nil || rate < 0 {
+		return 0, fmt.Errorf("hourly rate must be a non-negative number")
 	}
+	return rate, nil
+}
 
-	return nil
+// parseBudget parses the budget field into minutes; an empty field means no budget
+func parseBudget(text string) (int64, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, nil
+	}
+	minutes, err := utils.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid budget: %w", err)
+	}
+	return minutes, nil
 }
@@ -2,10 +2,15 @@ package tui
 
 import (
 	"fmt"
+	"io"
+	"path/filepath"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
 	"github.com/techthos/clockwork/internal/models"
 )
 
@@ -24,24 +29,47 @@ func (a *App) createProjectsView() tview.Primitive {
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
 	header.SetText("[::b]Clockwork - Project Management[::-]\n" +
-		"[gray]n: New | e: Edit | d: Delete | Enter: View Entries | q: Quit")
+		"[gray]n: New | e: Edit | a: Archive | A: Show Archived | d: Delete | U: Undo Delete | X: Clear Entries | *: Set Default | o: Sort | h: History | c: Catch Up | r: Review | Enter: View Entries | q: Quit")
 	header.SetBorderPadding(1, 1, 0, 0)
 
 	flex.AddItem(header, 4, 0, false)
 	flex.AddItem(table, 0, 1, true)
 
+	// Sort by name unless toggled to last activity
+	sortByActivity := false
+	// Archived projects are hidden unless toggled on
+	showArchived := false
+
 	// Load and display projects
 	loadProjects := func() {
 		table.Clear()
 
-		projects, err := a.store.ListProjects()
+		projects, err := a.store.ListProjects(showArchived)
 		if err != nil {
 			a.ShowErrorModal(fmt.Sprintf("Failed to load projects: %v", err), nil)
 			return
 		}
 
-		// Sort projects by name
+		defaultProjectID, err := a.store.GetDefaultProject()
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load default project: %v", err), nil)
+			return
+		}
+
+		lastActivity, err := a.store.ProjectLastActivity()
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load project activity: %v", err), nil)
+			return
+		}
+
+		// Sort projects by name, or most recent activity first
 		sort.Slice(projects, func(i, j int) bool {
+			if sortByActivity {
+				ti, tj := lastActivity[projects[i].ID], lastActivity[projects[j].ID]
+				if !ti.Equal(tj) {
+					return ti.After(tj)
+				}
+			}
 			return projects[i].Name < projects[j].Name
 		})
 
@@ -58,17 +86,32 @@ func (a *App) createProjectsView() tview.Primitive {
 			SetTextColor(ColorTableHeader).
 			SetAlign(tview.AlignLeft).
 			SetSelectable(false))
+		table.SetCell(0, 3, tview.NewTableCell("Last Activity").
+			SetTextColor(ColorTableHeader).
+			SetAlign(tview.AlignLeft).
+			SetSelectable(false))
 
 		// Add project rows
 		for i, project := range projects {
 			row := i + 1
-			table.SetCell(row, 0, tview.NewTableCell(project.Name).
-				SetTextColor(ColorTableText).
+			name := project.Name
+			if project.ID == defaultProjectID {
+				name = "★ " + name
+			}
+			color := ColorTableText
+			if project.Archived {
+				name += " (archived)"
+				color = ColorBorder
+			}
+			table.SetCell(row, 0, tview.NewTableCell(name).
+				SetTextColor(color).
 				SetReference(project))
 			table.SetCell(row, 1, tview.NewTableCell(project.GitRepoPath).
-				SetTextColor(ColorTableText))
+				SetTextColor(color))
 			table.SetCell(row, 2, tview.NewTableCell(FormatDate(project.CreatedAt)).
-				SetTextColor(ColorTableText))
+				SetTextColor(color))
+			table.SetCell(row, 3, tview.NewTableCell(formatLastActivity(lastActivity, project.ID)).
+				SetTextColor(color))
 		}
 
 		// If no projects, show message
@@ -82,6 +125,8 @@ func (a *App) createProjectsView() tview.Primitive {
 		if len(projects) > 0 {
 			table.Select(1, 0)
 		}
+
+		a.refreshMonthBadge()
 	}
 
 	// Set up keyboard shortcuts
@@ -93,6 +138,9 @@ func (a *App) createProjectsView() tview.Primitive {
 		case 'n':
 			a.ShowProjectForm(nil, loadProjects)
 			return nil
+		case 'U':
+			a.undoLastDeletion(loadProjects)
+			return nil
 		case 'e':
 			row, _ := table.GetSelection()
 			if row > 0 {
@@ -111,6 +159,56 @@ func (a *App) createProjectsView() tview.Primitive {
 				}
 			}
 			return nil
+		case 'a':
+			row, _ := table.GetSelection()
+			if row > 0 {
+				cell := table.GetCell(row, 0)
+				if project, ok := cell.Reference.(*models.Project); ok {
+					a.toggleArchiveProject(project, loadProjects)
+				}
+			}
+			return nil
+		case 'A':
+			showArchived = !showArchived
+			loadProjects()
+			return nil
+		case 'X':
+			row, _ := table.GetSelection()
+			if row > 0 {
+				cell := table.GetCell(row, 0)
+				if project, ok := cell.Reference.(*models.Project); ok {
+					a.showClearEntriesModal(project, loadProjects)
+				}
+			}
+			return nil
+		case 'h':
+			row, _ := table.GetSelection()
+			if row > 0 {
+				cell := table.GetCell(row, 0)
+				if project, ok := cell.Reference.(*models.Project); ok {
+					a.ShowProjectHistoryView(project)
+				}
+			}
+			return nil
+		case 'c':
+			a.ShowCatchUpWizard(loadProjects)
+			return nil
+		case 'r':
+			a.ShowReviewQueueView()
+			return nil
+		case 'o':
+			sortByActivity = !sortByActivity
+			loadProjects()
+			return nil
+		case '*':
+			row, _ := table.GetSelection()
+			if row > 0 {
+				cell := table.GetCell(row, 0)
+				if project, ok := cell.Reference.(*models.Project); ok {
+					a.toggleDefaultProject(project, loadProjects)
+				}
+			}
+			return nil
 		}
 
 		switch event.Key() {
@@ -136,16 +234,159 @@ func (a *App) createProjectsView() tview.Primitive {
 }
 
 func (a *App) confirmDeleteProject(project *models.Project, onComplete func()) {
-	message := fmt.Sprintf("Delete project '%s' and all its entries?", project.Name)
+	message := fmt.Sprintf("Permanently delete project '%s' and all its entries?\nPress 'a' in the list to archive it instead.", project.Name)
 	a.ShowConfirmModal(message,
 		func() {
-			// Confirmed - delete project
-			if err := a.store.DeleteProject(project.ID); err != nil {
+			// Confirmed - capture the project and its entries for undo, then delete
+			deleted, err := a.store.GetProject(project.ID)
+			var entries []*models.Entry
+			if err == nil {
+				entries, err = a.store.ListEntries(project.ID)
+			}
+			if err == nil {
+				err = a.store.DeleteProject(project.ID)
+			}
+			if err != nil {
 				a.ShowErrorModal(fmt.Sprintf("Failed to delete project: %v", err), nil)
 			} else {
+				a.lastDeletion = &deletion{project: deleted, entries: entries}
 				onComplete()
 			}
 		},
 		nil, // Cancel - do nothing
 	)
 }
+
+// showClearEntriesModal asks where to back up a project's entries, then moves them to the trash
+// after confirmation, keeping the project itself
+func (a *App) showClearEntriesModal(project *models.Project, onComplete func()) {
+	form := tview.NewForm()
+	backupPath := strings.ToLower(strings.ReplaceAll(project.Name, " ", "-")) + "-backup.csv"
+
+	form.AddTextView("", "Moves the project's unlocked entries to the trash; the project and its settings are kept", 56, 2, true, false)
+	form.AddInputField("Backup CSV (empty = none)", backupPath, 40, nil, func(text string) {
+		backupPath = strings.TrimSpace(text)
+	})
+
+	form.AddButton("Clear", func() {
+		entries, err := a.store.ListEntries(project.ID)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load entries: %v", err), nil)
+			return
+		}
+		unlocked := 0
+		for _, entry := range entries {
+			if !entry.Locked {
+				unlocked++
+			}
+		}
+		if unlocked == 0 {
+			a.ShowInfoModal("The project has no unlocked entries", nil)
+			return
+		}
+
+		message := fmt.Sprintf("Move all %d unlocked entries of '%s' to the trash?", unlocked, project.Name)
+		if backupPath == "" {
+			message += "\nNo backup will be written."
+		}
+		a.ShowConfirmModal(message, func() {
+			if backupPath != "" {
+				absPath, err := filepath.Abs(backupPath)
+				if err == nil {
+					err = writeExport(ExportCSV, absPath, func(w io.Writer) error {
+						return a.store.StreamExport(w, ExportCSV, db.EntryFilter{ProjectID: project.ID})
+					})
+				}
+				if err != nil {
+					a.ShowErrorModal(fmt.Sprintf("Backup failed, nothing was cleared: %v", err), nil)
+					return
+				}
+				backupPath = absPath
+			}
+
+			cleared, err := a.store.ClearProjectEntries(project.ID)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to clear entries: %v", err), nil)
+				return
+			}
+			a.HideModal("clear_entries_form")
+
+			result := fmt.Sprintf("Moved %d entries to the trash", cleared)
+			if backupPath != "" {
+				result += fmt.Sprintf("\nBackup: %s", backupPath)
+			}
+			a.ShowInfoModal(result, onComplete)
+		}, nil)
+	})
+
+	form.AddButton("Cancel", func() {
+		a.HideModal("clear_entries_form")
+	})
+
+	form.SetBorder(true).
+		SetTitle(fmt.Sprintf("Clear Entries - %s", project.Name)).
+		SetTitleAlign(tview.AlignLeft).
+		SetBorderColor(ColorPrimary)
+
+	form.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		if event.Key() == tcell.KeyEsc {
+			a.HideModal("clear_entries_form")
+			return nil
+		}
+		return event
+	})
+
+	// Center the form
+	modal := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(form, 10, 1, true).
+			AddItem(nil, 0, 1, false), 64, 1, true).
+		AddItem(nil, 0, 1, false)
+
+	a.ShowModal("clear_entries_form", modal)
+}
+
+func (a *App) toggleDefaultProject(project *models.Project, onComplete func()) {
+	defaultProjectID, err := a.store.GetDefaultProject()
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to load default project: %v", err), nil)
+		return
+	}
+
+	newDefault := project.ID
+	if defaultProjectID == project.ID {
+		newDefault = ""
+	}
+
+	if err := a.store.SetDefaultProject(newDefault); err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to set default project: %v", err), nil)
+	} else {
+		onComplete()
+	}
+}
+
+// toggleArchiveProject archives a project, or lists an archived one again
+func (a *App) toggleArchiveProject(project *models.Project, onComplete func()) {
+	var err error
+	if project.Archived {
+		_, err = a.store.UnarchiveProject(project.ID)
+	} else {
+		_, err = a.store.ArchiveProject(project.ID)
+	}
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to archive project: %v", err), nil)
+		return
+	}
+	onComplete()
+}
+
+// formatLastActivity formats a project's last entry date, or "—" if it has none
+func formatLastActivity(lastActivity map[string]time.Time, projectID string) string {
+	last, ok := lastActivity[projectID]
+	if !ok {
+		return "—"
+	}
+	return FormatDate(last)
+}
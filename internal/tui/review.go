@@ -0,0 +1,135 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/models"
+)
+
+func (a *App) createReviewQueueView() tview.Primitive {
+	// Create table for incomplete entries
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false)
+
+	// Create flex layout
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow)
+
+	// Header with title and instructions
+	header := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	header.SetText("[::b]Review Queue - Entries Missing Required Fields[::-]\n" +
+		"[gray]e/Enter: Fix Entry | q: Back")
+	header.SetBorderPadding(1, 1, 0, 0)
+
+	flex.AddItem(header, 4, 0, false)
+	flex.AddItem(table, 0, 1, true)
+
+	// Load and display incomplete entries
+	loadQueue := func() {
+		table.Clear()
+
+		requireReference, requireCategory, err := a.store.GetEntryPolicy()
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load settings: %v", err), nil)
+			return
+		}
+
+		if !requireReference && !requireCategory {
+			table.SetCell(0, 0, tview.NewTableCell("No entry policy configured. Set require_reference or require_category to 'true'.").
+				SetTextColor(ColorInfo).
+				SetSelectable(false))
+			return
+		}
+
+		entries, err := a.store.FindIncompleteEntries(requireReference, requireCategory)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load entries: %v", err), nil)
+			return
+		}
+
+		names := a.projectNames()
+
+		// Set table headers
+		for col, title := range []string{"Date", "Project", "Message", "Missing"} {
+			table.SetCell(0, col, tview.NewTableCell(title).
+				SetTextColor(ColorTableHeader).
+				SetSelectable(false))
+		}
+
+		for i, entry := range entries {
+			row := i + 1
+
+			projectName, ok := names[entry.ProjectID]
+			if !ok {
+				projectName = "Unknown Project"
+			}
+			missing := db.MissingEntryFields(entry, requireReference, requireCategory)
+
+			table.SetCell(row, 0, tview.NewTableCell(FormatDate(entry.CreatedAt)).
+				SetTextColor(ColorTableText).
+				SetReference(entry))
+			table.SetCell(row, 1, tview.NewTableCell(TruncateString(projectName, 20)).
+				SetTextColor(ColorTableText))
+			table.SetCell(row, 2, tview.NewTableCell(TruncateString(entry.Message, 50)).
+				SetTextColor(ColorTableText))
+			table.SetCell(row, 3, tview.NewTableCell(strings.Join(missing, ", ")).
+				SetTextColor(ColorError))
+		}
+
+		if len(entries) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("All entries are complete.").
+				SetTextColor(ColorInfo).
+				SetAlign(tview.AlignCenter))
+			return
+		}
+
+		table.Select(1, 0)
+	}
+
+	// Open the selected entry in the edit form
+	fixSelected := func() {
+		row, _ := table.GetSelection()
+		if row > 0 {
+			cell := table.GetCell(row, 0)
+			if entry, ok := cell.Reference.(*models.Entry); ok {
+				a.ShowEntryForm(entry, "", loadQueue)
+			}
+		}
+	}
+
+	// Set up keyboard shortcuts
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'q':
+			a.ShowProjectsView()
+			return nil
+		case 'e':
+			fixSelected()
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyEnter:
+			fixSelected()
+			return nil
+		case tcell.KeyEscape:
+			a.ShowProjectsView()
+			return nil
+		case tcell.KeyCtrlC, tcell.KeyCtrlQ:
+			a.Stop()
+			return nil
+		}
+
+		return event
+	})
+
+	loadQueue()
+	return flex
+}
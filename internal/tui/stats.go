@@ -8,8 +8,22 @@ import (
 
 	"github.com/gdamore/tcell/v2"
 	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/stats"
 )
 
+// compactStatsHeight is the view height (rows) below which statistics render compact by default
+const compactStatsHeight = 30
+
+// compactTopProjects is the number of projects listed in the compact statistics
+const compactTopProjects = 3
+
+// periodBarWidth is the width of the longest bar in the time-by-period section
+const periodBarWidth = 30
+
+// statsGroupings is the order the stats view cycles through time groupings ("" = off)
+var statsGroupings = []string{"", db.GroupByDay, db.GroupByWeek, db.GroupByMonth}
+
 func (a *App) createStatsView(projectID string, filterOptions *FilterOptions) tview.Primitive {
 	// Create text view for statistics
 	textView := tview.NewTextView().
@@ -25,12 +39,46 @@ func (a *App) createStatsView(projectID string, filterOptions *FilterOptions) tv
 		SetDynamicColors(true).
 		SetTextAlign(tview.AlignCenter)
 	header.SetText("[::b]Statistics[::-]\n" +
-		"[gray]f: Filter | r: Refresh | q: Back")
+		"[gray]f: Filter | r: Refresh | c: Compact/Full | t: By Ticket | a: Annual Summary | b: Burn-Down | w: Group by Day/Week/Month | q: Back")
 	header.SetBorderPadding(1, 1, 0, 0)
 
 	flex.AddItem(header, 4, 0, false)
 	flex.AddItem(textView, 0, 1, true)
 
+	// Compact mode follows the view height unless toggled manually
+	var fullText, compactText string
+	var compactOverride *bool
+	viewHeight := 0
+	isCompact := func() bool {
+		if compactOverride != nil {
+			return *compactOverride
+		}
+		return viewHeight > 0 && viewHeight < compactStatsHeight
+	}
+	showStats := func() {
+		if isCompact() {
+			textView.SetText(compactText)
+		} else {
+			textView.SetText(fullText)
+		}
+		textView.ScrollToBeginning()
+	}
+
+	// Re-render when the automatic choice changes with the terminal size
+	flex.SetDrawFunc(func(screen tcell.Screen, x, y, width, height int) (int, int, int, int) {
+		if height != viewHeight {
+			wasCompact := isCompact()
+			viewHeight = height
+			if isCompact() != wasCompact {
+				showStats()
+			}
+		}
+		return x, y, width, height
+	})
+
+	// Time grouping shown beneath the breakdowns, cycled with 'w'
+	groupBy := ""
+
 	// Load and display statistics
 	loadStats := func() {
 		textView.Clear()
@@ -71,6 +119,9 @@ func (a *App) createStatsView(projectID string, filterOptions *FilterOptions) tv
 		builder.WriteString("[::b]Overall Statistics[::-]\n\n")
 		builder.WriteString(fmt.Sprintf("Total Time:          %s (%.2f hours)\n",
 			FormatDuration(stats.TotalMinutes), stats.TotalHours))
+		if amount := formatAmounts(stats); amount != "" {
+			builder.WriteString(fmt.Sprintf("Billable Amount:     %s\n", amount))
+		}
 		builder.WriteString(fmt.Sprintf("Entry Count:         %d\n\n", stats.EntryCount))
 
 		// Date range
@@ -98,6 +149,20 @@ func (a *App) createStatsView(projectID string, filterOptions *FilterOptions) tv
 			builder.WriteString("No data available\n\n")
 		}
 
+		// Focus vs overhead split by tag
+		if stats.TotalMinutes > 0 {
+			builder.WriteString(a.focusSplitSection(projID, startDate, endDate, invoicedFilter))
+		}
+
+		// Estimate vs actual, over entries that had an estimate
+		if estimates := stats.Estimates; estimates != nil {
+			builder.WriteString("[::b]Estimate vs Actual[::-]\n\n")
+			builder.WriteString(fmt.Sprintf("Estimated Entries:   %d\n", estimates.EntryCount))
+			builder.WriteString(fmt.Sprintf("Estimated:           %s\n", FormatDuration(estimates.EstimateMinutes)))
+			builder.WriteString(fmt.Sprintf("Actual:              %s\n", FormatDuration(estimates.ActualMinutes)))
+			builder.WriteString(fmt.Sprintf("Average Ratio:       %.0f%% of estimate\n\n", estimates.AverageRatio*100))
+		}
+
 		// Project breakdown
 		if len(stats.ProjectBreakdown) > 0 {
 			builder.WriteString("[::b]Project Breakdown[::-]\n\n")
@@ -129,14 +194,61 @@ func (a *App) createStatsView(projectID string, filterOptions *FilterOptions) tv
 
 			for _, ps := range projectStats {
 				pct := FormatPercentage(float64(ps.minutes), float64(stats.TotalMinutes))
-				builder.WriteString(fmt.Sprintf("%-30s %s (%.2f hours) - %s\n",
-					TruncateString(ps.name, 30),
+				builder.WriteString(fmt.Sprintf("%s %s (%.2f hours) - %s\n",
+					PadRight(TruncateString(ps.name, 30), 30),
 					FormatDuration(ps.minutes),
 					float64(ps.minutes)/60.0,
 					pct))
 			}
 		}
 
+		// Tag breakdown (entries with several tags count towards each)
+		if len(stats.TagBreakdown) > 0 {
+			builder.WriteString("\n[::b]Tag Breakdown[::-]\n\n")
+
+			tags := make([]string, 0, len(stats.TagBreakdown))
+			for tag := range stats.TagBreakdown {
+				tags = append(tags, tag)
+			}
+			sort.Slice(tags, func(i, j int) bool {
+				if stats.TagBreakdown[tags[i]] != stats.TagBreakdown[tags[j]] {
+					return stats.TagBreakdown[tags[i]] > stats.TagBreakdown[tags[j]]
+				}
+				return tags[i] < tags[j]
+			})
+
+			for _, tag := range tags {
+				minutes := stats.TagBreakdown[tag]
+				builder.WriteString(fmt.Sprintf("%s %s (%.2f hours) - %s\n",
+					PadRight(TruncateString(tag, 30), 30),
+					FormatDuration(minutes),
+					float64(minutes)/60.0,
+					FormatPercentage(float64(minutes), float64(stats.TotalMinutes))))
+			}
+		}
+
+		// Distribution over the days of the week
+		if stats.TotalMinutes > 0 {
+			breakdown, err := a.store.WeekdayBreakdown(projID, startDate, endDate, invoicedFilter, time.Local)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to group statistics: %v", err), nil)
+				return
+			}
+			builder.WriteString("\n[::b]Time by Weekday[::-]\n\n")
+			builder.WriteString(renderWeekdayBars(db.WeekdayTotals(breakdown)))
+		}
+
+		// Time series per day, week or month
+		if groupBy != "" {
+			periods, err := a.store.GetPeriodTotals(projID, startDate, endDate, invoicedFilter, groupBy, time.Local)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to group statistics: %v", err), nil)
+				return
+			}
+			builder.WriteString(fmt.Sprintf("\n[::b]Time by %s[::-]\n\n", strings.ToUpper(groupBy[:1])+groupBy[1:]))
+			builder.WriteString(renderPeriodBars(periods))
+		}
+
 		// Active filters
 		if filterOptions != nil {
 			builder.WriteString("\n[::b]Active Filters[::-]\n\n")
@@ -167,7 +279,10 @@ func (a *App) createStatsView(projectID string, filterOptions *FilterOptions) tv
 			}
 		}
 
-		textView.SetText(builder.String())
+		fullText = builder.String()
+		compactText = renderStatsCompact(stats, a.projectNames())
+		showStats()
+		a.refreshMonthBadge()
 	}
 
 	// Set up keyboard shortcuts
@@ -184,6 +299,44 @@ func (a *App) createStatsView(projectID string, filterOptions *FilterOptions) tv
 		case 'r':
 			loadStats()
 			return nil
+		case 'w':
+			for i, grouping := range statsGroupings {
+				if grouping == groupBy {
+					groupBy = statsGroupings[(i+1)%len(statsGroupings)]
+					break
+				}
+			}
+			loadStats()
+			return nil
+		case 'c':
+			compact := !isCompact()
+			compactOverride = &compact
+			showStats()
+			return nil
+		case 't':
+			if filterOptions == nil {
+				filterOptions = &FilterOptions{ProjectID: projectID}
+			}
+			a.ShowTicketsView(filterOptions)
+			return nil
+		case 'a':
+			projID := projectID
+			if filterOptions != nil {
+				projID = filterOptions.ProjectID
+			}
+			a.ShowAnnualView(projID, time.Now().Year())
+			return nil
+		case 'b':
+			projID := projectID
+			if filterOptions != nil {
+				projID = filterOptions.ProjectID
+			}
+			if projID == "" {
+				a.ShowInfoModal("Filter the stats to a project to see its budget burn-down", nil)
+				return nil
+			}
+			a.ShowBurnDownView(projID)
+			return nil
 		case 'f':
 			if filterOptions == nil {
 				filterOptions = &FilterOptions{ProjectID: projectID}
@@ -204,3 +357,166 @@ func (a *App) createStatsView(projectID string, filterOptions *FilterOptions) tv
 	loadStats()
 	return flex
 }
+
+// formatAmounts renders the billable amounts per currency, noting time without a rate
+// Returns "" when no project in the statistics has an hourly rate
+func formatAmounts(statistics *db.Statistics) string {
+	amount := formatCurrencyAmounts(statistics.Amounts)
+	if amount != "" && statistics.UnpricedMinutes > 0 {
+		amount += fmt.Sprintf(" (%s without a rate)", FormatDuration(statistics.UnpricedMinutes))
+	}
+	return amount
+}
+
+// formatCurrencyAmounts renders amounts per currency, e.g. "450.00 EUR + 120.00 USD"
+// Returns "" when there are none
+func formatCurrencyAmounts(amounts map[string]float64) string {
+	currencies := make([]string, 0, len(amounts))
+	for currency := range amounts {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	parts := make([]string, 0, len(currencies))
+	for _, currency := range currencies {
+		parts = append(parts, strings.TrimSpace(fmt.Sprintf("%.2f %s", amounts[currency], currency)))
+	}
+	return strings.Join(parts, " + ")
+}
+
+// renderStatsCompact renders a dense summary of statistics that fits in about ten lines:
+// totals, date range, invoiced share and the top projects by time
+func renderStatsCompact(statistics *db.Statistics, projectNames map[string]string) string {
+	var builder strings.Builder
+
+	builder.WriteString(fmt.Sprintf("[::b]Total:[::-]     %s (%.2f hours) in %d entries\n",
+		FormatDuration(statistics.TotalMinutes), statistics.TotalHours, statistics.EntryCount))
+	if amount := formatAmounts(statistics); amount != "" {
+		builder.WriteString(fmt.Sprintf("[::b]Amount:[::-]    %s\n", amount))
+	}
+	if statistics.EarliestEntry != nil && statistics.LatestEntry != nil {
+		builder.WriteString(fmt.Sprintf("[::b]Range:[::-]     %s to %s\n",
+			FormatDate(*statistics.EarliestEntry), FormatDate(*statistics.LatestEntry)))
+	}
+	if statistics.TotalMinutes == 0 {
+		builder.WriteString("No data available\n")
+		return builder.String()
+	}
+
+	builder.WriteString(fmt.Sprintf("[::b]Invoiced:[::-]  %s - %s (uninvoiced %s)\n",
+		FormatDuration(statistics.InvoicedMinutes),
+		FormatPercentage(float64(statistics.InvoicedMinutes), float64(statistics.TotalMinutes)),
+		FormatDuration(statistics.UninvoicedMinutes)))
+
+	// Top projects by time, ties broken by name
+	type projectStat struct {
+		name    string
+		minutes int64
+	}
+	projectStats := make([]projectStat, 0, len(statistics.ProjectBreakdown))
+	for projectID, minutes := range statistics.ProjectBreakdown {
+		name, ok := projectNames[projectID]
+		if !ok {
+			name = "Unknown Project"
+		}
+		projectStats = append(projectStats, projectStat{name: name, minutes: minutes})
+	}
+	sort.Slice(projectStats, func(i, j int) bool {
+		if projectStats[i].minutes != projectStats[j].minutes {
+			return projectStats[i].minutes > projectStats[j].minutes
+		}
+		return projectStats[i].name < projectStats[j].name
+	})
+
+	builder.WriteString("\n[::b]Top Projects[::-]\n")
+	for i, ps := range projectStats {
+		if i == compactTopProjects {
+			builder.WriteString(fmt.Sprintf("   ... %d more\n", len(projectStats)-compactTopProjects))
+			break
+		}
+		builder.WriteString(fmt.Sprintf("%d. %s %s - %s\n",
+			i+1,
+			PadRight(TruncateString(ps.name, 24), 24),
+			FormatDuration(ps.minutes),
+			FormatPercentage(float64(ps.minutes), float64(statistics.TotalMinutes))))
+	}
+
+	return builder.String()
+}
+
+// focusSplitSection renders the focus vs overhead breakdown for the filtered entries
+func (a *App) focusSplitSection(projectID string, startDate, endDate *time.Time, invoicedFilter *bool) string {
+	entries, err := a.store.ListEntriesFiltered(projectID, startDate, endDate, invoicedFilter)
+	if err != nil {
+		return fmt.Sprintf("[red]Focus split unavailable: %v[-]\n\n", err)
+	}
+
+	value, err := a.store.GetSetting(db.SettingFocusMapping)
+	if err != nil {
+		return fmt.Sprintf("[red]Focus split unavailable: %v[-]\n\n", err)
+	}
+	mapping, err := stats.ParseFocusMapping(value)
+	if err != nil {
+		return fmt.Sprintf("[red]Invalid focus mapping: %v[-]\n\n", err)
+	}
+
+	split := stats.FocusSplit(entries, mapping)
+
+	var builder strings.Builder
+	builder.WriteString("[::b]Focus vs Overhead[::-]\n\n")
+	builder.WriteString(fmt.Sprintf("[green]Focus:[::-]           %s - %s\n",
+		FormatDuration(split.FocusMinutes), FormatPercentage(float64(split.FocusMinutes), float64(split.TotalMinutes))))
+	builder.WriteString(fmt.Sprintf("[yellow]Overhead:[::-]        %s - %s\n",
+		FormatDuration(split.OverheadMinutes), FormatPercentage(float64(split.OverheadMinutes), float64(split.TotalMinutes))))
+	builder.WriteString(fmt.Sprintf("Other:           %s - %s\n\n",
+		FormatDuration(split.OtherMinutes), FormatPercentage(float64(split.OtherMinutes), float64(split.TotalMinutes))))
+	return builder.String()
+}
+
+// renderPeriodBars lists each period with a bar scaled to the busiest one
+func renderPeriodBars(periods []db.PeriodTotal) string {
+	if len(periods) == 0 {
+		return "No data available\n"
+	}
+
+	labels := make([]string, len(periods))
+	minutes := make([]int64, len(periods))
+	for i, period := range periods {
+		labels[i], minutes[i] = period.Label, period.Minutes
+	}
+	return renderBars(labels, minutes)
+}
+
+// renderWeekdayBars lists each weekday with a bar scaled to the busiest one
+func renderWeekdayBars(weekdays []db.WeekdayTotal) string {
+	labels := make([]string, len(weekdays))
+	minutes := make([]int64, len(weekdays))
+	for i, weekday := range weekdays {
+		labels[i], minutes[i] = weekday.Weekday, weekday.Minutes
+	}
+	return renderBars(labels, minutes)
+}
+
+// renderBars draws one labelled bar per value, scaled so the largest fills periodBarWidth
+func renderBars(labels []string, minutes []int64) string {
+	var busiest int64
+	for _, value := range minutes {
+		if value > busiest {
+			busiest = value
+		}
+	}
+
+	var builder strings.Builder
+	for i, value := range minutes {
+		width := 0
+		if busiest > 0 {
+			width = int(value * periodBarWidth / busiest)
+		}
+		builder.WriteString(fmt.Sprintf("%s [green]%s[::-]%s %s\n",
+			PadRight(labels[i], 10),
+			strings.Repeat("█", width),
+			strings.Repeat(" ", periodBarWidth-width),
+			FormatDuration(value)))
+	}
+	return builder.String()
+}
@@ -0,0 +1,73 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/techthos/clockwork/internal/db"
+)
+
+func TestRenderStatsCompact(t *testing.T) {
+	earliest := time.Date(2026, time.October, 1, 9, 0, 0, 0, time.UTC)
+	latest := time.Date(2026, time.October, 15, 17, 0, 0, 0, time.UTC)
+	statistics := &db.Statistics{
+		TotalMinutes:      600,
+		TotalHours:        10,
+		EntryCount:        12,
+		InvoicedMinutes:   150,
+		UninvoicedMinutes: 450,
+		ProjectBreakdown: map[string]int64{
+			"p1": 300,
+			"p2": 120,
+			"p3": 120,
+			"p4": 60,
+		},
+		EarliestEntry: &earliest,
+		LatestEntry:   &latest,
+	}
+	names := map[string]string{"p1": "Alpha", "p2": "Charlie", "p3": "Bravo", "p4": "Delta"}
+
+	got := renderStatsCompact(statistics, names)
+	want := "[::b]Total:[::-]     10h (10.00 hours) in 12 entries\n" +
+		"[::b]Range:[::-]     2026-10-01 to 2026-10-15\n" +
+		"[::b]Invoiced:[::-]  2h 30m - 25.0% (uninvoiced 7h 30m)\n" +
+		"\n" +
+		"[::b]Top Projects[::-]\n" +
+		"1. Alpha                    5h - 50.0%\n" +
+		"2. Bravo                    2h - 20.0%\n" +
+		"3. Charlie                  2h - 20.0%\n" +
+		"   ... 1 more\n"
+	if got != want {
+		t.Errorf("renderStatsCompact() =\n%s\nwant\n%s", got, want)
+	}
+
+	if lines := strings.Count(got, "\n"); lines > 10 {
+		t.Errorf("renderStatsCompact() uses %d lines, want at most 10", lines)
+	}
+}
+
+func TestRenderStatsCompactEmpty(t *testing.T) {
+	got := renderStatsCompact(&db.Statistics{ProjectBreakdown: map[string]int64{}}, nil)
+	want := "[::b]Total:[::-]     0m (0.00 hours) in 0 entries\nNo data available\n"
+	if got != want {
+		t.Errorf("renderStatsCompact() = %q, want %q", got, want)
+	}
+}
+
+func TestRenderStatsCompactUnknownProject(t *testing.T) {
+	statistics := &db.Statistics{
+		TotalMinutes:     30,
+		TotalHours:       0.5,
+		EntryCount:       1,
+		ProjectBreakdown: map[string]int64{"gone": 30},
+	}
+
+	got := renderStatsCompact(statistics, map[string]string{})
+	if !strings.Contains(got, "1. Unknown Project") {
+		t.Errorf("Expected deleted project to show as unknown, got %q", got)
+	}
+	if strings.Contains(got, "more") {
+		t.Errorf("Expected no overflow line for a single project, got %q", got)
+	}
+}
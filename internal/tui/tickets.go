@@ -0,0 +1,120 @@
+package tui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/stats"
+)
+
+func (a *App) createTicketsView(filterOptions *FilterOptions) tview.Primitive {
+	// Create table for per-ticket totals
+	table := tview.NewTable().
+		SetBorders(false).
+		SetSelectable(true, false).
+		SetFixed(1, 0)
+
+	// Create flex layout
+	flex := tview.NewFlex().
+		SetDirection(tview.FlexRow)
+
+	// Header with title and instructions
+	header := tview.NewTextView().
+		SetDynamicColors(true).
+		SetTextAlign(tview.AlignCenter)
+	header.SetText("[::b]Time by Ticket[::-]\n" +
+		"[gray]r: Refresh | q: Back  (entries naming several tickets count towards each)")
+	header.SetBorderPadding(1, 1, 0, 0)
+
+	flex.AddItem(header, 4, 0, false)
+	flex.AddItem(table, 0, 1, true)
+
+	// Load and display ticket totals for the stats filter
+	loadTickets := func() {
+		table.Clear()
+
+		value, err := a.store.GetSetting(db.SettingTicketPattern)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load settings: %v", err), nil)
+			return
+		}
+		pattern, err := stats.ParseTicketPattern(value)
+		if err != nil {
+			a.ShowErrorModal(err.Error(), nil)
+			return
+		}
+
+		entries, err := a.store.ListEntriesFiltered(
+			filterOptions.ProjectID,
+			filterOptions.StartDate,
+			filterOptions.EndDate,
+			filterOptions.InvoicedFilter,
+		)
+		if err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to load entries: %v", err), nil)
+			return
+		}
+
+		// Set table headers
+		for col, title := range []string{"Ticket", "Time", "Hours", "Entries"} {
+			table.SetCell(0, col, tview.NewTableCell(title).
+				SetTextColor(ColorTableHeader).
+				SetSelectable(false))
+		}
+
+		totals := stats.ByTicket(entries, pattern)
+		for i, total := range totals {
+			row := i + 1
+			color := ColorTableText
+			if total.Ticket == stats.NoTicket {
+				color = ColorInfo
+			}
+
+			table.SetCell(row, 0, tview.NewTableCell(TruncateString(total.Ticket, 30)).
+				SetTextColor(color))
+			table.SetCell(row, 1, tview.NewTableCell(FormatDuration(total.Minutes)).
+				SetTextColor(color))
+			table.SetCell(row, 2, tview.NewTableCell(fmt.Sprintf("%.2f", float64(total.Minutes)/60.0)).
+				SetTextColor(color))
+			table.SetCell(row, 3, tview.NewTableCell(fmt.Sprintf("%d", total.EntryCount)).
+				SetTextColor(color))
+		}
+
+		if len(totals) == 0 {
+			table.SetCell(1, 0, tview.NewTableCell("No entries match the current filter.").
+				SetTextColor(ColorInfo).
+				SetSelectable(false))
+			return
+		}
+
+		table.Select(1, 0)
+	}
+
+	// Set up keyboard shortcuts
+	table.SetInputCapture(func(event *tcell.EventKey) *tcell.EventKey {
+		switch event.Rune() {
+		case 'q':
+			a.ShowStatsView(filterOptions.ProjectID, filterOptions)
+			return nil
+		case 'r':
+			loadTickets()
+			return nil
+		}
+
+		switch event.Key() {
+		case tcell.KeyEscape:
+			a.ShowStatsView(filterOptions.ProjectID, filterOptions)
+			return nil
+		case tcell.KeyCtrlC, tcell.KeyCtrlQ:
+			a.Stop()
+			return nil
+		}
+
+		return event
+	})
+
+	loadTickets()
+	return flex
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/techthos/clockwork/internal/db"
+	"github.com/techthos/clockwork/internal/utils"
+)
+
+// notifyRecoveredTimers tells the user about timers still running from a previous session
+// Timers past max_timer_minutes are closed first and listed separately
+func (a *App) notifyRecoveredTimers() {
+	now := time.Now()
+	closed, err := a.store.CloseStaleTimers(now)
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to close stale timers: %v", err), nil)
+		return
+	}
+
+	timers, err := a.store.ListTimers()
+	if err != nil || len(timers)+len(closed) == 0 {
+		return
+	}
+
+	names := a.projectNames()
+
+	var builder strings.Builder
+	if len(closed) > 0 {
+		builder.WriteString("Closed forgotten timers (logged at the cap, flagged for adjustment):\n")
+		for _, entry := range closed {
+			builder.WriteString(fmt.Sprintf("\n%s - %s (since %s)",
+				names[entry.ProjectID],
+				FormatDuration(entry.Duration),
+				FormatDateTime(entry.CreatedAt)))
+		}
+		if len(timers) > 0 {
+			builder.WriteString("\n\n")
+		}
+	}
+	if len(timers) > 0 {
+		builder.WriteString("Recovered active timers:\n")
+	}
+	for _, timer := range timers {
+		state := "running"
+		if timer.Paused() {
+			state = "paused"
+		}
+		builder.WriteString(fmt.Sprintf("\n%s - %s (%s, since %s)",
+			names[timer.ProjectID],
+			FormatDuration(int64(timer.Elapsed(now)/time.Minute)),
+			state,
+			FormatDateTime(timer.StartedAt)))
+	}
+
+	a.ShowInfoModal(builder.String(), nil)
+}
+
+// toggleTimer starts a timer for the project, or stops the running one and logs an entry
+func (a *App) toggleTimer(projectID string, onComplete func()) {
+	timer, err := a.store.GetTimer(projectID)
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to load timer: %v", err), nil)
+		return
+	}
+
+	if timer == nil {
+		if _, err := a.store.StartTimer(projectID, time.Now()); err != nil {
+			a.ShowErrorModal(fmt.Sprintf("Failed to start timer: %v", err), nil)
+			return
+		}
+		onComplete()
+		return
+	}
+
+	elapsed := FormatDuration(int64(timer.Elapsed(time.Now()) / time.Minute))
+	a.ShowConfirmModal(fmt.Sprintf("Stop timer and log %s?", elapsed),
+		func() {
+			project, err := a.store.GetProject(projectID)
+			if err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to load project: %v", err), nil)
+				return
+			}
+			message := timer.Message
+			if message == "" {
+				template, _ := a.store.GetSetting(db.SettingManualMessageTemplate)
+				message = utils.RenderMessageTemplate(template, project.Name, timer.StartedAt)
+			}
+
+			if _, err := a.store.StopTimer(projectID, message, false, time.Now()); err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to stop timer: %v", err), nil)
+				return
+			}
+			onComplete()
+		},
+		nil,
+	)
+}
+
+// togglePauseTimer pauses a running timer or resumes a paused one
+func (a *App) togglePauseTimer(projectID string, onComplete func()) {
+	timer, err := a.store.GetTimer(projectID)
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to load timer: %v", err), nil)
+		return
+	}
+	if timer == nil {
+		a.ShowErrorModal("No timer running for this project", nil)
+		return
+	}
+
+	if timer.Paused() {
+		_, err = a.store.ResumeTimer(projectID, time.Now())
+	} else {
+		_, err = a.store.PauseTimer(projectID, time.Now())
+	}
+	if err != nil {
+		a.ShowErrorModal(fmt.Sprintf("Failed to update timer: %v", err), nil)
+		return
+	}
+	onComplete()
+}
+
+// confirmDiscardTimer removes the project's timer without logging an entry
+func (a *App) confirmDiscardTimer(projectID string, onComplete func()) {
+	a.ShowConfirmModal("Discard the running timer without logging an entry?",
+		func() {
+			if err := a.store.DiscardTimer(projectID); err != nil {
+				a.ShowErrorModal(fmt.Sprintf("Failed to discard timer: %v", err), nil)
+				return
+			}
+			onComplete()
+		},
+		nil,
+	)
+}
@@ -0,0 +1,106 @@
+package utils
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Convert converts an amount between currencies using a rate table
+// Rates give the value of one unit of a shared base in each currency, e.g.
+// {"EUR": 1, "USD": 1.08} converts 10 EUR to 10.80 USD. Codes are case-insensitive.
+func Convert(amount float64, from, to string, rates map[string]float64) (float64, error) {
+	from = strings.ToUpper(strings.TrimSpace(from))
+	to = strings.ToUpper(strings.TrimSpace(to))
+
+	if from == to {
+		return amount, nil
+	}
+
+	fromRate, ok := lookupRate(rates, from)
+	if !ok {
+		return 0, fmt.Errorf("no conversion rate for %s", from)
+	}
+	toRate, ok := lookupRate(rates, to)
+	if !ok {
+		return 0, fmt.Errorf("no conversion rate for %s", to)
+	}
+
+	return amount / fromRate * toRate, nil
+}
+
+// ConvertTotals sums per-currency amounts into the target currency
+// Amounts without a usable rate are returned per currency instead of being
+// summed naively, so mixed-currency totals are never silently wrong.
+func ConvertTotals(amounts map[string]float64, target string, rates map[string]float64) (float64, map[string]float64) {
+	var total float64
+	unconverted := make(map[string]float64)
+
+	for currency, amount := range amounts {
+		converted, err := Convert(amount, currency, target, rates)
+		if err != nil {
+			unconverted[strings.ToUpper(currency)] += amount
+			continue
+		}
+		total += converted
+	}
+
+	return total, unconverted
+}
+
+// ParseRates parses a conversion table in the form "EUR=1,USD=1.08"
+// An empty string yields an empty table
+func ParseRates(input string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+
+	for _, pair := range strings.Split(input, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		code, value, ok := strings.Cut(pair, "=")
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if !ok || code == "" {
+			return nil, fmt.Errorf("invalid rate %q (use CODE=rate, e.g. 'USD=1.08')", pair)
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid rate for %s: must be a positive number", code)
+		}
+
+		rates[code] = rate
+	}
+
+	return rates, nil
+}
+
+// FormatRates renders a conversion table in the form accepted by ParseRates
+func FormatRates(rates map[string]float64) string {
+	codes := make([]string, 0, len(rates))
+	for code := range rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+
+	pairs := make([]string, 0, len(codes))
+	for _, code := range codes {
+		pairs = append(pairs, code+"="+strconv.FormatFloat(rates[code], 'f', -1, 64))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// lookupRate finds a rate by currency code, ignoring case in the table keys
+func lookupRate(rates map[string]float64, code string) (float64, bool) {
+	if rate, ok := rates[code]; ok && rate > 0 {
+		return rate, true
+	}
+	for key, rate := range rates {
+		if strings.EqualFold(key, code) && rate > 0 {
+			return rate, true
+		}
+	}
+	return 0, false
+}
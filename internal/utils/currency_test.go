@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	rates := map[string]float64{"EUR": 1, "USD": 1.08, "GBP": 0.85}
+
+	tests := []struct {
+		name     string
+		amount   float64
+		from, to string
+		want     float64
+	}{
+		{"same currency", 100, "EUR", "EUR", 100},
+		{"same currency without rate", 100, "CHF", "chf", 100},
+		{"base to quote", 100, "EUR", "USD", 108},
+		{"quote to base", 108, "USD", "EUR", 100},
+		{"cross rate", 108, "USD", "GBP", 85},
+		{"lowercase codes", 100, "eur", "usd", 108},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.amount, tt.from, tt.to, rates)
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Convert(100, "JPY", "EUR", rates); err == nil {
+		t.Error("Expected error for missing source rate")
+	}
+	if _, err := Convert(100, "EUR", "JPY", rates); err == nil {
+		t.Error("Expected error for missing target rate")
+	}
+}
+
+func TestConvertTotalsFallsBackPerCurrency(t *testing.T) {
+	rates := map[string]float64{"EUR": 1, "USD": 1.25}
+	amounts := map[string]float64{"EUR": 100, "USD": 50, "JPY": 3000}
+
+	total, unconverted := ConvertTotals(amounts, "EUR", rates)
+
+	if math.Abs(total-140) > 1e-9 {
+		t.Errorf("Expected converted total 140, got %v", total)
+	}
+	if len(unconverted) != 1 || unconverted["JPY"] != 3000 {
+		t.Errorf("Expected JPY kept separately, got %v", unconverted)
+	}
+
+	// No rates at all: everything except the target currency stays separate
+	total, unconverted = ConvertTotals(amounts, "EUR", nil)
+	if total != 100 {
+		t.Errorf("Expected only EUR in total, got %v", total)
+	}
+	if len(unconverted) != 2 {
+		t.Errorf("Expected 2 unconverted currencies, got %v", unconverted)
+	}
+}
+
+func TestParseRates(t *testing.T) {
+	rates, err := ParseRates(" eur=1, USD = 1.08 ,")
+	if err != nil {
+		t.Fatalf("ParseRates() error = %v", err)
+	}
+	if rates["EUR"] != 1 || rates["USD"] != 1.08 || len(rates) != 2 {
+		t.Errorf("Unexpected rates: %v", rates)
+	}
+
+	if got := FormatRates(rates); got != "EUR=1,USD=1.08" {
+		t.Errorf("FormatRates() = %q", got)
+	}
+
+	if rates, err := ParseRates(""); err != nil || len(rates) != 0 {
+		t.Errorf("Expected empty table, got %v (%v)", rates, err)
+	}
+
+	for _, input := range []string{"USD", "=1", "USD=abc", "USD=0", "USD=-1"} {
+		if _, err := ParseRates(input); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
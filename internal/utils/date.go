@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DateLayout is the date-only layout accepted alongside RFC3339
+const DateLayout = "2006-01-02"
+
+// DateFormatsHelp lists the accepted date formats for error messages and tool descriptions
+const DateFormatsHelp = "RFC3339 (e.g. '2026-01-15T14:30:00Z'), YYYY-MM-DD, or 'now', 'today', 'yesterday'"
+
+// ParseFlexibleDate parses a date argument
+// Accepted formats:
+//   - RFC3339: "2026-01-15T14:30:00Z"
+//   - Date only: "2026-01-15" -> start of that day in local time
+//   - Keywords: "now", "today" and "yesterday" (start of day in local time)
+func ParseFlexibleDate(input string) (time.Time, error) {
+	return parseFlexibleDate(input, time.Now(), false)
+}
+
+// ParseFlexibleEndDate parses a date argument used as the end of a range
+// Date-only values and day keywords resolve to the last instant of that day
+func ParseFlexibleEndDate(input string) (time.Time, error) {
+	return parseFlexibleDate(input, time.Now(), true)
+}
+
+func parseFlexibleDate(input string, now time.Time, endOfDay bool) (time.Time, error) {
+	value := strings.TrimSpace(input)
+
+	var day time.Time
+	switch strings.ToLower(value) {
+	case "":
+		return time.Time{}, fmt.Errorf("date cannot be empty; use %s", DateFormatsHelp)
+	case "now":
+		return now, nil
+	case "today":
+		day = startOfDay(now)
+	case "yesterday":
+		day = startOfDay(now).AddDate(0, 0, -1)
+	default:
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+			return parsed, nil
+		}
+		parsed, err := time.ParseInLocation(DateLayout, value, now.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q; use %s", input, DateFormatsHelp)
+		}
+		day = parsed
+	}
+
+	if endOfDay {
+		return day.AddDate(0, 0, 1).Add(-time.Nanosecond), nil
+	}
+	return day, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
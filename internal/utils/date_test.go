@@ -0,0 +1,54 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFlexibleDate(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	now := time.Date(2026, 3, 14, 15, 30, 0, 0, loc)
+
+	tests := []struct {
+		name     string
+		input    string
+		endOfDay bool
+		want     time.Time
+	}{
+		{"rfc3339", "2026-01-15T14:30:00Z", false, time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)},
+		{"rfc3339 as end", "2026-01-15T14:30:00Z", true, time.Date(2026, 1, 15, 14, 30, 0, 0, time.UTC)},
+		{"date only", "2026-01-15", false, time.Date(2026, 1, 15, 0, 0, 0, 0, loc)},
+		{"date only as end", "2026-01-15", true, time.Date(2026, 1, 16, 0, 0, 0, 0, loc).Add(-time.Nanosecond)},
+		{"now", "now", false, now},
+		{"today", "today", false, time.Date(2026, 3, 14, 0, 0, 0, 0, loc)},
+		{"today uppercase with spaces", "  TODAY ", false, time.Date(2026, 3, 14, 0, 0, 0, 0, loc)},
+		{"yesterday", "yesterday", false, time.Date(2026, 3, 13, 0, 0, 0, 0, loc)},
+		{"yesterday as end", "yesterday", true, time.Date(2026, 3, 14, 0, 0, 0, 0, loc).Add(-time.Nanosecond)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseFlexibleDate(tt.input, now, tt.endOfDay)
+			if err != nil {
+				t.Fatalf("parseFlexibleDate(%q) error = %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("parseFlexibleDate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlexibleDateErrors(t *testing.T) {
+	for _, input := range []string{"", "garbage", "2026-13-01", "15/01/2026", "tomorrowish"} {
+		_, err := ParseFlexibleDate(input)
+		if err == nil {
+			t.Errorf("Expected error for %q", input)
+			continue
+		}
+		if !strings.Contains(err.Error(), "YYYY-MM-DD") || !strings.Contains(err.Error(), "RFC3339") {
+			t.Errorf("Expected error for %q to list accepted formats, got %q", input, err)
+		}
+	}
+}
@@ -5,8 +5,33 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
+// Rounding policies for converting elapsed time to whole minutes
+// RoundDown is only accepted by RoundMinutes
+const (
+	RoundUp      = "up"
+	RoundNearest = "nearest"
+	RoundDown    = "down"
+)
+
+// Duration display modes
+const (
+	DisplayClock   = "clock"   // "1h 30m"
+	DisplayDecimal = "decimal" // "1.50h"
+)
+
+// Working time used by ParseDuration for the "d" and "w" units
+// Teams with other working hours can override these, e.g. WorkDayMinutes = 450 for 7.5-hour days
+var (
+	WorkDayMinutes int64   = 8 * 60 // Minutes in a working day
+	WorkWeekDays   float64 = 5      // Working days in a week
+)
+
+// durationUnitRegex matches one number-and-unit pair, including decimals and negative values
+var durationUnitRegex = regexp.MustCompile(`(-?\d+\.?\d*)\s*([wdhm])`)
+
 // ParseDuration converts duration strings to minutes
 // Supported formats:
 //   - "1h 30m" -> 90
@@ -14,6 +39,9 @@ import (
 //   - "45m" -> 45
 //   - "90" -> 90 (plain number treated as minutes)
 //   - "1.5h" -> 90
+//   - "1:30" -> 90 (hours:minutes, minutes 0-59)
+//   - "1d 4h 30m" -> 750 (a day is WorkDayMinutes, 8h by default)
+//   - "1w" -> 2400 (a week is WorkWeekDays working days, 5 by default)
 func ParseDuration(input string) (int64, error) {
 	if input == "" {
 		return 0, fmt.Errorf("duration cannot be empty")
@@ -29,32 +57,147 @@ func ParseDuration(input string) (int64, error) {
 		return int64(num), nil
 	}
 
-	// Parse hours and minutes
-	var totalMinutes float64
+	// Clock style as pasted from other timesheet tools
+	if strings.Contains(input, ":") {
+		return parseClockDuration(input)
+	}
 
-	// Extract hours (supports decimal: 1.5h, also catches negative values)
-	hoursRegex := regexp.MustCompile(`(-?\d+\.?\d*)h`)
-	if matches := hoursRegex.FindStringSubmatch(input); len(matches) > 1 {
-		hours, err := strconv.ParseFloat(matches[1], 64)
+	// Sum every unit in one pass, so mixed forms like "2d 3h" combine
+	var totalMinutes float64
+	for _, matches := range durationUnitRegex.FindAllStringSubmatch(input, -1) {
+		value, err := strconv.ParseFloat(matches[1], 64)
 		if err != nil {
-			return 0, fmt.Errorf("invalid hours value: %v", err)
+			return 0, fmt.Errorf("invalid %s value: %v", matches[2], err)
 		}
-		totalMinutes += hours * 60
-	}
 
-	// Extract minutes (also catches negative values)
-	minutesRegex := regexp.MustCompile(`(-?\d+)m`)
-	if matches := minutesRegex.FindStringSubmatch(input); len(matches) > 1 {
-		minutes, err := strconv.ParseFloat(matches[1], 64)
-		if err != nil {
-			return 0, fmt.Errorf("invalid minutes value: %v", err)
+		switch matches[2] {
+		case "w":
+			totalMinutes += value * WorkWeekDays * float64(WorkDayMinutes)
+		case "d":
+			totalMinutes += value * float64(WorkDayMinutes)
+		case "h":
+			totalMinutes += value * 60
+		case "m":
+			totalMinutes += value
 		}
-		totalMinutes += minutes
 	}
 
 	if totalMinutes <= 0 {
-		return 0, fmt.Errorf("invalid duration format. Use '1h 30m', '90m', or '90'")
+		return 0, fmt.Errorf("invalid duration format. Use '1d 2h 30m', '90m', or '90'")
 	}
 
 	return int64(totalMinutes), nil
 }
+
+// parseClockDuration parses "H:MM" into minutes
+func parseClockDuration(input string) (int64, error) {
+	parts := strings.Split(input, ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid duration %q: use a single colon, e.g. '1:30'", input)
+	}
+
+	hours, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid hours in %q: use whole hours, e.g. '1:30'", input)
+	}
+	minutes, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid minutes in %q: use whole minutes, e.g. '1:30'", input)
+	}
+	if minutes > 59 {
+		return 0, fmt.Errorf("invalid minutes in %q: must be between 0 and 59", input)
+	}
+
+	total := int64(hours)*60 + int64(minutes)
+	if total <= 0 {
+		return 0, fmt.Errorf("duration must be positive")
+	}
+	return total, nil
+}
+
+// RoundToMinutes converts an elapsed duration to whole minutes using a rounding policy
+// Stored durations are always integer minutes; an empty policy defaults to RoundNearest
+//   - "up": 90s -> 2, 60s -> 1
+//   - "nearest": 90s -> 2, 89s -> 1
+func RoundToMinutes(elapsed time.Duration, policy string) (int64, error) {
+	if elapsed < 0 {
+		return 0, fmt.Errorf("elapsed time cannot be negative")
+	}
+
+	switch policy {
+	case RoundUp:
+		minutes := int64(elapsed / time.Minute)
+		if elapsed%time.Minute != 0 {
+			minutes++
+		}
+		return minutes, nil
+	case RoundNearest, "":
+		return int64(elapsed.Round(time.Minute) / time.Minute), nil
+	default:
+		return 0, fmt.Errorf("invalid rounding policy %q (use '%s' or '%s')", policy, RoundUp, RoundNearest)
+	}
+}
+
+// ValidateRoundMode checks a rounding direction for RoundMinutes; empty means RoundUp
+func ValidateRoundMode(mode string) error {
+	switch mode {
+	case RoundUp, RoundNearest, RoundDown, "":
+		return nil
+	default:
+		return fmt.Errorf("invalid round mode %q (use '%s', '%s', or '%s')", mode, RoundUp, RoundNearest, RoundDown)
+	}
+}
+
+// RoundMinutes rounds minutes to a multiple of increment in the given direction
+// An increment of zero or less leaves minutes unchanged; an empty or unknown mode rounds up
+// (check modes with ValidateRoundMode). Nearest rounds halfway values up. Time worked never
+// rounds away: a positive duration is at least one increment in every mode.
+//   - 22 by 15 "up" -> 30, "nearest" -> 15, "down" -> 15
+//   - 23 by 15 "nearest" -> 30
+//   - 5 by 15 "down" -> 15
+func RoundMinutes(minutes, increment int64, mode string) int64 {
+	if increment <= 0 {
+		return minutes
+	}
+
+	var rounded int64
+	switch mode {
+	case RoundDown:
+		rounded = minutes / increment * increment
+	case RoundNearest:
+		rounded = (minutes + increment/2) / increment * increment
+	default:
+		rounded = (minutes + increment - 1) / increment * increment
+	}
+	if rounded == 0 && minutes > 0 {
+		return increment
+	}
+	return rounded
+}
+
+// FormatDuration formats minutes as hours and minutes
+//   - 90 -> "1h 30m"
+//   - 120 -> "2h"
+//   - 45 -> "45m"
+func FormatDuration(minutes int64) string {
+	if minutes == 0 {
+		return "0m"
+	}
+
+	hours := minutes / 60
+	mins := minutes % 60
+
+	if hours > 0 && mins > 0 {
+		return fmt.Sprintf("%dh %dm", hours, mins)
+	} else if hours > 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
+
+// FormatDecimalHours formats minutes as decimal hours with two decimals
+//   - 90 -> "1.50h"
+//   - 20 -> "0.33h"
+func FormatDecimalHours(minutes int64) string {
+	return fmt.Sprintf("%.2fh", float64(minutes)/60.0)
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"testing"
+	"time"
 )
 
 func TestParseDuration(t *testing.T) {
@@ -22,6 +23,17 @@ func TestParseDuration(t *testing.T) {
 		{"extra whitespace", "  1h  30m  ", 90, false},
 		{"no space", "1h30m", 90, false},
 		{"large value", "8h 45m", 525, false},
+		{"days only", "2d", 960, false},
+		{"days hours minutes", "1d 4h 30m", 750, false},
+		{"days and hours", "2d 3h", 1140, false},
+		{"days no space", "1d4h", 720, false},
+		{"decimal days", "0.5d", 240, false},
+		{"week", "1w", 2400, false},
+		{"week and days", "1w 2d", 3360, false},
+		{"colon minutes only", "0:45", 45, false},
+		{"colon whole hours", "10:00", 600, false},
+		{"colon hours and minutes", "1:30", 90, false},
+		{"colon single-digit minutes", "2:5", 125, false},
 
 		// Invalid formats
 		{"empty string", "", 0, true},
@@ -31,6 +43,16 @@ func TestParseDuration(t *testing.T) {
 		{"zero hours zero minutes", "0h 0m", 0, true},
 		{"only 'h'", "h", 0, true},
 		{"only 'm'", "m", 0, true},
+		{"only 'd'", "d", 0, true},
+		{"negative days", "-1d", 0, true},
+		{"colon minutes over 59", "1:75", 0, true},
+		{"colon twice", "1:2:3", 0, true},
+		{"colon zero", "0:00", 0, true},
+		{"colon missing minutes", "1:", 0, true},
+		{"colon missing hours", ":30", 0, true},
+		{"colon negative", "-1:30", 0, true},
+		{"colon decimal", "1.5:30", 0, true},
+		{"colon with units", "1h:30m", 0, true},
 	}
 
 	for _, tt := range tests {
@@ -46,3 +68,165 @@ func TestParseDuration(t *testing.T) {
 		})
 	}
 }
+
+func TestParseDurationWorkingTime(t *testing.T) {
+	defer func(day int64, week float64) {
+		WorkDayMinutes, WorkWeekDays = day, week
+	}(WorkDayMinutes, WorkWeekDays)
+
+	// 7.5-hour days, 4-day weeks
+	WorkDayMinutes = 450
+	WorkWeekDays = 4
+
+	tests := []struct {
+		input string
+		want  int64
+	}{
+		{"1d", 450},
+		{"2d 3h", 1080},
+		{"1w", 1800},
+		{"1w 1d 15m", 2265},
+		{"1h 30m", 90},
+		{"90", 90},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if err != nil {
+			t.Errorf("ParseDuration(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDuration(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRoundToMinutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		elapsed time.Duration
+		policy  string
+		want    int64
+		wantErr bool
+	}{
+		{"90 seconds up", 90 * time.Second, RoundUp, 2, false},
+		{"90 seconds nearest", 90 * time.Second, RoundNearest, 2, false},
+		{"89 seconds nearest", 89 * time.Second, RoundNearest, 1, false},
+		{"61 seconds up", 61 * time.Second, RoundUp, 2, false},
+		{"exact minute up", time.Minute, RoundUp, 1, false},
+		{"default policy", 90 * time.Second, "", 2, false},
+		{"zero", 0, RoundUp, 0, false},
+
+		{"negative elapsed", -time.Minute, RoundUp, 0, true},
+		{"unknown policy", time.Minute, "sideways", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RoundToMinutes(tt.elapsed, tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RoundToMinutes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RoundToMinutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundMinutes(t *testing.T) {
+	tests := []struct {
+		minutes   int64
+		increment int64
+		mode      string
+		want      int64
+	}{
+		// Mid-increment
+		{22, 15, RoundUp, 30},
+		{22, 15, RoundNearest, 15},
+		{23, 15, RoundNearest, 30},
+		{22, 15, RoundDown, 15},
+
+		// Exact multiples are unchanged in every mode
+		{30, 15, RoundUp, 30},
+		{30, 15, RoundNearest, 30},
+		{30, 15, RoundDown, 30},
+
+		// Just past a boundary
+		{31, 15, RoundUp, 45},
+		{31, 15, RoundNearest, 30},
+		{44, 15, RoundDown, 30},
+
+		// Halfway rounds up for nearest
+		{15, 30, RoundNearest, 30},
+		{45, 30, RoundNearest, 60},
+		{44, 30, RoundNearest, 30},
+
+		// Below one increment never rounds to zero
+		{5, 15, RoundUp, 15},
+		{14, 30, RoundNearest, 30},
+		{5, 15, RoundDown, 15},
+		{14, 15, RoundDown, 15},
+		{0, 15, RoundDown, 0},
+
+		// Default mode is up; no increment leaves minutes alone
+		{22, 15, "", 30},
+		{22, 0, RoundUp, 22},
+		{22, -5, RoundDown, 22},
+	}
+
+	for _, tt := range tests {
+		if got := RoundMinutes(tt.minutes, tt.increment, tt.mode); got != tt.want {
+			t.Errorf("RoundMinutes(%d, %d, %q) = %d, want %d", tt.minutes, tt.increment, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func TestValidateRoundMode(t *testing.T) {
+	for _, mode := range []string{RoundUp, RoundNearest, RoundDown, ""} {
+		if err := ValidateRoundMode(mode); err != nil {
+			t.Errorf("ValidateRoundMode(%q) error = %v", mode, err)
+		}
+	}
+	if err := ValidateRoundMode("sideways"); err == nil {
+		t.Error("Expected error for unknown round mode")
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		minutes int64
+		want    string
+	}{
+		{0, "0m"},
+		{45, "45m"},
+		{120, "2h"},
+		{90, "1h 30m"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDuration(tt.minutes); got != tt.want {
+			t.Errorf("FormatDuration(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestFormatDecimalHours(t *testing.T) {
+	tests := []struct {
+		minutes int64
+		want    string
+	}{
+		{0, "0.00h"},
+		{20, "0.33h"},
+		{90, "1.50h"},
+		{600, "10.00h"},
+	}
+
+	for _, tt := range tests {
+		if got := FormatDecimalHours(tt.minutes); got != tt.want {
+			t.Errorf("FormatDecimalHours(%d) = %q, want %q", tt.minutes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ScheduleHelp describes the formats ParseSchedule accepts, for tool descriptions
+const ScheduleHelp = "'HH:MM' daily, or days then time: 'mon-fri 18:00', 'mon,wed,fri 17:30'"
+
+// weekdayNames maps the day abbreviations a schedule uses to weekdays
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// Schedule is a time of day on some days of the week, in local time
+type Schedule struct {
+	Days   [7]bool // Indexed by time.Weekday
+	Hour   int
+	Minute int
+}
+
+// ParseSchedule parses a daily time ("18:00") or days followed by a time ("mon-fri 18:00",
+// "sat,sun 10:00"). Days are three-letter abbreviations, case-insensitive; a range wraps
+// around the week ("fri-mon").
+func ParseSchedule(input string) (Schedule, error) {
+	var schedule Schedule
+
+	fields := strings.Fields(strings.ToLower(input))
+	var days, clock string
+	switch len(fields) {
+	case 1:
+		clock = fields[0]
+		for i := range schedule.Days {
+			schedule.Days[i] = true
+		}
+	case 2:
+		days, clock = fields[0], fields[1]
+	default:
+		return schedule, fmt.Errorf("invalid schedule %q (use %s)", input, ScheduleHelp)
+	}
+
+	at, err := time.Parse("15:04", clock)
+	if err != nil {
+		return schedule, fmt.Errorf("invalid schedule time %q (use HH:MM)", clock)
+	}
+	schedule.Hour, schedule.Minute = at.Hour(), at.Minute()
+
+	if days == "" {
+		return schedule, nil
+	}
+	for _, part := range strings.Split(days, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdayNames[from]
+		if !ok {
+			return schedule, fmt.Errorf("invalid schedule day %q (use mon, tue, wed, thu, fri, sat, sun)", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdayNames[to]; !ok {
+				return schedule, fmt.Errorf("invalid schedule day %q (use mon, tue, wed, thu, fri, sat, sun)", to)
+			}
+		}
+		for day := first; ; day = (day + 1) % 7 {
+			schedule.Days[day] = true
+			if day == last {
+				break
+			}
+		}
+	}
+
+	return schedule, nil
+}
+
+// Previous returns the latest scheduled time at or before now, in now's location
+// It is zero only for a schedule without days, which ParseSchedule never returns.
+func (s Schedule) Previous(now time.Time) time.Time {
+	// Eight days back covers a weekly schedule whose time today has not come yet
+	for back := 0; back <= 7; back++ {
+		day := now.AddDate(0, 0, -back)
+		if !s.Days[day.Weekday()] {
+			continue
+		}
+		at := time.Date(day.Year(), day.Month(), day.Day(), s.Hour, s.Minute, 0, 0, now.Location())
+		if !at.After(now) {
+			return at
+		}
+	}
+	return time.Time{}
+}
+
+// Due reports whether a scheduled time has passed since lastRun, so a run is owed
+// Missed times (e.g. while the server was down) are owed once, not once per missed time.
+// A zero lastRun is due as soon as any scheduled time has passed.
+func (s Schedule) Due(now, lastRun time.Time) bool {
+	previous := s.Previous(now)
+	return !previous.IsZero() && previous.After(lastRun)
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	tests := []struct {
+		input  string
+		days   string // Sunday first, x = scheduled
+		hour   int
+		minute int
+	}{
+		{"18:00", "xxxxxxx", 18, 0},
+		{"mon-fri 18:00", ".xxxxx.", 18, 0},
+		{"Mon,Wed,Fri 17:30", ".x.x.x.", 17, 30},
+		{"fri-mon 09:05", "xx...xx", 9, 5},
+		{"sat 23:59", "......x", 23, 59},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			schedule, err := ParseSchedule(tt.input)
+			if err != nil {
+				t.Fatalf("ParseSchedule() error = %v", err)
+			}
+			days := ""
+			for _, on := range schedule.Days {
+				if on {
+					days += "x"
+				} else {
+					days += "."
+				}
+			}
+			if days != tt.days || schedule.Hour != tt.hour || schedule.Minute != tt.minute {
+				t.Errorf("got days %s at %02d:%02d, want %s at %02d:%02d", days, schedule.Hour, schedule.Minute, tt.days, tt.hour, tt.minute)
+			}
+		})
+	}
+
+	for _, input := range []string{"", "6pm", "25:00", "daily 18:00", "mon-fri", "mon 18:00 extra"} {
+		if _, err := ParseSchedule(input); err == nil {
+			t.Errorf("ParseSchedule(%q) expected an error", input)
+		}
+	}
+}
+
+func TestScheduleDue(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	// Wednesday 2026-10-14
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2026, 10, day, hour, minute, 0, 0, loc)
+	}
+
+	daily, _ := ParseSchedule("18:00")
+	weekdays, _ := ParseSchedule("mon-fri 18:00")
+
+	tests := []struct {
+		name     string
+		schedule Schedule
+		now      time.Time
+		lastRun  time.Time
+		want     bool
+	}{
+		{"before today's time", daily, at(14, 17, 59), at(13, 18, 0), false},
+		{"exactly at the time", daily, at(14, 18, 0), at(13, 18, 0), true},
+		{"later the same evening", daily, at(14, 22, 0), at(13, 18, 1), true},
+		{"already ran today", daily, at(14, 22, 0), at(14, 18, 0), false},
+		{"missed days are owed once", daily, at(14, 9, 0), at(10, 18, 0), true},
+		{"never ran", daily, at(14, 9, 0), time.Time{}, true},
+		{"weekend is skipped", weekdays, at(18, 19, 0), at(16, 18, 0), false},
+		{"monday after the weekend", weekdays, at(19, 18, 30), at(16, 18, 0), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.Due(tt.now, tt.lastRun); got != tt.want {
+				t.Errorf("Due(%v, %v) = %v, want %v", tt.now, tt.lastRun, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSchedulePrevious(t *testing.T) {
+	loc := time.FixedZone("CET", 3600)
+	saturday, _ := ParseSchedule("sat 10:00")
+
+	// Saturday 2026-10-17 before 10:00: the previous run was a week earlier
+	got := saturday.Previous(time.Date(2026, 10, 17, 9, 0, 0, 0, loc))
+	if want := time.Date(2026, 10, 10, 10, 0, 0, 0, loc); !got.Equal(want) {
+		t.Errorf("Previous() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,28 @@
+package utils
+
+import (
+	"strings"
+	"time"
+)
+
+// DefaultManualMessage is used when no message template is configured
+const DefaultManualMessage = "Manual entry"
+
+// RenderMessageTemplate renders a message template for an entry
+// Supported placeholders:
+//   - "{project}" -> project name
+//   - "{date}" -> entry date (YYYY-MM-DD)
+//
+// An empty template falls back to DefaultManualMessage
+func RenderMessageTemplate(template, projectName string, date time.Time) string {
+	template = strings.TrimSpace(template)
+	if template == "" {
+		return DefaultManualMessage
+	}
+
+	replacer := strings.NewReplacer(
+		"{project}", projectName,
+		"{date}", date.Format("2006-01-02"),
+	)
+	return replacer.Replace(template)
+}
@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRenderMessageTemplate(t *testing.T) {
+	date := time.Date(2026, 3, 14, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		template string
+		project  string
+		want     string
+	}{
+		{"project and date", "{project} — manual — {date}", "Clockwork", "Clockwork — manual — 2026-03-14"},
+		{"no placeholders", "Meeting", "Clockwork", "Meeting"},
+		{"repeated placeholder", "{project}/{project}", "Acme", "Acme/Acme"},
+		{"empty falls back", "", "Clockwork", "Manual entry"},
+		{"whitespace falls back", "   ", "Clockwork", "Manual entry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderMessageTemplate(tt.template, tt.project, date)
+			if got != tt.want {
+				t.Errorf("RenderMessageTemplate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}